@@ -0,0 +1,110 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package dnd defines an event for external drag and drop, such as dragging
+// files from a file manager, or text from another application, into a window.
+//
+// The events are sent to a window's EventDeque. A drag is a sequence of a
+// TypeEnter event, zero or more TypeMove events and then either a TypeLeave or
+// a TypeDrop event.
+package dnd // import "golang.org/x/exp/shiny/dnd"
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// TODO: let the app accept or reject a drag, or choose between copy, move and
+// link actions, instead of the driver always accepting a copy.
+//
+// TODO: dragging from a shiny window to other applications.
+
+// Type describes the type of a drag and drop event.
+type Type uint8
+
+const (
+	// TypeEnter is when a drag first moves over the window.
+	TypeEnter Type = 0
+	// TypeMove is when a drag moves within the window.
+	TypeMove Type = 1
+	// TypeLeave is when a drag leaves the window, or is cancelled, without
+	// dropping anything.
+	TypeLeave Type = 2
+	// TypeDrop is when the dragged data is dropped on the window.
+	TypeDrop Type = 3
+)
+
+func (t Type) String() string {
+	switch t {
+	case TypeEnter:
+		return "Enter"
+	case TypeMove:
+		return "Move"
+	case TypeLeave:
+		return "Leave"
+	case TypeDrop:
+		return "Drop"
+	default:
+		return fmt.Sprintf("dnd.Type(%d)", t)
+	}
+}
+
+// Event is a drag and drop event.
+type Event struct {
+	// Type is the drag and drop event type.
+	Type Type
+
+	// X and Y are the pointer position, in pixels. They are not meaningful
+	// for TypeLeave events.
+	X, Y float32
+
+	// URIs and Text are the dropped data, and are only set for TypeDrop
+	// events. Dropped files, which are the common case, are listed as "file"
+	// URIs. Dropped text that isn't a list of URIs is in the Text field.
+	URIs []string
+	Text string
+}
+
+// Files returns the local file names listed in e.URIs, skipping any URIs that
+// aren't "file" URIs.
+func (e Event) Files() []string {
+	var files []string
+	for _, s := range e.URIs {
+		u, err := url.Parse(s)
+		if err != nil || u.Scheme != "file" || (u.Host != "" && u.Host != "localhost") {
+			continue
+		}
+		path := u.Path
+		// A Windows file URI looks like "file:///C:/foo/bar.png".
+		if len(path) >= 3 && path[0] == '/' && path[2] == ':' {
+			path = path[1:]
+		}
+		files = append(files, filepath.FromSlash(path))
+	}
+	return files
+}
+
+// ParseURIList parses a "text/uri-list" MIME type payload, as per RFC 2483.
+func ParseURIList(s string) []string {
+	var uris []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		uris = append(uris, line)
+	}
+	return uris
+}
+
+// FileURI returns the "file" URI for the absolute local file name.
+func FileURI(name string) string {
+	path := filepath.ToSlash(name)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
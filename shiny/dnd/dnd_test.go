@@ -0,0 +1,44 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dnd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseURIList(t *testing.T) {
+	got := ParseURIList("# comment\r\nfile:///tmp/a.png\r\nhttp://example.com/b.png\r\n\r\n")
+	want := []string{
+		"file:///tmp/a.png",
+		"http://example.com/b.png",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	e := Event{
+		Type: TypeDrop,
+		URIs: []string{
+			"file:///tmp/a.png",
+			"http://example.com/b.png",
+			"file://localhost/tmp/c%20d.png",
+			"file://elsewhere/tmp/e.png",
+			FileURI("/tmp/f.png"),
+		},
+	}
+	got := e.Files()
+	want := []string{
+		filepath.FromSlash("/tmp/a.png"),
+		filepath.FromSlash("/tmp/c d.png"),
+		filepath.FromSlash("/tmp/f.png"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
@@ -30,6 +30,9 @@ type screenImpl struct {
 	// onto.
 	windowFrame image.Rectangle
 
+	// the size of the whole display, as reported by /dev/draw/new.
+	displaySize image.Rectangle
+
 	// list of existing window image IDs that have been allocated, so we know
 	// what to free at the end.
 	windows []*windowImpl
@@ -59,7 +62,7 @@ func (s *screenImpl) release() {
 	s.ctl.FreeScreen(s.screenId)
 }
 func newScreenImpl() (*screenImpl, error) {
-	ctrl, _, err := NewDrawCtrler()
+	ctrl, msg, err := NewDrawCtrler()
 	if err != nil {
 		return nil, fmt.Errorf("new controller: %v", err)
 	}
@@ -73,9 +76,10 @@ func newScreenImpl() (*screenImpl, error) {
 	}
 
 	return &screenImpl{
-		ctl:      ctrl,
-		windows:  make([]*windowImpl, 0),
-		screenId: sId,
+		ctl:         ctrl,
+		windows:     make([]*windowImpl, 0),
+		screenId:    sId,
+		displaySize: msg.DisplaySize,
 	}, nil
 }
 
@@ -28,9 +28,27 @@ func readWctl() (image.Rectangle, error) {
 		return image.ZR, err
 	}
 	sizes := strings.Fields(string(value))
-	// remove 4 pixels from each side to take rio's borders into consideration.
+	// remove rio's borders from each side.
 	return image.Rectangle{
-		Min: image.Point{strToInt(sizes[0]) + 4, strToInt(sizes[1]) + 4},
-		Max: image.Point{strToInt(sizes[2]) - 4, strToInt(sizes[3]) - 4},
+		Min: image.Point{strToInt(sizes[0]) + rioBorder, strToInt(sizes[1]) + rioBorder},
+		Max: image.Point{strToInt(sizes[2]) - rioBorder, strToInt(sizes[3]) - rioBorder},
 	}, nil
 }
+
+// rioBorder is the width, in pixels, of the border that rio draws around
+// each window.
+const rioBorder = 4
+
+// writeWctl writes a control message, such as "hide" or "move -minx 10 -miny
+// 20", to /dev/wctl. See rio(4) for the messages that rio understands. If the
+// message changes the window's size, rio will send a resize message on
+// /dev/mouse, which is turned into a size.Event by mouseEventHandler.
+func writeWctl(msg string) error {
+	ctl, err := os.OpenFile("/dev/wctl", os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer ctl.Close()
+	_, err = ctl.Write([]byte(msg))
+	return err
+}
@@ -5,8 +5,13 @@
 package devdrawdriver
 
 import (
+	"fmt"
+	"log"
+	"sync"
+
 	"golang.org/x/exp/shiny/driver/internal/drawer"
 	"golang.org/x/exp/shiny/driver/internal/event"
+	"golang.org/x/exp/shiny/driver/internal/frameclock"
 	"golang.org/x/exp/shiny/screen"
 	xdraw "golang.org/x/image/draw"
 	"golang.org/x/image/math/f64"
@@ -23,6 +28,18 @@ type windowImpl struct {
 	*uploadImpl
 	s *screenImpl
 	event.Deque
+
+	// frameClock sends frame timing events. /dev/draw has no notion of the
+	// display's vertical blank, so this is purely a timer.
+	frameClock frameclock.Clock
+
+	// mu guards the window management state below, which rio does not
+	// track for us.
+	mu               sync.Mutex
+	hidden           bool
+	fullscreen       bool
+	savedFrame       image.Rectangle
+	sizeMin, sizeMax image.Point
 }
 
 // Do an affine transformation on sr using src2dst.
@@ -132,6 +149,10 @@ func (w *windowImpl) Scale(dr image.Rectangle, src screen.Texture, sr image.Rect
 	drawer.Scale(w, dr, src, sr, op, opts)
 }
 
+func (w *windowImpl) RequestFrame() {
+	w.frameClock.Request(w)
+}
+
 func (w *windowImpl) Publish() screen.PublishResult {
 	redrawWindow(w.s, w.s.windowFrame)
 	return screen.PublishResult{false}
@@ -181,3 +202,106 @@ func (w *windowImpl) DrawUniform(src2dst f64.Aff3, src color.Color, sr image.Rec
 
 	w.s.ctl.Draw(uint32(w.imageId), colorID, colorID, newRectangle, image.ZP, image.ZP, op)
 }
+
+// wctl writes a message to /dev/wctl, logging any error. If the window was
+// hidden by Minimize, it is unhidden first, since rio ignores most messages
+// for hidden windows.
+func (w *windowImpl) wctl(msg string) {
+	w.mu.Lock()
+	hidden := w.hidden
+	w.hidden = false
+	w.mu.Unlock()
+	if hidden {
+		// The user may have already unhidden the window via rio's menu, in
+		// which case this fails harmlessly.
+		writeWctl("unhide")
+	}
+	if err := writeWctl(msg); err != nil {
+		log.Printf("devdrawdriver: write %q to /dev/wctl: %v\n", msg, err)
+	}
+}
+
+// clampSize applies the limits set by SetSizeLimits to size.
+func (w *windowImpl) clampSize(size image.Point) image.Point {
+	w.mu.Lock()
+	min, max := w.sizeMin, w.sizeMax
+	w.mu.Unlock()
+	if min.X > 0 && size.X < min.X {
+		size.X = min.X
+	}
+	if min.Y > 0 && size.Y < min.Y {
+		size.Y = min.Y
+	}
+	if max.X > 0 && size.X > max.X {
+		size.X = max.X
+	}
+	if max.Y > 0 && size.Y > max.Y {
+		size.Y = max.Y
+	}
+	return size
+}
+
+func (w *windowImpl) Resize(size image.Point) {
+	size = w.clampSize(size)
+	if size.X <= 0 || size.Y <= 0 {
+		return
+	}
+	// rio's -dx and -dy include the window's border.
+	w.wctl(fmt.Sprintf("resize -dx %d -dy %d", size.X+2*rioBorder, size.Y+2*rioBorder))
+}
+
+func (w *windowImpl) Move(p image.Point) {
+	w.wctl(fmt.Sprintf("move -minx %d -miny %d", p.X-rioBorder, p.Y-rioBorder))
+}
+
+func (w *windowImpl) SetFullscreen(fullscreen bool) {
+	w.mu.Lock()
+	if w.fullscreen == fullscreen {
+		w.mu.Unlock()
+		return
+	}
+	w.fullscreen = fullscreen
+	var r image.Rectangle
+	if fullscreen {
+		w.savedFrame = w.s.windowFrame
+		// Push rio's border off of the edges of the display.
+		r = w.s.displaySize.Inset(-rioBorder)
+	} else {
+		r = w.savedFrame.Inset(-rioBorder)
+	}
+	w.mu.Unlock()
+	w.wctl(fmt.Sprintf("resize -r %d %d %d %d", r.Min.X, r.Min.Y, r.Max.X, r.Max.Y))
+}
+
+func (w *windowImpl) Minimize() {
+	if err := writeWctl("hide"); err != nil {
+		log.Printf("devdrawdriver: write \"hide\" to /dev/wctl: %v\n", err)
+		return
+	}
+	w.mu.Lock()
+	w.hidden = true
+	w.mu.Unlock()
+}
+
+func (w *windowImpl) SetAlwaysOnTop(alwaysOnTop bool) {
+	// rio has no notion of a window that stays above the others. The best
+	// that we can do is to bring the window to the top once.
+	if alwaysOnTop {
+		w.wctl("top")
+	}
+}
+
+func (w *windowImpl) SetSizeLimits(min, max image.Point) {
+	// rio has no notion of size limits, so they are only enforced by Resize.
+	// If the current size is out of bounds, resize it now.
+	w.mu.Lock()
+	w.sizeMin, w.sizeMax = min, max
+	w.mu.Unlock()
+	if size := w.s.windowFrame.Size(); w.clampSize(size) != size {
+		w.Resize(size)
+	}
+}
+
+func (w *windowImpl) SetResizable(resizable bool) {
+	// TODO: rio has no way to stop the user from resizing a window.
+}
@@ -13,6 +13,7 @@ import (
 	"syscall"
 	"unsafe"
 
+	"golang.org/x/exp/shiny/dnd"
 	"golang.org/x/exp/shiny/driver/internal/win32"
 	"golang.org/x/exp/shiny/screen"
 	"golang.org/x/mobile/event/key"
@@ -124,6 +125,7 @@ func init() {
 	win32.PaintEvent = paintEvent
 	win32.MouseEvent = mouseEvent
 	win32.KeyEvent = keyEvent
+	win32.DndEvent = dndEvent
 	win32.LifecycleEvent = lifecycleEvent
 }
 
@@ -159,6 +161,14 @@ func keyEvent(hwnd syscall.Handle, e key.Event) {
 	w.Send(e)
 }
 
+func dndEvent(hwnd syscall.Handle, e dnd.Event) {
+	theScreen.mu.Lock()
+	w := theScreen.windows[uintptr(hwnd)]
+	theScreen.mu.Unlock()
+
+	w.Send(e)
+}
+
 func paintEvent(hwnd syscall.Handle, e paint.Event) {
 	theScreen.mu.Lock()
 	w := theScreen.windows[uintptr(hwnd)]
@@ -9,9 +9,11 @@ import (
 	"image/color"
 	"image/draw"
 	"sync"
+	"time"
 
 	"golang.org/x/exp/shiny/driver/internal/drawer"
 	"golang.org/x/exp/shiny/driver/internal/event"
+	"golang.org/x/exp/shiny/driver/internal/frameclock"
 	"golang.org/x/exp/shiny/driver/internal/lifecycler"
 	"golang.org/x/exp/shiny/screen"
 	"golang.org/x/image/math/f64"
@@ -45,6 +47,13 @@ type windowImpl struct {
 	publishDone chan screen.PublishResult
 	drawDone    chan struct{}
 
+	// frameClock sends frame timing events. With a swap interval of 1,
+	// swapping the buffers waits for the vertical blank, so each Publish
+	// re-synchronizes the clock with the display.
+	//
+	// TODO: set the swap interval on X11, as is done for Cocoa and Windows.
+	frameClock frameclock.Clock
+
 	// glctxMu is a mutex that enforces the atomicity of methods like
 	// Texture.Upload or Window.Draw that are conceptually one operation
 	// but are implemented by multiple OpenGL calls. OpenGL is a stateful
@@ -330,6 +339,7 @@ func (w *windowImpl) Publish() screen.PublishResult {
 
 	w.publish <- struct{}{}
 	res := <-w.publishDone
+	w.frameClock.Sync(time.Now())
 
 	select {
 	case w.drawDone <- struct{}{}:
@@ -338,3 +348,19 @@ func (w *windowImpl) Publish() screen.PublishResult {
 
 	return res
 }
+
+func (w *windowImpl) RequestFrame() {
+	w.frameClock.Request(w)
+}
+
+// TODO: implement the window management methods below for Cocoa, X11 (via
+// EWMH, as the x11driver does) and Windows (via the internal/win32 package,
+// as the windriver does). Until then, they are no-ops.
+
+func (w *windowImpl) Resize(size image.Point)            {}
+func (w *windowImpl) Move(p image.Point)                 {}
+func (w *windowImpl) SetFullscreen(fullscreen bool)      {}
+func (w *windowImpl) Minimize()                          {}
+func (w *windowImpl) SetAlwaysOnTop(alwaysOnTop bool)    {}
+func (w *windowImpl) SetSizeLimits(min, max image.Point) {}
+func (w *windowImpl) SetResizable(resizable bool)        {}
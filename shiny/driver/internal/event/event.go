@@ -6,52 +6,120 @@
 package event // import "golang.org/x/exp/shiny/driver/internal/event"
 
 import (
+	"context"
 	"sync"
+
+	"golang.org/x/exp/shiny/screen"
+	"golang.org/x/mobile/event/lifecycle"
+	"golang.org/x/mobile/event/mouse"
+	"golang.org/x/mobile/event/size"
 )
 
 // Deque is an infinitely buffered double-ended queue of events. The zero value
 // is usable, but a Deque value must not be copied.
 type Deque struct {
-	mu    sync.Mutex
-	cond  sync.Cond     // cond.L is lazily initialized to &Deque.mu.
-	back  []interface{} // FIFO.
-	front []interface{} // LIFO.
+	mu       sync.Mutex
+	cond     sync.Cond     // cond.L is lazily initialized to &Deque.mu.
+	back     []interface{} // FIFO.
+	front    []interface{} // LIFO.
+	coalesce screen.Coalesce
+
+	latestLifecycle lifecycle.Event
+	latestSize      size.Event
+}
+
+// lazyInit must only be called while holding q.mu.
+func (q *Deque) lazyInit() {
+	if q.cond.L == nil {
+		q.cond.L = &q.mu
+	}
+}
+
+// pop returns the next event, if any. It must only be called while holding
+// q.mu.
+func (q *Deque) pop() (event interface{}, ok bool) {
+	if n := len(q.front); n > 0 {
+		e := q.front[n-1]
+		q.front = q.front[:n-1]
+		return e, true
+	}
+
+	if n := len(q.back); n > 0 {
+		e := q.back[0]
+		q.back = q.back[1:]
+		return e, true
+	}
+
+	return nil, false
 }
 
 // NextEvent implements the screen.EventDeque interface.
 func (q *Deque) NextEvent() interface{} {
 	q.mu.Lock()
 	defer q.mu.Unlock()
-	if q.cond.L == nil {
-		q.cond.L = &q.mu
-	}
+	q.lazyInit()
 
 	for {
-		if n := len(q.front); n > 0 {
-			e := q.front[n-1]
-			q.front = q.front[:n-1]
+		if e, ok := q.pop(); ok {
 			return e
 		}
+		q.cond.Wait()
+	}
+}
 
-		if n := len(q.back); n > 0 {
-			e := q.back[0]
-			q.back = q.back[1:]
-			return e
-		}
+// NextEventContext implements the screen.EventDeque interface.
+func (q *Deque) NextEventContext(ctx context.Context) (interface{}, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.lazyInit()
+
+	if done := ctx.Done(); done != nil {
+		// Wake up the q.cond.Wait call below when ctx is done.
+		stop := make(chan struct{})
+		defer close(stop)
+		go func() {
+			select {
+			case <-done:
+				q.mu.Lock()
+				q.cond.Broadcast()
+				q.mu.Unlock()
+			case <-stop:
+			}
+		}()
+	}
 
+	for {
+		// Check for an event before checking ctx, so that a woken waiter
+		// that returns an error never leaves an event behind that another
+		// waiter should have been signaled for.
+		if e, ok := q.pop(); ok {
+			return e, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		q.cond.Wait()
 	}
 }
 
+// PollEvent implements the screen.EventDeque interface.
+func (q *Deque) PollEvent() (event interface{}, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.pop()
+}
+
 // Send implements the screen.EventDeque interface.
 func (q *Deque) Send(event interface{}) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
-	if q.cond.L == nil {
-		q.cond.L = &q.mu
-	}
+	q.lazyInit()
 
-	q.back = append(q.back, event)
+	q.record(event)
+	if !q.coalesceBack(event) {
+		q.back = append(q.back, event)
+	}
 	q.cond.Signal()
 }
 
@@ -59,10 +127,83 @@ func (q *Deque) Send(event interface{}) {
 func (q *Deque) SendFirst(event interface{}) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
-	if q.cond.L == nil {
-		q.cond.L = &q.mu
-	}
+	q.lazyInit()
 
+	q.record(event)
 	q.front = append(q.front, event)
 	q.cond.Signal()
 }
+
+// SetCoalesce implements the screen.EventDeque interface.
+func (q *Deque) SetCoalesce(c screen.Coalesce) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.coalesce = c
+}
+
+// LatestLifecycleEvent implements the screen.EventDeque interface.
+func (q *Deque) LatestLifecycleEvent() lifecycle.Event {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.latestLifecycle
+}
+
+// LatestSizeEvent implements the screen.EventDeque interface.
+func (q *Deque) LatestSizeEvent() size.Event {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.latestSize
+}
+
+// record must only be called while holding q.mu.
+func (q *Deque) record(event interface{}) {
+	switch e := event.(type) {
+	case lifecycle.Event:
+		q.latestLifecycle = e
+	case size.Event:
+		q.latestSize = e
+	}
+}
+
+// coalesceBack merges event with the pending events at the back of the
+// deque, according to q.coalesce. It returns whether it did so, in which case
+// event should not also be appended. It must only be called while holding
+// q.mu.
+func (q *Deque) coalesceBack(event interface{}) bool {
+	switch e := event.(type) {
+	case size.Event:
+		if q.coalesce&screen.CoalesceSize == 0 {
+			return false
+		}
+		// Drop any pending size.Events. Only the latest one matters.
+		back := q.back[:0]
+		for _, x := range q.back {
+			if _, ok := x.(size.Event); !ok {
+				back = append(back, x)
+			}
+		}
+		for i := len(back); i < len(q.back); i++ {
+			q.back[i] = nil
+		}
+		q.back = back
+
+	case mouse.Event:
+		if q.coalesce&screen.CoalesceMouseMove == 0 || e.Direction != mouse.DirNone {
+			return false
+		}
+		n := len(q.back)
+		if n == 0 {
+			return false
+		}
+		// Replace the previous event if it is an otherwise equivalent move.
+		if prev, ok := q.back[n-1].(mouse.Event); ok && prev.Direction == mouse.DirNone &&
+			prev.Button == e.Button && prev.Modifiers == e.Modifiers {
+			q.back[n-1] = e
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package event
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/shiny/screen"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/lifecycle"
+	"golang.org/x/mobile/event/mouse"
+	"golang.org/x/mobile/event/size"
+)
+
+func drain(q *Deque) (events []interface{}) {
+	for {
+		e, ok := q.PollEvent()
+		if !ok {
+			return events
+		}
+		events = append(events, e)
+	}
+}
+
+func TestOrder(t *testing.T) {
+	q := &Deque{}
+	q.Send(1)
+	q.Send(2)
+	q.SendFirst(3)
+	q.SendFirst(4)
+	got := drain(q)
+	want := []interface{}{4, 3, 1, 2}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestCoalesce(t *testing.T) {
+	move := func(x float32) mouse.Event { return mouse.Event{X: x, Direction: mouse.DirNone} }
+	press := mouse.Event{X: 9, Button: mouse.ButtonLeft, Direction: mouse.DirPress}
+	k := key.Event{Rune: 'a'}
+
+	send := func(q *Deque) {
+		q.Send(size.Event{WidthPx: 1})
+		q.Send(move(1))
+		q.Send(move(2))
+		q.Send(k)
+		q.Send(size.Event{WidthPx: 2})
+		q.Send(move(3))
+		q.Send(press)
+		q.Send(move(4))
+		q.Send(move(5))
+	}
+
+	testCases := []struct {
+		coalesce screen.Coalesce
+		want     []interface{}
+	}{{
+		coalesce: 0,
+		want: []interface{}{
+			size.Event{WidthPx: 1}, move(1), move(2), k,
+			size.Event{WidthPx: 2}, move(3), press, move(4), move(5),
+		},
+	}, {
+		coalesce: screen.CoalesceSize,
+		want: []interface{}{
+			move(1), move(2), k,
+			size.Event{WidthPx: 2}, move(3), press, move(4), move(5),
+		},
+	}, {
+		coalesce: screen.CoalesceMouseMove,
+		want: []interface{}{
+			size.Event{WidthPx: 1}, move(2), k,
+			size.Event{WidthPx: 2}, move(3), press, move(5),
+		},
+	}, {
+		coalesce: screen.CoalesceSize | screen.CoalesceMouseMove,
+		want: []interface{}{
+			move(2), k,
+			size.Event{WidthPx: 2}, move(3), press, move(5),
+		},
+	}}
+
+	for _, tc := range testCases {
+		q := &Deque{}
+		q.SetCoalesce(tc.coalesce)
+		send(q)
+		if got := drain(q); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("coalesce=%d:\ngot  %v\nwant %v", tc.coalesce, got, tc.want)
+		}
+	}
+}
+
+func TestLatest(t *testing.T) {
+	q := &Deque{}
+	if got := q.LatestSizeEvent(); got != (size.Event{}) {
+		t.Fatalf("LatestSizeEvent before any Send: got %v, want zero", got)
+	}
+	q.Send(size.Event{WidthPx: 1})
+	q.Send(lifecycle.Event{To: lifecycle.StageVisible})
+	q.SendFirst(size.Event{WidthPx: 2})
+	if got, want := q.LatestSizeEvent(), (size.Event{WidthPx: 2}); got != want {
+		t.Fatalf("LatestSizeEvent: got %v, want %v", got, want)
+	}
+	drain(q)
+	if got, want := q.LatestLifecycleEvent(), (lifecycle.Event{To: lifecycle.StageVisible}); got != want {
+		t.Fatalf("LatestLifecycleEvent: got %v, want %v", got, want)
+	}
+}
+
+func TestNextEventContext(t *testing.T) {
+	q := &Deque{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if e, err := q.NextEventContext(ctx); e != nil || err != context.DeadlineExceeded {
+		t.Fatalf("empty deque: got %v, %v, want nil, %v", e, err, context.DeadlineExceeded)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		q.Send(1)
+	}()
+	e, err := q.NextEventContext(context.Background())
+	if e != 1 || err != nil {
+		t.Fatalf("non-empty deque: got %v, %v, want 1, nil", e, err)
+	}
+
+	// An event that is already in the deque is returned even if ctx is done.
+	q.Send(2)
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if e, err := q.NextEventContext(ctx); e != 2 || err != nil {
+		t.Fatalf("cancelled context: got %v, %v, want 2, nil", e, err)
+	}
+}
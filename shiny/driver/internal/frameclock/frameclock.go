@@ -0,0 +1,79 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package frameclock provides timer-based frame timing, for drivers that
+// cannot be notified of the display's vertical blank by the window system.
+package frameclock // import "golang.org/x/exp/shiny/driver/internal/frameclock"
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/exp/shiny/screen"
+	"golang.org/x/exp/shiny/vsync"
+)
+
+// DefaultInterval is the frame interval used when the display's refresh rate
+// is unknown.
+const DefaultInterval = time.Second / 60
+
+// Clock sends vsync.Events on request. Its zero value is a valid Clock that
+// ticks every DefaultInterval.
+//
+// The ticks are at a fixed phase, so that successive frames are evenly spaced
+// even if a frame is requested part-way through the previous frame interval.
+type Clock struct {
+	mu       sync.Mutex
+	pending  bool
+	interval time.Duration
+	phase    time.Time
+}
+
+// SetInterval sets the frame interval, typically the display's refresh period.
+// A non-positive d means to use DefaultInterval.
+func (c *Clock) SetInterval(d time.Duration) {
+	c.mu.Lock()
+	c.interval = d
+	c.mu.Unlock()
+}
+
+// Sync sets the clock's phase so that a tick happens at t, such as when a
+// buffer swap, which waits for the vertical blank, has just completed.
+func (c *Clock) Sync(t time.Time) {
+	c.mu.Lock()
+	c.phase = t
+	c.mu.Unlock()
+}
+
+// Request arranges for a vsync.Event to be sent to q at the next tick.
+// Requests made before that tick are coalesced into one vsync.Event.
+func (c *Clock) Request(q screen.EventDeque) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.pending {
+		return
+	}
+	c.pending = true
+
+	interval := c.interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	now := time.Now()
+	next := c.phase.Add(now.Sub(c.phase).Truncate(interval) + interval)
+	if c.phase.IsZero() {
+		next = now.Truncate(interval).Add(interval)
+	}
+	time.AfterFunc(next.Sub(now), func() {
+		c.mu.Lock()
+		c.pending = false
+		c.mu.Unlock()
+
+		q.Send(vsync.Event{
+			Time:     next,
+			Interval: interval,
+		})
+	})
+}
@@ -48,6 +48,21 @@ type _WNDCLASS struct {
 	LpszClassName *uint16
 }
 
+type _MINMAXINFO struct {
+	PtReserved     _POINT
+	PtMaxSize      _POINT
+	PtMaxPosition  _POINT
+	PtMinTrackSize _POINT
+	PtMaxTrackSize _POINT
+}
+
+type _MONITORINFO struct {
+	CbSize    uint32
+	RcMonitor _RECT
+	RcWork    _RECT
+	DwFlags   uint32
+}
+
 type _WINDOWPOS struct {
 	HWND            syscall.Handle
 	HWNDInsertAfter syscall.Handle
@@ -63,6 +78,7 @@ const (
 	_WM_KILLFOCUS        = 8
 	_WM_PAINT            = 15
 	_WM_CLOSE            = 16
+	_WM_GETMINMAXINFO    = 36
 	_WM_WINDOWPOSCHANGED = 71
 	_WM_KEYDOWN          = 256
 	_WM_KEYUP            = 257
@@ -70,6 +86,7 @@ const (
 	_WM_SYSKEYUP         = 261
 	_WM_MOUSEMOVE        = 512
 	_WM_MOUSEWHEEL       = 522
+	_WM_DROPFILES        = 563
 	_WM_LBUTTONDOWN      = 513
 	_WM_LBUTTONUP        = 514
 	_WM_RBUTTONDOWN      = 516
@@ -87,6 +104,8 @@ const (
 	_WS_MINIMIZEBOX      = 0x00020000
 	_WS_MAXIMIZEBOX      = 0x00010000
 	_WS_OVERLAPPEDWINDOW = _WS_OVERLAPPED | _WS_CAPTION | _WS_SYSMENU | _WS_THICKFRAME | _WS_MINIMIZEBOX | _WS_MAXIMIZEBOX
+
+	_GWL_STYLE = -16
 )
 
 const (
@@ -115,11 +134,23 @@ const (
 const (
 	_CW_USEDEFAULT = 0x80000000 - 0x100000000
 
+	_SW_MINIMIZE    = 6
 	_SW_SHOWDEFAULT = 10
 
 	_HWND_MESSAGE = syscall.Handle(^uintptr(2)) // -3
 
-	_SWP_NOSIZE = 0x0001
+	_HWND_TOP       = syscall.Handle(0)
+	_HWND_TOPMOST   = syscall.Handle(^uintptr(0)) // -1
+	_HWND_NOTOPMOST = syscall.Handle(^uintptr(1)) // -2
+
+	_SWP_NOSIZE        = 0x0001
+	_SWP_NOMOVE        = 0x0002
+	_SWP_NOZORDER      = 0x0004
+	_SWP_NOACTIVATE    = 0x0010
+	_SWP_FRAMECHANGED  = 0x0020
+	_SWP_NOOWNERZORDER = 0x0200
+
+	_MONITOR_DEFAULTTONEAREST = 2
 )
 
 const (
@@ -163,6 +194,7 @@ func _HIWORD(l uintptr) uint16 {
 //sys	ReleaseDC(hwnd syscall.Handle, dc syscall.Handle) (err error) = user32.ReleaseDC
 //sys	sendMessage(hwnd syscall.Handle, uMsg uint32, wParam uintptr, lParam uintptr) (lResult uintptr) = user32.SendMessageW
 
+//sys	_AdjustWindowRectEx(rect *_RECT, style uint32, menu bool, exStyle uint32) (err error) = user32.AdjustWindowRectEx
 //sys	_CreateWindowEx(exstyle uint32, className *uint16, windowText *uint16, style uint32, x int32, y int32, width int32, height int32, parent syscall.Handle, menu syscall.Handle, hInstance syscall.Handle, lpParam uintptr) (hwnd syscall.Handle, err error) = user32.CreateWindowExW
 //sys	_DefWindowProc(hwnd syscall.Handle, uMsg uint32, wParam uintptr, lParam uintptr) (lResult uintptr) = user32.DefWindowProcW
 //sys	_DestroyWindow(hwnd syscall.Handle) (err error) = user32.DestroyWindow
@@ -171,13 +203,24 @@ func _HIWORD(l uintptr) uint16 {
 //sys   _GetKeyboardLayout(threadID uint32) (locale syscall.Handle) = user32.GetKeyboardLayout
 //sys   _GetKeyboardState(lpKeyState *byte) (err error) = user32.GetKeyboardState
 //sys	_GetKeyState(virtkey int32) (keystatus int16) = user32.GetKeyState
+//sys	_GetMonitorInfo(monitor syscall.Handle, mi *_MONITORINFO) (err error) = user32.GetMonitorInfoW
+//sys	_GetWindowLong(hwnd syscall.Handle, index int32) (long int32) = user32.GetWindowLongW
+//sys	_GetWindowRect(hwnd syscall.Handle, rect *_RECT) (err error) = user32.GetWindowRect
 //sys	_GetMessage(msg *_MSG, hwnd syscall.Handle, msgfiltermin uint32, msgfiltermax uint32) (ret int32, err error) [failretval==-1] = user32.GetMessageW
 //sys	_LoadCursor(hInstance syscall.Handle, cursorName uintptr) (cursor syscall.Handle, err error) = user32.LoadCursorW
 //sys	_LoadIcon(hInstance syscall.Handle, iconName uintptr) (icon syscall.Handle, err error) = user32.LoadIconW
+//sys	_MonitorFromWindow(hwnd syscall.Handle, flags uint32) (monitor syscall.Handle) = user32.MonitorFromWindow
 //sys	_PostMessage(hwnd syscall.Handle, uMsg uint32, wParam uintptr, lParam uintptr) (lResult bool) = user32.PostMessageW
 //sys   _PostQuitMessage(exitCode int32) = user32.PostQuitMessage
 //sys	_RegisterClass(wc *_WNDCLASS) (atom uint16, err error) = user32.RegisterClassW
+//sys	_SetWindowLong(hwnd syscall.Handle, index int32, newLong int32) (oldLong int32) = user32.SetWindowLongW
+//sys	_SetWindowPos(hwnd syscall.Handle, hwndInsertAfter syscall.Handle, x int32, y int32, cx int32, cy int32, flags uint32) (err error) = user32.SetWindowPos
 //sys	_ShowWindow(hwnd syscall.Handle, cmdshow int32) (wasvisible bool) = user32.ShowWindow
 //sys	_ScreenToClient(hwnd syscall.Handle, lpPoint *_POINT) (ok bool) = user32.ScreenToClient
 //sys   _ToUnicodeEx(wVirtKey uint32, wScanCode uint32, lpKeyState *byte, pwszBuff *uint16, cchBuff int32, wFlags uint32, dwhkl syscall.Handle) (ret int32) = user32.ToUnicodeEx
 //sys	_TranslateMessage(msg *_MSG) (done bool) = user32.TranslateMessage
+
+//sys	_DragAcceptFiles(hwnd syscall.Handle, accept bool) = shell32.DragAcceptFiles
+//sys	_DragFinish(hDrop syscall.Handle) = shell32.DragFinish
+//sys	_DragQueryFile(hDrop syscall.Handle, iFile uint32, lpszFile *uint16, cch uint32) (n uint32) = shell32.DragQueryFileW
+//sys	_DragQueryPoint(hDrop syscall.Handle, lppt *_POINT) (inClient bool) = shell32.DragQueryPoint
@@ -11,11 +11,13 @@ package win32 // import "golang.org/x/exp/shiny/driver/internal/win32"
 
 import (
 	"fmt"
+	"image"
 	"runtime"
 	"sync"
 	"syscall"
 	"unsafe"
 
+	"golang.org/x/exp/shiny/dnd"
 	"golang.org/x/exp/shiny/screen"
 	"golang.org/x/mobile/event/key"
 	"golang.org/x/mobile/event/lifecycle"
@@ -88,6 +90,7 @@ func newWindow(opts *screen.NewWindowOptions) (syscall.Handle, error) {
 	if err != nil {
 		return 0, err
 	}
+	_DragAcceptFiles(hwnd, true)
 	// TODO(andlabs): use proper nCmdShow
 	// TODO(andlabs): call UpdateWindow()
 
@@ -106,12 +109,212 @@ func Show(hwnd syscall.Handle) {
 }
 
 func Release(hwnd syscall.Handle) {
+	windowStates.Lock()
+	delete(windowStates.m, hwnd)
+	windowStates.Unlock()
+
 	// TODO(andlabs): check for errors from this?
 	// TODO(andlabs): remove unsafe
 	_DestroyWindow(hwnd)
 	// TODO(andlabs): what happens if we're still painting?
 }
 
+// windowState is the state needed to implement the window management
+// functions, such as SetFullscreen, that Windows does not track for us.
+type windowState struct {
+	minSize, maxSize image.Point
+
+	// fullscreen is whether the window is fullscreen. If so, savedStyle and
+	// savedRect are the window's style and rectangle to restore.
+	fullscreen bool
+	savedStyle int32
+	savedRect  _RECT
+}
+
+var windowStates = struct {
+	sync.Mutex
+	m map[syscall.Handle]*windowState
+}{
+	m: map[syscall.Handle]*windowState{},
+}
+
+func getWindowState(hwnd syscall.Handle) *windowState {
+	s := windowStates.m[hwnd]
+	if s == nil {
+		s = &windowState{}
+		windowStates.m[hwnd] = s
+	}
+	return s
+}
+
+// windowRect returns the window rectangle, including the title bar and
+// borders, whose client area is the given rectangle.
+func windowRect(hwnd syscall.Handle, client image.Rectangle) _RECT {
+	r := _RECT{
+		Left:   int32(client.Min.X),
+		Top:    int32(client.Min.Y),
+		Right:  int32(client.Max.X),
+		Bottom: int32(client.Max.Y),
+	}
+	style := uint32(_GetWindowLong(hwnd, _GWL_STYLE))
+	// TODO: check for errors from this?
+	_AdjustWindowRectEx(&r, style, false, 0)
+	return r
+}
+
+// Resize resizes the window so that its client area has the given size.
+func Resize(hwnd syscall.Handle, size image.Point) {
+	r := windowRect(hwnd, image.Rectangle{Max: size})
+	_SetWindowPos(hwnd, 0, 0, 0, r.Right-r.Left, r.Bottom-r.Top,
+		_SWP_NOMOVE|_SWP_NOZORDER|_SWP_NOACTIVATE)
+}
+
+// Move moves the window so that its client area's top-left corner is at the
+// given screen position.
+func Move(hwnd syscall.Handle, p image.Point) {
+	r := windowRect(hwnd, image.Rectangle{Min: p, Max: p})
+	_SetWindowPos(hwnd, 0, r.Left, r.Top, 0, 0,
+		_SWP_NOSIZE|_SWP_NOZORDER|_SWP_NOACTIVATE)
+}
+
+// SetFullscreen makes the window cover, or no longer cover, the whole of its
+// monitor, without a title bar or borders.
+func SetFullscreen(hwnd syscall.Handle, fullscreen bool) {
+	windowStates.Lock()
+	s := getWindowState(hwnd)
+	if s.fullscreen == fullscreen {
+		windowStates.Unlock()
+		return
+	}
+	s.fullscreen = fullscreen
+	if fullscreen {
+		s.savedStyle = _GetWindowLong(hwnd, _GWL_STYLE)
+		_GetWindowRect(hwnd, &s.savedRect)
+	}
+	style, r := s.savedStyle, s.savedRect
+	windowStates.Unlock()
+
+	if !fullscreen {
+		_SetWindowLong(hwnd, _GWL_STYLE, style)
+		_SetWindowPos(hwnd, 0, r.Left, r.Top, r.Right-r.Left, r.Bottom-r.Top,
+			_SWP_NOZORDER|_SWP_NOOWNERZORDER|_SWP_FRAMECHANGED)
+		return
+	}
+
+	mi := _MONITORINFO{}
+	mi.CbSize = uint32(unsafe.Sizeof(mi))
+	if err := _GetMonitorInfo(_MonitorFromWindow(hwnd, _MONITOR_DEFAULTTONEAREST), &mi); err != nil {
+		return
+	}
+	m := mi.RcMonitor
+	_SetWindowLong(hwnd, _GWL_STYLE, style&^_WS_OVERLAPPEDWINDOW)
+	_SetWindowPos(hwnd, _HWND_TOP, m.Left, m.Top, m.Right-m.Left, m.Bottom-m.Top,
+		_SWP_NOOWNERZORDER|_SWP_FRAMECHANGED)
+}
+
+// Minimize minimizes the window.
+func Minimize(hwnd syscall.Handle) {
+	_ShowWindow(hwnd, _SW_MINIMIZE)
+}
+
+// SetAlwaysOnTop places the window above, or no longer above, all windows
+// that are not themselves always on top.
+func SetAlwaysOnTop(hwnd syscall.Handle, alwaysOnTop bool) {
+	after := _HWND_NOTOPMOST
+	if alwaysOnTop {
+		after = _HWND_TOPMOST
+	}
+	_SetWindowPos(hwnd, after, 0, 0, 0, 0, _SWP_NOMOVE|_SWP_NOSIZE|_SWP_NOACTIVATE)
+}
+
+// SetSizeLimits sets the minimum and maximum client area size. A zero X or Y
+// value means no limit in that dimension.
+func SetSizeLimits(hwnd syscall.Handle, min, max image.Point) {
+	windowStates.Lock()
+	s := getWindowState(hwnd)
+	s.minSize, s.maxSize = min, max
+	fullscreen := s.fullscreen
+	windowStates.Unlock()
+
+	// The limits are only applied, via WM_GETMINMAXINFO, when the window is
+	// next resized, so clamp its current size now. A minimized window has an
+	// empty client area, which is left alone.
+	var r _RECT
+	if fullscreen || _GetClientRect(hwnd, &r) != nil {
+		return
+	}
+	size := image.Point{int(r.Right - r.Left), int(r.Bottom - r.Top)}
+	if size == (image.Point{}) {
+		return
+	}
+	if c := clampSize(size, min, max); c != size {
+		Resize(hwnd, c)
+	}
+}
+
+// clampSize applies the limits min and max, as passed to SetSizeLimits, to
+// size.
+func clampSize(size, min, max image.Point) image.Point {
+	if min.X > 0 && size.X < min.X {
+		size.X = min.X
+	}
+	if min.Y > 0 && size.Y < min.Y {
+		size.Y = min.Y
+	}
+	if max.X > 0 && size.X > max.X {
+		size.X = max.X
+	}
+	if max.Y > 0 && size.Y > max.Y {
+		size.Y = max.Y
+	}
+	return size
+}
+
+// SetResizable sets whether the window has a sizing border and a maximize
+// button.
+func SetResizable(hwnd syscall.Handle, resizable bool) {
+	style := _GetWindowLong(hwnd, _GWL_STYLE)
+	if resizable {
+		style |= _WS_THICKFRAME | _WS_MAXIMIZEBOX
+	} else {
+		style &^= _WS_THICKFRAME | _WS_MAXIMIZEBOX
+	}
+	_SetWindowLong(hwnd, _GWL_STYLE, style)
+	_SetWindowPos(hwnd, 0, 0, 0, 0, 0,
+		_SWP_NOMOVE|_SWP_NOSIZE|_SWP_NOZORDER|_SWP_NOACTIVATE|_SWP_FRAMECHANGED)
+}
+
+func sendMinMaxInfo(hwnd syscall.Handle, uMsg uint32, wParam, lParam uintptr) (lResult uintptr) {
+	windowStates.Lock()
+	s := windowStates.m[hwnd]
+	var min, max image.Point
+	if s != nil {
+		min, max = s.minSize, s.maxSize
+	}
+	windowStates.Unlock()
+
+	mmi := (*_MINMAXINFO)(unsafe.Pointer(lParam))
+	if min.X > 0 || min.Y > 0 {
+		r := windowRect(hwnd, image.Rectangle{Max: min})
+		if min.X > 0 {
+			mmi.PtMinTrackSize.X = r.Right - r.Left
+		}
+		if min.Y > 0 {
+			mmi.PtMinTrackSize.Y = r.Bottom - r.Top
+		}
+	}
+	if max.X > 0 || max.Y > 0 {
+		r := windowRect(hwnd, image.Rectangle{Max: max})
+		if max.X > 0 {
+			mmi.PtMaxTrackSize.X = r.Right - r.Left
+		}
+		if max.Y > 0 {
+			mmi.PtMaxTrackSize.Y = r.Bottom - r.Top
+		}
+	}
+	return 0
+}
+
 func sendFocus(hwnd syscall.Handle, uMsg uint32, wParam, lParam uintptr) (lResult uintptr) {
 	switch uMsg {
 	case _WM_SETFOCUS:
@@ -229,6 +432,31 @@ func sendMouseEvent(hwnd syscall.Handle, uMsg uint32, wParam, lParam uintptr) (l
 	return 0
 }
 
+// sendDropFiles sends a dnd.TypeDrop event for files dropped on the window.
+//
+// TODO: implement the OLE IDropTarget interface, so that we can also send
+// dnd.TypeEnter, TypeMove and TypeLeave events, and accept dropped text.
+func sendDropFiles(hwnd syscall.Handle, uMsg uint32, wParam, lParam uintptr) (lResult uintptr) {
+	hDrop := syscall.Handle(wParam)
+	defer _DragFinish(hDrop)
+
+	var p _POINT
+	_DragQueryPoint(hDrop, &p)
+	e := dnd.Event{
+		Type: dnd.TypeDrop,
+		X:    float32(p.X),
+		Y:    float32(p.Y),
+	}
+	n := _DragQueryFile(hDrop, 0xffffffff, nil, 0)
+	for i := uint32(0); i < n; i++ {
+		buf := make([]uint16, _DragQueryFile(hDrop, i, nil, 0)+1)
+		_DragQueryFile(hDrop, i, &buf[0], uint32(len(buf)))
+		e.URIs = append(e.URIs, dnd.FileURI(syscall.UTF16ToString(buf)))
+	}
+	DndEvent(hwnd, e)
+	return 0
+}
+
 // Precondition: this is called in immediate response to the message that triggered the event (so not after w.Send).
 func keyModifiers() (m key.Modifiers) {
 	down := func(x int32) bool {
@@ -256,6 +484,7 @@ var (
 	PaintEvent     func(hwnd syscall.Handle, e paint.Event)
 	SizeEvent      func(hwnd syscall.Handle, e size.Event)
 	KeyEvent       func(hwnd syscall.Handle, e key.Event)
+	DndEvent       func(hwnd syscall.Handle, e dnd.Event)
 	LifecycleEvent func(hwnd syscall.Handle, e lifecycle.Stage)
 
 	// TODO: use the golang.org/x/exp/shiny/driver/internal/lifecycler package
@@ -311,6 +540,7 @@ var windowMsgs = map[uint32]func(hwnd syscall.Handle, uMsg uint32, wParam, lPara
 	msgShow:              sendShow,
 	_WM_WINDOWPOSCHANGED: sendSizeEvent,
 	_WM_CLOSE:            sendClose,
+	_WM_GETMINMAXINFO:    sendMinMaxInfo,
 
 	_WM_LBUTTONDOWN: sendMouseEvent,
 	_WM_LBUTTONUP:   sendMouseEvent,
@@ -321,6 +551,8 @@ var windowMsgs = map[uint32]func(hwnd syscall.Handle, uMsg uint32, wParam, lPara
 	_WM_MOUSEMOVE:   sendMouseEvent,
 	_WM_MOUSEWHEEL:  sendMouseEvent,
 
+	_WM_DROPFILES: sendDropFiles,
+
 	_WM_KEYDOWN: sendKeyEvent,
 	_WM_KEYUP:   sendKeyEvent,
 	// TODO case _WM_SYSKEYDOWN, _WM_SYSKEYUP:
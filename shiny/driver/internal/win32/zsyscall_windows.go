@@ -37,29 +37,41 @@ func errnoErr(e syscall.Errno) error {
 }
 
 var (
-	moduser32 = windows.NewLazySystemDLL("user32.dll")
-
-	procGetDC             = moduser32.NewProc("GetDC")
-	procReleaseDC         = moduser32.NewProc("ReleaseDC")
-	procSendMessageW      = moduser32.NewProc("SendMessageW")
-	procCreateWindowExW   = moduser32.NewProc("CreateWindowExW")
-	procDefWindowProcW    = moduser32.NewProc("DefWindowProcW")
-	procDestroyWindow     = moduser32.NewProc("DestroyWindow")
-	procDispatchMessageW  = moduser32.NewProc("DispatchMessageW")
-	procGetClientRect     = moduser32.NewProc("GetClientRect")
-	procGetKeyboardLayout = moduser32.NewProc("GetKeyboardLayout")
-	procGetKeyboardState  = moduser32.NewProc("GetKeyboardState")
-	procGetKeyState       = moduser32.NewProc("GetKeyState")
-	procGetMessageW       = moduser32.NewProc("GetMessageW")
-	procLoadCursorW       = moduser32.NewProc("LoadCursorW")
-	procLoadIconW         = moduser32.NewProc("LoadIconW")
-	procPostMessageW      = moduser32.NewProc("PostMessageW")
-	procPostQuitMessage   = moduser32.NewProc("PostQuitMessage")
-	procRegisterClassW    = moduser32.NewProc("RegisterClassW")
-	procShowWindow        = moduser32.NewProc("ShowWindow")
-	procScreenToClient    = moduser32.NewProc("ScreenToClient")
-	procToUnicodeEx       = moduser32.NewProc("ToUnicodeEx")
-	procTranslateMessage  = moduser32.NewProc("TranslateMessage")
+	moduser32  = windows.NewLazySystemDLL("user32.dll")
+	modshell32 = windows.NewLazySystemDLL("shell32.dll")
+
+	procGetDC              = moduser32.NewProc("GetDC")
+	procReleaseDC          = moduser32.NewProc("ReleaseDC")
+	procSendMessageW       = moduser32.NewProc("SendMessageW")
+	procAdjustWindowRectEx = moduser32.NewProc("AdjustWindowRectEx")
+	procCreateWindowExW    = moduser32.NewProc("CreateWindowExW")
+	procDefWindowProcW     = moduser32.NewProc("DefWindowProcW")
+	procDestroyWindow      = moduser32.NewProc("DestroyWindow")
+	procDispatchMessageW   = moduser32.NewProc("DispatchMessageW")
+	procGetClientRect      = moduser32.NewProc("GetClientRect")
+	procGetKeyboardLayout  = moduser32.NewProc("GetKeyboardLayout")
+	procGetKeyboardState   = moduser32.NewProc("GetKeyboardState")
+	procGetKeyState        = moduser32.NewProc("GetKeyState")
+	procGetMonitorInfoW    = moduser32.NewProc("GetMonitorInfoW")
+	procGetWindowLongW     = moduser32.NewProc("GetWindowLongW")
+	procGetWindowRect      = moduser32.NewProc("GetWindowRect")
+	procGetMessageW        = moduser32.NewProc("GetMessageW")
+	procLoadCursorW        = moduser32.NewProc("LoadCursorW")
+	procLoadIconW          = moduser32.NewProc("LoadIconW")
+	procMonitorFromWindow  = moduser32.NewProc("MonitorFromWindow")
+	procPostMessageW       = moduser32.NewProc("PostMessageW")
+	procPostQuitMessage    = moduser32.NewProc("PostQuitMessage")
+	procRegisterClassW     = moduser32.NewProc("RegisterClassW")
+	procSetWindowLongW     = moduser32.NewProc("SetWindowLongW")
+	procSetWindowPos       = moduser32.NewProc("SetWindowPos")
+	procShowWindow         = moduser32.NewProc("ShowWindow")
+	procScreenToClient     = moduser32.NewProc("ScreenToClient")
+	procToUnicodeEx        = moduser32.NewProc("ToUnicodeEx")
+	procTranslateMessage   = moduser32.NewProc("TranslateMessage")
+	procDragAcceptFiles    = modshell32.NewProc("DragAcceptFiles")
+	procDragFinish         = modshell32.NewProc("DragFinish")
+	procDragQueryFileW     = modshell32.NewProc("DragQueryFileW")
+	procDragQueryPoint     = modshell32.NewProc("DragQueryPoint")
 )
 
 func GetDC(hwnd syscall.Handle) (dc syscall.Handle, err error) {
@@ -93,6 +105,24 @@ func sendMessage(hwnd syscall.Handle, uMsg uint32, wParam uintptr, lParam uintpt
 	return
 }
 
+func _AdjustWindowRectEx(rect *_RECT, style uint32, menu bool, exStyle uint32) (err error) {
+	var _p0 uint32
+	if menu {
+		_p0 = 1
+	} else {
+		_p0 = 0
+	}
+	r1, _, e1 := syscall.Syscall6(procAdjustWindowRectEx.Addr(), 4, uintptr(unsafe.Pointer(rect)), uintptr(style), uintptr(_p0), uintptr(exStyle), 0, 0)
+	if r1 == 0 {
+		if e1 != 0 {
+			err = errnoErr(e1)
+		} else {
+			err = syscall.EINVAL
+		}
+	}
+	return
+}
+
 func _CreateWindowEx(exstyle uint32, className *uint16, windowText *uint16, style uint32, x int32, y int32, width int32, height int32, parent syscall.Handle, menu syscall.Handle, hInstance syscall.Handle, lpParam uintptr) (hwnd syscall.Handle, err error) {
 	r0, _, e1 := syscall.Syscall12(procCreateWindowExW.Addr(), 12, uintptr(exstyle), uintptr(unsafe.Pointer(className)), uintptr(unsafe.Pointer(windowText)), uintptr(style), uintptr(x), uintptr(y), uintptr(width), uintptr(height), uintptr(parent), uintptr(menu), uintptr(hInstance), uintptr(lpParam))
 	hwnd = syscall.Handle(r0)
@@ -166,6 +196,36 @@ func _GetKeyState(virtkey int32) (keystatus int16) {
 	return
 }
 
+func _GetMonitorInfo(monitor syscall.Handle, mi *_MONITORINFO) (err error) {
+	r1, _, e1 := syscall.Syscall(procGetMonitorInfoW.Addr(), 2, uintptr(monitor), uintptr(unsafe.Pointer(mi)), 0)
+	if r1 == 0 {
+		if e1 != 0 {
+			err = errnoErr(e1)
+		} else {
+			err = syscall.EINVAL
+		}
+	}
+	return
+}
+
+func _GetWindowLong(hwnd syscall.Handle, index int32) (long int32) {
+	r0, _, _ := syscall.Syscall(procGetWindowLongW.Addr(), 2, uintptr(hwnd), uintptr(index), 0)
+	long = int32(r0)
+	return
+}
+
+func _GetWindowRect(hwnd syscall.Handle, rect *_RECT) (err error) {
+	r1, _, e1 := syscall.Syscall(procGetWindowRect.Addr(), 2, uintptr(hwnd), uintptr(unsafe.Pointer(rect)), 0)
+	if r1 == 0 {
+		if e1 != 0 {
+			err = errnoErr(e1)
+		} else {
+			err = syscall.EINVAL
+		}
+	}
+	return
+}
+
 func _GetMessage(msg *_MSG, hwnd syscall.Handle, msgfiltermin uint32, msgfiltermax uint32) (ret int32, err error) {
 	r0, _, e1 := syscall.Syscall6(procGetMessageW.Addr(), 4, uintptr(unsafe.Pointer(msg)), uintptr(hwnd), uintptr(msgfiltermin), uintptr(msgfiltermax), 0, 0)
 	ret = int32(r0)
@@ -205,6 +265,12 @@ func _LoadIcon(hInstance syscall.Handle, iconName uintptr) (icon syscall.Handle,
 	return
 }
 
+func _MonitorFromWindow(hwnd syscall.Handle, flags uint32) (monitor syscall.Handle) {
+	r0, _, _ := syscall.Syscall(procMonitorFromWindow.Addr(), 2, uintptr(hwnd), uintptr(flags), 0)
+	monitor = syscall.Handle(r0)
+	return
+}
+
 func _PostMessage(hwnd syscall.Handle, uMsg uint32, wParam uintptr, lParam uintptr) (lResult bool) {
 	r0, _, _ := syscall.Syscall6(procPostMessageW.Addr(), 4, uintptr(hwnd), uintptr(uMsg), uintptr(wParam), uintptr(lParam), 0, 0)
 	lResult = r0 != 0
@@ -229,6 +295,24 @@ func _RegisterClass(wc *_WNDCLASS) (atom uint16, err error) {
 	return
 }
 
+func _SetWindowLong(hwnd syscall.Handle, index int32, newLong int32) (oldLong int32) {
+	r0, _, _ := syscall.Syscall(procSetWindowLongW.Addr(), 3, uintptr(hwnd), uintptr(index), uintptr(newLong))
+	oldLong = int32(r0)
+	return
+}
+
+func _SetWindowPos(hwnd syscall.Handle, hwndInsertAfter syscall.Handle, x int32, y int32, cx int32, cy int32, flags uint32) (err error) {
+	r1, _, e1 := syscall.Syscall9(procSetWindowPos.Addr(), 7, uintptr(hwnd), uintptr(hwndInsertAfter), uintptr(x), uintptr(y), uintptr(cx), uintptr(cy), uintptr(flags), 0, 0)
+	if r1 == 0 {
+		if e1 != 0 {
+			err = errnoErr(e1)
+		} else {
+			err = syscall.EINVAL
+		}
+	}
+	return
+}
+
 func _ShowWindow(hwnd syscall.Handle, cmdshow int32) (wasvisible bool) {
 	r0, _, _ := syscall.Syscall(procShowWindow.Addr(), 2, uintptr(hwnd), uintptr(cmdshow), 0)
 	wasvisible = r0 != 0
@@ -252,3 +336,31 @@ func _TranslateMessage(msg *_MSG) (done bool) {
 	done = r0 != 0
 	return
 }
+
+func _DragAcceptFiles(hwnd syscall.Handle, accept bool) {
+	var _p0 uint32
+	if accept {
+		_p0 = 1
+	} else {
+		_p0 = 0
+	}
+	syscall.Syscall(procDragAcceptFiles.Addr(), 2, uintptr(hwnd), uintptr(_p0), 0)
+	return
+}
+
+func _DragFinish(hDrop syscall.Handle) {
+	syscall.Syscall(procDragFinish.Addr(), 1, uintptr(hDrop), 0, 0)
+	return
+}
+
+func _DragQueryFile(hDrop syscall.Handle, iFile uint32, lpszFile *uint16, cch uint32) (n uint32) {
+	r0, _, _ := syscall.Syscall6(procDragQueryFileW.Addr(), 4, uintptr(hDrop), uintptr(iFile), uintptr(unsafe.Pointer(lpszFile)), uintptr(cch), 0, 0)
+	n = uint32(r0)
+	return
+}
+
+func _DragQueryPoint(hDrop syscall.Handle, lppt *_POINT) (inClient bool) {
+	r0, _, _ := syscall.Syscall(procDragQueryPoint.Addr(), 2, uintptr(hDrop), uintptr(unsafe.Pointer(lppt)), 0)
+	inClient = r0 != 0
+	return
+}
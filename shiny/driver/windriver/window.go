@@ -17,8 +17,10 @@ import (
 	"syscall"
 	"unsafe"
 
+	"golang.org/x/exp/shiny/dnd"
 	"golang.org/x/exp/shiny/driver/internal/drawer"
 	"golang.org/x/exp/shiny/driver/internal/event"
+	"golang.org/x/exp/shiny/driver/internal/frameclock"
 	"golang.org/x/exp/shiny/driver/internal/win32"
 	"golang.org/x/exp/shiny/screen"
 	"golang.org/x/image/math/f64"
@@ -34,6 +36,10 @@ type windowImpl struct {
 
 	event.Deque
 
+	// TODO: synchronize frameClock with the display, e.g. via DwmFlush or
+	// the monitor's refresh rate from EnumDisplaySettings.
+	frameClock frameclock.Clock
+
 	sz             size.Event
 	lifecycleStage lifecycle.Stage
 }
@@ -91,6 +97,20 @@ func (w *windowImpl) DrawUniform(src2dst f64.Aff3, src color.Color, sr image.Rec
 	})
 }
 
+func (w *windowImpl) Resize(size image.Point) { win32.Resize(w.hwnd, size) }
+
+func (w *windowImpl) Move(p image.Point) { win32.Move(w.hwnd, p) }
+
+func (w *windowImpl) SetFullscreen(fullscreen bool) { win32.SetFullscreen(w.hwnd, fullscreen) }
+
+func (w *windowImpl) Minimize() { win32.Minimize(w.hwnd) }
+
+func (w *windowImpl) SetAlwaysOnTop(alwaysOnTop bool) { win32.SetAlwaysOnTop(w.hwnd, alwaysOnTop) }
+
+func (w *windowImpl) SetSizeLimits(min, max image.Point) { win32.SetSizeLimits(w.hwnd, min, max) }
+
+func (w *windowImpl) SetResizable(resizable bool) { win32.SetResizable(w.hwnd, resizable) }
+
 func drawWindow(dc syscall.Handle, src2dst f64.Aff3, src interface{}, sr image.Rectangle, op draw.Op) (retErr error) {
 	var dr image.Rectangle
 	if src2dst[1] != 0 || src2dst[3] != 0 {
@@ -166,6 +186,10 @@ func (w *windowImpl) Scale(dr image.Rectangle, src screen.Texture, sr image.Rect
 	drawer.Scale(w, dr, src, sr, op, opts)
 }
 
+func (w *windowImpl) RequestFrame() {
+	w.frameClock.Request(w)
+}
+
 func (w *windowImpl) Publish() screen.PublishResult {
 	// TODO
 	return screen.PublishResult{}
@@ -182,6 +206,7 @@ func init() {
 	win32.MouseEvent = func(hwnd syscall.Handle, e mouse.Event) { send(hwnd, e) }
 	win32.PaintEvent = func(hwnd syscall.Handle, e paint.Event) { send(hwnd, e) }
 	win32.KeyEvent = func(hwnd syscall.Handle, e key.Event) { send(hwnd, e) }
+	win32.DndEvent = func(hwnd syscall.Handle, e dnd.Event) { send(hwnd, e) }
 	win32.LifecycleEvent = lifecycleEvent
 	win32.SizeEvent = sizeEvent
 }
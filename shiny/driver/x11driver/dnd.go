@@ -0,0 +1,194 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package x11driver
+
+// This file implements the target side of the XDND protocol, as specified at
+// https://www.freedesktop.org/wiki/Specifications/XDND/
+
+import (
+	"github.com/BurntSushi/xgb"
+	"github.com/BurntSushi/xgb/xproto"
+
+	"golang.org/x/exp/shiny/dnd"
+)
+
+// TODO: handle the INCR mechanism for dropped data that is too large to
+// transfer in a single property.
+
+// xdndVersion is the XDND protocol version that we implement.
+const xdndVersion = 5
+
+// xdndMaxLen is the maximum length, in 4-byte units, of a property that we
+// read when handling XDND messages.
+const xdndMaxLen = 1 << 20
+
+// handleXdnd handles an XDND client message. It returns whether the message's
+// window was found.
+func (s *screenImpl) handleXdnd(ev xproto.ClientMessageEvent) (found bool) {
+	w := s.findWindow(ev.Window)
+	if w == nil {
+		return false
+	}
+	d := ev.Data.Data32
+	switch ev.Type {
+	case s.atomXdndEnter:
+		w.handleXdndEnter(d)
+	case s.atomXdndPosition:
+		w.handleXdndPosition(d)
+	case s.atomXdndLeave:
+		w.handleXdndLeave(d)
+	case s.atomXdndDrop:
+		w.handleXdndDrop(d)
+	}
+	return true
+}
+
+func (w *windowImpl) handleXdndEnter(d []uint32) {
+	w.dndSource = xproto.Window(d[0])
+	w.dndVersion = d[1] >> 24
+	w.dndEntered = false
+
+	types := []xproto.Atom{xproto.Atom(d[2]), xproto.Atom(d[3]), xproto.Atom(d[4])}
+	if d[1]&1 != 0 {
+		// The source offers more than three types, listed in a property.
+		r, err := xproto.GetProperty(w.s.xc, false, w.dndSource,
+			w.s.atomXdndTypeList, xproto.AtomAtom, 0, xdndMaxLen).Reply()
+		if err == nil && r != nil && r.Format == 32 {
+			types = types[:0]
+			for b := r.Value; len(b) >= 4; b = b[4:] {
+				types = append(types, xproto.Atom(xgb.Get32(b)))
+			}
+		}
+	}
+
+	// Choose the type to ask for, in decreasing order of preference.
+	w.dndTarget = 0
+	for _, want := range [...]xproto.Atom{
+		w.s.atomTextURIList,
+		w.s.atomUTF8String,
+		w.s.atomTextPlainUTF8,
+		w.s.atomTextPlain,
+	} {
+		for _, t := range types {
+			if t == want {
+				w.dndTarget = want
+				break
+			}
+		}
+		if w.dndTarget != 0 {
+			break
+		}
+	}
+}
+
+func (w *windowImpl) handleXdndPosition(d []uint32) {
+	if xproto.Window(d[0]) != w.dndSource {
+		return
+	}
+	rootX, rootY := int16(d[2]>>16), int16(d[2])
+	if r, err := xproto.TranslateCoordinates(w.s.xc, w.s.xsi.Root, w.xw, rootX, rootY).Reply(); err == nil {
+		w.dndX, w.dndY = float32(r.DstX), float32(r.DstY)
+	}
+
+	// Setting bit 1 of the second word asks the source to keep sending
+	// position messages, even within the same rectangle, and the rectangle in
+	// the third and fourth words is empty.
+	accept, action := uint32(0), uint32(0)
+	if w.dndTarget != 0 {
+		accept, action = 1, uint32(w.s.atomXdndActionCopy)
+	}
+	w.s.sendClientMessage(w.dndSource, xproto.EventMaskNoEvent, w.dndSource, w.s.atomXdndStatus,
+		uint32(w.xw), accept|2, 0, 0, action)
+
+	t := dnd.TypeMove
+	if !w.dndEntered {
+		w.dndEntered = true
+		t = dnd.TypeEnter
+	}
+	w.Send(dnd.Event{
+		Type: t,
+		X:    w.dndX,
+		Y:    w.dndY,
+	})
+}
+
+func (w *windowImpl) handleXdndLeave(d []uint32) {
+	if xproto.Window(d[0]) != w.dndSource {
+		return
+	}
+	w.endXdnd()
+}
+
+func (w *windowImpl) handleXdndDrop(d []uint32) {
+	if xproto.Window(d[0]) != w.dndSource {
+		return
+	}
+	if w.dndTarget == 0 {
+		w.finishXdnd(false)
+		return
+	}
+	t := xproto.Timestamp(xproto.TimeCurrentTime)
+	if w.dndVersion >= 1 {
+		t = xproto.Timestamp(d[2])
+	}
+	// The dropped data arrives later, via a SelectionNotifyEvent.
+	xproto.ConvertSelection(w.s.xc, w.xw, w.s.atomXdndSelection, w.dndTarget, w.s.atomXdndSelection, t)
+}
+
+func (w *windowImpl) handleSelectionNotify(ev xproto.SelectionNotifyEvent) {
+	if ev.Selection != w.s.atomXdndSelection || w.dndSource == 0 {
+		return
+	}
+	if ev.Property == xproto.AtomNone {
+		// The source could not convert the data to the requested type.
+		w.finishXdnd(false)
+		return
+	}
+	r, err := xproto.GetProperty(w.s.xc, true, w.xw, ev.Property,
+		xproto.GetPropertyTypeAny, 0, xdndMaxLen).Reply()
+	if err != nil || r == nil {
+		w.finishXdnd(false)
+		return
+	}
+
+	e := dnd.Event{
+		Type: dnd.TypeDrop,
+		X:    w.dndX,
+		Y:    w.dndY,
+	}
+	if w.dndTarget == w.s.atomTextURIList {
+		e.URIs = dnd.ParseURIList(string(r.Value))
+	} else {
+		e.Text = string(r.Value)
+	}
+	w.Send(e)
+	w.finishXdnd(true)
+}
+
+// finishXdnd tells the source that the drop is complete. If the drop was not
+// successful, the window is sent a TypeLeave event.
+func (w *windowImpl) finishXdnd(success bool) {
+	accepted, action := uint32(0), uint32(0)
+	if success {
+		accepted, action = 1, uint32(w.s.atomXdndActionCopy)
+	}
+	if w.dndVersion >= 2 {
+		w.s.sendClientMessage(w.dndSource, xproto.EventMaskNoEvent, w.dndSource, w.s.atomXdndFinished,
+			uint32(w.xw), accepted, action)
+	}
+	if success {
+		w.dndSource, w.dndEntered = 0, false
+		return
+	}
+	w.endXdnd()
+}
+
+// endXdnd forgets the current drag, sending a TypeLeave event if necessary.
+func (w *windowImpl) endXdnd() {
+	if w.dndEntered {
+		w.Send(dnd.Event{Type: dnd.TypeLeave})
+	}
+	w.dndSource, w.dndEntered = 0, false
+}
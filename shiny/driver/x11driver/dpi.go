@@ -0,0 +1,211 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package x11driver
+
+import (
+	"bufio"
+	"bytes"
+	"image"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/xgb/randr"
+	"github.com/BurntSushi/xgb/xproto"
+)
+
+const (
+	mmPerInch = 25.4
+	ptPerInch = 72
+)
+
+// monitor is a RandR CRTC that is showing (part of) the root window, i.e. a
+// physical monitor.
+type monitor struct {
+	bounds      image.Rectangle
+	pixelsPerPt float32
+
+	// refreshInterval is the monitor's refresh period, or zero if unknown.
+	refreshInterval time.Duration
+}
+
+// initScale determines the default pixelsPerPt, and, if the RandR extension
+// is available, the per-monitor pixelsPerPt values.
+//
+// In decreasing order of precedence, a monitor's scale comes from:
+//   - the monitor's physical size, as reported by RandR,
+//   - the Xft.dpi X resource, as set by e.g. xrdb or a desktop environment,
+//   - the root window's physical size, as reported by the core protocol.
+//
+// The latter two also give the scale for windows that aren't on any monitor.
+// Xft.dpi is a single, global value, often set to 96 regardless of the
+// hardware, so it would give every monitor the same scale. The GDK_SCALE
+// environment variable, if set, multiplies all of those scales.
+func (s *screenImpl) initScale(useRandR bool) {
+	pixelsPerMM := float32(s.xsi.WidthInPixels) / float32(s.xsi.WidthInMillimeters)
+	s.pixelsPerPt = pixelsPerMM * mmPerInch / ptPerInch
+	if dpi := s.xftDPI(); dpi > 0 {
+		s.pixelsPerPt = dpi / ptPerInch
+	}
+	s.scale = envScale("GDK_SCALE")
+
+	if !useRandR {
+		return
+	}
+	s.randr = true
+	randr.SelectInput(s.xc, s.xsi.Root, randr.NotifyMaskScreenChange|randr.NotifyMaskCrtcChange)
+	s.updateMonitors()
+}
+
+// xftDPI returns the Xft.dpi value from the root window's RESOURCE_MANAGER
+// property, or zero if there is no such value.
+func (s *screenImpl) xftDPI() float32 {
+	// The RESOURCE_MANAGER property can be large, but it's unlikely to be
+	// more than 64 KiB.
+	const maxLen = 1 << 14 // In 4-byte units.
+	r, err := xproto.GetProperty(s.xc, false, s.xsi.Root,
+		xproto.AtomResourceManager, xproto.AtomString, 0, maxLen).Reply()
+	if err != nil || r == nil {
+		return 0
+	}
+	return parseXftDPI(r.Value)
+}
+
+// parseXftDPI parses the Xft.dpi value out of an X resource database string,
+// such as the output of "xrdb -query".
+func parseXftDPI(b []byte) float32 {
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	for sc.Scan() {
+		line := sc.Text()
+		i := strings.IndexByte(line, ':')
+		if i < 0 || strings.TrimSpace(line[:i]) != "Xft.dpi" {
+			continue
+		}
+		dpi, err := strconv.ParseFloat(strings.TrimSpace(line[i+1:]), 32)
+		if err != nil || dpi <= 0 {
+			return 0
+		}
+		return float32(dpi)
+	}
+	return 0
+}
+
+// envScale returns the positive scale factor held in the named environment
+// variable, or 1.
+func envScale(name string) float32 {
+	f, err := strconv.ParseFloat(os.Getenv(name), 32)
+	if err != nil || f <= 0 {
+		return 1
+	}
+	return float32(f)
+}
+
+// updateMonitors re-reads the RandR monitor configuration. It is called at
+// start up and whenever the RandR configuration changes.
+func (s *screenImpl) updateMonitors() {
+	res, err := randr.GetScreenResourcesCurrent(s.xc, s.xsi.Root).Reply()
+	if err != nil {
+		log.Printf("x11driver: randr.GetScreenResourcesCurrent failed: %v", err)
+		return
+	}
+	monitors := []monitor(nil)
+	for _, crtc := range res.Crtcs {
+		ci, err := randr.GetCrtcInfo(s.xc, crtc, res.ConfigTimestamp).Reply()
+		if err != nil || ci.Mode == 0 || len(ci.Outputs) == 0 {
+			continue
+		}
+		m := monitor{
+			bounds:          image.Rect(int(ci.X), int(ci.Y), int(ci.X)+int(ci.Width), int(ci.Y)+int(ci.Height)),
+			pixelsPerPt:     s.pixelsPerPt,
+			refreshInterval: refreshInterval(res.Modes, ci.Mode),
+		}
+		// TODO: a CRTC can drive more than one output (i.e. cloned displays).
+		// We use the first one's physical size.
+		if oi, err := randr.GetOutputInfo(s.xc, ci.Outputs[0], res.ConfigTimestamp).Reply(); err == nil {
+			mmWidth := oi.MmWidth
+			if ci.Rotation&(randr.RotationRotate90|randr.RotationRotate270) != 0 {
+				mmWidth = oi.MmHeight
+			}
+			m.pixelsPerPt = physicalPixelsPerPt(int(ci.Width), int(mmWidth), s.pixelsPerPt)
+		}
+		monitors = append(monitors, m)
+	}
+
+	s.monitorsMu.Lock()
+	s.monitors = monitors
+	s.monitorsMu.Unlock()
+}
+
+// physicalPixelsPerPt returns the scale of a monitor that is width pixels and
+// mmWidth millimeters wide, or fallback if its physical size is unknown. Some
+// outputs, such as projectors, report a zero physical size.
+func physicalPixelsPerPt(width, mmWidth int, fallback float32) float32 {
+	if width <= 0 || mmWidth <= 0 {
+		return fallback
+	}
+	return float32(width) / float32(mmWidth) * mmPerInch / ptPerInch
+}
+
+// refreshInterval returns the refresh period of the mode with the given ID, or
+// zero if unknown.
+func refreshInterval(modes []randr.ModeInfo, id randr.Mode) time.Duration {
+	for _, m := range modes {
+		if m.Id != uint32(id) {
+			continue
+		}
+		vtotal := uint64(m.Vtotal)
+		if m.ModeFlags&randr.ModeFlagDoubleScan != 0 {
+			vtotal *= 2
+		}
+		if m.ModeFlags&randr.ModeFlagInterlace != 0 {
+			vtotal /= 2
+		}
+		if m.DotClock == 0 || m.Htotal == 0 || vtotal == 0 {
+			return 0
+		}
+		return time.Duration(uint64(time.Second) * uint64(m.Htotal) * vtotal / uint64(m.DotClock))
+	}
+	return 0
+}
+
+// monitorAt returns the monitor for a window whose bounds, in root window
+// coordinates, is r. When a window straddles two or more monitors, the one
+// that shows the largest part of the window wins. If no monitor shows any of
+// the window, the returned monitor has the default pixelsPerPt.
+//
+// The returned pixelsPerPt does not include the user-requested scale.
+func (s *screenImpl) monitorAt(r image.Rectangle) monitor {
+	ret, bestArea := monitor{pixelsPerPt: s.pixelsPerPt}, 0
+
+	s.monitorsMu.Lock()
+	for _, m := range s.monitors {
+		i := m.bounds.Intersect(r)
+		if area := i.Dx() * i.Dy(); area > bestArea {
+			ret, bestArea = m, area
+		}
+	}
+	s.monitorsMu.Unlock()
+
+	return ret
+}
+
+// handleScreenChange is called when the RandR configuration changes, e.g. a
+// monitor was plugged in or its resolution changed.
+func (s *screenImpl) handleScreenChange() {
+	s.updateMonitors()
+
+	s.mu.Lock()
+	windows := make([]*windowImpl, 0, len(s.windows))
+	for _, w := range s.windows {
+		windows = append(windows, w)
+	}
+	s.mu.Unlock()
+
+	for _, w := range windows {
+		w.updateSize(w.width, w.height)
+	}
+}
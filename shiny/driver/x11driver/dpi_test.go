@@ -0,0 +1,114 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package x11driver
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/xgb/randr"
+)
+
+func TestParseXftDPI(t *testing.T) {
+	testCases := []struct {
+		s    string
+		want float32
+	}{
+		{"", 0},
+		{"Xft.dpi:\t96\n", 96},
+		{"Xft.antialias:\t1\nXft.dpi:\t192\nXft.hinting:\t1\n", 192},
+		{"Xft.dpi: 144.5", 144.5},
+		{"  Xft.dpi  :  120  \n", 120},
+		{"Xft.dpi:\tbig\n", 0},
+		{"Xft.dpi:\t-96\n", 0},
+		{"Xft.dpix:\t96\nXcursor.size:\t24\n", 0},
+		{"no colon\n", 0},
+	}
+	for _, tc := range testCases {
+		if got := parseXftDPI([]byte(tc.s)); got != tc.want {
+			t.Errorf("parseXftDPI(%q): got %v, want %v", tc.s, got, tc.want)
+		}
+	}
+}
+
+func TestEnvScale(t *testing.T) {
+	const name = "SHINY_X11DRIVER_TEST_SCALE"
+	defer os.Unsetenv(name)
+
+	testCases := []struct {
+		value string
+		want  float32
+	}{
+		{"", 1},
+		{"2", 2},
+		{"1.5", 1.5},
+		{"0", 1},
+		{"-2", 1},
+		{"two", 1},
+	}
+	for _, tc := range testCases {
+		os.Setenv(name, tc.value)
+		if got := envScale(name); got != tc.want {
+			t.Errorf("%q: got %v, want %v", tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestPhysicalPixelsPerPt(t *testing.T) {
+	testCases := []struct {
+		width, mmWidth int
+		want           float32
+	}{
+		// A 4K monitor, 600mm wide, is about 162 DPI.
+		{3840, 600, 3840.0 / 600 * mmPerInch / ptPerInch},
+		// A 1080p monitor, 508mm wide, is 96 DPI.
+		{1920, 508, 96.0 / ptPerInch},
+		// An unknown physical size falls back to the global scale.
+		{1920, 0, 1.5},
+		{0, 0, 1.5},
+	}
+	for _, tc := range testCases {
+		if got := physicalPixelsPerPt(tc.width, tc.mmWidth, 1.5); got != tc.want {
+			t.Errorf("%d pixels, %dmm: got %v, want %v", tc.width, tc.mmWidth, got, tc.want)
+		}
+	}
+}
+
+func TestRefreshInterval(t *testing.T) {
+	// The modes are 1920x1080 at 60Hz, with various flags.
+	mode := func(id, flags uint32, dotClock uint32) randr.ModeInfo {
+		return randr.ModeInfo{
+			Id:        id,
+			DotClock:  dotClock,
+			Htotal:    2200,
+			Vtotal:    1125,
+			ModeFlags: flags,
+		}
+	}
+	modes := []randr.ModeInfo{
+		mode(1, 0, 148500000),
+		mode(2, randr.ModeFlagInterlace, 148500000),
+		mode(3, randr.ModeFlagDoubleScan, 148500000),
+		mode(4, 0, 0),
+	}
+	testCases := []struct {
+		id   randr.Mode
+		want time.Duration
+	}{
+		{1, 16666666 * time.Nanosecond},
+		// An interlaced mode's fields are half of its odd total height,
+		// rounded down.
+		{2, 8325925 * time.Nanosecond},
+		{3, 33333333 * time.Nanosecond},
+		{4, 0},
+		{5, 0},
+	}
+	for _, tc := range testCases {
+		if got := refreshInterval(modes, tc.id); got != tc.want {
+			t.Errorf("mode %d: got %v, want %v", tc.id, got, tc.want)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"sync"
 
 	"github.com/BurntSushi/xgb"
+	"github.com/BurntSushi/xgb/randr"
 	"github.com/BurntSushi/xgb/render"
 	"github.com/BurntSushi/xgb/shm"
 	"github.com/BurntSushi/xgb/xproto"
@@ -33,11 +34,41 @@ type screenImpl struct {
 	xsi     *xproto.ScreenInfo
 	keysyms x11key.KeysymTable
 
-	atomWMDeleteWindow xproto.Atom
-	atomWMProtocols    xproto.Atom
-	atomWMTakeFocus    xproto.Atom
+	atomNETWMState           xproto.Atom
+	atomNETWMStateAbove      xproto.Atom
+	atomNETWMStateFullscreen xproto.Atom
+	atomWMChangeState        xproto.Atom
+	atomWMDeleteWindow       xproto.Atom
+	atomWMProtocols          xproto.Atom
+	atomWMTakeFocus          xproto.Atom
+
+	atomTextPlain      xproto.Atom
+	atomTextPlainUTF8  xproto.Atom
+	atomTextURIList    xproto.Atom
+	atomUTF8String     xproto.Atom
+	atomXdndActionCopy xproto.Atom
+	atomXdndAware      xproto.Atom
+	atomXdndDrop       xproto.Atom
+	atomXdndEnter      xproto.Atom
+	atomXdndFinished   xproto.Atom
+	atomXdndLeave      xproto.Atom
+	atomXdndPosition   xproto.Atom
+	atomXdndSelection  xproto.Atom
+	atomXdndStatus     xproto.Atom
+	atomXdndTypeList   xproto.Atom
+
+	// pixelsPerPt is the scale for windows that aren't on any monitor, and
+	// for monitors whose physical size is unknown. Per-monitor scales are in
+	// the monitors field. scale is the user-requested scale factor, applied
+	// on top of both.
+	pixelsPerPt float32
+	scale       float32
+
+	// randr is whether the RandR extension is available.
+	randr      bool
+	monitorsMu sync.Mutex
+	monitors   []monitor
 
-	pixelsPerPt  float32
 	pictformat24 render.Pictformat
 	pictformat32 render.Pictformat
 
@@ -62,7 +93,7 @@ type screenImpl struct {
 	completionKeys  []uint16
 }
 
-func newScreenImpl(xc *xgb.Conn) (*screenImpl, error) {
+func newScreenImpl(xc *xgb.Conn, useRandR bool) (*screenImpl, error) {
 	s := &screenImpl{
 		xc:      xc,
 		xsi:     xproto.Setup(xc).DefaultScreen(xc),
@@ -76,12 +107,7 @@ func newScreenImpl(xc *xgb.Conn) (*screenImpl, error) {
 	if err := s.initKeyboardMapping(); err != nil {
 		return nil, err
 	}
-	const (
-		mmPerInch = 25.4
-		ptPerInch = 72
-	)
-	pixelsPerMM := float32(s.xsi.WidthInPixels) / float32(s.xsi.WidthInMillimeters)
-	s.pixelsPerPt = pixelsPerMM * mmPerInch / ptPerInch
+	s.initScale(useRandR)
 	if err := s.initPictformats(); err != nil {
 		return nil, err
 	}
@@ -131,20 +157,29 @@ func (s *screenImpl) run() {
 			s.handleCompletions()
 			s.mu.Unlock()
 
+		case randr.ScreenChangeNotifyEvent, randr.NotifyEvent:
+			s.handleScreenChange()
+
 		case xproto.ClientMessageEvent:
-			if ev.Type != s.atomWMProtocols || ev.Format != 32 {
+			if ev.Format != 32 {
 				break
 			}
-			switch xproto.Atom(ev.Data.Data32[0]) {
-			case s.atomWMDeleteWindow:
-				if w := s.findWindow(ev.Window); w != nil {
-					w.lifecycler.SetDead(true)
-					w.lifecycler.SendEvent(w, nil)
-				} else {
-					noWindowFound = true
+			switch ev.Type {
+			case s.atomXdndEnter, s.atomXdndPosition, s.atomXdndLeave, s.atomXdndDrop:
+				noWindowFound = !s.handleXdnd(ev)
+
+			case s.atomWMProtocols:
+				switch xproto.Atom(ev.Data.Data32[0]) {
+				case s.atomWMDeleteWindow:
+					if w := s.findWindow(ev.Window); w != nil {
+						w.lifecycler.SetDead(true)
+						w.lifecycler.SendEvent(w, nil)
+					} else {
+						noWindowFound = true
+					}
+				case s.atomWMTakeFocus:
+					xproto.SetInputFocus(s.xc, xproto.InputFocusParent, ev.Window, xproto.Timestamp(ev.Data.Data32[1]))
 				}
-			case s.atomWMTakeFocus:
-				xproto.SetInputFocus(s.xc, xproto.InputFocusParent, ev.Window, xproto.Timestamp(ev.Data.Data32[1]))
 			}
 
 		case xproto.ConfigureNotifyEvent:
@@ -154,6 +189,13 @@ func (s *screenImpl) run() {
 				noWindowFound = true
 			}
 
+		case xproto.SelectionNotifyEvent:
+			if w := s.findWindow(ev.Requestor); w != nil {
+				w.handleSelectionNotify(ev)
+			} else {
+				noWindowFound = true
+			}
+
 		case xproto.ExposeEvent:
 			if w := s.findWindow(ev.Window); w != nil {
 				// A non-zero Count means that there are more expose events
@@ -392,11 +434,12 @@ func (s *screenImpl) NewWindow(opts *screen.NewWindowOptions) (screen.Window, er
 	}
 
 	w := &windowImpl{
-		s:       s,
-		xw:      xw,
-		xg:      xg,
-		xp:      xp,
-		xevents: make(chan xgb.Event),
+		s:         s,
+		xw:        xw,
+		xg:        xg,
+		xp:        xp,
+		xevents:   make(chan xgb.Event),
+		resizable: true,
 	}
 
 	s.mu.Lock()
@@ -421,6 +464,7 @@ func (s *screenImpl) NewWindow(opts *screen.NewWindowOptions) (screen.Window, er
 		},
 	)
 	s.setProperty(xw, s.atomWMProtocols, s.atomWMDeleteWindow, s.atomWMTakeFocus)
+	s.setProperty(xw, s.atomXdndAware, xdndVersion)
 	xproto.CreateGC(s.xc, xg, xproto.Drawable(xw), 0, nil)
 	render.CreatePicture(s.xc, xp, xproto.Drawable(xw), pictformat, 0, nil)
 	xproto.MapWindow(s.xc, xw)
@@ -429,6 +473,22 @@ func (s *screenImpl) NewWindow(opts *screen.NewWindowOptions) (screen.Window, er
 }
 
 func (s *screenImpl) initAtoms() (err error) {
+	s.atomNETWMState, err = s.internAtom("_NET_WM_STATE")
+	if err != nil {
+		return err
+	}
+	s.atomNETWMStateAbove, err = s.internAtom("_NET_WM_STATE_ABOVE")
+	if err != nil {
+		return err
+	}
+	s.atomNETWMStateFullscreen, err = s.internAtom("_NET_WM_STATE_FULLSCREEN")
+	if err != nil {
+		return err
+	}
+	s.atomWMChangeState, err = s.internAtom("WM_CHANGE_STATE")
+	if err != nil {
+		return err
+	}
 	s.atomWMDeleteWindow, err = s.internAtom("WM_DELETE_WINDOW")
 	if err != nil {
 		return err
@@ -441,6 +501,62 @@ func (s *screenImpl) initAtoms() (err error) {
 	if err != nil {
 		return err
 	}
+	s.atomTextPlain, err = s.internAtom("text/plain")
+	if err != nil {
+		return err
+	}
+	s.atomTextPlainUTF8, err = s.internAtom("text/plain;charset=utf-8")
+	if err != nil {
+		return err
+	}
+	s.atomTextURIList, err = s.internAtom("text/uri-list")
+	if err != nil {
+		return err
+	}
+	s.atomUTF8String, err = s.internAtom("UTF8_STRING")
+	if err != nil {
+		return err
+	}
+	s.atomXdndActionCopy, err = s.internAtom("XdndActionCopy")
+	if err != nil {
+		return err
+	}
+	s.atomXdndAware, err = s.internAtom("XdndAware")
+	if err != nil {
+		return err
+	}
+	s.atomXdndDrop, err = s.internAtom("XdndDrop")
+	if err != nil {
+		return err
+	}
+	s.atomXdndEnter, err = s.internAtom("XdndEnter")
+	if err != nil {
+		return err
+	}
+	s.atomXdndFinished, err = s.internAtom("XdndFinished")
+	if err != nil {
+		return err
+	}
+	s.atomXdndLeave, err = s.internAtom("XdndLeave")
+	if err != nil {
+		return err
+	}
+	s.atomXdndPosition, err = s.internAtom("XdndPosition")
+	if err != nil {
+		return err
+	}
+	s.atomXdndSelection, err = s.internAtom("XdndSelection")
+	if err != nil {
+		return err
+	}
+	s.atomXdndStatus, err = s.internAtom("XdndStatus")
+	if err != nil {
+		return err
+	}
+	s.atomXdndTypeList, err = s.internAtom("XdndTypeList")
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -561,6 +677,14 @@ func findVisual(xsi *xproto.ScreenInfo, depth byte) (xproto.Visualid, error) {
 }
 
 func (s *screenImpl) setProperty(xw xproto.Window, prop xproto.Atom, values ...xproto.Atom) {
+	u := make([]uint32, len(values))
+	for i, v := range values {
+		u[i] = uint32(v)
+	}
+	s.setProperty32(xw, prop, xproto.AtomAtom, u...)
+}
+
+func (s *screenImpl) setProperty32(xw xproto.Window, prop, typ xproto.Atom, values ...uint32) {
 	b := make([]byte, len(values)*4)
 	for i, v := range values {
 		b[4*i+0] = uint8(v >> 0)
@@ -568,7 +692,30 @@ func (s *screenImpl) setProperty(xw xproto.Window, prop xproto.Atom, values ...x
 		b[4*i+2] = uint8(v >> 16)
 		b[4*i+3] = uint8(v >> 24)
 	}
-	xproto.ChangeProperty(s.xc, xproto.PropModeReplace, xw, prop, xproto.AtomAtom, 32, uint32(len(values)), b)
+	xproto.ChangeProperty(s.xc, xproto.PropModeReplace, xw, prop, typ, 32, uint32(len(values)), b)
+}
+
+// sendRootMessage sends a client message about the window xw to the root
+// window, which is how EWMH and ICCCM clients make requests of the window
+// manager.
+func (s *screenImpl) sendRootMessage(xw xproto.Window, typ xproto.Atom, data ...uint32) {
+	s.sendClientMessage(s.xsi.Root,
+		xproto.EventMaskSubstructureNotify|xproto.EventMaskSubstructureRedirect,
+		xw, typ, data...)
+}
+
+// sendClientMessage sends a client message, whose window field is xw, to the
+// destination window dst.
+func (s *screenImpl) sendClientMessage(dst xproto.Window, eventMask uint32, xw xproto.Window, typ xproto.Atom, data ...uint32) {
+	d := make([]uint32, 5)
+	copy(d, data)
+	ev := xproto.ClientMessageEvent{
+		Format: 32,
+		Window: xw,
+		Type:   typ,
+		Data:   xproto.ClientMessageDataUnionData32New(d),
+	}
+	xproto.SendEvent(s.xc, false, dst, eventMask, string(ev.Bytes()))
 }
 
 func (s *screenImpl) drawUniform(xp render.Picture, src2dst *f64.Aff3, src color.Color, sr image.Rectangle, op draw.Op, opts *screen.DrawOptions) {
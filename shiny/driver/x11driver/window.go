@@ -18,6 +18,7 @@ import (
 
 	"golang.org/x/exp/shiny/driver/internal/drawer"
 	"golang.org/x/exp/shiny/driver/internal/event"
+	"golang.org/x/exp/shiny/driver/internal/frameclock"
 	"golang.org/x/exp/shiny/driver/internal/lifecycler"
 	"golang.org/x/exp/shiny/driver/internal/x11key"
 	"golang.org/x/exp/shiny/screen"
@@ -39,14 +40,36 @@ type windowImpl struct {
 	event.Deque
 	xevents chan xgb.Event
 
+	// TODO: use the Present extension's vertical blank notifications instead
+	// of a timer. The vendored xgb package cannot decode X Generic Events,
+	// which is how Present events are sent. Until then, the timer ticks at
+	// the refresh rate of the window's monitor, as reported by RandR.
+	frameClock frameclock.Clock
+
 	// This next group of variables are mutable, but are only modified in the
 	// screenImpl.run goroutine.
 	width, height int
+	pixelsPerPt   float32
+
+	// dndXxx is the state of an XDND drag and drop over this window.
+	// dndSource is the source window, or zero if there is no such drag.
+	// dndTarget is the data type to ask the source for, or zero if the
+	// source offers nothing that we understand.
+	dndSource  xproto.Window
+	dndTarget  xproto.Atom
+	dndVersion uint32
+	dndEntered bool
+	dndX, dndY float32
 
 	lifecycler lifecycler.State
 
 	mu       sync.Mutex
 	released bool
+
+	// sizeMin, sizeMax and resizable are the WM_NORMAL_HINTS state, as set
+	// by SetSizeLimits and SetResizable. They are guarded by mu.
+	sizeMin, sizeMax image.Point
+	resizable        bool
 }
 
 func (w *windowImpl) Release() {
@@ -106,23 +129,202 @@ func (w *windowImpl) Publish() screen.PublishResult {
 	return screen.PublishResult{}
 }
 
+func (w *windowImpl) RequestFrame() {
+	w.frameClock.Request(w)
+}
+
+// clampSize applies the limits set by SetSizeLimits to size.
+func (w *windowImpl) clampSize(size image.Point) image.Point {
+	w.mu.Lock()
+	min, max := w.sizeMin, w.sizeMax
+	w.mu.Unlock()
+	if min.X > 0 && size.X < min.X {
+		size.X = min.X
+	}
+	if min.Y > 0 && size.Y < min.Y {
+		size.Y = min.Y
+	}
+	if max.X > 0 && size.X > max.X {
+		size.X = max.X
+	}
+	if max.Y > 0 && size.Y > max.Y {
+		size.Y = max.Y
+	}
+	return size
+}
+
+func (w *windowImpl) Resize(size image.Point) {
+	size = w.clampSize(size)
+	if size.X <= 0 || size.Y <= 0 {
+		return
+	}
+	w.mu.Lock()
+	resizable := w.resizable
+	w.mu.Unlock()
+	if !resizable {
+		// The size hints pin a non-resizable window to its current size, and
+		// the window manager would reject a ConfigureWindow that broke them,
+		// so move the pin first.
+		w.setSizeHints(size)
+	}
+	xproto.ConfigureWindow(w.s.xc, w.xw,
+		xproto.ConfigWindowWidth|xproto.ConfigWindowHeight,
+		[]uint32{uint32(size.X), uint32(size.Y)},
+	)
+}
+
+func (w *windowImpl) Move(p image.Point) {
+	xproto.ConfigureWindow(w.s.xc, w.xw,
+		xproto.ConfigWindowX|xproto.ConfigWindowY,
+		[]uint32{uint32(int32(p.X)), uint32(int32(p.Y))},
+	)
+}
+
+// These constants are from the EWMH and ICCCM specifications.
+const (
+	netWMStateRemove = 0
+	netWMStateAdd    = 1
+
+	// netWMSourceApplication means that a _NET_WM_STATE request comes from a
+	// normal application, as opposed to a pager or similar tool.
+	netWMSourceApplication = 1
+
+	iconicState = 3
+
+	sizeHintsPMinSize = 1 << 4
+	sizeHintsPMaxSize = 1 << 5
+	sizeHintsLen      = 18
+)
+
+func (w *windowImpl) setNETWMState(add bool, state xproto.Atom) {
+	action := uint32(netWMStateRemove)
+	if add {
+		action = netWMStateAdd
+	}
+	w.s.sendRootMessage(w.xw, w.s.atomNETWMState, action, uint32(state), 0, netWMSourceApplication)
+}
+
+func (w *windowImpl) SetFullscreen(fullscreen bool) {
+	w.setNETWMState(fullscreen, w.s.atomNETWMStateFullscreen)
+}
+
+func (w *windowImpl) SetAlwaysOnTop(alwaysOnTop bool) {
+	w.setNETWMState(alwaysOnTop, w.s.atomNETWMStateAbove)
+}
+
+func (w *windowImpl) Minimize() {
+	w.s.sendRootMessage(w.xw, w.s.atomWMChangeState, iconicState)
+}
+
+func (w *windowImpl) SetSizeLimits(min, max image.Point) {
+	w.mu.Lock()
+	w.sizeMin, w.sizeMax = min, max
+	w.mu.Unlock()
+	w.setSizeHints(image.Point{})
+
+	// Window managers apply the new hints only when the window is next
+	// resized, so clamp its current size now.
+	g, err := xproto.GetGeometry(w.s.xc, xproto.Drawable(w.xw)).Reply()
+	if err != nil {
+		return
+	}
+	if size := (image.Point{int(g.Width), int(g.Height)}); w.clampSize(size) != size {
+		w.Resize(size)
+	}
+}
+
+func (w *windowImpl) SetResizable(resizable bool) {
+	w.mu.Lock()
+	w.resizable = resizable
+	w.mu.Unlock()
+	w.setSizeHints(image.Point{})
+}
+
+// setSizeHints sets the WM_NORMAL_HINTS property. A window that is not
+// resizable has equal minimum and maximum sizes, which window managers
+// conventionally treat as meaning that the user cannot resize it. That fixed
+// size is the given size or, if that is zero, the window's current size.
+func (w *windowImpl) setSizeHints(fixed image.Point) {
+	w.mu.Lock()
+	min, max, resizable := w.sizeMin, w.sizeMax, w.resizable
+	w.mu.Unlock()
+
+	if !resizable {
+		if fixed == (image.Point{}) {
+			// The w.width and w.height fields are only safe to use in the
+			// screenImpl.run goroutine, so ask the X11 server instead.
+			g, err := xproto.GetGeometry(w.s.xc, xproto.Drawable(w.xw)).Reply()
+			if err != nil {
+				return
+			}
+			fixed = image.Point{int(g.Width), int(g.Height)}
+		}
+		min, max = fixed, fixed
+	}
+
+	hints := make([]uint32, sizeHintsLen)
+	if min.X > 0 || min.Y > 0 {
+		hints[0] |= sizeHintsPMinSize
+		hints[5] = uint32(positiveOr(min.X, 0))
+		hints[6] = uint32(positiveOr(min.Y, 0))
+	}
+	if max.X > 0 || max.Y > 0 {
+		hints[0] |= sizeHintsPMaxSize
+		hints[7] = uint32(positiveOr(max.X, maxShmSide))
+		hints[8] = uint32(positiveOr(max.Y, maxShmSide))
+	}
+	w.s.setProperty32(w.xw, xproto.AtomWmNormalHints, xproto.AtomWmSizeHints, hints...)
+}
+
+// positiveOr returns v if it is positive, or otherwise unset.
+func positiveOr(v, unset int) int {
+	if v <= 0 {
+		return unset
+	}
+	return v
+}
+
 func (w *windowImpl) handleConfigureNotify(ev xproto.ConfigureNotifyEvent) {
 	// TODO: does the order of these lifecycle and size events matter? Should
 	// they really be a single, atomic event?
 	w.lifecycler.SetVisible((int(ev.X)+int(ev.Width)) > 0 && (int(ev.Y)+int(ev.Height)) > 0)
 	w.lifecycler.SendEvent(w, nil)
 
-	newWidth, newHeight := int(ev.Width), int(ev.Height)
-	if w.width == newWidth && w.height == newHeight {
+	w.updateSize(int(ev.Width), int(ev.Height))
+}
+
+// updateSize sends a size.Event if the window's size, or the scale of the
+// monitor that the window is on, has changed. It must only be called from the
+// screenImpl.run goroutine.
+func (w *windowImpl) updateSize(newWidth, newHeight int) {
+	m := monitor{pixelsPerPt: w.s.pixelsPerPt}
+	if w.s.randr {
+		// The ConfigureNotifyEvent's X and Y are relative to the parent
+		// window, which is typically the window manager's frame, not the
+		// root window, so we have to ask where we are.
+		r, err := xproto.TranslateCoordinates(w.s.xc, w.xw, w.s.xsi.Root, 0, 0).Reply()
+		if err == nil {
+			p := image.Point{int(r.DstX), int(r.DstY)}
+			m = w.s.monitorAt(image.Rectangle{
+				Min: p,
+				Max: p.Add(image.Point{newWidth, newHeight}),
+			})
+		}
+	}
+	w.frameClock.SetInterval(m.refreshInterval)
+
+	newPixelsPerPt := m.pixelsPerPt * w.s.scale
+
+	if w.width == newWidth && w.height == newHeight && w.pixelsPerPt == newPixelsPerPt {
 		return
 	}
-	w.width, w.height = newWidth, newHeight
+	w.width, w.height, w.pixelsPerPt = newWidth, newHeight, newPixelsPerPt
 	w.Send(size.Event{
 		WidthPx:     newWidth,
 		HeightPx:    newHeight,
-		WidthPt:     geom.Pt(newWidth),
-		HeightPt:    geom.Pt(newHeight),
-		PixelsPerPt: w.s.pixelsPerPt,
+		WidthPt:     geom.Pt(float32(newWidth) / newPixelsPerPt),
+		HeightPt:    geom.Pt(float32(newHeight) / newPixelsPerPt),
+		PixelsPerPt: newPixelsPerPt,
 	})
 }
 
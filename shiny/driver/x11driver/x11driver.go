@@ -14,6 +14,7 @@ import (
 	"fmt"
 
 	"github.com/BurntSushi/xgb"
+	"github.com/BurntSushi/xgb/randr"
 	"github.com/BurntSushi/xgb/render"
 	"github.com/BurntSushi/xgb/shm"
 
@@ -51,7 +52,17 @@ func main(f func(screen.Screen)) (retErr error) {
 		return fmt.Errorf("x11driver: shm.Init failed: %v", err)
 	}
 
-	s, err := newScreenImpl(xc)
+	// RandR is optional. Without it, we can't tell one monitor from another,
+	// so all windows get the same scale. Per-monitor geometry needs version
+	// 1.3, for GetScreenResourcesCurrent.
+	useRandR := false
+	if err := randr.Init(xc); err == nil {
+		if v, err := randr.QueryVersion(xc, 1, 3).Reply(); err == nil {
+			useRandR = v.MajorVersion > 1 || (v.MajorVersion == 1 && v.MinorVersion >= 3)
+		}
+	}
+
+	s, err := newScreenImpl(xc, useRandR)
 	if err != nil {
 		return err
 	}
@@ -14,7 +14,6 @@ package main
 import (
 	"encoding/binary"
 	"fmt"
-	"image"
 	"image/color"
 	"log"
 	"math"
@@ -44,7 +43,7 @@ func main() {
 			colorPatch(colornames.Green, unit.Pixels(50), unit.Pixels(50)),
 			widget.WithLayoutData(t1.w, flex.LayoutData{Grow: 1, Align: flex.AlignItemStretch}),
 			colorPatch(colornames.Blue, unit.Pixels(50), unit.Pixels(50)),
-			widget.WithLayoutData(t2.w, flex.LayoutData{MinSize: image.Point{80, 80}}),
+			widget.WithLayoutData(t2.w, flex.LayoutData{MinSize: flex.Size{Width: unit.Pixels(80), Height: unit.Pixels(80)}}),
 			colorPatch(colornames.Green, unit.Pixels(50), unit.Pixels(50)),
 		))
 
@@ -84,32 +84,21 @@ func (w *Body) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
 // TODO: make a standard widget.Button.
 
 type Button struct {
-	node.LeafEmbed
-	icon    []byte
+	widget.Icon
 	onClick func()
-	z       iconvg.Rasterizer
 }
 
 func NewButton(icon []byte, onClick func()) *Button {
 	w := &Button{
-		icon:    icon,
 		onClick: onClick,
 	}
 	w.Wrapper = w
+	if err := w.SetSrc(icon); err != nil {
+		log.Fatal(err)
+	}
 	return w
 }
 
-func (w *Button) Measure(t *theme.Theme, widthHint, heightHint int) {
-	px := t.Pixels(unit.Ems(1)).Ceil()
-	w.MeasuredSize = image.Point{px, px}
-}
-
-func (w *Button) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
-	w.Marks.UnmarkNeedsPaintBase()
-	w.z.SetDstImage(ctx.Dst, w.Rect.Add(origin), draw.Over)
-	return iconvg.Decode(&w.z, w.icon, nil)
-}
-
 func (w *Button) OnInputEvent(e interface{}, origin image.Point) node.EventHandled {
 	switch e := e.(type) {
 	case gesture.Event:
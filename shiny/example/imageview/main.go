@@ -9,18 +9,25 @@
 // -tags=example" to install it.
 
 // Imageview is a basic image viewer. Supported image formats include BMP, GIF,
-// JPEG, PNG, TIFF and WEBP.
+// JPEG, PNG, TIFF and WEBP. Dropping a PNG file onto the window views that
+// file instead.
 package main
 
 import (
 	"fmt"
 	"image"
+	"image/color"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 
+	"golang.org/x/exp/shiny/dnd"
 	"golang.org/x/exp/shiny/driver"
 	"golang.org/x/exp/shiny/screen"
 	"golang.org/x/exp/shiny/widget"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
 
 	_ "image/gif"
 	_ "image/jpeg"
@@ -46,6 +53,41 @@ func decode(filename string) (image.Image, error) {
 	return m, nil
 }
 
+// dropTarget is a shell widget that views a dropped PNG file.
+type dropTarget struct {
+	node.ShellEmbed
+	img *widget.Image
+}
+
+func newDropTarget(img *widget.Image) *dropTarget {
+	w := &dropTarget{img: img}
+	w.Wrapper = w
+	// The Uniform background paints over any previous, larger image.
+	w.Insert(widget.NewUniform(theme.StaticColor(color.Black), img), nil)
+	return w
+}
+
+func (w *dropTarget) OnInputEvent(e interface{}, origin image.Point) node.EventHandled {
+	de, ok := e.(dnd.Event)
+	if !ok || de.Type != dnd.TypeDrop {
+		return w.ShellEmbed.OnInputEvent(e, origin)
+	}
+	for _, filename := range de.Files() {
+		if strings.ToLower(filepath.Ext(filename)) != ".png" {
+			continue
+		}
+		src, err := decode(filename)
+		if err != nil {
+			log.Print(err)
+			continue
+		}
+		w.img.Src, w.img.SrcRect = src, src.Bounds()
+		w.img.Mark(node.MarkNeedsPaintBase)
+		return node.Handled
+	}
+	return node.NotHandled
+}
+
 func main() {
 	log.SetFlags(0)
 	driver.Main(func(s screen.Screen) {
@@ -57,7 +99,7 @@ func main() {
 		if err != nil {
 			log.Fatal(err)
 		}
-		w := widget.NewSheet(widget.NewImage(src, src.Bounds()))
+		w := widget.NewSheet(newDropTarget(widget.NewImage(src, src.Bounds())))
 		if err := widget.RunWindow(s, w, nil); err != nil {
 			log.Fatal(err)
 		}
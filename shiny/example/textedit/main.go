@@ -11,8 +11,7 @@
 // Textedit is a basic text editor.
 package main
 
-// TODO: cursors + editing (not just viewing) text, key + mouse events,
-// scrolling, load/save, clipboard.
+// TODO: load/save, clipboard.
 
 import (
 	"log"
@@ -50,12 +49,11 @@ func main() {
 		divider := widget.NewSizer(unit.Value{}, unit.DIPs(2),
 			widget.NewUniform(theme.Foreground, nil),
 		)
-		body := widget.NewText(prideAndPrejudice)
+		body := widget.NewTextArea(prideAndPrejudice)
 
 		w := widget.NewFlow(widget.AxisVertical,
 			stretch(widget.NewSheet(header), 0),
 			stretch(widget.NewSheet(divider), 0),
-			// TODO: make the body's sheet scrollable.
 			stretch(widget.NewSheet(body), 1),
 		)
 
@@ -16,12 +16,15 @@ import (
 	"image/color"
 	"image/draw"
 	"log"
+	"strconv"
 
 	"golang.org/x/exp/shiny/driver"
 	"golang.org/x/exp/shiny/gesture"
 	"golang.org/x/exp/shiny/screen"
+	"golang.org/x/exp/shiny/unit"
 	"golang.org/x/exp/shiny/widget"
 	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
 )
 
 var uniforms = [...]*image.Uniform{
@@ -66,12 +69,93 @@ func (w *custom) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error
 	return nil
 }
 
+// row returns a horizontal row of the given nodes, with a margin.
+func row(children ...node.Node) node.Node {
+	return widget.NewPadder(widget.AxisBoth, unit.Ems(0.25),
+		widget.NewFlow(widget.AxisHorizontal, children...),
+	)
+}
+
+// gap returns a horizontal space of fixed width.
+func gap() node.Node {
+	return widget.NewSizer(unit.Ems(1), unit.Value{}, nil)
+}
+
+func newForm(th *widget.Themer) node.Node {
+	name := widget.NewTextField("")
+	subscribe := widget.NewCheckbox("Subscribe to the newsletter", true)
+
+	sizes := &widget.RadioGroup{}
+	small := widget.NewRadio("Small", sizes)
+	medium := widget.NewRadio("Medium", sizes)
+	large := widget.NewRadio("Large", sizes)
+	sizes.Select(medium)
+
+	volume := widget.NewSlider(0, 10, 5)
+	volume.Step = 1
+	volumeLabel := widget.NewLabel("5")
+	volume.OnChange = func(v float64) {
+		volumeLabel.Text = strconv.FormatFloat(v, 'f', -1, 64)
+		volumeLabel.Mark(node.MarkNeedsPaintBase)
+	}
+
+	submit := widget.NewButton("Submit", func() {
+		log.Printf("name=%q subscribe=%t size=%q volume=%v",
+			name.Text(), subscribe.Checked(), sizes.Selected().Text, volume.Value())
+	})
+	submit.SetDisabled(true)
+	agree := widget.NewSwitch(false)
+	agree.OnChange = func(on bool) {
+		submit.SetDisabled(!on)
+	}
+
+	var edit *widget.Button
+	menu := widget.NewMenu(
+		widget.MenuItem{Text: "Cut", OnSelect: func() { log.Print("cut") }},
+		widget.MenuItem{Text: "Copy", OnSelect: func() { log.Print("copy") }},
+		widget.MenuItem{Text: "Paste", Disabled: true},
+	)
+	edit = widget.NewButton("Edit", func() { menu.ShowAt(edit) })
+
+	var about *widget.Dialog
+	about = widget.NewDialog("About",
+		widget.NewLabel("Widgetgallery exhibits the shiny/widget package's widget set."),
+		widget.NewButton("OK", func() { about.Dismiss() }),
+	)
+	aboutButton := widget.NewButton("About", nil)
+	aboutButton.OnClick = func() { about.Show(aboutButton) }
+
+	dark := widget.NewSwitch(false)
+	dark.OnChange = func(on bool) {
+		if on {
+			th.SetTheme(theme.DarkTheme)
+		} else {
+			th.SetTheme(theme.LightTheme)
+		}
+	}
+
+	return widget.NewUniform(theme.Background, widget.NewPadder(widget.AxisBoth, unit.Ems(0.5),
+		widget.NewFlow(widget.AxisVertical,
+			row(widget.NewLabel("Name: "), name),
+			row(subscribe),
+			row(widget.NewLabel("Size: "), small, gap(), medium, gap(), large),
+			row(widget.NewLabel("Volume: "), volume, widget.NewLabel(" "), volumeLabel),
+			row(widget.NewLabel("Color (tap to change): "), widget.NewSizer(unit.Ems(4), unit.Ems(1), newCustom())),
+			row(widget.NewLabel("I agree: "), agree),
+			row(submit),
+			row(edit, gap(), aboutButton),
+			row(widget.NewLabel("Dark theme: "), dark),
+		),
+	))
+}
+
 func main() {
 	log.SetFlags(0)
 	driver.Main(func(s screen.Screen) {
-		// TODO: create a bunch of standard widgets: buttons, labels, etc.
-		w := widget.NewSheet(newCustom())
-		if err := widget.RunWindow(s, w, nil); err != nil {
+		th := widget.NewThemer(theme.LightTheme, nil)
+		th.Insert(widget.NewScroller(widget.AxisVertical, newForm(th)), nil)
+		// Pressing F12 shows the Inspector's debugging overlay.
+		if err := widget.RunWindow(s, widget.NewInspector(th), nil); err != nil {
 			log.Fatal(err)
 		}
 	})
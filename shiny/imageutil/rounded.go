@@ -0,0 +1,160 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imageutil
+
+import (
+	"image"
+)
+
+// roundedSamples is the number of samples per pixel, along each axis, that
+// anti-alias the rounded corners.
+const roundedSamples = 4
+
+// RoundedRect returns an anti-aliased mask, whose bounds are r, of the
+// rectangle r with its corners rounded to the given radius. The radius is
+// reduced, if necessary, to half of r's width or height.
+func RoundedRect(r image.Rectangle, radius int) *image.Alpha {
+	m := image.NewAlpha(r)
+	if r.Empty() {
+		return m
+	}
+	s := newRounded(r, radius)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			m.Pix[m.PixOffset(x, y)] = s.coverage(x, y)
+		}
+	}
+	return m
+}
+
+// RoundedBorder returns an anti-aliased mask, whose bounds are outer, of those
+// points inside the rectangle outer with its corners rounded to the given
+// radius, but outside of inner with its corners rounded to match. The
+// difference between outer and inner, on each side, is the border's width on
+// that side, and may differ from side to side. inner should be inside outer.
+//
+// As with the CSS border-radius property, the inner corners' radii are the
+// outer radius less the adjacent sides' widths, so that an inner corner is
+// square if the border is at least as wide as the radius, and is elliptical
+// if the adjacent sides' widths differ.
+func RoundedBorder(outer, inner image.Rectangle, radius int) *image.Alpha {
+	m := image.NewAlpha(outer)
+	if outer.Empty() {
+		return m
+	}
+	o := newRounded(outer, radius)
+	i := rounded{r: inner}
+	if !inner.Empty() {
+		// The corners are ordered top-left, top-right, bottom-right and
+		// bottom-left.
+		left := float64(inner.Min.X - outer.Min.X)
+		top := float64(inner.Min.Y - outer.Min.Y)
+		right := float64(outer.Max.X - inner.Max.X)
+		bottom := float64(outer.Max.Y - inner.Max.Y)
+		i.rx = [4]float64{o.rx[0] - left, o.rx[1] - right, o.rx[2] - right, o.rx[3] - left}
+		i.ry = [4]float64{o.ry[0] - top, o.ry[1] - top, o.ry[2] - bottom, o.ry[3] - bottom}
+		for j := range i.rx {
+			if i.rx[j] <= 0 || i.ry[j] <= 0 {
+				i.rx[j], i.ry[j] = 0, 0
+			}
+		}
+	}
+	for y := outer.Min.Y; y < outer.Max.Y; y++ {
+		for x := outer.Min.X; x < outer.Max.X; x++ {
+			a := int(o.coverage(x, y))
+			if !inner.Empty() {
+				a -= int(i.coverage(x, y))
+			}
+			if a > 0 {
+				m.Pix[m.PixOffset(x, y)] = uint8(a)
+			}
+		}
+	}
+	return m
+}
+
+// rounded is a rectangle with elliptical corners, whose horizontal and
+// vertical radii are rx and ry. The corners are ordered top-left, top-right,
+// bottom-right and bottom-left.
+type rounded struct {
+	r      image.Rectangle
+	rx, ry [4]float64
+}
+
+func newRounded(r image.Rectangle, radius int) rounded {
+	if max := r.Dx() / 2; radius > max {
+		radius = max
+	}
+	if max := r.Dy() / 2; radius > max {
+		radius = max
+	}
+	if radius < 0 {
+		radius = 0
+	}
+	f := float64(radius)
+	return rounded{
+		r:  r,
+		rx: [4]float64{f, f, f, f},
+		ry: [4]float64{f, f, f, f},
+	}
+}
+
+// coverage returns how much of the pixel (x, y) is inside s, from 0 to 0xff.
+func (s *rounded) coverage(x, y int) uint8 {
+	if !(image.Point{x, y}).In(s.r) {
+		return 0
+	}
+	if !s.nearCorner(x, y) {
+		return 0xff
+	}
+	n := 0
+	for j := 0; j < roundedSamples; j++ {
+		fy := float64(y) + (float64(j)+0.5)/roundedSamples
+		for i := 0; i < roundedSamples; i++ {
+			fx := float64(x) + (float64(i)+0.5)/roundedSamples
+			if s.contains(fx, fy) {
+				n++
+			}
+		}
+	}
+	return uint8(n * 0xff / (roundedSamples * roundedSamples))
+}
+
+// nearCorner returns whether the pixel (x, y) overlaps the square that
+// bounds any of the corners' ellipses.
+func (s *rounded) nearCorner(x, y int) bool {
+	x0, y0 := float64(s.r.Min.X), float64(s.r.Min.Y)
+	x1, y1 := float64(s.r.Max.X), float64(s.r.Max.Y)
+	fx, fy := float64(x), float64(y)
+	left := fx < x0+s.rx[0] && fy < y0+s.ry[0] ||
+		fx < x0+s.rx[3] && fy+1 > y1-s.ry[3]
+	right := fx+1 > x1-s.rx[1] && fy < y0+s.ry[1] ||
+		fx+1 > x1-s.rx[2] && fy+1 > y1-s.ry[2]
+	return left || right
+}
+
+// contains returns whether the point (x, y) is inside s.
+func (s *rounded) contains(x, y float64) bool {
+	x0, y0 := float64(s.r.Min.X), float64(s.r.Min.Y)
+	x1, y1 := float64(s.r.Max.X), float64(s.r.Max.Y)
+	if x < x0 || x >= x1 || y < y0 || y >= y1 {
+		return false
+	}
+	var cx, cy, rx, ry float64
+	switch {
+	case x < x0+s.rx[0] && y < y0+s.ry[0]:
+		cx, cy, rx, ry = x0+s.rx[0], y0+s.ry[0], s.rx[0], s.ry[0]
+	case x >= x1-s.rx[1] && y < y0+s.ry[1]:
+		cx, cy, rx, ry = x1-s.rx[1], y0+s.ry[1], s.rx[1], s.ry[1]
+	case x >= x1-s.rx[2] && y >= y1-s.ry[2]:
+		cx, cy, rx, ry = x1-s.rx[2], y1-s.ry[2], s.rx[2], s.ry[2]
+	case x < x0+s.rx[3] && y >= y1-s.ry[3]:
+		cx, cy, rx, ry = x0+s.rx[3], y1-s.ry[3], s.rx[3], s.ry[3]
+	default:
+		return true
+	}
+	dx, dy := (x-cx)/rx, (y-cy)/ry
+	return dx*dx+dy*dy <= 1
+}
@@ -0,0 +1,108 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imageutil
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+// coverage returns the total alpha of m, in units of whole pixels.
+func coverage(m *image.Alpha) float64 {
+	sum := 0
+	for _, a := range m.Pix {
+		sum += int(a)
+	}
+	return float64(sum) / 0xff
+}
+
+func TestRoundedRect(t *testing.T) {
+	r := image.Rect(10, 20, 110, 70)
+	testCases := []struct {
+		radius int
+		want   float64
+	}{
+		{0, 100 * 50},
+		{10, 100*50 - (4-math.Pi)*10*10},
+		// The radius is reduced to half of the height.
+		{40, 100*50 - (4-math.Pi)*25*25},
+	}
+	for _, tc := range testCases {
+		m := RoundedRect(r, tc.radius)
+		if m.Bounds() != r {
+			t.Errorf("radius=%d: bounds: got %v, want %v", tc.radius, m.Bounds(), r)
+		}
+		if got := coverage(m); math.Abs(got-tc.want) > 2 {
+			t.Errorf("radius=%d: coverage: got %.2f, want %.2f", tc.radius, got, tc.want)
+		}
+		if got := m.AlphaAt(60, 45).A; got != 0xff {
+			t.Errorf("radius=%d: center: got %#02x, want 0xff", tc.radius, got)
+		}
+		if tc.radius > 0 {
+			if got := m.AlphaAt(10, 20).A; got != 0 {
+				t.Errorf("radius=%d: corner: got %#02x, want 0x00", tc.radius, got)
+			}
+		}
+	}
+}
+
+// roundedArea returns the area of a dx by dy rectangle whose corners are
+// rounded to the given radius.
+func roundedArea(dx, dy, radius float64) float64 {
+	return dx*dy - (4-math.Pi)*radius*radius
+}
+
+func TestRoundedBorder(t *testing.T) {
+	outer := image.Rect(0, 0, 100, 60)
+	testCases := []struct {
+		width, radius int
+	}{
+		{1, 0},
+		{5, 0},
+		{2, 10},
+		{5, 20},
+		// The border is wider than the radius, so the inner corners are
+		// square.
+		{12, 10},
+	}
+	for _, tc := range testCases {
+		inner := outer.Inset(tc.width)
+		m := RoundedBorder(outer, inner, tc.radius)
+		innerRadius := math.Max(0, float64(tc.radius-tc.width))
+		want := roundedArea(100, 60, float64(tc.radius)) -
+			roundedArea(float64(inner.Dx()), float64(inner.Dy()), innerRadius)
+		if got := coverage(m); math.Abs(got-want) > 2 {
+			t.Errorf("width=%d, radius=%d: coverage: got %.2f, want %.2f", tc.width, tc.radius, got, want)
+		}
+		if got := m.AlphaAt(50, 30).A; got != 0 {
+			t.Errorf("width=%d, radius=%d: center: got %#02x, want 0x00", tc.width, tc.radius, got)
+		}
+		if got := m.AlphaAt(50, 0).A; got != 0xff {
+			t.Errorf("width=%d, radius=%d: top: got %#02x, want 0xff", tc.width, tc.radius, got)
+		}
+	}
+
+	// Sides of different widths, including a missing top side.
+	m := RoundedBorder(outer, image.Rect(10, 0, 98, 55), 20)
+	testPoints := []struct {
+		x, y int
+		want uint8
+	}{
+		{5, 30, 0xff},
+		{15, 30, 0x00},
+		{50, 0, 0x00},
+		{50, 57, 0xff},
+		{97, 30, 0x00},
+		{98, 30, 0xff},
+		{99, 30, 0xff},
+		{0, 0, 0x00},
+	}
+	for _, p := range testPoints {
+		if got := m.AlphaAt(p.x, p.y).A; got != p.want {
+			t.Errorf("uneven sides: (%d, %d): got %#02x, want %#02x", p.x, p.y, got, p.want)
+		}
+	}
+}
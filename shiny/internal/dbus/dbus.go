@@ -0,0 +1,854 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package dbus implements the small part of the D-Bus wire protocol that the
+// atspi package needs: connecting to a bus over a Unix domain socket, EXTERNAL
+// authentication, and sending and receiving method calls, replies, errors
+// and signals.
+//
+// D-Bus values are represented by Go values, driven by their D-Bus
+// signatures: 'y' is a byte, 'b' a bool, 'n', 'q', 'i', 'u', 'x' and 't' are
+// the sized integers, 'd' a float64, 's' a string, 'o' an ObjectPath and 'g' a
+// Signature. Arrays, structs and dict entries are all []interface{}, and 'v'
+// is a Variant.
+//
+// See https://dbus.freedesktop.org/doc/dbus-specification.html
+package dbus // import "golang.org/x/exp/shiny/internal/dbus"
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ObjectPath is a D-Bus object path, such as "/org/freedesktop/DBus".
+type ObjectPath string
+
+// Signature is a D-Bus type signature, such as "a{sv}".
+type Signature string
+
+// Variant is a D-Bus variant: a value together with its type.
+type Variant struct {
+	Sig   Signature
+	Value interface{}
+}
+
+// Message types.
+const (
+	typeMethodCall   = 1
+	typeMethodReturn = 2
+	typeError        = 3
+	typeSignal       = 4
+)
+
+// flagNoReplyExpected is the message flag for method calls that do not need
+// a reply.
+const flagNoReplyExpected = 0x1
+
+// Header field codes.
+const (
+	fieldPath        = 1
+	fieldInterface   = 2
+	fieldMember      = 3
+	fieldErrorName   = 4
+	fieldReplySerial = 5
+	fieldDestination = 6
+	fieldSender      = 7
+	fieldSignature   = 8
+)
+
+// maxMessageSize is the maximum length of a D-Bus message, from the
+// specification.
+const maxMessageSize = 1 << 27
+
+// Message is a D-Bus message. Only the fields needed to handle incoming
+// method calls and signals are exported.
+type Message struct {
+	typ         byte
+	flags       byte
+	serial      uint32
+	Path        ObjectPath
+	Interface   string
+	Member      string
+	errName     string
+	replySerial uint32
+	dest        string
+	sender      string
+	sig         Signature
+	Body        []interface{}
+}
+
+// Error is an error reply to a method call.
+type Error struct {
+	Name, Text string
+}
+
+func (e *Error) Error() string {
+	if e.Text == "" {
+		return "dbus: " + e.Name
+	}
+	return "dbus: " + e.Name + ": " + e.Text
+}
+
+// Conn is a connection to a D-Bus bus.
+type Conn struct {
+	c net.Conn
+	r *bufio.Reader
+
+	// Name is the connection's unique bus name, assigned by the bus.
+	Name string
+
+	// handleCall and handleSignal, if non-nil, are called, on the
+	// connection's read goroutine, with incoming method calls and signals.
+	// They are set before the connection starts reading.
+	handleCall   func(*Message)
+	handleSignal func(*Message)
+
+	// wmu guards writing and serial.
+	wmu    sync.Mutex
+	serial uint32
+
+	// mu guards pending and err.
+	mu      sync.Mutex
+	pending map[uint32]chan *Message
+	err     error
+}
+
+// Dial connects to the D-Bus bus at the given address, which is a
+// semi-colon separated list of addresses such as "unix:path=/run/bus", and
+// authenticates. Only the unix transport is supported.
+//
+// handleCall and handleSignal, if non-nil, are called, on the connection's
+// read goroutine, with incoming method calls and signals. A method call must
+// be answered with Reply or ReplyError.
+func Dial(address string, handleCall, handleSignal func(*Message)) (*Conn, error) {
+	var nc net.Conn
+	err := errors.New("dbus: no supported D-Bus address in " + strconv.Quote(address))
+	for _, a := range strings.Split(address, ";") {
+		i := strings.IndexByte(a, ':')
+		if i < 0 || a[:i] != "unix" {
+			continue
+		}
+		for _, kv := range strings.Split(a[i+1:], ",") {
+			j := strings.IndexByte(kv, '=')
+			if j < 0 {
+				continue
+			}
+			v, uerr := unescapeAddress(kv[j+1:])
+			if uerr != nil {
+				err = uerr
+				continue
+			}
+			switch kv[:j] {
+			case "path":
+				nc, err = net.Dial("unix", v)
+			case "abstract":
+				nc, err = net.Dial("unix", "@"+v)
+			default:
+				continue
+			}
+			break
+		}
+		if nc != nil {
+			break
+		}
+	}
+	if nc == nil {
+		return nil, err
+	}
+
+	c := &Conn{
+		c:            nc,
+		r:            bufio.NewReader(nc),
+		handleCall:   handleCall,
+		handleSignal: handleSignal,
+		pending:      map[uint32]chan *Message{},
+	}
+	if err := c.auth(); err != nil {
+		nc.Close()
+		return nil, err
+	}
+	go c.readLoop()
+
+	body, err := c.Call("org.freedesktop.DBus", "/org/freedesktop/DBus", "org.freedesktop.DBus", "Hello", "")
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	if len(body) != 1 {
+		c.Close()
+		return nil, errors.New("dbus: invalid Hello reply")
+	}
+	c.Name, _ = body[0].(string)
+	return c, nil
+}
+
+// unescapeAddress decodes the %xx escapes in a D-Bus address value.
+func unescapeAddress(s string) (string, error) {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			b = append(b, s[i])
+			continue
+		}
+		if i+2 >= len(s) {
+			return "", errors.New("dbus: invalid D-Bus address escape")
+		}
+		x, err := hex.DecodeString(s[i+1 : i+3])
+		if err != nil {
+			return "", errors.New("dbus: invalid D-Bus address escape")
+		}
+		b = append(b, x...)
+		i += 2
+	}
+	return string(b), nil
+}
+
+// auth authenticates with the EXTERNAL mechanism, which sends the process'
+// user ID.
+func (c *Conn) auth() error {
+	uid := hex.EncodeToString([]byte(strconv.Itoa(os.Getuid())))
+	if _, err := io.WriteString(c.c, "\x00AUTH EXTERNAL "+uid+"\r\n"); err != nil {
+		return err
+	}
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "OK ") {
+		return errors.New("dbus: D-Bus authentication failed: " + strings.TrimSpace(line))
+	}
+	_, err = io.WriteString(c.c, "BEGIN\r\n")
+	return err
+}
+
+// Close closes the connection.
+func (c *Conn) Close() error {
+	return c.c.Close()
+}
+
+func (c *Conn) readLoop() {
+	for {
+		m, err := c.read()
+		if err != nil {
+			c.mu.Lock()
+			c.err = err
+			for serial, ch := range c.pending {
+				close(ch)
+				delete(c.pending, serial)
+			}
+			c.mu.Unlock()
+			return
+		}
+		switch m.typ {
+		case typeMethodReturn, typeError:
+			c.mu.Lock()
+			ch := c.pending[m.replySerial]
+			delete(c.pending, m.replySerial)
+			c.mu.Unlock()
+			if ch != nil {
+				ch <- m
+			}
+		case typeMethodCall:
+			if c.handleCall != nil {
+				c.handleCall(m)
+			} else if m.flags&flagNoReplyExpected == 0 {
+				c.ReplyError(m, "org.freedesktop.DBus.Error.UnknownMethod", "")
+			}
+		case typeSignal:
+			if c.handleSignal != nil {
+				c.handleSignal(m)
+			}
+		}
+	}
+}
+
+// Call calls a method and waits for its reply.
+func (c *Conn) Call(dest string, path ObjectPath, iface, member string, sig Signature, args ...interface{}) ([]interface{}, error) {
+	ch := make(chan *Message, 1)
+	err := c.send(&Message{
+		typ:       typeMethodCall,
+		Path:      path,
+		Interface: iface,
+		Member:    member,
+		dest:      dest,
+		sig:       sig,
+		Body:      args,
+	}, ch)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := <-ch
+	if !ok {
+		c.mu.Lock()
+		err := c.err
+		c.mu.Unlock()
+		return nil, err
+	}
+	if m.typ == typeError {
+		e := &Error{Name: m.errName}
+		if len(m.Body) > 0 {
+			e.Text, _ = m.Body[0].(string)
+		}
+		return nil, e
+	}
+	return m.Body, nil
+}
+
+// Reply sends the reply to the method call m, unless m expects no reply.
+func (c *Conn) Reply(m *Message, sig Signature, args ...interface{}) error {
+	if m.flags&flagNoReplyExpected != 0 {
+		return nil
+	}
+	return c.send(&Message{
+		typ:         typeMethodReturn,
+		replySerial: m.serial,
+		dest:        m.sender,
+		sig:         sig,
+		Body:        args,
+	}, nil)
+}
+
+// ReplyError sends an error reply to the method call m, unless m expects no
+// reply.
+func (c *Conn) ReplyError(m *Message, name, text string) error {
+	if m.flags&flagNoReplyExpected != 0 {
+		return nil
+	}
+	r := &Message{
+		typ:         typeError,
+		errName:     name,
+		replySerial: m.serial,
+		dest:        m.sender,
+	}
+	if text != "" {
+		r.sig, r.Body = "s", []interface{}{text}
+	}
+	return c.send(r, nil)
+}
+
+// Emit broadcasts a signal.
+func (c *Conn) Emit(path ObjectPath, iface, member string, sig Signature, args ...interface{}) error {
+	return c.send(&Message{
+		typ:       typeSignal,
+		Path:      path,
+		Interface: iface,
+		Member:    member,
+		sig:       sig,
+		Body:      args,
+	}, nil)
+}
+
+// send sends m, after giving it the next serial number. If ch is non-nil, it
+// is sent the reply to m.
+func (c *Conn) send(m *Message, ch chan *Message) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	c.serial++
+	m.serial = c.serial
+	b, err := m.marshal()
+	if err != nil {
+		return err
+	}
+	if ch != nil {
+		c.mu.Lock()
+		if c.err != nil {
+			err := c.err
+			c.mu.Unlock()
+			return err
+		}
+		c.pending[m.serial] = ch
+		c.mu.Unlock()
+	}
+	if _, err := c.c.Write(b); err != nil {
+		if ch != nil {
+			c.mu.Lock()
+			delete(c.pending, m.serial)
+			c.mu.Unlock()
+		}
+		return err
+	}
+	return nil
+}
+
+// read reads the next message.
+func (c *Conn) read() (*Message, error) {
+	var fixed [16]byte
+	if _, err := io.ReadFull(c.r, fixed[:]); err != nil {
+		return nil, err
+	}
+	var order binary.ByteOrder
+	switch fixed[0] {
+	case 'l':
+		order = binary.LittleEndian
+	case 'B':
+		order = binary.BigEndian
+	default:
+		return nil, errors.New("dbus: invalid D-Bus message")
+	}
+	bodyLen := order.Uint32(fixed[4:])
+	fieldsLen := order.Uint32(fixed[12:])
+	if bodyLen > maxMessageSize || fieldsLen > maxMessageSize {
+		return nil, errors.New("dbus: D-Bus message is too long")
+	}
+	headerLen := (16 + int(fieldsLen) + 7) &^ 7
+	b := make([]byte, headerLen+int(bodyLen))
+	copy(b, fixed[:])
+	if _, err := io.ReadFull(c.r, b[16:]); err != nil {
+		return nil, err
+	}
+	return unmarshal(b, order, headerLen)
+}
+
+func (m *Message) marshal() ([]byte, error) {
+	var fields []interface{}
+	addField := func(code byte, sig Signature, v interface{}) {
+		fields = append(fields, []interface{}{code, Variant{sig, v}})
+	}
+	if m.Path != "" {
+		addField(fieldPath, "o", m.Path)
+	}
+	if m.Interface != "" {
+		addField(fieldInterface, "s", m.Interface)
+	}
+	if m.Member != "" {
+		addField(fieldMember, "s", m.Member)
+	}
+	if m.errName != "" {
+		addField(fieldErrorName, "s", m.errName)
+	}
+	if m.replySerial != 0 {
+		addField(fieldReplySerial, "u", m.replySerial)
+	}
+	if m.dest != "" {
+		addField(fieldDestination, "s", m.dest)
+	}
+	if m.sig != "" {
+		addField(fieldSignature, "g", m.sig)
+	}
+
+	body := &encoder{order: binary.LittleEndian}
+	if err := body.encodeAll(m.sig, m.Body); err != nil {
+		return nil, err
+	}
+
+	e := &encoder{order: binary.LittleEndian}
+	err := e.encodeAll("yyyyuua(yv)", []interface{}{
+		byte('l'), m.typ, m.flags, byte(1), uint32(len(body.b)), m.serial, fields,
+	})
+	if err != nil {
+		return nil, err
+	}
+	e.align(8)
+	return append(e.b, body.b...), nil
+}
+
+func unmarshal(b []byte, order binary.ByteOrder, headerLen int) (*Message, error) {
+	d := &decoder{b: b[:headerLen], order: order}
+	header, err := d.decodeAll("yyyyuua(yv)")
+	if err != nil {
+		return nil, err
+	}
+	m := &Message{
+		typ:    header[1].(byte),
+		flags:  header[2].(byte),
+		serial: header[5].(uint32),
+	}
+	for _, f := range header[6].([]interface{}) {
+		f := f.([]interface{})
+		v := f[1].(Variant).Value
+		switch f[0].(byte) {
+		case fieldPath:
+			m.Path, _ = v.(ObjectPath)
+		case fieldInterface:
+			m.Interface, _ = v.(string)
+		case fieldMember:
+			m.Member, _ = v.(string)
+		case fieldErrorName:
+			m.errName, _ = v.(string)
+		case fieldReplySerial:
+			m.replySerial, _ = v.(uint32)
+		case fieldDestination:
+			m.dest, _ = v.(string)
+		case fieldSender:
+			m.sender, _ = v.(string)
+		case fieldSignature:
+			m.sig, _ = v.(Signature)
+		}
+	}
+
+	d = &decoder{b: b[headerLen:], order: order}
+	if m.Body, err = d.decodeAll(m.sig); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// nextType splits sig into its first complete type and the rest.
+func nextType(sig Signature) (first, rest Signature, err error) {
+	if sig == "" {
+		return "", "", errors.New("dbus: empty D-Bus signature")
+	}
+	switch sig[0] {
+	case 'a':
+		elem, rest, err := nextType(sig[1:])
+		if err != nil {
+			return "", "", err
+		}
+		return "a" + elem, rest, nil
+	case '(', '{':
+		end := byte(')')
+		if sig[0] == '{' {
+			end = '}'
+		}
+		s := sig[1:]
+		for len(s) > 0 && s[0] != end {
+			_, r, err := nextType(s)
+			if err != nil {
+				return "", "", err
+			}
+			s = r
+		}
+		if s == "" {
+			return "", "", errors.New("dbus: invalid D-Bus signature " + strconv.Quote(string(sig)))
+		}
+		n := len(sig) - len(s) + 1
+		return sig[:n], sig[n:], nil
+	case 'y', 'b', 'n', 'q', 'i', 'u', 'x', 't', 'd', 's', 'o', 'g', 'v':
+		return sig[:1], sig[1:], nil
+	}
+	return "", "", errors.New("dbus: unsupported D-Bus signature " + strconv.Quote(string(sig)))
+}
+
+// alignment returns the alignment of values of the type sig.
+func alignment(sig Signature) int {
+	switch sig[0] {
+	case 'n', 'q':
+		return 2
+	case 'b', 'i', 'u', 's', 'o', 'a':
+		return 4
+	case 'x', 't', 'd', '(', '{':
+		return 8
+	}
+	return 1
+}
+
+type encoder struct {
+	b     []byte
+	order binary.ByteOrder
+}
+
+func (e *encoder) align(n int) {
+	for len(e.b)%n != 0 {
+		e.b = append(e.b, 0)
+	}
+}
+
+func (e *encoder) uint32(x uint32) {
+	e.align(4)
+	var buf [4]byte
+	e.order.PutUint32(buf[:], x)
+	e.b = append(e.b, buf[:]...)
+}
+
+func (e *encoder) uint64(x uint64) {
+	e.align(8)
+	var buf [8]byte
+	e.order.PutUint64(buf[:], x)
+	e.b = append(e.b, buf[:]...)
+}
+
+// encodeAll encodes the values vs, whose types are the sequence of complete
+// types sig.
+func (e *encoder) encodeAll(sig Signature, vs []interface{}) error {
+	for _, v := range vs {
+		t, rest, err := nextType(sig)
+		if err != nil {
+			return err
+		}
+		if err := e.encode(t, v); err != nil {
+			return err
+		}
+		sig = rest
+	}
+	if sig != "" {
+		return errors.New("dbus: too few D-Bus values for signature")
+	}
+	return nil
+}
+
+// encode encodes v, whose type is the complete type sig.
+func (e *encoder) encode(sig Signature, v interface{}) (err error) {
+	defer func() {
+		// A v of the wrong Go type for sig panics in a type assertion.
+		if r := recover(); r != nil {
+			err = fmt.Errorf("dbus: cannot encode %T as D-Bus type %q", v, sig)
+		}
+	}()
+
+	switch sig[0] {
+	case 'y':
+		e.b = append(e.b, v.(byte))
+	case 'b':
+		x := uint32(0)
+		if v.(bool) {
+			x = 1
+		}
+		e.uint32(x)
+	case 'n', 'q':
+		e.align(2)
+		var buf [2]byte
+		if x, ok := v.(int16); ok {
+			e.order.PutUint16(buf[:], uint16(x))
+		} else {
+			e.order.PutUint16(buf[:], v.(uint16))
+		}
+		e.b = append(e.b, buf[:]...)
+	case 'i':
+		e.uint32(uint32(v.(int32)))
+	case 'u':
+		e.uint32(v.(uint32))
+	case 'x':
+		e.uint64(uint64(v.(int64)))
+	case 't':
+		e.uint64(v.(uint64))
+	case 'd':
+		e.uint64(math.Float64bits(v.(float64)))
+	case 's', 'o':
+		var s string
+		switch v := v.(type) {
+		case string:
+			s = v
+		case ObjectPath:
+			s = string(v)
+		default:
+			panic("bad type")
+		}
+		e.uint32(uint32(len(s)))
+		e.b = append(e.b, s...)
+		e.b = append(e.b, 0)
+	case 'g':
+		var s string
+		switch v := v.(type) {
+		case string:
+			s = v
+		case Signature:
+			s = string(v)
+		default:
+			panic("bad type")
+		}
+		e.b = append(e.b, byte(len(s)))
+		e.b = append(e.b, s...)
+		e.b = append(e.b, 0)
+	case 'a':
+		e.uint32(0)
+		lenPos := len(e.b) - 4
+		e.align(alignment(sig[1:]))
+		start := len(e.b)
+		for _, x := range v.([]interface{}) {
+			if err := e.encode(sig[1:], x); err != nil {
+				return err
+			}
+		}
+		e.order.PutUint32(e.b[lenPos:], uint32(len(e.b)-start))
+	case '(', '{':
+		e.align(8)
+		return e.encodeAll(sig[1:len(sig)-1], v.([]interface{}))
+	case 'v':
+		x := v.(Variant)
+		if _, rest, err := nextType(x.Sig); err != nil || rest != "" {
+			return errors.New("dbus: invalid D-Bus variant signature")
+		}
+		e.b = append(e.b, byte(len(x.Sig)))
+		e.b = append(e.b, x.Sig...)
+		e.b = append(e.b, 0)
+		return e.encode(x.Sig, x.Value)
+	}
+	return nil
+}
+
+type decoder struct {
+	b     []byte
+	pos   int
+	order binary.ByteOrder
+}
+
+var errShortMessage = errors.New("dbus: D-Bus message is too short")
+
+func (d *decoder) align(n int) error {
+	p := (d.pos + n - 1) &^ (n - 1)
+	if p > len(d.b) {
+		return errShortMessage
+	}
+	d.pos = p
+	return nil
+}
+
+func (d *decoder) next(n int) ([]byte, error) {
+	if n < 0 || len(d.b)-d.pos < n {
+		return nil, errShortMessage
+	}
+	b := d.b[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *decoder) uint32() (uint32, error) {
+	if err := d.align(4); err != nil {
+		return 0, err
+	}
+	b, err := d.next(4)
+	if err != nil {
+		return 0, err
+	}
+	return d.order.Uint32(b), nil
+}
+
+func (d *decoder) uint64() (uint64, error) {
+	if err := d.align(8); err != nil {
+		return 0, err
+	}
+	b, err := d.next(8)
+	if err != nil {
+		return 0, err
+	}
+	return d.order.Uint64(b), nil
+}
+
+// string decodes a string whose length has already been decoded.
+func (d *decoder) string(n int) (string, error) {
+	b, err := d.next(n + 1)
+	if err != nil {
+		return "", err
+	}
+	return string(b[:n]), nil
+}
+
+// decodeAll decodes values whose types are the sequence of complete types
+// sig.
+func (d *decoder) decodeAll(sig Signature) ([]interface{}, error) {
+	var vs []interface{}
+	for sig != "" {
+		t, rest, err := nextType(sig)
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.decode(t)
+		if err != nil {
+			return nil, err
+		}
+		vs = append(vs, v)
+		sig = rest
+	}
+	return vs, nil
+}
+
+// decode decodes a value whose type is the complete type sig.
+func (d *decoder) decode(sig Signature) (interface{}, error) {
+	switch sig[0] {
+	case 'y':
+		b, err := d.next(1)
+		if err != nil {
+			return nil, err
+		}
+		return b[0], nil
+	case 'b':
+		x, err := d.uint32()
+		return x != 0, err
+	case 'n', 'q':
+		if err := d.align(2); err != nil {
+			return nil, err
+		}
+		b, err := d.next(2)
+		if err != nil {
+			return nil, err
+		}
+		if sig[0] == 'n' {
+			return int16(d.order.Uint16(b)), nil
+		}
+		return d.order.Uint16(b), nil
+	case 'i':
+		x, err := d.uint32()
+		return int32(x), err
+	case 'u':
+		return d.uint32()
+	case 'x':
+		x, err := d.uint64()
+		return int64(x), err
+	case 't':
+		return d.uint64()
+	case 'd':
+		x, err := d.uint64()
+		return math.Float64frombits(x), err
+	case 's', 'o':
+		n, err := d.uint32()
+		if err != nil {
+			return nil, err
+		}
+		s, err := d.string(int(n))
+		if sig[0] == 'o' {
+			return ObjectPath(s), err
+		}
+		return s, err
+	case 'g':
+		b, err := d.next(1)
+		if err != nil {
+			return nil, err
+		}
+		s, err := d.string(int(b[0]))
+		return Signature(s), err
+	case 'a':
+		n, err := d.uint32()
+		if err != nil {
+			return nil, err
+		}
+		if err := d.align(alignment(sig[1:])); err != nil {
+			return nil, err
+		}
+		end := d.pos + int(n)
+		if n > maxMessageSize || end > len(d.b) {
+			return nil, errShortMessage
+		}
+		vs := []interface{}{}
+		for d.pos < end {
+			v, err := d.decode(sig[1:])
+			if err != nil {
+				return nil, err
+			}
+			vs = append(vs, v)
+		}
+		return vs, nil
+	case '(', '{':
+		if err := d.align(8); err != nil {
+			return nil, err
+		}
+		return d.decodeAll(sig[1 : len(sig)-1])
+	case 'v':
+		b, err := d.next(1)
+		if err != nil {
+			return nil, err
+		}
+		s, err := d.string(int(b[0]))
+		if err != nil {
+			return nil, err
+		}
+		t, rest, err := nextType(Signature(s))
+		if err != nil || rest != "" {
+			return nil, errors.New("dbus: invalid D-Bus variant signature")
+		}
+		v, err := d.decode(t)
+		return Variant{t, v}, err
+	}
+	return nil, errors.New("dbus: unsupported D-Bus signature " + strconv.Quote(string(sig)))
+}
@@ -0,0 +1,52 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbus
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestMarshal(t *testing.T) {
+	m := &Message{
+		typ:       typeSignal,
+		serial:    7,
+		Path:      "/a/b",
+		Interface: "x.y",
+		Member:    "Z",
+		sig:       "yba{sv}(so)auxdng",
+		Body: []interface{}{
+			byte(3),
+			true,
+			[]interface{}{
+				[]interface{}{"one", Variant{"i", int32(-1)}},
+				[]interface{}{"two", Variant{"as", []interface{}{"a", "bc"}}},
+			},
+			[]interface{}{":1.2", ObjectPath("/p")},
+			[]interface{}{uint32(1), uint32(2)},
+			int64(-5),
+			2.5,
+			int16(-2),
+			Signature("a(ss)"),
+		},
+	}
+	b, err := m.marshal()
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	headerLen := (16 + int(binary.LittleEndian.Uint32(b[12:])) + 7) &^ 7
+	got, err := unmarshal(b, binary.LittleEndian, headerLen)
+	if err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("round trip:\ngot  %#v\nwant %#v", got, m)
+	}
+
+	if _, err := (&Message{sig: "s", Body: []interface{}{int32(1)}}).marshal(); err == nil {
+		t.Error("marshal with a mismatched type: got nil error")
+	}
+}
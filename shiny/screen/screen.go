@@ -55,11 +55,14 @@
 package screen // import "golang.org/x/exp/shiny/screen"
 
 import (
+	"context"
 	"image"
 	"image/color"
 	"image/draw"
 
 	"golang.org/x/image/math/f64"
+	"golang.org/x/mobile/event/lifecycle"
+	"golang.org/x/mobile/event/size"
 )
 
 // TODO: specify image format (Alpha or Gray, not just RGBA) for NewBuffer
@@ -193,12 +196,46 @@ type EventDeque interface {
 	// events, of those types above or of other types, via Send or SendFirst.
 	NextEvent() interface{}
 
-	// TODO: LatestLifecycleEvent? Is that still worth it if the
-	// lifecycle.Event struct type loses its DrawContext field?
-
-	// TODO: LatestSizeEvent?
+	// NextEventContext is like NextEvent, but returns early, with a nil event
+	// and ctx.Err(), if ctx is done before an event is sent. Use a context
+	// with a deadline to wait for an event for a limited time.
+	NextEventContext(ctx context.Context) (interface{}, error)
+
+	// PollEvent returns the next event in the deque, if there is one, without
+	// blocking. Its ok result is whether there was such an event.
+	PollEvent() (event interface{}, ok bool)
+
+	// SetCoalesce sets which redundant events the deque may merge or drop as
+	// they are sent, so that a slow consumer does not fall ever further
+	// behind. The default, zero value, is to keep every event.
+	SetCoalesce(c Coalesce)
+
+	// LatestLifecycleEvent returns the most recently sent lifecycle.Event,
+	// whether or not it has been returned by NextEvent yet. It returns the
+	// zero value if no such event has been sent.
+	LatestLifecycleEvent() lifecycle.Event
+
+	// LatestSizeEvent returns the most recently sent size.Event, whether or
+	// not it has been returned by NextEvent yet. It returns the zero value if
+	// no such event has been sent.
+	LatestSizeEvent() size.Event
 }
 
+// Coalesce is a bitmask of the kinds of redundant events that an EventDeque
+// may merge or drop. It only applies to events sent via Send, not SendFirst.
+type Coalesce uint32
+
+const (
+	// CoalesceSize means to keep only the latest pending size.Event. Sending
+	// a size.Event drops any earlier size.Event that is still in the deque.
+	CoalesceSize Coalesce = 1 << iota
+
+	// CoalesceMouseMove means to merge consecutive mouse moves. Sending a
+	// mouse.Event with Direction mouse.DirNone replaces the last event in the
+	// deque if that is also a move, with the same Button and Modifiers.
+	CoalesceMouseMove
+)
+
 // Window is a top-level, double-buffered GUI window.
 type Window interface {
 	// Release closes the window.
@@ -216,6 +253,49 @@ type Window interface {
 	// Publish flushes any pending Upload and Draw calls to the window, and
 	// swaps the back buffer to the front.
 	Publish() PublishResult
+
+	// RequestFrame asks for a frame timing event, a vsync.Event from the
+	// golang.org/x/exp/shiny/vsync package, to be sent to the window's
+	// EventDeque at the start of the display's next refresh. Multiple calls
+	// before that event is sent result in only one event.
+	//
+	// Apps that animate typically paint in response to that event, via a
+	// vsync.Scheduler, so that they paint at most once per frame.
+	RequestFrame()
+
+	// The methods below manage the window's size, position and state. They
+	// are requests to the underlying window system, which may adjust or
+	// ignore them. For example, a tiling window manager may not allow a
+	// window to be moved. The methods do not block, and any change to the
+	// window's size is reported asynchronously, via a size.Event.
+
+	// Resize requests that the window's content area be resized to the given
+	// size, in pixels.
+	Resize(size image.Point)
+
+	// Move requests that the top-left corner of the window's content area be
+	// moved to the given position, in pixels, relative to the top-left
+	// corner of the screen.
+	Move(p image.Point)
+
+	// SetFullscreen requests that the window enter or leave fullscreen mode.
+	SetFullscreen(fullscreen bool)
+
+	// Minimize requests that the window be minimized (iconified or hidden).
+	Minimize()
+
+	// SetAlwaysOnTop requests that the window be kept above, or no longer
+	// above, other windows.
+	SetAlwaysOnTop(alwaysOnTop bool)
+
+	// SetSizeLimits sets the minimum and maximum size, in pixels, that the
+	// window's content area may be resized to, whether by the user or by the
+	// Resize method. A zero X or Y value means no limit in that dimension.
+	SetSizeLimits(min, max image.Point)
+
+	// SetResizable sets whether the user may resize the window. A window that
+	// is not resizable by the user may still be resized by the Resize method.
+	SetResizable(resizable bool)
 }
 
 // PublishResult is the result of an Window.Publish call.
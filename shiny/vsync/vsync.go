@@ -0,0 +1,88 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package vsync provides frame timing events, and a Scheduler that uses them
+// to pace painting to the display's refresh rate.
+//
+// A window sends an Event only when asked to, via its RequestFrame method, so
+// that an idle window, one that is not animating, uses no CPU.
+package vsync // import "golang.org/x/exp/shiny/vsync"
+
+import (
+	"time"
+
+	"golang.org/x/mobile/event/paint"
+)
+
+// Event is a frame timing event. It is sent at most once per RequestFrame
+// call, at the start of the display's next refresh, which is the time to
+// paint the next frame.
+type Event struct {
+	// Time is when the frame that is about to be painted is expected to be
+	// shown. Animations should be computed for this time, not for when the
+	// Event is received.
+	Time time.Time
+
+	// Interval is the display's refresh period, or zero if that is unknown.
+	Interval time.Duration
+}
+
+// FrameRequester is implemented by a screen.Window. It is a separate interface
+// so that a Scheduler can be tested without a real window.
+type FrameRequester interface {
+	// RequestFrame asks for an Event to be sent at the start of the next
+	// frame.
+	RequestFrame()
+}
+
+// Scheduler coalesces requests for painting a window, so that the window is
+// sent at most one paint.Event per frame.
+//
+// A Scheduler is not safe for concurrent use. It is typically used only in a
+// window's event loop goroutine.
+type Scheduler struct {
+	// Window is the window that is to be painted. It should not be nil.
+	Window FrameRequester
+
+	// Frame is the most recent Event processed by Filter.
+	Frame Event
+
+	requested  bool
+	needsPaint bool
+}
+
+// SchedulePaint records that the window needs painting. A paint.Event will be
+// returned by Filter when the next frame begins. Multiple calls before then
+// result in only one paint.Event.
+func (s *Scheduler) SchedulePaint() {
+	s.needsPaint = true
+	if !s.requested {
+		s.requested = true
+		s.Window.RequestFrame()
+	}
+}
+
+// Filter filters the event. It can return e, a different event, or nil to
+// consume the event.
+//
+// An Event that is due to a SchedulePaint call is converted to a paint.Event.
+// Other Events are consumed. A paint.Event from elsewhere, such as the driver
+// asking for the window to be repainted, is passed through, and satisfies any
+// scheduled paint.
+func (s *Scheduler) Filter(e interface{}) interface{} {
+	switch e := e.(type) {
+	case Event:
+		s.Frame = e
+		s.requested = false
+		if s.needsPaint {
+			s.needsPaint = false
+			return paint.Event{}
+		}
+		return nil
+
+	case paint.Event:
+		s.needsPaint = false
+	}
+	return e
+}
@@ -0,0 +1,60 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vsync
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/paint"
+)
+
+type requester struct {
+	n int
+}
+
+func (r *requester) RequestFrame() { r.n++ }
+
+func TestScheduler(t *testing.T) {
+	r := &requester{}
+	s := &Scheduler{Window: r}
+
+	// Multiple paint requests within a frame coalesce into one RequestFrame
+	// call and one paint.Event.
+	s.SchedulePaint()
+	s.SchedulePaint()
+	s.SchedulePaint()
+	if r.n != 1 {
+		t.Fatalf("after SchedulePaint: RequestFrame calls: got %d, want 1", r.n)
+	}
+	if got := s.Filter(key.Event{Rune: 'a'}); got != (key.Event{Rune: 'a'}) {
+		t.Fatalf("key.Event: got %v, want it passed through", got)
+	}
+	t0 := time.Unix(1000, 0)
+	if got := s.Filter(Event{Time: t0}); got != (paint.Event{}) {
+		t.Fatalf("first frame: got %v, want paint.Event", got)
+	}
+	if s.Frame.Time != t0 {
+		t.Fatalf("Frame.Time: got %v, want %v", s.Frame.Time, t0)
+	}
+
+	// A frame with no scheduled paint is consumed.
+	if got := s.Filter(Event{Time: t0.Add(time.Second / 60)}); got != nil {
+		t.Fatalf("second frame: got %v, want nil", got)
+	}
+
+	// A paint.Event from elsewhere satisfies a scheduled paint.
+	s.SchedulePaint()
+	if r.n != 2 {
+		t.Fatalf("after second SchedulePaint: RequestFrame calls: got %d, want 2", r.n)
+	}
+	if got := s.Filter(paint.Event{External: true}); got != (paint.Event{External: true}) {
+		t.Fatalf("external paint.Event: got %v, want it passed through", got)
+	}
+	if got := s.Filter(Event{}); got != nil {
+		t.Fatalf("third frame: got %v, want nil", got)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+)
+
+// TODO: clip children that extend beyond the Absolute's Rect?
+
+// Absolute is a container widget that lays out each of its children at a
+// fixed position, given by the child's LayoutData, which should be an
+// AbsoluteLayoutData. A child without one is laid out at the top left corner.
+// Children may overlap: later children are painted over earlier ones, and get
+// input events before earlier ones.
+//
+// For example, annotations can be placed over an image by giving them
+// positions in the image's coordinate space.
+type Absolute struct {
+	node.ContainerEmbed
+
+	// theme is the theme from the most recent Layout call. Moving a child
+	// lays it out again without a Layout call on the Absolute.
+	theme *theme.Theme
+}
+
+// NewAbsolute returns a new Absolute widget containing the given children.
+func NewAbsolute(children ...node.Node) *Absolute {
+	w := &Absolute{}
+	w.Wrapper = w
+	for _, c := range children {
+		w.Insert(c, nil)
+	}
+	return w
+}
+
+// Raise moves the child n above all of its siblings.
+func (w *Absolute) Raise(n node.Node) { raise(&w.ContainerEmbed, n) }
+
+// Lower moves the child n below all of its siblings.
+func (w *Absolute) Lower(n node.Node) { lower(&w.ContainerEmbed, n) }
+
+// Move sets the position of the child n, keeping the rest of its
+// AbsoluteLayoutData.
+func (w *Absolute) Move(n node.Node, x, y unit.Value) {
+	c := n.Wrappee()
+	if c.Parent != &w.Embed {
+		panic("widget: Absolute.Move called for a non-child node")
+	}
+	d, _ := c.LayoutData.(AbsoluteLayoutData)
+	d.X, d.Y = x, y
+	c.LayoutData = d
+	c.SetOuterRect(w.theme, d.rect(w.theme, c.OuterSize(w.theme)))
+	c.Wrapper.Layout(w.theme)
+	w.Mark(node.MarkNeedsMeasureLayout | node.MarkNeedsPaintBase)
+}
+
+func (w *Absolute) Measure(t *theme.Theme, widthHint, heightHint int) {
+	mSize := image.Point{}
+	for c := w.FirstChild; c != nil; c = c.NextSibling {
+		c.MeasureOuter(t, node.NoHint, node.NoHint)
+		d, _ := c.LayoutData.(AbsoluteLayoutData)
+		r := d.rect(t, c.OuterSize(t))
+		if mSize.X < r.Max.X {
+			mSize.X = r.Max.X
+		}
+		if mSize.Y < r.Max.Y {
+			mSize.Y = r.Max.Y
+		}
+	}
+	w.MeasuredSize = mSize
+}
+
+func (w *Absolute) Layout(t *theme.Theme) {
+	w.theme = t
+	for c := w.FirstChild; c != nil; c = c.NextSibling {
+		d, _ := c.LayoutData.(AbsoluteLayoutData)
+		c.SetOuterRect(t, d.rect(t, c.OuterSize(t)))
+		c.Wrapper.Layout(t)
+	}
+}
+
+// AbsoluteLayoutData is the node LayoutData type for an Absolute's children.
+type AbsoluteLayoutData struct {
+	// X and Y are the position of the top left corner of the child's outer
+	// rectangle, including its Box, relative to the Absolute's top left
+	// corner.
+	X, Y unit.Value
+
+	// Width and Height are the size of the child's outer rectangle. A zero
+	// Value means to use the child's natural size.
+	Width, Height unit.Value
+}
+
+// rect returns the child's outer rectangle, given its natural outer size.
+func (d *AbsoluteLayoutData) rect(t *theme.Theme, size image.Point) image.Rectangle {
+	if d.Width.F != 0 {
+		size.X = t.Pixels(d.Width).Round()
+	}
+	if d.Height.F != 0 {
+		size.Y = t.Pixels(d.Height).Round()
+	}
+	p := image.Point{t.Pixels(d.X).Round(), t.Pixels(d.Y).Round()}
+	return image.Rectangle{p, p.Add(size)}
+}
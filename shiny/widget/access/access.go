@@ -0,0 +1,242 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package access exports a widget tree's accessibility information, as given
+// by each node's Accessibility method, to assistive technologies such as
+// screen readers.
+//
+// A Tree takes snapshots of a widget tree. Each snapshot is a tree of
+// Objects, one for each exposed node or item, which can be serialized to JSON,
+// such as for testing, or given to an Exporter, such as the atspi package's
+// Bridge, which publishes it to a platform's accessibility service.
+package access // import "golang.org/x/exp/shiny/widget/access"
+
+import (
+	"encoding/json"
+	"image"
+	"io"
+
+	"golang.org/x/exp/shiny/widget/node"
+)
+
+// TODO: incremental snapshots, instead of re-visiting the whole widget tree
+// for every snapshot.
+
+// Exporter publishes snapshots of a window's accessibility tree.
+type Exporter interface {
+	// Export is called, on the window's event loop goroutine, with a new
+	// snapshot after the window is painted, at most ten times per second.
+	// The snapshot is not modified after Export is called, so the Exporter
+	// may use it from other goroutines. Export should not block, as the
+	// event loop waits for it to return.
+	//
+	// run schedules a function to be called later on the window's event loop
+	// goroutine. It can be called from any goroutine, and is typically used
+	// to perform an Object's Actions.
+	Export(root *Object, run func(f func()))
+}
+
+// Object is an exposed node or item in a snapshot of a widget tree.
+type Object struct {
+	// ID identifies the Object's node or item. The same node or item has the
+	// same ID in successive snapshots taken by the same Tree, and the IDs are
+	// positive.
+	ID int
+
+	Role  node.Role
+	Name  string
+	Value string
+	Range *node.Range
+	State node.State
+
+	// Actions are the names of the Object's actions, which are performed by
+	// calling Do.
+	Actions []string
+
+	// Bounds is the Object's position and size, in the window's coordinate
+	// space.
+	Bounds image.Rectangle
+
+	Parent   *Object
+	Children []*Object
+
+	do []func()
+}
+
+// Do performs the i'th action of the Object, and returns whether there is
+// such an action. It must be called on the window's event loop goroutine.
+func (o *Object) Do(i int) bool {
+	if i < 0 || len(o.do) <= i {
+		return false
+	}
+	o.do[i]()
+	return true
+}
+
+// Find returns the Object with the given ID in the tree rooted at o, or nil.
+func (o *Object) Find(id int) *Object {
+	if o.ID == id {
+		return o
+	}
+	for _, c := range o.Children {
+		if x := c.Find(id); x != nil {
+			return x
+		}
+	}
+	return nil
+}
+
+// IndexInParent returns the index of o in its Parent's Children, or -1 if o
+// has no Parent.
+func (o *Object) IndexInParent() int {
+	if o.Parent != nil {
+		for i, c := range o.Parent.Children {
+			if c == o {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+type jsonObject struct {
+	Role     string        `json:"role"`
+	Name     string        `json:"name,omitempty"`
+	Value    string        `json:"value,omitempty"`
+	Range    *jsonRange    `json:"range,omitempty"`
+	State    []string      `json:"state,omitempty"`
+	Actions  []string      `json:"actions,omitempty"`
+	Bounds   [4]int        `json:"bounds"`
+	Children []*jsonObject `json:"children,omitempty"`
+}
+
+type jsonRange struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Step  float64 `json:"step,omitempty"`
+	Value float64 `json:"value"`
+}
+
+func (o *Object) toJSON() *jsonObject {
+	j := &jsonObject{
+		Role:    o.Role.String(),
+		Name:    o.Name,
+		Value:   o.Value,
+		State:   o.State.Names(),
+		Actions: o.Actions,
+		Bounds:  [4]int{o.Bounds.Min.X, o.Bounds.Min.Y, o.Bounds.Max.X, o.Bounds.Max.Y},
+	}
+	if r := o.Range; r != nil {
+		j.Range = &jsonRange{r.Min, r.Max, r.Step, r.Value}
+	}
+	for _, c := range o.Children {
+		j.Children = append(j.Children, c.toJSON())
+	}
+	return j
+}
+
+// MarshalJSON implements the json.Marshaler interface. IDs are omitted, so
+// that the JSON for a widget tree does not depend on which snapshots were
+// taken before. Bounds are given as [minX, minY, maxX, maxY].
+func (o *Object) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.toJSON())
+}
+
+// WriteJSON writes a snapshot of the widget tree rooted at root to w, as
+// indented JSON.
+func WriteJSON(w io.Writer, root node.Node) error {
+	b, err := json.MarshalIndent((&Tree{}).Snapshot(root), "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// Tree takes snapshots of a widget tree. Its zero value is ready to use.
+type Tree struct {
+	ids    map[interface{}]int
+	nextID int
+}
+
+// itemKey identifies a node's item.
+type itemKey struct {
+	n node.Node
+	i int
+}
+
+// Snapshot returns the Object for root, and the Objects of its exposed
+// descendants. If root's Role is node.RoleNone, its Object has the
+// node.RoleWindow Role.
+//
+// Snapshot must be called on the window's event loop goroutine, after the
+// widget tree has been laid out.
+func (t *Tree) Snapshot(root node.Node) *Object {
+	old := t.ids
+	t.ids = map[interface{}]int{}
+	a := root.Accessibility()
+	if a.Role == node.RoleNone {
+		a.Role = node.RoleWindow
+	}
+	e := root.Wrappee()
+	o := t.newObject(old, root, a, e.Rect, e.Focusable)
+	t.visitChildren(old, o, e, e.Rect.Min)
+	t.addItems(old, o, root, a.Items, e.Rect.Min)
+	return o
+}
+
+// visitChildren adds the Objects for e's exposed descendants to o's Children.
+// origin is e's top-left, in the window's coordinate space.
+func (t *Tree) visitChildren(old map[interface{}]int, o *Object, e *node.Embed, origin image.Point) {
+	for c := e.FirstChild; c != nil; c = c.NextSibling {
+		a := c.Wrapper.Accessibility()
+		r := c.Rect.Add(origin)
+		if a.Role == node.RoleNone {
+			t.visitChildren(old, o, c, r.Min)
+			continue
+		}
+		x := t.newObject(old, c.Wrapper, a, r, c.Focusable)
+		x.Parent = o
+		o.Children = append(o.Children, x)
+		t.visitChildren(old, x, c, r.Min)
+		t.addItems(old, x, c.Wrapper, a.Items, r.Min)
+	}
+}
+
+// addItems adds the Objects for n's items to o's Children. origin is n's
+// top-left, in the window's coordinate space.
+func (t *Tree) addItems(old map[interface{}]int, o *Object, n node.Node, items []node.Item, origin image.Point) {
+	for i, item := range items {
+		x := t.newObject(old, itemKey{n, i}, item.Accessibility, item.Bounds.Add(origin), false)
+		x.Parent = o
+		o.Children = append(o.Children, x)
+	}
+}
+
+func (t *Tree) newObject(old map[interface{}]int, key interface{}, a node.Accessibility, bounds image.Rectangle, focusable bool) *Object {
+	id, ok := old[key]
+	if !ok {
+		t.nextID++
+		id = t.nextID
+	}
+	t.ids[key] = id
+
+	o := &Object{
+		ID:     id,
+		Role:   a.Role,
+		Name:   a.Name,
+		Value:  a.Value,
+		Range:  a.Range,
+		State:  a.State,
+		Bounds: bounds,
+	}
+	if focusable {
+		o.State |= node.StateFocusable
+	}
+	for _, action := range a.Actions {
+		o.Actions = append(o.Actions, action.Name)
+		o.do = append(o.do, action.Do)
+	}
+	return o
+}
@@ -0,0 +1,152 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package access
+
+import (
+	"bytes"
+	"image"
+	"testing"
+
+	"golang.org/x/exp/shiny/widget/node"
+)
+
+type testLeaf struct {
+	node.LeafEmbed
+	a node.Accessibility
+}
+
+func newTestLeaf(a node.Accessibility, r image.Rectangle) *testLeaf {
+	w := &testLeaf{a: a}
+	w.Wrapper = w
+	w.Rect = r
+	return w
+}
+
+func (w *testLeaf) Accessibility() node.Accessibility { return w.a }
+
+type testContainer struct {
+	node.ContainerEmbed
+}
+
+func newTestContainer(r image.Rectangle, children ...node.Node) *testContainer {
+	w := &testContainer{}
+	w.Wrapper = w
+	w.Rect = r
+	for _, c := range children {
+		w.Insert(c, nil)
+	}
+	return w
+}
+
+func TestSnapshot(t *testing.T) {
+	clicks := 0
+	button := newTestLeaf(node.Accessibility{
+		Role:    node.RoleButton,
+		Name:    "OK",
+		Actions: []node.Action{{Name: node.ActionClick, Do: func() { clicks++ }}},
+	}, image.Rect(1, 2, 11, 12))
+	button.Focusable = true
+	menu := newTestLeaf(node.Accessibility{
+		Role: node.RoleMenu,
+		Items: []node.Item{{
+			Accessibility: node.Accessibility{Role: node.RoleMenuItem, Name: "Open"},
+			Bounds:        image.Rect(0, 0, 20, 5),
+		}},
+	}, image.Rect(0, 30, 20, 40))
+	// The inner container has RoleNone, so it is flattened away.
+	root := newTestContainer(image.Rect(0, 0, 100, 100),
+		newTestContainer(image.Rect(5, 5, 50, 50), button),
+		menu,
+	)
+
+	tree := &Tree{}
+	o := tree.Snapshot(root)
+	if o.Role != node.RoleWindow {
+		t.Errorf("root role: got %v, want %v", o.Role, node.RoleWindow)
+	}
+	if len(o.Children) != 2 {
+		t.Fatalf("root children: got %d, want 2", len(o.Children))
+	}
+	b := o.Children[0]
+	if b.Name != "OK" || b.Parent != o || b.IndexInParent() != 0 {
+		t.Errorf("button: got %q, parent %p, index %d", b.Name, b.Parent, b.IndexInParent())
+	}
+	if want := image.Rect(6, 7, 16, 17); b.Bounds != want {
+		t.Errorf("button bounds: got %v, want %v", b.Bounds, want)
+	}
+	if b.State&node.StateFocusable == 0 {
+		t.Errorf("button state: got %v, want focusable", b.State.Names())
+	}
+	if !b.Do(0) || clicks != 1 {
+		t.Errorf("Do: got %d clicks, want 1", clicks)
+	}
+	if b.Do(1) {
+		t.Errorf("Do(1): got true, want false")
+	}
+	m := o.Children[1]
+	if len(m.Children) != 1 || m.Children[0].Name != "Open" {
+		t.Fatalf("menu items: got %v", m.Children)
+	}
+	if want := image.Rect(0, 30, 20, 35); m.Children[0].Bounds != want {
+		t.Errorf("item bounds: got %v, want %v", m.Children[0].Bounds, want)
+	}
+
+	// IDs are stable across snapshots.
+	o2 := tree.Snapshot(root)
+	if o2.ID != o.ID || o2.Children[0].ID != b.ID || o2.Children[1].Children[0].ID != m.Children[0].ID {
+		t.Errorf("IDs changed between snapshots")
+	}
+	if o2.Find(b.ID) != o2.Children[0] {
+		t.Errorf("Find: did not find the button")
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	root := newTestContainer(image.Rect(0, 0, 10, 10),
+		newTestLeaf(node.Accessibility{
+			Role:  node.RoleSlider,
+			Value: "5",
+			Range: &node.Range{Min: 0, Max: 10, Value: 5},
+			State: node.StateFocused,
+		}, image.Rect(0, 0, 10, 2)),
+	)
+	buf := new(bytes.Buffer)
+	if err := WriteJSON(buf, root); err != nil {
+		t.Fatal(err)
+	}
+	const want = `{
+	"role": "window",
+	"bounds": [
+		0,
+		0,
+		10,
+		10
+	],
+	"children": [
+		{
+			"role": "slider",
+			"value": "5",
+			"range": {
+				"min": 0,
+				"max": 10,
+				"value": 5
+			},
+			"state": [
+				"focused"
+			],
+			"bounds": [
+				0,
+				0,
+				10,
+				2
+			]
+		}
+	]
+}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
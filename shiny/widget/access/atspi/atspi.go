@@ -0,0 +1,735 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package atspi publishes a widget tree's accessibility information to the
+// AT-SPI accessibility service, used by screen readers such as Orca on Linux
+// and other free desktops, over D-Bus.
+//
+// A Bridge is an access.Exporter, typically given to a window by the
+// widget.RunWindowOptions' Accessibility field:
+//
+//	b, err := atspi.Connect("My App")
+//	if err != nil {
+//		// Assistive technologies are not available.
+//	}
+//	defer b.Close()
+//	widget.RunWindow(s, root, &widget.RunWindowOptions{Accessibility: b})
+//
+// See https://www.freedesktop.org/wiki/Accessibility/AT-SPI2/
+package atspi // import "golang.org/x/exp/shiny/widget/access/atspi"
+
+// TODO: the Text, EditableText, Selection and Cache interfaces.
+
+// TODO: screen coordinates. Bounds are given in window coordinates for both
+// the screen and window coordinate types, as the window's position is not
+// known.
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/exp/shiny/internal/dbus"
+	"golang.org/x/exp/shiny/widget/access"
+	"golang.org/x/exp/shiny/widget/node"
+)
+
+const (
+	rootPath   = dbus.ObjectPath("/org/a11y/atspi/accessible/root")
+	nullPath   = dbus.ObjectPath("/org/a11y/atspi/null")
+	objectBase = "/org/a11y/atspi/accessible/"
+
+	ifaceAccessible  = "org.a11y.atspi.Accessible"
+	ifaceAction      = "org.a11y.atspi.Action"
+	ifaceApplication = "org.a11y.atspi.Application"
+	ifaceComponent   = "org.a11y.atspi.Component"
+	ifaceValue       = "org.a11y.atspi.Value"
+	ifaceEvent       = "org.a11y.atspi.Event.Object"
+	ifaceProperties  = "org.freedesktop.DBus.Properties"
+	ifaceIntrospect  = "org.freedesktop.DBus.Introspectable"
+	ifacePeer        = "org.freedesktop.DBus.Peer"
+
+	errUnknownObject = "org.freedesktop.DBus.Error.UnknownObject"
+	errUnknownMethod = "org.freedesktop.DBus.Error.UnknownMethod"
+	errInvalidArgs   = "org.freedesktop.DBus.Error.InvalidArgs"
+)
+
+// Bridge is an access.Exporter that serves its most recently exported
+// snapshot to the AT-SPI accessibility service. Changes between snapshots are
+// sent as AT-SPI events, on the Bridge's own goroutine, so that a slow bus
+// does not block the window's event loop.
+//
+// The snapshot's root Object, typically a window, is the only child of the
+// Bridge's application object.
+type Bridge struct {
+	c       *dbus.Conn
+	appName string
+
+	mu      sync.Mutex
+	root    *access.Object
+	objects map[int]*access.Object
+	run     func(func())
+
+	// exported is signalled by Export when there is a new snapshot to send
+	// the events for. done is closed by Close.
+	exported  chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// parent is the desktop object that the application is embedded in, and
+	// id is the application's ID, as set by the AT-SPI registry.
+	parent []interface{}
+	id     int32
+}
+
+// Connect returns a new Bridge that is connected to the accessibility bus of
+// the user's session, and registered with the AT-SPI registry as an
+// application with the given name.
+//
+// The accessibility bus's address is taken from the AT_SPI_BUS_ADDRESS
+// environment variable or, if that is not set, asked for from the session
+// bus given by the DBUS_SESSION_BUS_ADDRESS environment variable.
+func Connect(appName string) (*Bridge, error) {
+	addr := os.Getenv("AT_SPI_BUS_ADDRESS")
+	if addr == "" {
+		session := os.Getenv("DBUS_SESSION_BUS_ADDRESS")
+		if session == "" {
+			return nil, errors.New("atspi: no D-Bus session bus")
+		}
+		c, err := dbus.Dial(session, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		body, err := c.Call("org.a11y.Bus", "/org/a11y/bus", "org.a11y.Bus", "GetAddress", "")
+		c.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(body) != 1 {
+			return nil, errors.New("atspi: invalid GetAddress reply")
+		}
+		addr, _ = body[0].(string)
+	}
+
+	b, err := Dial(addr, appName)
+	if err != nil {
+		return nil, err
+	}
+	body, err := b.c.Call("org.a11y.atspi.Registry", rootPath, "org.a11y.atspi.Socket", "Embed",
+		"(so)", b.ref(rootPath))
+	if err != nil {
+		b.Close()
+		return nil, err
+	}
+	if len(body) == 1 {
+		b.mu.Lock()
+		b.parent, _ = body[0].([]interface{})
+		b.mu.Unlock()
+	}
+	return b, nil
+}
+
+// Dial returns a new Bridge that is connected to the D-Bus bus with the given
+// address, but not registered with the AT-SPI registry. It is typically used
+// for testing, with a private bus.
+func Dial(address, appName string) (*Bridge, error) {
+	b := &Bridge{
+		appName:  appName,
+		exported: make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	c, err := dbus.Dial(address, b.handle, nil)
+	if err != nil {
+		return nil, err
+	}
+	b.c = c
+	go b.emitLoop()
+	return b, nil
+}
+
+// Close closes the Bridge's D-Bus connection.
+func (b *Bridge) Close() error {
+	b.closeOnce.Do(func() { close(b.done) })
+	return b.c.Close()
+}
+
+// Export implements the access.Exporter interface. It does not block: the
+// snapshot is served from now on, and the events for its changes are sent
+// later, on the Bridge's goroutine. If several snapshots are exported before
+// then, only the differences between the first and the last are sent.
+func (b *Bridge) Export(root *access.Object, run func(f func())) {
+	objects := map[int]*access.Object{}
+	var visit func(o *access.Object)
+	visit = func(o *access.Object) {
+		objects[o.ID] = o
+		for _, c := range o.Children {
+			visit(c)
+		}
+	}
+	visit(root)
+
+	b.mu.Lock()
+	b.root, b.objects, b.run = root, objects, run
+	b.mu.Unlock()
+
+	select {
+	case b.exported <- struct{}{}:
+	default:
+		// The goroutine has yet to handle a previous signal, and will see
+		// this snapshot when it does.
+	}
+}
+
+// emitLoop sends the events for each exported snapshot, until the Bridge is
+// closed.
+func (b *Bridge) emitLoop() {
+	var oldRoot *access.Object
+	var oldObjects map[int]*access.Object
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-b.exported:
+		}
+		b.mu.Lock()
+		root, objects := b.root, b.objects
+		b.mu.Unlock()
+
+		if oldRoot == nil || oldRoot.ID != root.ID {
+			if oldRoot != nil {
+				b.emitChildrenChanged(rootPath, "remove", 0, oldRoot)
+			}
+			b.emitChildrenChanged(rootPath, "add", 0, root)
+		}
+		for id, o := range objects {
+			if old := oldObjects[id]; old != nil {
+				b.emitChanges(old, o)
+			}
+		}
+		oldRoot, oldObjects = root, objects
+	}
+}
+
+// emitChanges sends the events for the differences between two snapshots of
+// the same Object.
+func (b *Bridge) emitChanges(old, o *access.Object) {
+	path := b.path(o)
+	if old.Name != o.Name {
+		b.c.Emit(path, ifaceEvent, "PropertyChange", "siiva{sv}",
+			"accessible-name", int32(0), int32(0), dbus.Variant{Sig: "s", Value: o.Name}, []interface{}{})
+	}
+	if old.Value != o.Value {
+		b.c.Emit(path, ifaceEvent, "PropertyChange", "siiva{sv}",
+			"accessible-value", int32(0), int32(0), dbus.Variant{Sig: "s", Value: o.Value}, []interface{}{})
+	}
+	for _, s := range []struct {
+		state node.State
+		name  string
+	}{
+		// The disabled state is emitted as its inverse.
+		{node.StateDisabled, "enabled"},
+		{node.StateFocused, "focused"},
+		{node.StateChecked, "checked"},
+		{node.StateSelected, "selected"},
+		{node.StatePressed, "pressed"},
+	} {
+		was, is := old.State&s.state != 0, o.State&s.state != 0
+		if was == is {
+			continue
+		}
+		if s.state == node.StateDisabled {
+			was, is = is, was
+		}
+		detail := int32(0)
+		if is {
+			detail = 1
+		}
+		b.c.Emit(path, ifaceEvent, "StateChanged", "siiva{sv}",
+			s.name, detail, int32(0), dbus.Variant{Sig: "i", Value: int32(0)}, []interface{}{})
+	}
+
+	if !sameChildren(old, o) {
+		for i := len(old.Children) - 1; i >= 0; i-- {
+			b.emitChildrenChanged(path, "remove", i, old.Children[i])
+		}
+		for i, c := range o.Children {
+			b.emitChildrenChanged(path, "add", i, c)
+		}
+	}
+}
+
+func (b *Bridge) emitChildrenChanged(path dbus.ObjectPath, detail string, i int, child *access.Object) {
+	b.c.Emit(path, ifaceEvent, "ChildrenChanged", "siiva{sv}",
+		detail, int32(i), int32(0), dbus.Variant{Sig: "(so)", Value: b.ref(b.path(child))}, []interface{}{})
+}
+
+func sameChildren(x, y *access.Object) bool {
+	if len(x.Children) != len(y.Children) {
+		return false
+	}
+	for i := range x.Children {
+		if x.Children[i].ID != y.Children[i].ID {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *Bridge) path(o *access.Object) dbus.ObjectPath {
+	if o == nil {
+		return rootPath
+	}
+	return dbus.ObjectPath(objectBase + strconv.Itoa(o.ID))
+}
+
+// ref returns an AT-SPI object reference, which is a bus name and an object
+// path.
+func (b *Bridge) ref(path dbus.ObjectPath) []interface{} {
+	return []interface{}{b.c.Name, path}
+}
+
+func nullRef() []interface{} {
+	return []interface{}{"", nullPath}
+}
+
+// handle handles a method call. A nil Object means the application object.
+func (b *Bridge) handle(m *dbus.Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var o *access.Object
+	if m.Path != rootPath {
+		if strings.HasPrefix(string(m.Path), objectBase) {
+			if id, err := strconv.Atoi(string(m.Path[len(objectBase):])); err == nil {
+				o = b.objects[id]
+			}
+		}
+		if o == nil {
+			b.c.ReplyError(m, errUnknownObject, string(m.Path))
+			return
+		}
+	}
+
+	var err error
+	switch m.Interface {
+	case ifaceAccessible:
+		err = b.handleAccessible(m, o)
+	case ifaceComponent:
+		err = b.handleComponent(m, o)
+	case ifaceAction:
+		err = b.handleAction(m, o)
+	case ifaceProperties:
+		err = b.handleProperties(m, o)
+	case ifaceIntrospect:
+		if m.Member == "Introspect" {
+			err = b.c.Reply(m, "s", introspect(b.interfaces(o)))
+		} else {
+			err = errUnknown
+		}
+	case ifacePeer:
+		if m.Member == "Ping" {
+			err = b.c.Reply(m, "")
+		} else {
+			err = errUnknown
+		}
+	default:
+		err = errUnknown
+	}
+	switch err {
+	case errUnknown:
+		b.c.ReplyError(m, errUnknownMethod, m.Interface+"."+m.Member)
+	case errArgs:
+		b.c.ReplyError(m, errInvalidArgs, m.Interface+"."+m.Member)
+	}
+}
+
+var (
+	errUnknown = errors.New("atspi: unknown method")
+	errArgs    = errors.New("atspi: invalid arguments")
+)
+
+func (b *Bridge) children(o *access.Object) []*access.Object {
+	if o == nil {
+		if b.root == nil {
+			return nil
+		}
+		return []*access.Object{b.root}
+	}
+	return o.Children
+}
+
+func (b *Bridge) parentRef(o *access.Object) []interface{} {
+	switch {
+	case o == nil:
+		if b.parent != nil {
+			return b.parent
+		}
+		return nullRef()
+	case o.Parent == nil:
+		return b.ref(rootPath)
+	}
+	return b.ref(b.path(o.Parent))
+}
+
+func (b *Bridge) interfaces(o *access.Object) []interface{} {
+	if o == nil {
+		return []interface{}{ifaceAccessible, ifaceApplication}
+	}
+	ifaces := []interface{}{ifaceAccessible, ifaceComponent}
+	if len(o.Actions) > 0 {
+		ifaces = append(ifaces, ifaceAction)
+	}
+	if o.Range != nil {
+		ifaces = append(ifaces, ifaceValue)
+	}
+	return ifaces
+}
+
+func (b *Bridge) handleAccessible(m *dbus.Message, o *access.Object) error {
+	switch m.Member {
+	case "GetChildAtIndex":
+		i, ok := intArg(m, 0)
+		if !ok {
+			return errArgs
+		}
+		children := b.children(o)
+		ref := nullRef()
+		if 0 <= i && i < len(children) {
+			ref = b.ref(b.path(children[i]))
+		}
+		return b.c.Reply(m, "(so)", ref)
+	case "GetChildren":
+		refs := []interface{}{}
+		for _, c := range b.children(o) {
+			refs = append(refs, b.ref(b.path(c)))
+		}
+		return b.c.Reply(m, "a(so)", refs)
+	case "GetIndexInParent":
+		i := int32(0)
+		if o != nil && o.Parent != nil {
+			i = int32(o.IndexInParent())
+		} else if o == nil {
+			i = -1
+		}
+		return b.c.Reply(m, "i", i)
+	case "GetRelationSet":
+		return b.c.Reply(m, "a(ua(so))", []interface{}{})
+	case "GetRole":
+		return b.c.Reply(m, "u", role(o).id)
+	case "GetRoleName", "GetLocalizedRoleName":
+		return b.c.Reply(m, "s", role(o).name)
+	case "GetState":
+		s := states(o)
+		return b.c.Reply(m, "au", []interface{}{uint32(s), uint32(s >> 32)})
+	case "GetAttributes":
+		return b.c.Reply(m, "a{ss}", []interface{}{
+			[]interface{}{"toolkit", "shiny"},
+		})
+	case "GetApplication":
+		return b.c.Reply(m, "(so)", b.ref(rootPath))
+	case "GetInterfaces":
+		return b.c.Reply(m, "as", b.interfaces(o))
+	}
+	return errUnknown
+}
+
+func (b *Bridge) handleComponent(m *dbus.Message, o *access.Object) error {
+	if o == nil {
+		return errUnknown
+	}
+	r := o.Bounds
+	switch m.Member {
+	case "GetExtents":
+		return b.c.Reply(m, "(iiii)", []interface{}{
+			int32(r.Min.X), int32(r.Min.Y), int32(r.Dx()), int32(r.Dy()),
+		})
+	case "GetPosition":
+		return b.c.Reply(m, "ii", int32(r.Min.X), int32(r.Min.Y))
+	case "GetSize":
+		return b.c.Reply(m, "ii", int32(r.Dx()), int32(r.Dy()))
+	case "Contains":
+		x, ok0 := intArg(m, 0)
+		y, ok1 := intArg(m, 1)
+		if !ok0 || !ok1 {
+			return errArgs
+		}
+		return b.c.Reply(m, "b", r.Min.X <= x && x < r.Max.X && r.Min.Y <= y && y < r.Max.Y)
+	case "GetAccessibleAtPoint":
+		x, ok0 := intArg(m, 0)
+		y, ok1 := intArg(m, 1)
+		if !ok0 || !ok1 {
+			return errArgs
+		}
+		ref := nullRef()
+		if at := objectAt(o, x, y); at != nil && at != o {
+			ref = b.ref(b.path(at))
+		}
+		return b.c.Reply(m, "(so)", ref)
+	case "GetLayer":
+		// ATSPI_LAYER_WIDGET and ATSPI_LAYER_WINDOW.
+		layer := uint32(3)
+		if o.Parent == nil {
+			layer = 7
+		}
+		return b.c.Reply(m, "u", layer)
+	case "GetMDIZOrder":
+		return b.c.Reply(m, "n", int16(0))
+	case "GrabFocus":
+		return b.c.Reply(m, "b", false)
+	case "GetAlpha":
+		return b.c.Reply(m, "d", 1.0)
+	}
+	return errUnknown
+}
+
+// objectAt returns the deepest descendant of o, or o itself, whose Bounds
+// contain (x, y), or nil.
+func objectAt(o *access.Object, x, y int) *access.Object {
+	r := o.Bounds
+	if x < r.Min.X || r.Max.X <= x || y < r.Min.Y || r.Max.Y <= y {
+		return nil
+	}
+	for i := len(o.Children) - 1; i >= 0; i-- {
+		if at := objectAt(o.Children[i], x, y); at != nil {
+			return at
+		}
+	}
+	return o
+}
+
+func (b *Bridge) handleAction(m *dbus.Message, o *access.Object) error {
+	if o == nil {
+		return errUnknown
+	}
+	switch m.Member {
+	case "GetActions":
+		actions := []interface{}{}
+		for _, a := range o.Actions {
+			actions = append(actions, []interface{}{a, "", ""})
+		}
+		return b.c.Reply(m, "a(sss)", actions)
+	case "GetName", "GetLocalizedName", "GetDescription", "GetKeyBinding":
+		i, ok := intArg(m, 0)
+		if !ok {
+			return errArgs
+		}
+		s := ""
+		if (m.Member == "GetName" || m.Member == "GetLocalizedName") && 0 <= i && i < len(o.Actions) {
+			s = o.Actions[i]
+		}
+		return b.c.Reply(m, "s", s)
+	case "DoAction":
+		i, ok := intArg(m, 0)
+		if !ok {
+			return errArgs
+		}
+		if i < 0 || len(o.Actions) <= i || b.run == nil {
+			return b.c.Reply(m, "b", false)
+		}
+		b.run(func() { o.Do(i) })
+		return b.c.Reply(m, "b", true)
+	}
+	return errUnknown
+}
+
+func (b *Bridge) handleProperties(m *dbus.Message, o *access.Object) error {
+	switch m.Member {
+	case "Get":
+		if len(m.Body) != 2 {
+			return errArgs
+		}
+		iface, _ := m.Body[0].(string)
+		name, _ := m.Body[1].(string)
+		for _, p := range b.properties(o, iface) {
+			if p := p.([]interface{}); p[0] == name {
+				return b.c.Reply(m, "v", p[1])
+			}
+		}
+		return errArgs
+	case "GetAll":
+		if len(m.Body) != 1 {
+			return errArgs
+		}
+		iface, _ := m.Body[0].(string)
+		return b.c.Reply(m, "a{sv}", b.properties(o, iface))
+	case "Set":
+		if len(m.Body) != 3 {
+			return errArgs
+		}
+		iface, _ := m.Body[0].(string)
+		name, _ := m.Body[1].(string)
+		v, _ := m.Body[2].(dbus.Variant)
+		if id, ok := v.Value.(int32); ok && o == nil && iface == ifaceApplication && name == "Id" {
+			b.id = id
+			return b.c.Reply(m, "")
+		}
+		return errArgs
+	}
+	return errUnknown
+}
+
+// properties returns the name and value of each of the interface iface's
+// properties, as an a{sv}.
+func (b *Bridge) properties(o *access.Object, iface string) []interface{} {
+	var ps []interface{}
+	add := func(name string, sig dbus.Signature, v interface{}) {
+		ps = append(ps, []interface{}{name, dbus.Variant{Sig: sig, Value: v}})
+	}
+	switch iface {
+	case ifaceAccessible:
+		name, description := b.appName, ""
+		if o != nil {
+			name, description = o.Name, o.Value
+		}
+		add("Name", "s", name)
+		add("Description", "s", description)
+		add("Parent", "(so)", b.parentRef(o))
+		add("ChildCount", "i", int32(len(b.children(o))))
+		add("Locale", "s", "")
+		add("AccessibleId", "s", "")
+		add("HelpText", "s", "")
+	case ifaceApplication:
+		if o == nil {
+			add("ToolkitName", "s", "shiny")
+			add("Version", "s", "")
+			add("AtspiVersion", "s", "2.1")
+			add("Id", "i", b.id)
+		}
+	case ifaceAction:
+		if o != nil {
+			add("NActions", "i", int32(len(o.Actions)))
+		}
+	case ifaceValue:
+		if o != nil && o.Range != nil {
+			add("MinimumValue", "d", o.Range.Min)
+			add("MaximumValue", "d", o.Range.Max)
+			add("MinimumIncrement", "d", o.Range.Step)
+			add("CurrentValue", "d", o.Range.Value)
+			add("Text", "s", o.Value)
+		}
+	}
+	if ps == nil {
+		ps = []interface{}{}
+	}
+	return ps
+}
+
+// intArg returns the i'th argument of m, which should be an int32 or a
+// uint32.
+func intArg(m *dbus.Message, i int) (int, bool) {
+	if i >= len(m.Body) {
+		return 0, false
+	}
+	switch x := m.Body[i].(type) {
+	case int32:
+		return int(x), true
+	case uint32:
+		return int(x), true
+	}
+	return 0, false
+}
+
+func introspect(ifaces []interface{}) string {
+	s := `<!DOCTYPE node PUBLIC "-//freedesktop//DTD D-BUS Object Introspection 1.0//EN" ` +
+		`"http://www.freedesktop.org/standards/dbus/1.0/introspect.dtd">` + "\n<node>\n"
+	for _, iface := range ifaces {
+		s += "  <interface name=\"" + iface.(string) + "\"/>\n"
+	}
+	return s + "</node>\n"
+}
+
+// atspiRole is an AT-SPI role, from the AtspiRole enumeration.
+type atspiRole struct {
+	id   uint32
+	name string
+}
+
+var roles = [...]atspiRole{
+	node.RoleNone:        {67, "unknown"},
+	node.RoleWindow:      {23, "frame"},
+	node.RoleGroup:       {39, "panel"},
+	node.RoleLabel:       {29, "label"},
+	node.RoleText:        {73, "paragraph"},
+	node.RoleTextField:   {61, "text"},
+	node.RoleButton:      {43, "push button"},
+	node.RoleCheckBox:    {7, "check box"},
+	node.RoleRadioButton: {44, "radio button"},
+	node.RoleSwitch:      {62, "toggle button"},
+	node.RoleSlider:      {51, "slider"},
+	node.RoleList:        {31, "list"},
+	node.RoleListItem:    {32, "list item"},
+	node.RoleMenu:        {41, "popup menu"},
+	node.RoleMenuItem:    {35, "menu item"},
+	node.RoleDialog:      {16, "dialog"},
+	node.RoleScrollPane:  {49, "scroll pane"},
+	node.RoleImage:       {27, "image"},
+}
+
+func role(o *access.Object) atspiRole {
+	if o == nil {
+		return atspiRole{75, "application"}
+	}
+	if int(o.Role) < len(roles) {
+		return roles[o.Role]
+	}
+	return roles[node.RoleNone]
+}
+
+// AT-SPI states, from the AtspiStateType enumeration.
+const (
+	stateActive          = 1
+	stateChecked         = 4
+	stateEditable        = 7
+	stateEnabled         = 8
+	stateFocusable       = 11
+	stateFocused         = 12
+	stateModal           = 16
+	stateMultiLine       = 17
+	stateMultiSelectable = 18
+	statePressed         = 20
+	stateSelected        = 23
+	stateSensitive       = 24
+	stateShowing         = 25
+	stateSingleLine      = 26
+	stateVisible         = 30
+)
+
+// states returns the AT-SPI states of o, as a 64-bit set.
+func states(o *access.Object) uint64 {
+	if o == nil {
+		return 0
+	}
+	s := uint64(1<<stateVisible | 1<<stateShowing)
+	if o.Parent == nil {
+		// TODO: track whether the window is active.
+		s |= 1 << stateActive
+	}
+	if o.State&node.StateDisabled == 0 {
+		s |= 1<<stateEnabled | 1<<stateSensitive
+	}
+	for _, x := range []struct {
+		state node.State
+		atspi uint
+	}{
+		{node.StateFocusable, stateFocusable},
+		{node.StateFocused, stateFocused},
+		{node.StateChecked, stateChecked},
+		{node.StateSelected, stateSelected},
+		{node.StatePressed, statePressed},
+		{node.StateEditable, stateEditable},
+		{node.StateMultiLine, stateMultiLine},
+		{node.StateMultiSelectable, stateMultiSelectable},
+		{node.StateModal, stateModal},
+	} {
+		if o.State&x.state != 0 {
+			s |= 1 << x.atspi
+		}
+	}
+	if o.State&node.StateEditable != 0 && o.State&node.StateMultiLine == 0 {
+		s |= 1 << stateSingleLine
+	}
+	return s
+}
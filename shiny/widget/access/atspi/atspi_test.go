@@ -0,0 +1,196 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package atspi
+
+import (
+	"bufio"
+	"image"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/shiny/internal/dbus"
+	"golang.org/x/exp/shiny/widget/access"
+	"golang.org/x/exp/shiny/widget/node"
+)
+
+// startBus starts a private D-Bus bus, and returns its address and a function
+// that stops it.
+func startBus(t *testing.T) (addr string, stop func()) {
+	daemon, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon not found")
+	}
+	dir, err := ioutil.TempDir("", "atspi-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := filepath.Join(dir, "bus.conf")
+	err = ioutil.WriteFile(config, []byte(`<!DOCTYPE busconfig PUBLIC "-//freedesktop//DTD D-Bus Bus Configuration 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/busconfig.dtd">
+<busconfig>
+  <type>session</type>
+  <listen>unix:path=`+filepath.Join(dir, "bus")+`</listen>
+  <auth>EXTERNAL</auth>
+  <policy context="default">
+    <allow send_destination="*" eavesdrop="true"/>
+    <allow eavesdrop="true"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(daemon, "--config-file="+config, "--nofork", "--print-address")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		os.RemoveAll(dir)
+		t.Skipf("starting dbus-daemon: %v", err)
+	}
+	stop = func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+		os.RemoveAll(dir)
+	}
+	addr, err = bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		stop()
+		t.Skipf("reading dbus-daemon address: %v", err)
+	}
+	return strings.TrimSpace(addr), stop
+}
+
+type testLeaf struct {
+	node.LeafEmbed
+	a node.Accessibility
+}
+
+func newTestLeaf(a node.Accessibility, r image.Rectangle) *testLeaf {
+	w := &testLeaf{a: a}
+	w.Wrapper = w
+	w.Rect = r
+	return w
+}
+
+func (w *testLeaf) Accessibility() node.Accessibility { return w.a }
+
+type testContainer struct {
+	node.ContainerEmbed
+}
+
+func TestBridge(t *testing.T) {
+	addr, stop := startBus(t)
+	defer stop()
+
+	b, err := Dial(addr, "Test")
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer b.Close()
+
+	signals := make(chan *dbus.Message, 100)
+	client, err := dbus.Dial(addr, nil, func(m *dbus.Message) { signals <- m })
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer client.Close()
+	_, err = client.Call("org.freedesktop.DBus", "/org/freedesktop/DBus", "org.freedesktop.DBus", "AddMatch",
+		"s", "type='signal',interface='"+ifaceEvent+"'")
+	if err != nil {
+		t.Fatalf("AddMatch: %v", err)
+	}
+
+	clicks := 0
+	button := newTestLeaf(node.Accessibility{
+		Role:    node.RoleButton,
+		Name:    "OK",
+		Actions: []node.Action{{Name: node.ActionClick, Do: func() { clicks++ }}},
+	}, image.Rect(10, 20, 50, 40))
+	root := &testContainer{}
+	root.Wrapper = root
+	root.Rect = image.Rect(0, 0, 100, 100)
+	root.Insert(newTestLeaf(node.Accessibility{Role: node.RoleLabel, Name: "Hello"}, image.Rect(0, 0, 10, 10)), nil)
+	root.Insert(button, nil)
+
+	var queued []func()
+	run := func(f func()) { queued = append(queued, f) }
+	tree := &access.Tree{}
+	b.Export(tree.Snapshot(root), run)
+
+	call := func(path dbus.ObjectPath, iface, member string, sig dbus.Signature, args ...interface{}) []interface{} {
+		t.Helper()
+		body, err := client.Call(b.c.Name, path, iface, member, sig, args...)
+		if err != nil {
+			t.Fatalf("%s.%s on %s: %v", iface, member, path, err)
+		}
+		return body
+	}
+	childPath := func(path dbus.ObjectPath, i int) dbus.ObjectPath {
+		t.Helper()
+		ref := call(path, ifaceAccessible, "GetChildAtIndex", "i", int32(i))[0].([]interface{})
+		return ref[1].(dbus.ObjectPath)
+	}
+
+	if got := call(rootPath, ifaceAccessible, "GetRole", "")[0]; got != uint32(75) {
+		t.Errorf("application role: got %v, want 75", got)
+	}
+	window := childPath(rootPath, 0)
+	if got := call(window, ifaceAccessible, "GetChildren", "")[0].([]interface{}); len(got) != 2 {
+		t.Fatalf("window children: got %d, want 2", len(got))
+	}
+	buttonPath := childPath(window, 1)
+	if got := call(buttonPath, ifaceAccessible, "GetRole", "")[0]; got != uint32(43) {
+		t.Errorf("button role: got %v, want 43", got)
+	}
+	if got := call(buttonPath, ifaceProperties, "Get", "ss", ifaceAccessible, "Name")[0].(dbus.Variant).Value; got != "OK" {
+		t.Errorf("button name: got %q, want \"OK\"", got)
+	}
+	extents := call(buttonPath, ifaceComponent, "GetExtents", "u", uint32(1))[0]
+	if want := []interface{}{int32(10), int32(20), int32(40), int32(20)}; !reflect.DeepEqual(extents, want) {
+		t.Errorf("button extents: got %v, want %v", extents, want)
+	}
+	if got := call(window, ifaceComponent, "GetAccessibleAtPoint", "iiu", int32(15), int32(25), uint32(1))[0].([]interface{}); got[1] != buttonPath {
+		t.Errorf("accessible at point: got %v, want %v", got[1], buttonPath)
+	}
+
+	if got := call(buttonPath, ifaceAction, "DoAction", "i", int32(0))[0]; got != true {
+		t.Errorf("DoAction: got %v, want true", got)
+	}
+	if len(queued) != 1 || clicks != 0 {
+		t.Fatalf("DoAction: got %d queued, %d clicks, want 1, 0", len(queued), clicks)
+	}
+	queued[0]()
+	if clicks != 1 {
+		t.Errorf("DoAction: got %d clicks, want 1", clicks)
+	}
+
+	// Changing the button's state sends an event.
+	for len(signals) > 0 {
+		<-signals
+	}
+	button.a.State = node.StateFocused
+	b.Export(tree.Snapshot(root), run)
+	if got := call(buttonPath, ifaceAccessible, "GetState", "")[0].([]interface{}); got[0].(uint32)&(1<<stateFocused) == 0 {
+		t.Errorf("GetState: got %v, want focused", got)
+	}
+	select {
+	case m := <-signals:
+		if m.Member != "StateChanged" || m.Path != buttonPath || m.Body[0] != "focused" || m.Body[1] != int32(1) {
+			t.Errorf("signal: got %s %s %v", m.Member, m.Path, m.Body)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("signal: timed out")
+	}
+}
@@ -0,0 +1,403 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package anim provides animations of widget properties, such as a widget's
+// position, size, color or opacity, over time.
+//
+// An Animation is advanced by an Animator, which is typically ticked once per
+// frame by the widget.RunWindow event loop. After advancing each Animation,
+// the Animator marks that Animation's node, so that it is painted again.
+// Animating a property that only affects the effects pass, such as a
+// widget.Sheet's Offset or Transparency, needs only a node.MarkNeedsPaint,
+// and re-uses the Sheet's cached pixel buffer instead of calling PaintBase
+// again.
+package anim // import "golang.org/x/exp/shiny/widget/anim"
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"time"
+
+	"golang.org/x/exp/shiny/widget/node"
+)
+
+// TODO: reversing, repeating and cancelling with a completion callback.
+
+// Animation is a property that changes over time.
+type Animation interface {
+	// Step sets the animated property to its value at d, the time elapsed
+	// since the Animation started. It returns whether the Animation has
+	// finished. Step is called with non-decreasing values of d, unless the
+	// Animation is restarted, in which case d decreases.
+	Step(d time.Duration) (done bool)
+}
+
+// Easing maps an animation's linear progress, in the range [0, 1], to its
+// eased progress. An Easing should map 0 to 0 and 1 to 1, but may overshoot
+// in between.
+type Easing func(t float64) float64
+
+var (
+	// Linear is the identity Easing.
+	Linear Easing = func(t float64) float64 { return t }
+	// EaseIn starts slowly and finishes quickly.
+	EaseIn = CubicBezier(0.42, 0, 1, 1)
+	// EaseOut starts quickly and finishes slowly.
+	EaseOut = CubicBezier(0, 0, 0.58, 1)
+	// EaseInOut starts and finishes slowly.
+	EaseInOut = CubicBezier(0.42, 0, 0.58, 1)
+)
+
+// CubicBezier returns the Easing given by the cubic Bézier curve from (0, 0)
+// to (1, 1) with control points (x1, y1) and (x2, y2), like the CSS
+// cubic-bezier timing function. x1 and x2 should be in the range [0, 1].
+func CubicBezier(x1, y1, x2, y2 float64) Easing {
+	// bezier returns the coordinate, at parameter s, of the 1-dimensional
+	// curve with end points 0 and 1 and control points p1 and p2.
+	bezier := func(p1, p2, s float64) float64 {
+		r := 1 - s
+		return 3*r*r*s*p1 + 3*r*s*s*p2 + s*s*s
+	}
+	return func(t float64) float64 {
+		if t <= 0 {
+			return 0
+		}
+		if t >= 1 {
+			return 1
+		}
+		// Find the parameter s whose x coordinate is t, by bisection, as
+		// x is monotonic in s when x1 and x2 are in [0, 1].
+		lo, hi := 0.0, 1.0
+		for i := 0; i < 32; i++ {
+			s := (lo + hi) / 2
+			if bezier(x1, x2, s) < t {
+				lo = s
+			} else {
+				hi = s
+			}
+		}
+		return bezier(y1, y2, (lo+hi)/2)
+	}
+}
+
+// Tween is an Animation that interpolates between two values over a fixed
+// duration.
+type Tween struct {
+	// Duration is the length of the animation. A non-positive Duration means
+	// that the animation jumps to its end value.
+	Duration time.Duration
+
+	// Easing is the Tween's easing curve. A nil Easing means Linear.
+	Easing Easing
+
+	// Apply is called with the eased progress, from 0 at the start to 1 at
+	// the end. It should set the animated property, typically with a helper
+	// function such as Float, Point or RGBA.
+	Apply func(f float64)
+}
+
+// NewTween returns a new Tween.
+func NewTween(duration time.Duration, easing Easing, apply func(f float64)) *Tween {
+	return &Tween{
+		Duration: duration,
+		Easing:   easing,
+		Apply:    apply,
+	}
+}
+
+func (a *Tween) Step(d time.Duration) (done bool) {
+	t := 1.0
+	if d < a.Duration {
+		t = float64(d) / float64(a.Duration)
+	}
+	if a.Easing != nil {
+		t = a.Easing(t)
+	}
+	if a.Apply != nil {
+		a.Apply(t)
+	}
+	return d >= a.Duration
+}
+
+// Lerp linearly interpolates between from and to.
+func Lerp(from, to, f float64) float64 {
+	return from + (to-from)*f
+}
+
+// Float returns an Apply function, for a Tween, that interpolates the value
+// pointed to by p between from and to.
+func Float(p *float64, from, to float64) func(f float64) {
+	return func(f float64) {
+		*p = Lerp(from, to, f)
+	}
+}
+
+// Point returns an Apply function, for a Tween, that interpolates the point
+// pointed to by p between from and to, such as to animate a widget.Sheet's
+// Offset.
+func Point(p *image.Point, from, to image.Point) func(f float64) {
+	return func(f float64) {
+		p.X = round(Lerp(float64(from.X), float64(to.X), f))
+		p.Y = round(Lerp(float64(from.Y), float64(to.Y), f))
+	}
+}
+
+// RGBA returns an Apply function, for a Tween, that interpolates the color
+// pointed to by p between from and to, in premultiplied alpha space.
+func RGBA(p *color.RGBA, from, to color.RGBA) func(f float64) {
+	lerp := func(a, b uint8, f float64) uint8 {
+		x := round(Lerp(float64(a), float64(b), f))
+		if x < 0 {
+			return 0
+		}
+		if x > 0xff {
+			return 0xff
+		}
+		return uint8(x)
+	}
+	return func(f float64) {
+		*p = color.RGBA{
+			R: lerp(from.R, to.R, f),
+			G: lerp(from.G, to.G, f),
+			B: lerp(from.B, to.B, f),
+			A: lerp(from.A, to.A, f),
+		}
+	}
+}
+
+func round(x float64) int {
+	return int(math.Floor(x + 0.5))
+}
+
+// springStep is the time step for integrating a Spring's motion.
+const springStep = time.Millisecond
+
+// Spring is an Animation that moves a value towards a target as if it was
+// attached to that target by a damped spring. It finishes when the value is
+// at rest, within Tolerance of the target.
+//
+// Unlike a Tween, a Spring has no fixed duration, and its target can be
+// changed while it is running, such as when a dragged widget is let go.
+type Spring struct {
+	// Stiffness, Damping and Mass are the spring's physical parameters. A
+	// zero Mass means 1.
+	Stiffness, Damping, Mass float64
+
+	// Value and Velocity are the spring's current value and velocity, in
+	// units per second. Target is the value that the spring moves towards.
+	Value, Velocity, Target float64
+
+	// Tolerance is how close the Value and Velocity need to be to the Target
+	// and zero for the spring to be at rest. A zero Tolerance means 0.01.
+	Tolerance float64
+
+	// Apply, if non-nil, is called with the spring's Value after each Step.
+	Apply func(x float64)
+
+	// elapsed is how far the spring's motion has been integrated, and last
+	// is the d argument of the previous Step call.
+	elapsed, last time.Duration
+}
+
+// NewSpring returns a new Spring with the given stiffness and damping that
+// moves a value from from to to. A damping of 2*math.Sqrt(stiffness) is
+// critically damped: the value reaches the target as quickly as possible
+// without overshooting it.
+func NewSpring(stiffness, damping, from, to float64, apply func(x float64)) *Spring {
+	return &Spring{
+		Stiffness: stiffness,
+		Damping:   damping,
+		Value:     from,
+		Target:    to,
+		Apply:     apply,
+	}
+}
+
+func (a *Spring) Step(d time.Duration) (done bool) {
+	mass, tolerance := a.Mass, a.Tolerance
+	if mass <= 0 {
+		mass = 1
+	}
+	if tolerance <= 0 {
+		tolerance = 0.01
+	}
+	// Use semi-implicit Euler integration, with a fixed time step so that the
+	// motion does not depend on the frame rate.
+	const dt = float64(springStep) / float64(time.Second)
+	if d < a.last {
+		// The spring was restarted, from its current Value and Velocity.
+		a.elapsed = 0
+	}
+	a.last = d
+	for ; a.elapsed < d; a.elapsed += springStep {
+		force := -a.Stiffness*(a.Value-a.Target) - a.Damping*a.Velocity
+		a.Velocity += force / mass * dt
+		a.Value += a.Velocity * dt
+	}
+	done = math.Abs(a.Value-a.Target) < tolerance && math.Abs(a.Velocity) < tolerance
+	if done {
+		a.Value, a.Velocity = a.Target, 0
+	}
+	if a.Apply != nil {
+		a.Apply(a.Value)
+	}
+	return done
+}
+
+// Sequence returns an Animation that runs the given Animations one after the
+// other. Each Animation starts when the Step that finished the previous one
+// was called, so that, for Tweens, their total duration may be slightly
+// longer than the sum of their Durations.
+func Sequence(animations ...Animation) Animation {
+	return &sequence{animations: animations}
+}
+
+type sequence struct {
+	animations []Animation
+	i          int
+	start      time.Duration
+	last       time.Duration
+}
+
+func (a *sequence) Step(d time.Duration) (done bool) {
+	if d < a.last {
+		a.i, a.start = 0, 0
+	}
+	a.last = d
+	for a.i < len(a.animations) {
+		if !a.animations[a.i].Step(d - a.start) {
+			return false
+		}
+		a.i++
+		a.start = d
+	}
+	return true
+}
+
+// Parallel returns an Animation that runs the given Animations at the same
+// time. It finishes when all of them have finished.
+func Parallel(animations ...Animation) Animation {
+	return &parallel{
+		animations: animations,
+		done:       make([]bool, len(animations)),
+	}
+}
+
+type parallel struct {
+	animations []Animation
+	done       []bool
+	last       time.Duration
+}
+
+func (a *parallel) Step(d time.Duration) (done bool) {
+	if d < a.last {
+		for i := range a.done {
+			a.done[i] = false
+		}
+	}
+	a.last = d
+	done = true
+	for i, b := range a.animations {
+		if !a.done[i] {
+			a.done[i] = b.Step(d)
+			done = done && a.done[i]
+		}
+	}
+	return done
+}
+
+// Delay returns an Animation that does nothing for the duration d. It is
+// typically used in a Sequence.
+func Delay(d time.Duration) Animation {
+	return &delay{d}
+}
+
+type delay struct {
+	d time.Duration
+}
+
+func (a *delay) Step(d time.Duration) (done bool) {
+	return d >= a.d
+}
+
+// Animator runs Animations, advancing them each time that it is ticked. Its
+// zero value is usable and has no running Animations. Animations are compared
+// with ==, so they are typically pointers, such as *Tween and *Spring.
+//
+// An Animator is not safe for concurrent use. It is typically used only in a
+// window's event loop goroutine, which ticks it once per frame.
+type Animator struct {
+	running []running
+}
+
+type running struct {
+	a       Animation
+	n       node.Node
+	m       node.Marks
+	start   time.Time
+	started bool
+}
+
+// Start starts, or restarts, the Animation a at the next Tick. Each time that a is
+// advanced, the node n, if non-nil, is given the marks m. Animating a property
+// that is only used by n's Paint method, and not by its PaintBase method,
+// needs only node.MarkNeedsPaint.
+func (r *Animator) Start(a Animation, n node.Node, m node.Marks) {
+	r.Stop(a)
+	r.running = append(r.running, running{a: a, n: n, m: m})
+}
+
+// Stop stops the Animation a, leaving its property at its current value. It
+// does nothing if a is not running.
+func (r *Animator) Stop(a Animation) {
+	if i := r.index(a); i >= 0 {
+		r.running = append(r.running[:i], r.running[i+1:]...)
+	}
+}
+
+// Running returns whether any Animations are running.
+func (r *Animator) Running() bool {
+	return len(r.running) > 0
+}
+
+// Tick advances the running Animations to the time now, and marks their
+// nodes. Animations that have not yet been advanced start at now. Animations
+// that finish are removed from the Animator.
+func (r *Animator) Tick(now time.Time) {
+	// Advancing an Animation may start or stop others, so iterate over a copy
+	// of the running Animations.
+	animations := make([]Animation, len(r.running))
+	for i, x := range r.running {
+		animations[i] = x.a
+	}
+	for _, a := range animations {
+		i := r.index(a)
+		if i < 0 {
+			// An earlier Animation stopped a.
+			continue
+		}
+		x := &r.running[i]
+		if !x.started {
+			x.start, x.started = now, true
+		}
+		start, n, m := x.start, x.n, x.m
+		if a.Step(now.Sub(start)) {
+			r.Stop(a)
+		}
+		if n != nil {
+			n.Wrappee().Mark(m)
+		}
+	}
+}
+
+// index returns the index of the Animation a in r.running, or -1.
+func (r *Animator) index(a Animation) int {
+	for i, x := range r.running {
+		if x.a == a {
+			return i
+		}
+	}
+	return -1
+}
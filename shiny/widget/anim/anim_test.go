@@ -0,0 +1,206 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package anim
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/shiny/widget/node"
+)
+
+func TestEasing(t *testing.T) {
+	testCases := []struct {
+		name string
+		e    Easing
+	}{
+		{"Linear", Linear},
+		{"EaseIn", EaseIn},
+		{"EaseOut", EaseOut},
+		{"EaseInOut", EaseInOut},
+	}
+	for _, tc := range testCases {
+		if got := tc.e(0); got != 0 {
+			t.Errorf("%s(0): got %v, want 0", tc.name, got)
+		}
+		if got := tc.e(1); got != 1 {
+			t.Errorf("%s(1): got %v, want 1", tc.name, got)
+		}
+		prev := 0.0
+		for i := 1; i <= 100; i++ {
+			got := tc.e(float64(i) / 100)
+			if got < prev {
+				t.Errorf("%s(%v): got %v, want >= %v", tc.name, float64(i)/100, got, prev)
+				break
+			}
+			prev = got
+		}
+	}
+
+	if got := EaseInOut(0.5); math.Abs(got-0.5) > 1e-6 {
+		t.Errorf("EaseInOut(0.5): got %v, want 0.5", got)
+	}
+	if got := EaseIn(0.25); got >= 0.25 {
+		t.Errorf("EaseIn(0.25): got %v, want < 0.25", got)
+	}
+	if got := EaseOut(0.25); got <= 0.25 {
+		t.Errorf("EaseOut(0.25): got %v, want > 0.25", got)
+	}
+}
+
+func TestTween(t *testing.T) {
+	x := 0.0
+	a := NewTween(100*time.Millisecond, nil, Float(&x, 10, 20))
+	testCases := []struct {
+		d    time.Duration
+		want float64
+		done bool
+	}{
+		{0, 10, false},
+		{25 * time.Millisecond, 12.5, false},
+		{50 * time.Millisecond, 15, false},
+		{100 * time.Millisecond, 20, true},
+		{150 * time.Millisecond, 20, true},
+	}
+	for _, tc := range testCases {
+		done := a.Step(tc.d)
+		if x != tc.want || done != tc.done {
+			t.Errorf("d=%v: got %v, %t, want %v, %t", tc.d, x, done, tc.want, tc.done)
+		}
+	}
+
+	p := image.Point{}
+	NewTween(time.Second, nil, Point(&p, image.Point{0, 10}, image.Point{10, 0})).Step(300 * time.Millisecond)
+	if want := (image.Point{3, 7}); p != want {
+		t.Errorf("Point: got %v, want %v", p, want)
+	}
+
+	c := color.RGBA{}
+	NewTween(time.Second, nil, RGBA(&c, color.RGBA{0x00, 0x00, 0xff, 0xff}, color.RGBA{0xff, 0x00, 0x00, 0xff})).Step(500 * time.Millisecond)
+	if want := (color.RGBA{0x80, 0x00, 0x80, 0xff}); c != want {
+		t.Errorf("RGBA: got %v, want %v", c, want)
+	}
+}
+
+func TestSpring(t *testing.T) {
+	x := 0.0
+	// A critically damped spring does not overshoot.
+	a := NewSpring(100, 20, 0, 10, func(v float64) { x = v })
+	prev, done := 0.0, false
+	d := time.Duration(0)
+	for ; !done && d < 10*time.Second; d += 16 * time.Millisecond {
+		done = a.Step(d)
+		if x < prev || x > 10 {
+			t.Fatalf("d=%v: got %v, want in [%v, 10]", d, x, prev)
+		}
+		prev = x
+	}
+	if !done || x != 10 {
+		t.Fatalf("got %v, %t after %v, want 10, true", x, done, d)
+	}
+	if d > 2*time.Second {
+		t.Errorf("took %v, want <= 2s", d)
+	}
+
+	// An under-damped spring overshoots.
+	max := 0.0
+	a = NewSpring(100, 5, 0, 10, func(v float64) {
+		if max < v {
+			max = v
+		}
+	})
+	for d := time.Duration(0); !a.Step(d); d += 16 * time.Millisecond {
+	}
+	if max <= 10 {
+		t.Errorf("under-damped: max: got %v, want > 10", max)
+	}
+}
+
+func TestSequenceParallel(t *testing.T) {
+	x, y := 0.0, 0.0
+	a := Sequence(
+		NewTween(100*time.Millisecond, nil, Float(&x, 0, 1)),
+		Delay(100*time.Millisecond),
+		Parallel(
+			NewTween(100*time.Millisecond, nil, Float(&x, 1, 2)),
+			NewTween(200*time.Millisecond, nil, Float(&y, 0, 1)),
+		),
+	)
+	testCases := []struct {
+		d        time.Duration
+		wantX    float64
+		wantY    float64
+		wantDone bool
+	}{
+		{50 * time.Millisecond, 0.5, 0, false},
+		{100 * time.Millisecond, 1, 0, false},
+		{150 * time.Millisecond, 1, 0, false},
+		{200 * time.Millisecond, 1, 0, false},
+		{250 * time.Millisecond, 1.5, 0.25, false},
+		{300 * time.Millisecond, 2, 0.5, false},
+		{400 * time.Millisecond, 2, 1, true},
+	}
+	for _, tc := range testCases {
+		done := a.Step(tc.d)
+		if x != tc.wantX || y != tc.wantY || done != tc.wantDone {
+			t.Errorf("d=%v: got %v, %v, %t, want %v, %v, %t",
+				tc.d, x, y, done, tc.wantX, tc.wantY, tc.wantDone)
+		}
+	}
+
+	// Restarting the sequence starts again from its first animation.
+	if a.Step(0) || x != 0 {
+		t.Errorf("restart: got %v, want 0", x)
+	}
+}
+
+type markLeaf struct {
+	node.LeafEmbed
+}
+
+func TestAnimator(t *testing.T) {
+	n := &markLeaf{}
+	n.Wrapper = n
+
+	x, y := 0.0, 0.0
+	a := NewTween(100*time.Millisecond, nil, Float(&x, 0, 1))
+	b := NewTween(200*time.Millisecond, nil, Float(&y, 0, 1))
+	r := &Animator{}
+	r.Start(a, n, node.MarkNeedsPaint)
+	if !r.Running() {
+		t.Fatal("Running: got false, want true")
+	}
+
+	t0 := time.Unix(1000, 0)
+	r.Tick(t0)
+	if x != 0 || !n.Marks.NeedsPaint() {
+		t.Errorf("first tick: got %v, %t, want 0, true", x, n.Marks.NeedsPaint())
+	}
+	n.Marks.UnmarkNeedsPaint()
+
+	// b starts at the next tick, not at t0.
+	r.Start(b, nil, 0)
+	r.Tick(t0.Add(50 * time.Millisecond))
+	if x != 0.5 || y != 0 {
+		t.Errorf("second tick: got %v, %v, want 0.5, 0", x, y)
+	}
+
+	r.Tick(t0.Add(150 * time.Millisecond))
+	if x != 1 || y != 0.5 || !r.Running() {
+		t.Errorf("third tick: got %v, %v, %t, want 1, 0.5, true", x, y, r.Running())
+	}
+
+	r.Stop(b)
+	if r.Running() {
+		t.Error("after Stop: Running: got true, want false")
+	}
+	r.Tick(t0.Add(200 * time.Millisecond))
+	if y != 0.5 {
+		t.Errorf("after Stop: got %v, want 0.5", y)
+	}
+}
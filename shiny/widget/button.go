@@ -0,0 +1,119 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/draw"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// Button is a leaf widget that can be clicked, by a tap or, when it has the
+// keyboard focus, by the space bar or the enter key.
+type Button struct {
+	node.LeafEmbed
+	Text string
+
+	// OnClick, if non-nil, is called when the button is clicked.
+	OnClick func()
+
+	c control
+}
+
+// NewButton returns a new Button widget.
+func NewButton(text string, onClick func()) *Button {
+	w := &Button{
+		Text:    text,
+		OnClick: onClick,
+	}
+	w.Wrapper = w
+	w.Focusable = true
+	return w
+}
+
+// Disabled returns whether the button is disabled.
+func (w *Button) Disabled() bool { return w.c.disabled }
+
+// SetDisabled sets whether the button is disabled. A disabled button cannot
+// be clicked or focused.
+func (w *Button) SetDisabled(disabled bool) { w.c.setDisabled(&w.Embed, disabled) }
+
+func (w *Button) padding(t *theme.Theme) image.Point {
+	return image.Point{
+		t.Pixels(unit.Ems(1)).Ceil(),
+		t.Pixels(unit.Ems(0.5)).Ceil(),
+	}
+}
+
+func (w *Button) Measure(t *theme.Theme, widthHint, heightHint int) {
+	face := t.AcquireFontFace(theme.FontFaceOptions{})
+	defer t.ReleaseFontFace(theme.FontFaceOptions{}, face)
+	m := face.Metrics()
+
+	padding := w.padding(t)
+	w.MeasuredSize.X = font.MeasureString(face, w.Text).Ceil() + 2*padding.X
+	w.MeasuredSize.Y = m.Ascent.Ceil() + m.Descent.Ceil() + 2*padding.Y
+}
+
+func (w *Button) Baseline(t *theme.Theme) int {
+	return w.padding(t).Y + fontAscent(t)
+}
+
+func (w *Button) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
+	w.Marks.UnmarkNeedsPaintBase()
+	r := w.Rect.Add(origin)
+	dst := ctx.Dst.SubImage(r).(*image.RGBA)
+	if dst.Bounds().Empty() {
+		return nil
+	}
+
+	face := ctx.Theme.AcquireFontFace(theme.FontFaceOptions{})
+	defer ctx.Theme.ReleaseFontFace(theme.FontFaceOptions{}, face)
+	m := face.Metrics()
+	ascent, descent := m.Ascent.Ceil(), m.Descent.Ceil()
+
+	draw.Draw(dst, r, w.c.fill(ctx.Theme), image.Point{}, draw.Src)
+	w.c.paintFocus(dst, ctx.Theme, r)
+
+	// Center the text.
+	d := font.Drawer{
+		Dst:  dst,
+		Src:  w.c.ink(ctx.Theme),
+		Face: face,
+	}
+	x := (r.Min.X + r.Max.X - d.MeasureString(w.Text).Ceil()) / 2
+	y := (r.Min.Y+r.Max.Y-ascent-descent)/2 + ascent
+	d.Dot = fixed.P(x, y)
+	d.DrawString(w.Text)
+	return nil
+}
+
+func (w *Button) OnInputEvent(e interface{}, origin image.Point) node.EventHandled {
+	handled, activate := w.c.onInputEvent(&w.Embed, e)
+	if activate {
+		w.click()
+	}
+	return handled
+}
+
+func (w *Button) click() {
+	if w.OnClick != nil {
+		w.OnClick()
+	}
+}
+
+func (w *Button) Accessibility() node.Accessibility {
+	return node.Accessibility{
+		Role:    node.RoleButton,
+		Name:    w.Text,
+		State:   w.c.state(),
+		Actions: w.c.actions(node.Action{Name: node.ActionClick, Do: w.click}),
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/canvas"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+)
+
+// TODO: input events, mapped to the Draw function's user space.
+
+// Canvas is a leaf widget that paints by calling a function with a
+// canvas.Context, which provides a 2D vector drawing API of paths, fills,
+// strokes, gradients, transformations, clipping and text.
+//
+// The Context draws into the PaintBaseContext's Dst, with its origin at the
+// top left of the Canvas' Rect, and the drawing is clipped to that Rect.
+type Canvas struct {
+	node.LeafEmbed
+
+	// NaturalWidth and NaturalHeight are the Canvas' measured size.
+	NaturalWidth  unit.Value
+	NaturalHeight unit.Value
+
+	// Draw paints the Canvas. It is called by PaintBase, so call Redraw after
+	// changing what it draws.
+	Draw func(c *canvas.Context) error
+
+	ctx canvas.Context
+}
+
+// NewCanvas returns a new Canvas widget of the given natural size, that paints
+// by calling draw.
+func NewCanvas(naturalWidth, naturalHeight unit.Value, draw func(c *canvas.Context) error) *Canvas {
+	w := &Canvas{
+		NaturalWidth:  naturalWidth,
+		NaturalHeight: naturalHeight,
+		Draw:          draw,
+	}
+	w.Wrapper = w
+	return w
+}
+
+// Redraw marks the Canvas as needing its Draw function to be called again.
+func (w *Canvas) Redraw() {
+	w.Mark(node.MarkNeedsPaintBase)
+}
+
+func (w *Canvas) Measure(t *theme.Theme, widthHint, heightHint int) {
+	w.MeasuredSize = image.Point{
+		X: t.Pixels(w.NaturalWidth).Ceil(),
+		Y: t.Pixels(w.NaturalHeight).Ceil(),
+	}
+}
+
+func (w *Canvas) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
+	w.Marks.UnmarkNeedsPaintBase()
+	if w.Draw == nil {
+		return nil
+	}
+	w.ctx.Reset(ctx.Dst, w.Rect.Add(origin), ctx.Theme)
+	return w.Draw(&w.ctx)
+}
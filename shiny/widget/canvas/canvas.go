@@ -0,0 +1,318 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package canvas provides a 2D vector drawing API, for charts, diagrams and
+// other custom widgets.
+//
+// A Context draws onto a rectangle of an *image.RGBA, such as the Dst of a
+// node.PaintBaseContext. Its API is modeled on the HTML canvas element's: a
+// path is built, in user space, by methods such as MoveTo, LineTo and Arc,
+// and is then filled, stroked or used as a clip. User space is mapped to the
+// rectangle's pixels by a transformation, which is initially the identity
+// with the origin at the rectangle's top left, and is changed by methods such
+// as Translate and Rotate. Save and Restore save and restore the
+// transformation, clip and font.
+//
+// Shapes are rasterized with anti-aliasing by golang.org/x/image/vector,
+// using the non-zero winding rule.
+//
+// The widget package's Canvas is a widget that draws with a Context.
+package canvas // import "golang.org/x/exp/shiny/widget/canvas"
+
+import (
+	"image"
+	"image/draw"
+	"math"
+
+	"golang.org/x/exp/shiny/widget/theme"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/f64"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// TODO: an even-odd fill rule.
+
+// TODO: global alpha and composition operators other than draw.Over.
+
+// TODO: text that follows the transformation's scale and rotation, not just
+// its translation.
+
+// Identity is the identity transformation.
+var Identity = f64.Aff3{
+	1, 0, 0,
+	0, 1, 0,
+}
+
+// Context draws onto a rectangle of an *image.RGBA.
+//
+// Its zero value is not usable: call Reset or use NewContext.
+type Context struct {
+	theme *theme.Theme
+	dst   *image.RGBA
+
+	// origin is the top left of the Context's rectangle, in dst's coordinate
+	// space, which is the origin of device space. vis is the part of that
+	// rectangle inside dst, which is where the rasterizer and masks draw,
+	// and off is the offset from device space to that rasterizer space.
+	origin image.Point
+	vis    image.Rectangle
+	off    point
+
+	z vector.Rasterizer
+
+	// path is the current path, in device space.
+	path []pathOp
+	// first and pen are the start of the current subpath and the current
+	// point, in device space. hasPen is whether there is a current point.
+	first, pen point
+	hasPen     bool
+
+	state
+	stack []state
+
+	// scratch is a mask for shapes that are clipped, and for text.
+	scratch *image.Alpha
+}
+
+type state struct {
+	// m is the user space to device space transformation.
+	m f64.Aff3
+
+	// clip, if non-nil, is the clip mask, in rasterizer space. It is never
+	// modified, once set, so that saved states can share it.
+	clip *image.Alpha
+
+	font theme.FontFaceOptions
+}
+
+// NewContext returns a new Context that draws onto the rectangle r of dst,
+// using the theme t for its font faces and colors.
+func NewContext(dst *image.RGBA, r image.Rectangle, t *theme.Theme) *Context {
+	c := &Context{}
+	c.Reset(dst, r, t)
+	return c
+}
+
+// Reset resets c to draw onto the rectangle r of dst, using the theme t. It
+// clears the path, the saved states, the transformation, the clip and the
+// font, but re-uses c's memory allocations.
+func (c *Context) Reset(dst *image.RGBA, r image.Rectangle, t *theme.Theme) {
+	c.theme = t
+	c.dst = dst
+	c.origin = r.Min
+	c.vis = r.Intersect(dst.Bounds())
+	c.off = point{float64(r.Min.X - c.vis.Min.X), float64(r.Min.Y - c.vis.Min.Y)}
+	c.BeginPath()
+	c.state = state{m: Identity}
+	c.stack = c.stack[:0]
+	if size := c.vis.Size(); c.scratch == nil || c.scratch.Rect.Size() != size {
+		c.scratch = nil
+		if !c.vis.Empty() {
+			c.scratch = image.NewAlpha(image.Rectangle{Max: size})
+		}
+	}
+}
+
+// Theme returns the Context's theme.
+func (c *Context) Theme() *theme.Theme { return c.theme }
+
+// Save pushes the transformation, clip and font onto a stack.
+func (c *Context) Save() {
+	c.stack = append(c.stack, c.state)
+}
+
+// Restore pops the transformation, clip and font that were most recently
+// pushed by Save. It does nothing if the stack is empty.
+func (c *Context) Restore() {
+	if n := len(c.stack); n > 0 {
+		c.state = c.stack[n-1]
+		c.stack = c.stack[:n-1]
+	}
+}
+
+// CurrentTransform returns the transformation from user space to device
+// space, whose origin is the top left of the Context's rectangle and whose
+// unit is a pixel.
+func (c *Context) CurrentTransform() f64.Aff3 { return c.m }
+
+// SetTransform sets the transformation from user space to device space.
+func (c *Context) SetTransform(m f64.Aff3) { c.m = m }
+
+// Transform applies m to user space: the new user space coordinates (x, y)
+// are m's transformation of (x, y) in the old user space.
+func (c *Context) Transform(m f64.Aff3) { c.m = mul(&c.m, &m) }
+
+// Translate moves user space's origin to (x, y).
+func (c *Context) Translate(x, y float64) {
+	c.Transform(f64.Aff3{1, 0, x, 0, 1, y})
+}
+
+// Scale scales user space by sx horizontally and sy vertically.
+func (c *Context) Scale(sx, sy float64) {
+	c.Transform(f64.Aff3{sx, 0, 0, 0, sy, 0})
+}
+
+// Rotate rotates user space by angle radians, clockwise on the screen.
+func (c *Context) Rotate(angle float64) {
+	sin, cos := math.Sincos(angle)
+	c.Transform(f64.Aff3{cos, -sin, 0, sin, cos, 0})
+}
+
+func mul(a, b *f64.Aff3) f64.Aff3 {
+	return f64.Aff3{
+		a[0]*b[0] + a[1]*b[3],
+		a[0]*b[1] + a[1]*b[4],
+		a[0]*b[2] + a[1]*b[5] + a[2],
+		a[3]*b[0] + a[4]*b[3],
+		a[3]*b[1] + a[4]*b[4],
+		a[3]*b[2] + a[4]*b[5] + a[5],
+	}
+}
+
+// invert returns the inverse of m, or false if m is not invertible.
+func invert(m *f64.Aff3) (f64.Aff3, bool) {
+	det := m[0]*m[4] - m[1]*m[3]
+	if det == 0 || math.IsNaN(det) || math.IsInf(det, 0) {
+		return f64.Aff3{}, false
+	}
+	return f64.Aff3{
+		m[4] / det,
+		-m[1] / det,
+		(m[1]*m[5] - m[4]*m[2]) / det,
+		-m[3] / det,
+		m[0] / det,
+		(m[3]*m[2] - m[0]*m[5]) / det,
+	}, true
+}
+
+// scale returns the factor by which the transformation scales lengths,
+// averaged over all directions if it does not scale uniformly.
+func (c *Context) scale() float64 {
+	return math.Sqrt(math.Abs(c.m[0]*c.m[4] - c.m[1]*c.m[3]))
+}
+
+// Fill fills the current path with the paint p. Any open subpaths are
+// implicitly closed.
+func (c *Context) Fill(p Paint) {
+	if !c.rasterizePath() {
+		return
+	}
+	c.draw(p)
+}
+
+// Clip intersects the clip with the current path. Like Fill, any open
+// subpaths are implicitly closed.
+func (c *Context) Clip() {
+	mask := image.NewAlpha(image.Rectangle{Max: c.vis.Size()})
+	if c.rasterizePath() {
+		c.z.Draw(mask, mask.Bounds(), image.Opaque, image.Point{})
+		if c.clip != nil {
+			intersectMask(mask, c.clip)
+		}
+	}
+	c.clip = mask
+}
+
+// rasterizePath resets the rasterizer and adds the current path to it,
+// closing its subpaths. It returns false if there is nothing to draw.
+//
+// Curves are flattened by the Context, not the rasterizer, as the
+// rasterizer's tolerance is too coarse for small shapes such as circles.
+func (c *Context) rasterizePath() bool {
+	if c.vis.Empty() {
+		return false
+	}
+	c.z.Reset(c.vis.Dx(), c.vis.Dy())
+	for _, l := range c.flatten() {
+		if len(l.pts) < 3 {
+			continue
+		}
+		c.z.MoveTo(c.raster(l.pts[0]))
+		for _, p := range l.pts[1:] {
+			c.lineTo(p)
+		}
+		c.z.ClosePath()
+	}
+	return true
+}
+
+// raster converts from device space to rasterizer space.
+func (c *Context) raster(p point) (x, y float32) {
+	return float32(p.x + c.off.x), float32(p.y + c.off.y)
+}
+
+func (c *Context) lineTo(p point) {
+	c.z.LineTo(c.raster(p))
+}
+
+// draw draws the paint p through the rasterizer's shape and the clip.
+func (c *Context) draw(p Paint) {
+	src := p.Image(c.theme, c.m)
+	sp := c.vis.Min.Sub(c.origin)
+	if c.clip == nil {
+		c.z.Draw(c.dst, c.vis, src, sp)
+		return
+	}
+	clearMask(c.scratch)
+	c.z.Draw(c.scratch, c.scratch.Bounds(), image.Opaque, image.Point{})
+	c.drawMask(src, sp)
+}
+
+// drawMask draws src through the scratch mask and the clip.
+func (c *Context) drawMask(src image.Image, sp image.Point) {
+	if c.clip != nil {
+		intersectMask(c.scratch, c.clip)
+	}
+	draw.DrawMask(c.dst, c.vis, src, sp, c.scratch, image.Point{}, draw.Over)
+}
+
+func clearMask(m *image.Alpha) {
+	for i := range m.Pix {
+		m.Pix[i] = 0
+	}
+}
+
+// intersectMask multiplies dst by src. They have the same bounds.
+func intersectMask(dst, src *image.Alpha) {
+	for i, s := range src.Pix {
+		dst.Pix[i] = uint8((uint32(dst.Pix[i])*uint32(s) + 0x7f) / 0xff)
+	}
+}
+
+// SetFont sets the font face options for FillText and MeasureText. The face
+// is acquired from the Context's theme.
+func (c *Context) SetFont(o theme.FontFaceOptions) { c.font = o }
+
+// MeasureText returns the width of s in the current font, in pixels.
+func (c *Context) MeasureText(s string) float64 {
+	face := c.theme.AcquireFontFace(c.font)
+	defer c.theme.ReleaseFontFace(c.font, face)
+	return float64(font.MeasureString(face, s)) / 64
+}
+
+// FillText draws s with the paint p, in the current font, with the left end
+// of its baseline at (x, y) in user space. Only the transformation's
+// translation applies to the text, not its scale or rotation.
+func (c *Context) FillText(s string, x, y float64, p Paint) {
+	if c.vis.Empty() {
+		return
+	}
+	face := c.theme.AcquireFontFace(c.font)
+	defer c.theme.ReleaseFontFace(c.font, face)
+
+	dot := c.transform(x, y)
+	clearMask(c.scratch)
+	d := font.Drawer{
+		Dst:  c.scratch,
+		Src:  image.Opaque,
+		Face: face,
+		Dot: fixed.Point26_6{
+			X: fixed.Int26_6(math.Floor((dot.x + c.off.x) * 64)),
+			Y: fixed.Int26_6(math.Floor((dot.y + c.off.y) * 64)),
+		},
+	}
+	d.DrawString(s)
+	c.drawMask(p.Image(c.theme, c.m), c.vis.Min.Sub(c.origin))
+}
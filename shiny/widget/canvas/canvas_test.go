@@ -0,0 +1,259 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package canvas
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/shiny/widget/theme"
+)
+
+var (
+	black = color.RGBA{0x00, 0x00, 0x00, 0xff}
+	red   = color.RGBA{0xff, 0x00, 0x00, 0xff}
+	blue  = color.RGBA{0x00, 0x00, 0xff, 0xff}
+)
+
+func newTestContext(w, h int) (*Context, *image.RGBA) {
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	return NewContext(dst, dst.Bounds(), theme.Default), dst
+}
+
+// alphaAt returns the alpha of dst's pixel (x, y).
+func alphaAt(dst *image.RGBA, x, y int) uint8 {
+	return dst.RGBAAt(x, y).A
+}
+
+func TestFillRect(t *testing.T) {
+	c, dst := newTestContext(20, 20)
+	c.Rect(5, 5, 10, 10)
+	c.Fill(Solid(red))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 20; x++ {
+			want := color.RGBA{}
+			if 5 <= x && x < 15 && 5 <= y && y < 15 {
+				want = red
+			}
+			if got := dst.RGBAAt(x, y); got != want {
+				t.Fatalf("(%d, %d): got %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestFillOffsetRect(t *testing.T) {
+	// The Context's rectangle sticks out of dst, so only part of the fill is
+	// visible.
+	dst := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	c := NewContext(dst, image.Rect(10, -10, 40, 20), theme.Default)
+	c.Rect(0, 5, 5, 15)
+	c.Fill(Solid(red))
+	testCases := []struct {
+		x, y int
+		want uint8
+	}{
+		{9, 0, 0x00},
+		{10, 0, 0xff},
+		{14, 0, 0xff},
+		{15, 0, 0x00},
+		{12, 9, 0xff},
+		{12, 10, 0x00},
+	}
+	for _, tc := range testCases {
+		if got := alphaAt(dst, tc.x, tc.y); got != tc.want {
+			t.Errorf("(%d, %d): got alpha %#02x, want %#02x", tc.x, tc.y, got, tc.want)
+		}
+	}
+}
+
+func TestTransform(t *testing.T) {
+	c, dst := newTestContext(40, 40)
+	c.Translate(20, 10)
+	c.Scale(2, 3)
+	c.Save()
+	c.Rotate(math.Pi / 2)
+	c.Restore()
+	c.Rect(0, 0, 5, 5)
+	c.Fill(Solid(red))
+	for _, p := range []image.Point{{20, 10}, {29, 24}} {
+		if got := alphaAt(dst, p.X, p.Y); got != 0xff {
+			t.Errorf("%v: got alpha %#02x, want 0xff", p, got)
+		}
+	}
+	for _, p := range []image.Point{{19, 10}, {30, 24}, {29, 25}} {
+		if got := alphaAt(dst, p.X, p.Y); got != 0x00 {
+			t.Errorf("%v: got alpha %#02x, want 0x00", p, got)
+		}
+	}
+
+	// A rotation by 90 degrees maps +x to +y.
+	c.SetTransform(Identity)
+	c.Rotate(math.Pi / 2)
+	m := c.CurrentTransform()
+	if x, y := m[0]*1+m[1]*0+m[2], m[3]*1+m[4]*0+m[5]; math.Abs(x) > 1e-9 || math.Abs(y-1) > 1e-9 {
+		t.Errorf("rotating (1, 0): got (%g, %g), want (0, 1)", x, y)
+	}
+}
+
+func TestStrokeWidth(t *testing.T) {
+	c, dst := newTestContext(20, 20)
+	c.MoveTo(2, 10)
+	c.LineTo(18, 10)
+	c.Stroke(Solid(black), Stroke{Width: 4})
+	for y := 0; y < 20; y++ {
+		want := uint8(0x00)
+		if 8 <= y && y < 12 {
+			want = 0xff
+		}
+		if got := alphaAt(dst, 10, y); got != want {
+			t.Errorf("(10, %d): got alpha %#02x, want %#02x", y, got, want)
+		}
+	}
+	// A ButtCap ends at the end points, and a SquareCap extends beyond them.
+	if got := alphaAt(dst, 1, 10); got != 0x00 {
+		t.Errorf("butt cap: got alpha %#02x, want 0x00", got)
+	}
+	c.BeginPath()
+	c.MoveTo(2, 2)
+	c.LineTo(18, 2)
+	c.Stroke(Solid(black), Stroke{Width: 2, Cap: SquareCap})
+	if got := alphaAt(dst, 1, 2); got != 0xff {
+		t.Errorf("square cap: got alpha %#02x, want 0xff", got)
+	}
+}
+
+func TestStrokeJoins(t *testing.T) {
+	testCases := []struct {
+		join Join
+		want uint8
+	}{
+		{MiterJoin, 0xff},
+		{BevelJoin, 0x00},
+	}
+	for _, tc := range testCases {
+		c, dst := newTestContext(30, 30)
+		c.MoveTo(5, 20)
+		c.LineTo(20, 20)
+		c.LineTo(20, 5)
+		c.Stroke(Solid(black), Stroke{Width: 6, Join: tc.join})
+		// The outer corner of the join.
+		if got := alphaAt(dst, 22, 22); got != tc.want {
+			t.Errorf("join %d: got alpha %#02x, want %#02x", tc.join, got, tc.want)
+		}
+		// The segments overlap at the inner corner, but as they have the same
+		// orientation, they do not cancel each other out.
+		if got := alphaAt(dst, 19, 19); got != 0xff {
+			t.Errorf("join %d: inner corner: got alpha %#02x, want 0xff", tc.join, got)
+		}
+	}
+}
+
+func TestStrokeDashes(t *testing.T) {
+	c, dst := newTestContext(40, 10)
+	c.MoveTo(0, 5)
+	c.LineTo(40, 5)
+	c.Stroke(Solid(black), Stroke{Width: 2, Dashes: []float64{4, 6}, DashOffset: 2})
+	got := ""
+	for x := 0; x < 40; x++ {
+		if alphaAt(dst, x, 5) == 0xff {
+			got += "#"
+		} else {
+			got += "."
+		}
+	}
+	want := "##......####......####......####......##"
+	if got != want {
+		t.Errorf("\ngot  %s\nwant %s", got, want)
+	}
+}
+
+func TestClip(t *testing.T) {
+	c, dst := newTestContext(20, 20)
+	c.Save()
+	c.Rect(0, 0, 10, 20)
+	c.Clip()
+	c.BeginPath()
+	c.Rect(0, 0, 20, 10)
+	c.Fill(Solid(red))
+	c.Restore()
+	c.BeginPath()
+	c.Rect(0, 15, 20, 5)
+	c.Fill(Solid(blue))
+
+	testCases := []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{5, 5, red},
+		{15, 5, color.RGBA{}},
+		{5, 12, color.RGBA{}},
+		{15, 17, blue},
+	}
+	for _, tc := range testCases {
+		if got := dst.RGBAAt(tc.x, tc.y); got != tc.want {
+			t.Errorf("(%d, %d): got %v, want %v", tc.x, tc.y, got, tc.want)
+		}
+	}
+}
+
+func TestLinearGradient(t *testing.T) {
+	c, dst := newTestContext(100, 1)
+	c.Rect(0, 0, 100, 1)
+	c.Fill(&LinearGradient{
+		X0: 0, X1: 100,
+		Stops: []GradientStop{
+			{0, color.RGBA{0x00, 0x00, 0x00, 0xff}},
+			{1, color.RGBA{0xff, 0xff, 0xff, 0xff}},
+		},
+	})
+	prev := -1
+	for x := 0; x < 100; x++ {
+		r := int(dst.RGBAAt(x, 0).R)
+		if r < prev {
+			t.Fatalf("x=%d: red decreased from %d to %d", x, prev, r)
+		}
+		prev = r
+	}
+	if got := dst.RGBAAt(50, 0).R; got < 0x7e || got > 0x82 {
+		t.Errorf("middle: got red %#02x, want about 0x80", got)
+	}
+}
+
+func TestRadialGradient(t *testing.T) {
+	c, dst := newTestContext(21, 21)
+	c.Rect(0, 0, 21, 21)
+	c.Fill(&RadialGradient{
+		X: 10.5, Y: 10.5, R: 5,
+		Stops: []GradientStop{
+			{0, red},
+			{1, blue},
+		},
+	})
+	if got := dst.RGBAAt(10, 10); got != red {
+		t.Errorf("center: got %v, want %v", got, red)
+	}
+	if got := dst.RGBAAt(0, 0); got != blue {
+		t.Errorf("corner: got %v, want %v", got, blue)
+	}
+}
+
+func TestCircle(t *testing.T) {
+	c, dst := newTestContext(40, 40)
+	c.Circle(20, 20, 10)
+	c.Fill(Solid(black))
+	n := 0
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 40; x++ {
+			n += int(alphaAt(dst, x, y))
+		}
+	}
+	area, want := float64(n)/0xff, math.Pi*10*10
+	if math.Abs(area-want) > 1.5 {
+		t.Errorf("area: got %.2f, want %.2f", area, want)
+	}
+}
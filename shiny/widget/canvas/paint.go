@@ -0,0 +1,167 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package canvas
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"golang.org/x/exp/shiny/widget/theme"
+	"golang.org/x/image/math/f64"
+)
+
+// TODO: pattern paints, from an image.Image in user space.
+
+// TODO: gradients that repeat or reflect beyond their ends, instead of
+// extending their end colors.
+
+// Paint is what fills and strokes are painted with.
+type Paint interface {
+	// Image returns the paint's colors, for the theme t and the user space
+	// to device space transformation m. The image's pixel (x, y) is device
+	// space's pixel (x, y), and its bounds must contain the Context's
+	// rectangle.
+	Image(t *theme.Theme, m f64.Aff3) image.Image
+}
+
+// Solid returns a Paint of the single color c.
+func Solid(c color.Color) Paint {
+	return solid{image.NewUniform(c)}
+}
+
+type solid struct {
+	u *image.Uniform
+}
+
+func (p solid) Image(t *theme.Theme, m f64.Aff3) image.Image { return p.u }
+
+// Themed returns a Paint of the single color c, such as theme.Foreground,
+// which depends on the Context's theme.
+func Themed(c theme.Color) Paint {
+	return themed{c}
+}
+
+type themed struct {
+	c theme.Color
+}
+
+func (p themed) Image(t *theme.Theme, m f64.Aff3) image.Image {
+	return image.NewUniform(p.c.Color(t))
+}
+
+// GradientStop is a color at an offset, from 0 to 1, along a gradient.
+type GradientStop struct {
+	Offset float64
+	Color  color.Color
+}
+
+// LinearGradient is a Paint whose color varies along the line from (X0, Y0)
+// to (X1, Y1), in user space. Its colors are the Stops' colors, interpolated
+// in alpha-premultiplied RGBA, and are constant along lines perpendicular to
+// that line. Beyond the line's ends, the colors are the end Stops' colors.
+//
+// The Stops must be sorted by Offset.
+type LinearGradient struct {
+	X0, Y0, X1, Y1 float64
+	Stops          []GradientStop
+}
+
+func (g *LinearGradient) Image(t *theme.Theme, m f64.Aff3) image.Image {
+	dx, dy := g.X1-g.X0, g.Y1-g.Y0
+	d2 := dx*dx + dy*dy
+	return newGradient(g.Stops, m, func(x, y float64) float64 {
+		if d2 == 0 {
+			return 0
+		}
+		return ((x-g.X0)*dx + (y-g.Y0)*dy) / d2
+	})
+}
+
+// RadialGradient is a Paint whose color varies with the distance from
+// (X, Y), in user space: the Stop at offset 0 is at (X, Y), and the Stop at
+// offset 1 is on the circle of radius R around it. Beyond that circle, the
+// color is the last Stop's color.
+//
+// The Stops must be sorted by Offset.
+type RadialGradient struct {
+	X, Y, R float64
+	Stops   []GradientStop
+}
+
+func (g *RadialGradient) Image(t *theme.Theme, m f64.Aff3) image.Image {
+	return newGradient(g.Stops, m, func(x, y float64) float64 {
+		if g.R <= 0 {
+			return 1
+		}
+		return math.Hypot(x-g.X, y-g.Y) / g.R
+	})
+}
+
+// gradient is an image.Image whose colors are interpolated between stops.
+type gradient struct {
+	offsets []float64
+	colors  []color.RGBA64
+
+	// inv is the device space to user space transformation, and ok is
+	// whether it exists.
+	inv f64.Aff3
+	ok  bool
+
+	// offset returns the offset along the gradient of the point (x, y) in
+	// user space.
+	offset func(x, y float64) float64
+}
+
+func newGradient(stops []GradientStop, m f64.Aff3, offset func(x, y float64) float64) *gradient {
+	g := &gradient{
+		offsets: make([]float64, len(stops)),
+		colors:  make([]color.RGBA64, len(stops)),
+		offset:  offset,
+	}
+	for i, s := range stops {
+		g.offsets[i] = s.Offset
+		if s.Color != nil {
+			g.colors[i] = color.RGBA64Model.Convert(s.Color).(color.RGBA64)
+		}
+	}
+	g.inv, g.ok = invert(&m)
+	return g
+}
+
+func (g *gradient) ColorModel() color.Model { return color.RGBA64Model }
+
+func (g *gradient) Bounds() image.Rectangle {
+	return image.Rect(-1e9, -1e9, 1e9, 1e9)
+}
+
+func (g *gradient) At(x, y int) color.Color {
+	n := len(g.offsets)
+	if n == 0 || !g.ok {
+		return color.RGBA64{}
+	}
+	// Sample at the pixel's center.
+	fx, fy := float64(x)+0.5, float64(y)+0.5
+	ux := g.inv[0]*fx + g.inv[1]*fy + g.inv[2]
+	uy := g.inv[3]*fx + g.inv[4]*fy + g.inv[5]
+	t := g.offset(ux, uy)
+	if math.IsNaN(t) || t <= g.offsets[0] {
+		return g.colors[0]
+	}
+	for i := 1; i < n; i++ {
+		if t < g.offsets[i] {
+			t0, t1 := g.offsets[i-1], g.offsets[i]
+			return lerpRGBA64(g.colors[i-1], g.colors[i], (t-t0)/(t1-t0))
+		}
+	}
+	return g.colors[n-1]
+}
+
+func lerpRGBA64(a, b color.RGBA64, t float64) color.RGBA64 {
+	f := func(x, y uint16) uint16 {
+		return uint16(float64(x) + (float64(y)-float64(x))*t + 0.5)
+	}
+	return color.RGBA64{f(a.R, b.R), f(a.G, b.G), f(a.B, b.B), f(a.A, b.A)}
+}
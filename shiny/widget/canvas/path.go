@@ -0,0 +1,244 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package canvas
+
+import (
+	"math"
+)
+
+type point struct {
+	x, y float64
+}
+
+func (p point) add(q point) point             { return point{p.x + q.x, p.y + q.y} }
+func (p point) sub(q point) point             { return point{p.x - q.x, p.y - q.y} }
+func (p point) mul(k float64) point           { return point{p.x * k, p.y * k} }
+func (p point) dot(q point) float64           { return p.x*q.x + p.y*q.y }
+func (p point) len() float64                  { return math.Hypot(p.x, p.y) }
+func (p point) lerp(q point, t float64) point { return p.add(q.sub(p).mul(t)) }
+
+// perp returns p rotated by 90 degrees.
+func (p point) perp() point { return point{-p.y, p.x} }
+
+type opKind uint8
+
+const (
+	opMove opKind = iota
+	opLine
+	opQuad
+	opCube
+	opClose
+)
+
+// pathOp is a path operation, whose points are in device space.
+type pathOp struct {
+	kind opKind
+	p    [3]point
+}
+
+// transform returns the device space point for the user space point (x, y).
+func (c *Context) transform(x, y float64) point {
+	return point{
+		c.m[0]*x + c.m[1]*y + c.m[2],
+		c.m[3]*x + c.m[4]*y + c.m[5],
+	}
+}
+
+// BeginPath clears the current path.
+func (c *Context) BeginPath() {
+	c.path = c.path[:0]
+	c.hasPen = false
+}
+
+// MoveTo starts a new subpath at (x, y).
+func (c *Context) MoveTo(x, y float64) {
+	p := c.transform(x, y)
+	c.path = append(c.path, pathOp{kind: opMove, p: [3]point{p}})
+	c.first, c.pen, c.hasPen = p, p, true
+}
+
+// ensurePen starts a new subpath at (x, y) if there is no current point.
+func (c *Context) ensurePen(x, y float64) {
+	if !c.hasPen {
+		c.MoveTo(x, y)
+	}
+}
+
+// LineTo adds a line from the current point to (x, y).
+func (c *Context) LineTo(x, y float64) {
+	c.ensurePen(x, y)
+	p := c.transform(x, y)
+	c.path = append(c.path, pathOp{kind: opLine, p: [3]point{p}})
+	c.pen = p
+}
+
+// QuadTo adds a quadratic Bézier curve from the current point, via the
+// control point (x1, y1), to (x, y).
+func (c *Context) QuadTo(x1, y1, x, y float64) {
+	c.ensurePen(x1, y1)
+	p := c.transform(x, y)
+	c.path = append(c.path, pathOp{kind: opQuad, p: [3]point{c.transform(x1, y1), p}})
+	c.pen = p
+}
+
+// CubeTo adds a cubic Bézier curve from the current point, via the control
+// points (x1, y1) and (x2, y2), to (x, y).
+func (c *Context) CubeTo(x1, y1, x2, y2, x, y float64) {
+	c.ensurePen(x1, y1)
+	p := c.transform(x, y)
+	c.path = append(c.path, pathOp{kind: opCube, p: [3]point{c.transform(x1, y1), c.transform(x2, y2), p}})
+	c.pen = p
+}
+
+// ClosePath adds a line from the current point to the start of the current
+// subpath, and closes that subpath.
+func (c *Context) ClosePath() {
+	if !c.hasPen {
+		return
+	}
+	c.path = append(c.path, pathOp{kind: opClose})
+	c.pen = c.first
+}
+
+// Rect adds a closed subpath for the rectangle with top left (x, y), width w
+// and height h.
+func (c *Context) Rect(x, y, w, h float64) {
+	c.MoveTo(x, y)
+	c.LineTo(x+w, y)
+	c.LineTo(x+w, y+h)
+	c.LineTo(x, y+h)
+	c.ClosePath()
+}
+
+// Arc adds a circular arc, centered at (cx, cy) with radius r, from the angle
+// startAngle to endAngle, in radians clockwise on the screen from the positive
+// x axis. It sweeps counter-clockwise if endAngle is less than startAngle. A
+// line is added from the current point, if any, to the start of the arc.
+func (c *Context) Arc(cx, cy, r, startAngle, endAngle float64) {
+	sin, cos := math.Sincos(startAngle)
+	if c.hasPen {
+		c.LineTo(cx+r*cos, cy+r*sin)
+	} else {
+		c.MoveTo(cx+r*cos, cy+r*sin)
+	}
+
+	sweep := endAngle - startAngle
+	if sweep > 2*math.Pi {
+		sweep = 2 * math.Pi
+	} else if sweep < -2*math.Pi {
+		sweep = -2 * math.Pi
+	}
+	// Approximate the arc by cubic Bézier curves of at most 90 degrees each.
+	n := int(math.Ceil(math.Abs(sweep) / (math.Pi / 2)))
+	if n == 0 {
+		return
+	}
+	step := sweep / float64(n)
+	k := 4.0 / 3 * math.Tan(step/4)
+	a := startAngle
+	for i := 0; i < n; i++ {
+		sin0, cos0 := math.Sincos(a)
+		sin1, cos1 := math.Sincos(a + step)
+		c.CubeTo(
+			cx+r*(cos0-k*sin0), cy+r*(sin0+k*cos0),
+			cx+r*(cos1+k*sin1), cy+r*(sin1-k*cos1),
+			cx+r*cos1, cy+r*sin1,
+		)
+		a += step
+	}
+}
+
+// Circle adds a closed subpath for the circle centered at (cx, cy) with
+// radius r.
+func (c *Context) Circle(cx, cy, r float64) {
+	c.hasPen = false
+	c.Arc(cx, cy, r, 0, 2*math.Pi)
+	c.ClosePath()
+}
+
+// flattenTolerance is the maximum distance, in pixels, between a curve and
+// the line segments that approximate it. It is smaller than is visible, as
+// shapes are also shrunk by that much, on their convex side.
+const flattenTolerance = 0.025
+
+// polyline is a flattened subpath. drawn is whether the subpath has any
+// segments, even if they are degenerate, as opposed to only a MoveTo.
+type polyline struct {
+	pts    []point
+	closed bool
+	drawn  bool
+}
+
+// flatten returns the current path's subpaths as polylines, with no
+// consecutive duplicate points.
+func (c *Context) flatten() []polyline {
+	var (
+		lines []polyline
+		cur   *polyline
+		pen   point
+	)
+	add := func(p point) {
+		if n := len(cur.pts); n == 0 || cur.pts[n-1] != p {
+			cur.pts = append(cur.pts, p)
+		}
+		pen = p
+	}
+	for _, op := range c.path {
+		switch op.kind {
+		case opMove:
+			lines = append(lines, polyline{})
+			cur = &lines[len(lines)-1]
+			add(op.p[0])
+		case opLine:
+			cur.drawn = true
+			add(op.p[0])
+		case opQuad:
+			cur.drawn = true
+			p0, p1, p2 := pen, op.p[0], op.p[1]
+			dd := p0.sub(p1.mul(2)).add(p2).len()
+			n := segments(dd / 4)
+			for i := 1; i <= n; i++ {
+				t := float64(i) / float64(n)
+				add(p0.lerp(p1, t).lerp(p1.lerp(p2, t), t))
+			}
+		case opCube:
+			cur.drawn = true
+			p0, p1, p2, p3 := pen, op.p[0], op.p[1], op.p[2]
+			dd := math.Max(
+				p0.sub(p1.mul(2)).add(p2).len(),
+				p1.sub(p2.mul(2)).add(p3).len(),
+			)
+			n := segments(dd * 3 / 4)
+			for i := 1; i <= n; i++ {
+				t := float64(i) / float64(n)
+				q0, q1, q2 := p0.lerp(p1, t), p1.lerp(p2, t), p2.lerp(p3, t)
+				add(q0.lerp(q1, t).lerp(q1.lerp(q2, t), t))
+			}
+		case opClose:
+			cur.closed, cur.drawn = true, true
+			if n := len(cur.pts); n > 1 && cur.pts[n-1] == cur.pts[0] {
+				cur.pts = cur.pts[:n-1]
+			}
+			// Any further segments start a new subpath at the same point.
+			first := cur.pts[0]
+			lines = append(lines, polyline{})
+			cur = &lines[len(lines)-1]
+			add(first)
+		}
+	}
+	return lines
+}
+
+// segments returns the number of line segments that approximate a curve whose
+// error, when approximated by a single segment, is e.
+func segments(e float64) int {
+	n := int(math.Ceil(math.Sqrt(e / flattenTolerance)))
+	if n < 1 {
+		return 1
+	} else if n > 256 {
+		return 256
+	}
+	return n
+}
@@ -0,0 +1,317 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package canvas
+
+import (
+	"math"
+)
+
+// Cap is the shape at the ends of stroked open subpaths and dashes.
+type Cap uint8
+
+const (
+	// ButtCap ends a stroke at its end point.
+	ButtCap Cap = iota
+	// RoundCap ends a stroke with a semicircle.
+	RoundCap
+	// SquareCap ends a stroke half its width beyond its end point.
+	SquareCap
+)
+
+// Join is the shape where a stroke's segments meet.
+type Join uint8
+
+const (
+	// MiterJoin extends the segments' outer edges until they meet, unless
+	// that is beyond the Stroke's MiterLimit, in which case it is a
+	// BevelJoin.
+	MiterJoin Join = iota
+	// RoundJoin joins the segments with a circular arc.
+	RoundJoin
+	// BevelJoin joins the segments' outer corners with a straight line.
+	BevelJoin
+)
+
+// DefaultMiterLimit is the default miter limit, the same as the HTML canvas
+// element's.
+const DefaultMiterLimit = 10
+
+// Stroke is how paths are stroked.
+type Stroke struct {
+	// Width is the stroke's width, in user space. Zero means 1.
+	Width float64
+
+	Cap  Cap
+	Join Join
+
+	// MiterLimit is the maximum ratio of a MiterJoin's length to the stroke's
+	// width. Zero means DefaultMiterLimit.
+	MiterLimit float64
+
+	// Dashes, if non-empty, are the alternating lengths of dashes and gaps,
+	// in user space. If there are an odd number, they are repeated to make an
+	// even number.
+	Dashes []float64
+
+	// DashOffset is the distance into the dash pattern at which each subpath
+	// starts.
+	DashOffset float64
+}
+
+// Stroke strokes the current path with the paint p.
+//
+// The stroke is computed in device space, with its width and dashes scaled by
+// the average scale of the transformation, so a transformation that does not
+// scale uniformly does not make the stroke's width vary with direction.
+func (c *Context) Stroke(p Paint, s Stroke) {
+	if c.vis.Empty() {
+		return
+	}
+	k := c.scale()
+	width := s.Width
+	if width == 0 {
+		width = 1
+	}
+	st := stroker{
+		c:          c,
+		hw:         width * k / 2,
+		cap:        s.Cap,
+		join:       s.Join,
+		miterLimit: s.MiterLimit,
+	}
+	if st.hw <= 0 || math.IsNaN(st.hw) {
+		return
+	}
+	if st.miterLimit <= 0 {
+		st.miterLimit = DefaultMiterLimit
+	}
+	dashes, total := []float64(nil), 0.0
+	for _, d := range s.Dashes {
+		if d < 0 || math.IsNaN(d) || math.IsInf(d, 0) {
+			dashes = nil
+			break
+		}
+		dashes = append(dashes, d*k)
+		total += d * k
+	}
+	if len(dashes)%2 == 1 {
+		dashes = append(dashes, dashes...)
+		total *= 2
+	}
+	if total == 0 {
+		dashes = nil
+	}
+
+	c.z.Reset(c.vis.Dx(), c.vis.Dy())
+	for _, l := range c.flatten() {
+		if !l.drawn {
+			continue
+		}
+		if dashes == nil {
+			st.polyline(l.pts, l.closed)
+			continue
+		}
+		pts := l.pts
+		if l.closed {
+			pts = append(pts[:len(pts):len(pts)], pts[0])
+		}
+		st.dashed(pts, dashes, math.Mod(s.DashOffset*k, total))
+	}
+	c.draw(p)
+}
+
+// stroker adds the polygons that make up a stroke to its Context's
+// rasterizer. All of the polygons have the same orientation, so that where
+// they overlap, they combine instead of cancelling each other out.
+type stroker struct {
+	c          *Context
+	hw         float64
+	cap        Cap
+	join       Join
+	miterLimit float64
+}
+
+// polygon adds a closed polygon.
+func (s *stroker) polygon(pts ...point) {
+	area := 0.0
+	for i, p := range pts {
+		q := pts[(i+1)%len(pts)]
+		area += p.x*q.y - q.x*p.y
+	}
+	if area == 0 {
+		return
+	}
+	if area < 0 {
+		for i, j := 0, len(pts)-1; i < j; i, j = i+1, j-1 {
+			pts[i], pts[j] = pts[j], pts[i]
+		}
+	}
+	z := &s.c.z
+	z.MoveTo(s.c.raster(pts[0]))
+	for _, p := range pts[1:] {
+		s.c.lineTo(p)
+	}
+	z.ClosePath()
+}
+
+// circle adds a circle of the stroke's width, centered at p.
+func (s *stroker) circle(p point) {
+	n := 8
+	if s.hw > flattenTolerance {
+		n = int(math.Ceil(math.Pi / math.Acos(1-flattenTolerance/s.hw)))
+	}
+	if n < 8 {
+		n = 8
+	} else if n > 256 {
+		n = 256
+	}
+	pts := make([]point, n)
+	for i := range pts {
+		sin, cos := math.Sincos(2 * math.Pi * float64(i) / float64(n))
+		pts[i] = point{p.x + s.hw*cos, p.y + s.hw*sin}
+	}
+	s.polygon(pts...)
+}
+
+// polyline strokes a polyline, which has no consecutive duplicate points.
+func (s *stroker) polyline(pts []point, closed bool) {
+	if len(pts) == 1 {
+		s.dot(pts[0])
+		return
+	}
+	n := len(pts)
+	for i := 0; i < n-1; i++ {
+		s.segment(pts[i], pts[i+1])
+	}
+	if closed {
+		s.segment(pts[n-1], pts[0])
+		for i := range pts {
+			s.joint(pts[(i+n-1)%n], pts[i], pts[(i+1)%n])
+		}
+		return
+	}
+	for i := 1; i < n-1; i++ {
+		s.joint(pts[i-1], pts[i], pts[i+1])
+	}
+	s.capAt(pts[0], pts[1])
+	s.capAt(pts[n-1], pts[n-2])
+}
+
+// dot strokes a degenerate, zero length, subpath at p, which is only visible
+// with a round or square cap.
+func (s *stroker) dot(p point) {
+	switch s.cap {
+	case RoundCap:
+		s.circle(p)
+	case SquareCap:
+		s.polygon(
+			point{p.x - s.hw, p.y - s.hw}, point{p.x + s.hw, p.y - s.hw},
+			point{p.x + s.hw, p.y + s.hw}, point{p.x - s.hw, p.y + s.hw},
+		)
+	}
+}
+
+// segment adds the rectangle for the segment from a to b.
+func (s *stroker) segment(a, b point) {
+	d := b.sub(a)
+	n := d.perp().mul(s.hw / d.len())
+	s.polygon(a.add(n), b.add(n), b.sub(n), a.sub(n))
+}
+
+// joint adds the join at b, between the segments from a to b and from b to c.
+func (s *stroker) joint(a, b, c point) {
+	if s.join == RoundJoin {
+		s.circle(b)
+		return
+	}
+	d0, d1 := b.sub(a), c.sub(b)
+	d0, d1 = d0.mul(1/d0.len()), d1.mul(1/d1.len())
+	n0, n1 := d0.perp().mul(s.hw), d1.perp().mul(s.hw)
+	// Only the outer side of the join needs filling in: the inner side is
+	// covered by the segments.
+	if n0.dot(d1) > 0 {
+		n0, n1 = n0.mul(-1), n1.mul(-1)
+	}
+	p0, p1 := b.add(n0), b.add(n1)
+	if s.join == MiterJoin {
+		// The ratio of the miter's length to the stroke's width is
+		// 1/cos(θ/2), where θ is the angle between the segments' directions.
+		cosHalf := math.Sqrt((1 + d0.dot(d1)) / 2)
+		if cosHalf > 0 && 1/cosHalf <= s.miterLimit {
+			bisector := n0.add(n1)
+			if l := bisector.len(); l > 0 {
+				tip := b.add(bisector.mul(s.hw / cosHalf / l))
+				s.polygon(b, p0, tip, p1)
+				return
+			}
+		}
+	}
+	s.polygon(b, p0, p1)
+}
+
+// capAt adds the cap at the end p of a subpath, whose neighboring point is q.
+func (s *stroker) capAt(p, q point) {
+	switch s.cap {
+	case RoundCap:
+		s.circle(p)
+	case SquareCap:
+		d := p.sub(q)
+		d = d.mul(s.hw / d.len())
+		n := d.perp()
+		s.polygon(p.add(n), p.add(n).add(d), p.sub(n).add(d), p.sub(n))
+	}
+}
+
+// dashed strokes the dashes of an open polyline. offset is the distance into
+// the dash pattern at which the polyline starts.
+func (s *stroker) dashed(pts []point, dashes []float64, offset float64) {
+	if offset < 0 {
+		offset += sumOf(dashes)
+	}
+	// i is the index of the current dash or gap, and left is how much of it
+	// is left.
+	i := 0
+	for offset >= dashes[i] {
+		offset -= dashes[i]
+		i = (i + 1) % len(dashes)
+	}
+	left := dashes[i] - offset
+
+	var dash []point
+	if i%2 == 0 {
+		dash = append(dash, pts[0])
+	}
+	for j := 1; j < len(pts); j++ {
+		a, b := pts[j-1], pts[j]
+		l := b.sub(a).len()
+		t := 0.0
+		for l-t > left {
+			t += left
+			p := a.lerp(b, t/l)
+			if i%2 == 0 {
+				s.polyline(append(dash, p), false)
+				dash = dash[:0]
+			} else {
+				dash = append(dash[:0], p)
+			}
+			i = (i + 1) % len(dashes)
+			left = dashes[i]
+		}
+		left -= l - t
+		if i%2 == 0 {
+			dash = append(dash, b)
+		}
+	}
+	if i%2 == 0 && len(dash) > 1 {
+		s.polyline(dash, false)
+	}
+}
+
+func sumOf(x []float64) (sum float64) {
+	for _, f := range x {
+		sum += f
+	}
+	return sum
+}
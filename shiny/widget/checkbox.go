@@ -0,0 +1,115 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/draw"
+
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+)
+
+// Checkbox is a leaf widget that can be checked or unchecked. Clicking it, by
+// a tap or, when it has the keyboard focus, by the space bar or the enter key,
+// toggles it.
+type Checkbox struct {
+	node.LeafEmbed
+	Text string
+
+	// OnChange, if non-nil, is called when the user toggles the checkbox.
+	OnChange func(checked bool)
+
+	checked bool
+	c       control
+}
+
+// NewCheckbox returns a new Checkbox widget.
+func NewCheckbox(text string, checked bool) *Checkbox {
+	w := &Checkbox{
+		Text:    text,
+		checked: checked,
+	}
+	w.Wrapper = w
+	w.Focusable = true
+	return w
+}
+
+// Checked returns whether the checkbox is checked.
+func (w *Checkbox) Checked() bool { return w.checked }
+
+// SetChecked sets whether the checkbox is checked. It does not call OnChange.
+func (w *Checkbox) SetChecked(checked bool) {
+	if w.checked != checked {
+		w.checked = checked
+		w.Mark(node.MarkNeedsPaintBase)
+	}
+}
+
+// Disabled returns whether the checkbox is disabled.
+func (w *Checkbox) Disabled() bool { return w.c.disabled }
+
+// SetDisabled sets whether the checkbox is disabled. A disabled checkbox
+// cannot be toggled or focused.
+func (w *Checkbox) SetDisabled(disabled bool) { w.c.setDisabled(&w.Embed, disabled) }
+
+func (w *Checkbox) Measure(t *theme.Theme, widthHint, heightHint int) {
+	w.MeasuredSize = measureMarked(t, w.Text)
+}
+
+func (w *Checkbox) Baseline(t *theme.Theme) int { return fontAscent(t) }
+
+func (w *Checkbox) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
+	w.Marks.UnmarkNeedsPaintBase()
+	r := w.Rect.Add(origin)
+	dst := ctx.Dst.SubImage(r).(*image.RGBA)
+	if dst.Bounds().Empty() {
+		return nil
+	}
+
+	t := ctx.Theme
+	lw := lineWidth(t)
+	box := paintMarked(dst, t, r, w.Text, w.c.labelInk(t))
+	if w.checked && !w.c.disabled {
+		draw.Draw(dst, box, t.GetPalette().Accent(), image.Point{}, draw.Src)
+		drawCheckMark(dst, box, t.GetPalette().Background(), lw)
+	} else {
+		draw.Draw(dst, box, w.c.fill(t), image.Point{}, draw.Src)
+		drawBorder(dst, box, w.c.labelInk(t), lw)
+		if w.checked {
+			drawCheckMark(dst, box, w.c.labelInk(t), lw)
+		}
+	}
+	w.c.paintFocus(dst, t, r)
+	return nil
+}
+
+func (w *Checkbox) OnInputEvent(e interface{}, origin image.Point) node.EventHandled {
+	handled, activate := w.c.onInputEvent(&w.Embed, e)
+	if activate {
+		w.toggle()
+	}
+	return handled
+}
+
+func (w *Checkbox) toggle() {
+	w.SetChecked(!w.checked)
+	if w.OnChange != nil {
+		w.OnChange(w.checked)
+	}
+}
+
+func (w *Checkbox) Accessibility() node.Accessibility {
+	s := w.c.state()
+	if w.checked {
+		s |= node.StateChecked
+	}
+	return node.Accessibility{
+		Role:    node.RoleCheckBox,
+		Name:    w.Text,
+		State:   s,
+		Actions: w.c.actions(node.Action{Name: node.ActionToggle, Do: w.toggle}),
+	}
+}
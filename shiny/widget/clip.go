@@ -0,0 +1,116 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"golang.org/x/exp/shiny/screen"
+	"golang.org/x/image/math/f64"
+)
+
+// clipDrawer is a screen.Drawer that clips what is drawn to a rectangle, in
+// dst space. It is used to clip the effects painted by a widget's
+// descendants, such as a Scroller's inner widget, to that widget.
+//
+// Drawing that is entirely inside or outside of the clip rectangle is passed
+// on or skipped. Drawing that is partly inside is clipped exactly if its
+// transform is an integer translation, as is typical for widgets' effects.
+// Otherwise, it is passed on unclipped.
+//
+// TODO: clip other transforms, which needs a clip rectangle or mask in the
+// screen.Drawer interface.
+type clipDrawer struct {
+	d    screen.Drawer
+	clip image.Rectangle
+}
+
+// newClipDrawer returns a clipDrawer that clips drawing to d to the rectangle
+// r, which is transformed by src2dst into dst space.
+func newClipDrawer(d screen.Drawer, src2dst f64.Aff3, r image.Rectangle) *clipDrawer {
+	return &clipDrawer{d: d, clip: transformRect(&src2dst, r)}
+}
+
+func (c *clipDrawer) Draw(src2dst f64.Aff3, src screen.Texture, sr image.Rectangle, op draw.Op, opts *screen.DrawOptions) {
+	if sr, ok := c.clipAff3(&src2dst, sr); ok {
+		c.d.Draw(src2dst, src, sr, op, opts)
+	}
+}
+
+func (c *clipDrawer) DrawUniform(src2dst f64.Aff3, src color.Color, sr image.Rectangle, op draw.Op, opts *screen.DrawOptions) {
+	if sr, ok := c.clipAff3(&src2dst, sr); ok {
+		c.d.DrawUniform(src2dst, src, sr, op, opts)
+	}
+}
+
+func (c *clipDrawer) Copy(dp image.Point, src screen.Texture, sr image.Rectangle, op draw.Op, opts *screen.DrawOptions) {
+	dr := sr.Add(dp.Sub(sr.Min))
+	clipped := dr.Intersect(c.clip)
+	if clipped.Empty() {
+		return
+	}
+	sr = clipped.Add(sr.Min.Sub(dp))
+	c.d.Copy(clipped.Min, src, sr, op, opts)
+}
+
+func (c *clipDrawer) Scale(dr image.Rectangle, src screen.Texture, sr image.Rectangle, op draw.Op, opts *screen.DrawOptions) {
+	clipped := dr.Intersect(c.clip)
+	switch {
+	case clipped.Empty():
+		return
+	case clipped != dr:
+		// Clip sr in proportion, to the nearest pixel.
+		scale := func(v, d0, d1, s0, s1 int) int {
+			return s0 + int(math.Round(float64(v-d0)*float64(s1-s0)/float64(d1-d0)))
+		}
+		sr = image.Rect(
+			scale(clipped.Min.X, dr.Min.X, dr.Max.X, sr.Min.X, sr.Max.X),
+			scale(clipped.Min.Y, dr.Min.Y, dr.Max.Y, sr.Min.Y, sr.Max.Y),
+			scale(clipped.Max.X, dr.Min.X, dr.Max.X, sr.Min.X, sr.Max.X),
+			scale(clipped.Max.Y, dr.Min.Y, dr.Max.Y, sr.Min.Y, sr.Max.Y),
+		)
+		if sr.Empty() {
+			return
+		}
+		dr = clipped
+	}
+	c.d.Scale(dr, src, sr, op, opts)
+}
+
+// clipAff3 returns sr clipped so that, when transformed by src2dst, it is
+// inside c.clip, and whether anything is left to draw.
+func (c *clipDrawer) clipAff3(src2dst *f64.Aff3, sr image.Rectangle) (image.Rectangle, bool) {
+	dr := transformRect(src2dst, sr)
+	switch {
+	case dr.In(c.clip):
+		return sr, true
+	case !dr.Overlaps(c.clip):
+		return sr, false
+	}
+	a := src2dst
+	if a[0] != 1 || a[1] != 0 || a[3] != 0 || a[4] != 1 || a[2] != math.Trunc(a[2]) || a[5] != math.Trunc(a[5]) {
+		return sr, true
+	}
+	d := image.Point{int(a[2]), int(a[5])}
+	sr = sr.Intersect(c.clip.Sub(d))
+	return sr, !sr.Empty()
+}
+
+// transformRect returns the smallest integer rectangle that contains r
+// transformed by a.
+func transformRect(a *f64.Aff3, r image.Rectangle) image.Rectangle {
+	x0, y0 := math.Inf(+1), math.Inf(+1)
+	x1, y1 := math.Inf(-1), math.Inf(-1)
+	for _, p := range [4]image.Point{r.Min, {r.Max.X, r.Min.Y}, {r.Min.X, r.Max.Y}, r.Max} {
+		x := a[0]*float64(p.X) + a[1]*float64(p.Y) + a[2]
+		y := a[3]*float64(p.X) + a[4]*float64(p.Y) + a[5]
+		x0, y0 = math.Min(x0, x), math.Min(y0, y)
+		x1, y1 = math.Max(x1, x), math.Max(y1, y)
+	}
+	return image.Rect(int(math.Floor(x0)), int(math.Floor(y0)), int(math.Ceil(x1)), int(math.Ceil(y1)))
+}
@@ -0,0 +1,297 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/draw"
+
+	"golang.org/x/exp/shiny/gesture"
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/mouse"
+)
+
+// control is the interaction state that is common to clickable controls such
+// as Button and Checkbox.
+type control struct {
+	disabled bool
+	focused  bool
+	pressed  bool
+
+	// hovered is whether the mouse pointer is over the control, as reported
+	// by node.PointerEvents.
+	hovered bool
+}
+
+func (c *control) setDisabled(n *node.Embed, disabled bool) {
+	if c.disabled == disabled {
+		return
+	}
+	c.disabled = disabled
+	c.focused = false
+	c.pressed = false
+	c.hovered = false
+	n.Focusable = !disabled
+	n.Mark(node.MarkNeedsPaintBase)
+}
+
+// onInputEvent updates the state of the control n for focus, mouse, gesture
+// and key events. It returns whether the event was handled and whether it
+// activated the control, by a tap or by pressing and releasing the space bar
+// or the enter key.
+//
+// Only left button presses and releases, and mouse moves, are handled. Other
+// mouse events, such as wheel steps, are left for an ancestor such as a
+// Scroller.
+func (c *control) onInputEvent(n *node.Embed, e interface{}) (handled node.EventHandled, activate bool) {
+	old := *c
+	if e, ok := e.(node.FocusEvent); ok {
+		// Focus events are tracked even when disabled, as a control is
+		// typically blurred after it becomes disabled and unfocusable.
+		c.focused = e.Focused && !c.disabled
+		c.pressed = false
+		if *c != old {
+			n.Mark(node.MarkNeedsPaintBase)
+		}
+		return node.Handled, false
+	}
+	if c.disabled {
+		return node.NotHandled, false
+	}
+	switch e := e.(type) {
+	case node.PointerEvent:
+		c.hovered = e.Entered
+		handled = node.Handled
+
+	case mouse.Event:
+		switch {
+		case e.Direction == mouse.DirNone:
+		case e.Button != mouse.ButtonLeft:
+			return node.NotHandled, false
+		case e.Direction == mouse.DirPress:
+			c.pressed = true
+		case e.Direction == mouse.DirRelease:
+			c.pressed = false
+		default:
+			return node.NotHandled, false
+		}
+		handled = node.Handled
+
+	case gesture.Event:
+		switch e.Type {
+		case gesture.TypeTap:
+			activate = true
+		case gesture.TypeEnd:
+			c.pressed = false
+		}
+		handled = node.Handled
+
+	case key.Event:
+		if e.Code != key.CodeSpacebar && e.Code != key.CodeReturnEnter && e.Code != key.CodeKeypadEnter {
+			break
+		}
+		switch e.Direction {
+		case key.DirPress:
+			c.pressed = true
+		case key.DirRelease:
+			activate, c.pressed = c.pressed, false
+		default:
+			// Some drivers do not distinguish presses from releases.
+			activate = true
+		}
+		handled = node.Handled
+	}
+
+	if *c != old {
+		n.Mark(node.MarkNeedsPaintBase)
+	}
+	return handled, activate
+}
+
+// state returns the control's accessibility state.
+func (c *control) state() node.State {
+	s := node.State(0)
+	if c.disabled {
+		s |= node.StateDisabled
+	}
+	if c.focused {
+		s |= node.StateFocused
+	}
+	if c.pressed {
+		s |= node.StatePressed
+	}
+	return s
+}
+
+// actions returns the control's accessibility actions, which are none if the
+// control is disabled.
+func (c *control) actions(actions ...node.Action) []node.Action {
+	if c.disabled {
+		return nil
+	}
+	return actions
+}
+
+// fill returns the color for a control's surface, such as a button's face.
+func (c *control) fill(t *theme.Theme) *image.Uniform {
+	pal := t.GetPalette()
+	switch {
+	case c.disabled:
+		return pal.Light()
+	case c.pressed:
+		return pal.Accent()
+	case c.hovered:
+		return pal.Dark()
+	}
+	return pal.Neutral()
+}
+
+// ink returns the color for a control's text and marks, drawn on its fill
+// color.
+func (c *control) ink(t *theme.Theme) *image.Uniform {
+	pal := t.GetPalette()
+	switch {
+	case c.disabled:
+		return pal.Disabled()
+	case c.pressed:
+		return pal.Background()
+	}
+	return pal.Foreground()
+}
+
+// labelInk returns the color for a control's text label that is not drawn on
+// its fill color, such as a checkbox's label.
+func (c *control) labelInk(t *theme.Theme) *image.Uniform {
+	pal := t.GetPalette()
+	if c.disabled {
+		return pal.Disabled()
+	}
+	return pal.Foreground()
+}
+
+// paintFocus draws a focus ring around r, if the control has the focus.
+func (c *control) paintFocus(dst *image.RGBA, t *theme.Theme, r image.Rectangle) {
+	if c.focused {
+		drawBorder(dst, r, t.GetPalette().Accent(), lineWidth(t))
+	}
+}
+
+// lineWidth returns the width of thin lines, such as borders and carets.
+func lineWidth(t *theme.Theme) int {
+	if w := t.Pixels(unit.DIPs(1)).Round(); w > 1 {
+		return w
+	}
+	return 1
+}
+
+// drawBorder draws a border of the given width just inside r.
+func drawBorder(dst *image.RGBA, r image.Rectangle, src image.Image, width int) {
+	draw.Draw(dst, image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+width), src, image.Point{}, draw.Src)
+	draw.Draw(dst, image.Rect(r.Min.X, r.Max.Y-width, r.Max.X, r.Max.Y), src, image.Point{}, draw.Src)
+	draw.Draw(dst, image.Rect(r.Min.X, r.Min.Y, r.Min.X+width, r.Max.Y), src, image.Point{}, draw.Src)
+	draw.Draw(dst, image.Rect(r.Max.X-width, r.Min.Y, r.Max.X, r.Max.Y), src, image.Point{}, draw.Src)
+}
+
+// kappa is the distance, relative to the radius, of the control points of the
+// cubic Bézier curves that approximate a quarter circle.
+const kappa = 0.5522847498
+
+// fillRoundRect fills r, with fully rounded ends along its shorter axis, such
+// as a pill shape or, if r is square, a circle.
+func fillRoundRect(dst *image.RGBA, r image.Rectangle, src image.Image) {
+	if r.Empty() {
+		return
+	}
+	w, h := float32(r.Dx()), float32(r.Dy())
+	rad := h / 2
+	if w < h {
+		rad = w / 2
+	}
+	k := rad * kappa
+
+	z := vector.NewRasterizer(r.Dx(), r.Dy())
+	z.MoveTo(rad, 0)
+	z.LineTo(w-rad, 0)
+	z.CubeTo(w-rad+k, 0, w, rad-k, w, rad)
+	z.LineTo(w, h-rad)
+	z.CubeTo(w, h-rad+k, w-rad+k, h, w-rad, h)
+	z.LineTo(rad, h)
+	z.CubeTo(rad-k, h, 0, h-rad+k, 0, h-rad)
+	z.LineTo(0, rad)
+	z.CubeTo(0, rad-k, rad-k, 0, rad, 0)
+	z.ClosePath()
+	z.DrawOp = draw.Over
+	z.Draw(dst, r, src, image.Point{})
+}
+
+// drawCheckMark draws a tick inside r, with strokes of the given width.
+func drawCheckMark(dst *image.RGBA, r image.Rectangle, src image.Image, width int) {
+	if r.Empty() {
+		return
+	}
+	w, h, s := float32(r.Dx()), float32(r.Dy()), float32(width)
+
+	// The tick is a polyline from (0.15, 0.5) to (0.4, 0.75) to (0.85, 0.25),
+	// in units of r's size, thickened by s.
+	z := vector.NewRasterizer(r.Dx(), r.Dy())
+	z.MoveTo(0.15*w, 0.5*h-s)
+	z.LineTo(0.4*w, 0.75*h-s)
+	z.LineTo(0.85*w, 0.25*h-s)
+	z.LineTo(0.85*w, 0.25*h+s)
+	z.LineTo(0.4*w, 0.75*h+s)
+	z.LineTo(0.15*w, 0.5*h+s)
+	z.ClosePath()
+	z.DrawOp = draw.Over
+	z.Draw(dst, r, src, image.Point{})
+}
+
+// markedMetrics returns the size of a control's mark, such as a checkbox's
+// box, and the gap between that mark and the control's text label.
+func markedMetrics(t *theme.Theme, face font.Face) (mark, gap int) {
+	m := face.Metrics()
+	return m.Ascent.Ceil(), t.Pixels(unit.Ems(0.5)).Ceil()
+}
+
+// measureMarked returns the size of a control that is a mark followed by a
+// text label.
+func measureMarked(t *theme.Theme, text string) image.Point {
+	face := t.AcquireFontFace(theme.FontFaceOptions{})
+	defer t.ReleaseFontFace(theme.FontFaceOptions{}, face)
+	m := face.Metrics()
+
+	mark, gap := markedMetrics(t, face)
+	return image.Point{
+		mark + gap + font.MeasureString(face, text).Ceil(),
+		m.Ascent.Ceil() + m.Descent.Ceil(),
+	}
+}
+
+// paintMarked draws the text label of a control that is a mark followed by a
+// text label, in the rectangle r. It returns where the mark should be drawn.
+func paintMarked(dst *image.RGBA, t *theme.Theme, r image.Rectangle, text string, ink image.Image) (mark image.Rectangle) {
+	face := t.AcquireFontFace(theme.FontFaceOptions{})
+	defer t.ReleaseFontFace(theme.FontFaceOptions{}, face)
+	m := face.Metrics()
+	ascent, descent := m.Ascent.Ceil(), m.Descent.Ceil()
+
+	size, gap := markedMetrics(t, face)
+	y := (r.Min.Y + r.Max.Y - ascent - descent) / 2
+	mark = image.Rect(r.Min.X, y+ascent-size, r.Min.X+size, y+ascent)
+
+	d := font.Drawer{
+		Dst:  dst,
+		Src:  ink,
+		Face: face,
+		Dot:  fixed.P(mark.Max.X+gap, y+ascent),
+	}
+	d.DrawString(text)
+	return mark
+}
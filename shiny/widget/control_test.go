@@ -0,0 +1,309 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"testing"
+
+	"golang.org/x/exp/shiny/gesture"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/mouse"
+)
+
+// testControl is a clickable control, such as a Button.
+type testControl interface {
+	node.Node
+	SetDisabled(disabled bool)
+	Accessibility() node.Accessibility
+}
+
+// testControls returns constructors for each clickable control, each of which
+// counts the number of times that it is activated.
+func testControls() []struct {
+	name string
+	new  func(n *int) testControl
+} {
+	return []struct {
+		name string
+		new  func(n *int) testControl
+	}{
+		{"Button", func(n *int) testControl {
+			return NewButton("b", func() { *n++ })
+		}},
+		{"Checkbox", func(n *int) testControl {
+			w := NewCheckbox("c", false)
+			w.OnChange = func(bool) { *n++ }
+			return w
+		}},
+		{"Radio", func(n *int) testControl {
+			w := NewRadio("r", nil)
+			w.Group().OnChange = func(*Radio) { *n++ }
+			return w
+		}},
+		{"Switch", func(n *int) testControl {
+			w := NewSwitch(false)
+			w.OnChange = func(bool) { *n++ }
+			return w
+		}},
+	}
+}
+
+func keyEvent(code key.Code, dir key.Direction) key.Event {
+	return key.Event{Code: code, Direction: dir}
+}
+
+func mouseEvent(x, y float32, b mouse.Button, dir mouse.Direction) mouse.Event {
+	return mouse.Event{X: x, Y: y, Button: b, Direction: dir}
+}
+
+func TestControlActivation(t *testing.T) {
+	tap := gesture.Event{Type: gesture.TypeTap}
+	testCases := []struct {
+		desc        string
+		events      []interface{}
+		wantHandled node.EventHandled
+		want        int
+	}{{
+		"space",
+		[]interface{}{keyEvent(key.CodeSpacebar, key.DirPress), keyEvent(key.CodeSpacebar, key.DirRelease)},
+		node.Handled, 1,
+	}, {
+		"enter",
+		[]interface{}{keyEvent(key.CodeReturnEnter, key.DirPress), keyEvent(key.CodeReturnEnter, key.DirRelease)},
+		node.Handled, 1,
+	}, {
+		"keypad enter, no direction",
+		[]interface{}{keyEvent(key.CodeKeypadEnter, key.DirNone)},
+		node.Handled, 1,
+	}, {
+		"space, released only",
+		[]interface{}{keyEvent(key.CodeSpacebar, key.DirRelease)},
+		node.Handled, 0,
+	}, {
+		"other key",
+		[]interface{}{keyEvent(key.CodeA, key.DirPress), keyEvent(key.CodeA, key.DirRelease)},
+		node.NotHandled, 0,
+	}, {
+		"tap",
+		[]interface{}{tap},
+		node.Handled, 1,
+	}, {
+		// Only a tap activates a control, which the gesture package does
+		// not send for a press that is released outside of the control.
+		"mouse press, released outside",
+		[]interface{}{
+			mouseEvent(5, 5, mouse.ButtonLeft, mouse.DirPress),
+			mouseEvent(500, 5, mouse.ButtonNone, mouse.DirNone),
+			mouseEvent(500, 5, mouse.ButtonLeft, mouse.DirRelease),
+			gesture.Event{Type: gesture.TypeEnd},
+		},
+		node.Handled, 0,
+	}, {
+		"wheel",
+		[]interface{}{mouseEvent(5, 5, mouse.ButtonWheelDown, mouse.DirStep)},
+		node.NotHandled, 0,
+	}}
+	for _, c := range testControls() {
+		for _, tc := range testCases {
+			n := 0
+			w := c.new(&n)
+			w.Wrappee().Rect = image.Rect(0, 0, 50, 20)
+			var handled node.EventHandled
+			for _, e := range tc.events {
+				handled = w.OnInputEvent(e, image.Point{})
+			}
+			if handled != tc.wantHandled {
+				t.Errorf("%s, %s: handled: got %v, want %v", c.name, tc.desc, handled, tc.wantHandled)
+			}
+			if n != tc.want {
+				t.Errorf("%s, %s: activated %d times, want %d", c.name, tc.desc, n, tc.want)
+			}
+			if s := w.Accessibility().State; s&node.StatePressed != 0 {
+				t.Errorf("%s, %s: still pressed", c.name, tc.desc)
+			}
+		}
+	}
+}
+
+func TestControlDisabled(t *testing.T) {
+	for _, c := range testControls() {
+		n := 0
+		w := c.new(&n)
+		w.Wrappee().Rect = image.Rect(0, 0, 50, 20)
+		f := &node.Focus{}
+		f.Set(w)
+		if s := w.Accessibility().State; s&node.StateFocused == 0 {
+			t.Errorf("%s: not focused", c.name)
+		}
+
+		// Disabling a control makes it unfocusable, and drops the focus.
+		w.SetDisabled(true)
+		f.Validate(w)
+		if f.Node() != nil {
+			t.Errorf("%s: disabled: focus is %v, want none", c.name, f.Node())
+		}
+		a := w.Accessibility()
+		if a.State&node.StateFocused != 0 || a.State&node.StateDisabled == 0 {
+			t.Errorf("%s: disabled: got state %v", c.name, a.State)
+		}
+		if len(a.Actions) != 0 {
+			t.Errorf("%s: disabled: got %d actions, want none", c.name, len(a.Actions))
+		}
+		f.Set(w)
+		if f.Node() != nil {
+			t.Errorf("%s: disabled: focus set to %v, want none", c.name, f.Node())
+		}
+
+		// A disabled control ignores input.
+		for _, e := range []interface{}{
+			keyEvent(key.CodeSpacebar, key.DirPress),
+			keyEvent(key.CodeSpacebar, key.DirRelease),
+			mouseEvent(5, 5, mouse.ButtonLeft, mouse.DirPress),
+			gesture.Event{Type: gesture.TypeTap},
+		} {
+			if w.OnInputEvent(e, image.Point{}) != node.NotHandled {
+				t.Errorf("%s: disabled: %T handled", c.name, e)
+			}
+		}
+		if n != 0 {
+			t.Errorf("%s: disabled: activated %d times", c.name, n)
+		}
+
+		w.SetDisabled(false)
+		f.Set(w)
+		if f.Node() != node.Node(w) {
+			t.Errorf("%s: re-enabled: not focusable", c.name)
+		}
+	}
+}
+
+func TestRadioGroup(t *testing.T) {
+	g := &RadioGroup{}
+	var changes []*Radio
+	g.OnChange = func(r *Radio) { changes = append(changes, r) }
+	a, b := NewRadio("a", g), NewRadio("b", g)
+	tap := gesture.Event{Type: gesture.TypeTap}
+
+	b.OnInputEvent(tap, image.Point{})
+	if !b.Selected() || a.Selected() || g.Selected() != b {
+		t.Errorf("tap b: got selected %v, want b", g.Selected())
+	}
+	// Tapping the selected radio doesn't change anything.
+	b.OnInputEvent(tap, image.Point{})
+	a.OnInputEvent(tap, image.Point{})
+	if !a.Selected() || b.Selected() {
+		t.Errorf("tap a: got selected %v, want a", g.Selected())
+	}
+	if len(changes) != 2 || changes[0] != b || changes[1] != a {
+		t.Errorf("OnChange: got %v, want b then a", changes)
+	}
+
+	// Select does not call OnChange.
+	g.Select(nil)
+	if a.Selected() || b.Selected() || len(changes) != 2 {
+		t.Errorf("Select(nil): got selected %v, %d changes", g.Selected(), len(changes))
+	}
+
+	// Radios with no group are in groups of their own.
+	c, d := NewRadio("c", nil), NewRadio("d", nil)
+	c.OnInputEvent(tap, image.Point{})
+	d.OnInputEvent(tap, image.Point{})
+	if !c.Selected() || !d.Selected() {
+		t.Error("radios with no group: not both selected")
+	}
+}
+
+func TestSliderClamp(t *testing.T) {
+	testCases := []struct {
+		min, max, step, value float64
+		want                  float64
+	}{
+		{0, 10, 0, 5, 5},
+		{0, 10, 0, 15, 10},
+		{0, 10, 0, -5, 0},
+		{0, 10, 2.5, 3.6, 2.5},
+		{0, 10, 2.5, 3.8, 5},
+		{0, 10, 2.5, 9.9, 10},
+		// Steps are multiples above Min.
+		{1, 10, 2, 4, 5},
+		{1, 10, 2, 3.9, 3},
+		// The top step is clamped to Max.
+		{1, 10, 2, 10, 10},
+	}
+	for _, tc := range testCases {
+		w := NewSlider(tc.min, tc.max, 0)
+		w.Step = tc.step
+		w.SetValue(tc.value)
+		if got := w.Value(); got != tc.want {
+			t.Errorf("[%v, %v] step %v, SetValue(%v): got %v, want %v",
+				tc.min, tc.max, tc.step, tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestSliderInput(t *testing.T) {
+	w := NewSlider(0, 100, 50)
+	var changes []float64
+	w.OnChange = func(v float64) { changes = append(changes, v) }
+	// The thumb's center moves from x=5 to x=105.
+	w.Rect = image.Rect(0, 0, 110, 10)
+
+	testCases := []struct {
+		desc string
+		e    interface{}
+		want float64
+	}{
+		{"right", keyEvent(key.CodeRightArrow, key.DirPress), 51},
+		{"up", keyEvent(key.CodeUpArrow, key.DirPress), 52},
+		{"left", keyEvent(key.CodeLeftArrow, key.DirPress), 51},
+		{"down", keyEvent(key.CodeDownArrow, key.DirPress), 50},
+		{"page up", keyEvent(key.CodePageUp, key.DirPress), 60},
+		{"page down", keyEvent(key.CodePageDown, key.DirPress), 50},
+		{"end", keyEvent(key.CodeEnd, key.DirPress), 100},
+		{"right at end", keyEvent(key.CodeRightArrow, key.DirPress), 100},
+		{"home", keyEvent(key.CodeHome, key.DirPress), 0},
+		{"press", mouseEvent(30, 5, mouse.ButtonLeft, mouse.DirPress), 25},
+		{"drag", mouseEvent(80, 5, mouse.ButtonNone, mouse.DirNone), 75},
+		{"drag past end", mouseEvent(500, 5, mouse.ButtonNone, mouse.DirNone), 100},
+		{"mouse release", mouseEvent(500, 5, mouse.ButtonLeft, mouse.DirRelease), 100},
+		{"move", mouseEvent(55, 5, mouse.ButtonNone, mouse.DirNone), 100},
+	}
+	var want []float64
+	for _, tc := range testCases {
+		if w.OnInputEvent(tc.e, image.Point{}) != node.Handled {
+			t.Errorf("%s: not handled", tc.desc)
+		}
+		if got := w.Value(); got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.desc, got, tc.want)
+		}
+		if len(want) == 0 || want[len(want)-1] != tc.want {
+			want = append(want, tc.want)
+		}
+	}
+	// OnChange is only called when the value changes.
+	if len(changes) != len(want) {
+		t.Fatalf("OnChange: got %v, want %v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("OnChange: got %v, want %v", changes, want)
+		}
+	}
+
+	// Key releases are left alone.
+	if w.OnInputEvent(keyEvent(key.CodeDownArrow, key.DirRelease), image.Point{}) != node.NotHandled {
+		t.Error("key release: handled")
+	}
+
+	w.Step = 10
+	w.SetValue(50)
+	w.OnInputEvent(keyEvent(key.CodeRightArrow, key.DirPress), image.Point{})
+	w.OnInputEvent(keyEvent(key.CodePageDown, key.DirPress), image.Point{})
+	if got := w.Value(); got != 0 {
+		t.Errorf("Step 10: got %v, want 0", got)
+	}
+}
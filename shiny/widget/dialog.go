@@ -0,0 +1,91 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/node"
+)
+
+// TODO: a default button, activated by the enter key.
+
+// Dialog is a shell widget that shows a title, some content and a row of
+// buttons, such as "OK" and "Cancel", in a modal Popup. While a Dialog is
+// shown, the rest of the widget tree is dimmed and receives no input events.
+// The escape key dismisses the Dialog.
+type Dialog struct {
+	node.ShellEmbed
+
+	// OnDismiss, if non-nil, is called after the dialog is dismissed.
+	OnDismiss func()
+
+	title string
+	popup *Popup
+}
+
+// NewDialog returns a new Dialog widget. The buttons are laid out in a row,
+// aligned to the right edge of the dialog. Typically, their OnClick functions
+// call the Dialog's Dismiss method.
+func NewDialog(title string, content node.Node, buttons ...node.Node) *Dialog {
+	w := &Dialog{
+		title: title,
+	}
+	w.Wrapper = w
+
+	body := NewFlow(AxisVertical, NewLabel(title))
+	if content != nil {
+		body.Insert(NewPadder(AxisVertical, unit.Ems(0.5), content), nil)
+	}
+	if len(buttons) > 0 {
+		row := NewFlow(AxisHorizontal, WithLayoutData(NewSpace(), FlowLayoutData{
+			AlongWeight: 1,
+			ExpandAlong: true,
+		}))
+		for i, b := range buttons {
+			if i > 0 {
+				row.Insert(NewSizer(unit.Ems(0.5), unit.Value{}, nil), nil)
+			}
+			row.Insert(b, nil)
+		}
+		body.Insert(WithLayoutData(row, FlowLayoutData{
+			ExpandAcross: true,
+		}), nil)
+	}
+	w.Insert(NewPadder(AxisBoth, unit.Ems(1), body), nil)
+
+	w.popup = NewPopup(w)
+	w.popup.Placement = PlaceCenter
+	w.popup.Modal = true
+	w.popup.OnDismiss = func() {
+		if w.OnDismiss != nil {
+			w.OnDismiss()
+		}
+	}
+	return w
+}
+
+// Show shows the dialog, centered in the Overlay of n's widget tree.
+func (w *Dialog) Show(n node.Node) {
+	o := FindOverlay(n)
+	if o == nil {
+		panic("widget: Dialog shown for a node with no Overlay ancestor")
+	}
+	o.Show(w.popup)
+}
+
+// Shown returns whether the dialog is shown.
+func (w *Dialog) Shown() bool { return w.popup.Shown() }
+
+// Dismiss dismisses the dialog, if it is shown.
+func (w *Dialog) Dismiss() { w.popup.Dismiss() }
+
+func (w *Dialog) Accessibility() node.Accessibility {
+	return node.Accessibility{
+		Role:    node.RoleDialog,
+		Name:    w.title,
+		State:   node.StateModal,
+		Actions: []node.Action{{Name: node.ActionDismiss, Do: w.Dismiss}},
+	}
+}
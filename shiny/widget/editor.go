@@ -0,0 +1,836 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/draw"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/exp/shiny/gesture"
+	"golang.org/x/exp/shiny/text"
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/mouse"
+)
+
+// blinkPeriod is how long the caret is shown, and then hidden, for.
+const blinkPeriod = 500 * time.Millisecond
+
+// wheelLines is how many lines a mouse wheel step scrolls by.
+const wheelLines = 3
+
+type editorDrag uint8
+
+const (
+	dragNone editorDrag = iota
+	dragText
+	dragScrollbar
+)
+
+// editor is the editing machinery shared by the TextField and TextArea
+// widgets. Its text is held in a text.Frame, and is modified via the Frame's
+// Carets.
+//
+// Positions in the text are byte offsets, as for the text.Caret type.
+type editor struct {
+	frame text.Frame
+
+	// caret is the insertion point. anchor is the other end of the selection,
+	// which is empty if the two Carets are at the same position.
+	caret, anchor *text.Caret
+
+	// multiLine is whether the text can have more than one line, wrapped to
+	// the widget's width and scrolled vertically. Otherwise, the text is a
+	// single line, scrolled horizontally.
+	multiLine bool
+
+	focused bool
+
+	// lineCache and textCache hold the text's lines and bytes, in layout
+	// order. They are computed lazily, and are nil if the text or its layout
+	// has changed since. maxWidth is the Frame's maximum line width.
+	lineCache []editorLine
+	textCache []byte
+	maxWidth  fixed.Int26_6
+
+	// face and the other metrics below are set by the layout method, as
+	// input events are not given a theme. The face is acquired from
+	// faceTheme, and released when the theme changes.
+	face       font.Face
+	faceTheme  *theme.Theme
+	ascent     int
+	lineHeight int
+	lineWidth  int
+
+	// content and scrollbar are the text and scrollbar areas, relative to
+	// the widget's Rect.Min.
+	content   image.Rectangle
+	scrollbar image.Rectangle
+
+	// scroll is the position of the content's top-left pixel, relative to
+	// the start of the text.
+	scroll image.Point
+
+	// drag is what, if anything, a mouse button press started dragging.
+	// dragY and dragScrollY are the mouse position and scroll.Y when
+	// dragging the scrollbar started.
+	drag        editorDrag
+	dragY       int
+	dragScrollY int
+
+	// blinkStart is when the caret was last moved or the focus was gained.
+	// The caret is alternately shown and hidden for a blinkPeriod after that.
+	blinkStart time.Time
+	// wakeAt is the time of the most recently requested wake-up, so that
+	// repeated Paint calls do not request the same wake-up more than once.
+	wakeAt time.Time
+}
+
+func (e *editor) init(multiLine bool, s string) {
+	e.multiLine = multiLine
+	e.caret = e.frame.NewCaret()
+	e.anchor = e.frame.NewCaret()
+	e.insert(s)
+	e.setCaret(0, false)
+}
+
+// editorLine is a laid out Line of text.
+type editorLine struct {
+	// start is the position of the line's first byte.
+	start int
+	// text is the line's text, including any trailing '\n'.
+	text []byte
+}
+
+// end returns the position after the line's last byte, other than any
+// trailing '\n'.
+func (l *editorLine) end() int {
+	return l.start + len(trimNewline(l.text))
+}
+
+func trimNewline(s []byte) []byte {
+	if n := len(s); n > 0 && s[n-1] == '\n' {
+		return s[:n-1]
+	}
+	return s
+}
+
+// lines returns the text's lines, in layout order. There is always at least
+// one line, and if the text ends with a '\n' then the last line is empty.
+//
+// The lines are cached until the text or its layout changes, so callers must
+// not modify them.
+func (e *editor) lines() []editorLine {
+	if e.lineCache != nil {
+		return e.lineCache
+	}
+	f := &e.frame
+	b, lines := make([]byte, 0, f.Len()), []editorLine(nil)
+	for p := f.FirstParagraph(); p != nil; p = p.Next(f) {
+		for l := p.FirstLine(f); l != nil; l = l.Next(f) {
+			lines = append(lines, editorLine{start: len(b)})
+			for x := l.FirstBox(f); x != nil; x = x.Next(f) {
+				b = append(b, x.Text(f)...)
+			}
+		}
+	}
+	if n := len(lines); n == 0 || lines[n-1].start < len(b) && b[len(b)-1] == '\n' {
+		lines = append(lines, editorLine{start: len(b)})
+	}
+	// Slice the lines' text only after b has stopped growing.
+	for i := range lines {
+		end := len(b)
+		if i+1 < len(lines) {
+			end = lines[i+1].start
+		}
+		lines[i].text = b[lines[i].start:end:end]
+	}
+	e.lineCache, e.textCache = lines, b
+	return lines
+}
+
+// bytes returns the text. Like the lines, it is cached and must not be
+// modified.
+func (e *editor) bytes() []byte {
+	e.lines()
+	return e.textCache
+}
+
+// invalidate discards the cached lines, after the text or its layout changes.
+func (e *editor) invalidate() {
+	e.lineCache, e.textCache = nil, nil
+}
+
+// setMaxWidth sets the Frame's maximum line width, which can change its
+// layout.
+func (e *editor) setMaxWidth(m fixed.Int26_6) {
+	if e.maxWidth != m {
+		e.maxWidth = m
+		e.frame.SetMaxWidth(m)
+		e.invalidate()
+	}
+}
+
+// lineAt returns the index of the line containing the position pos. A
+// position at the boundary of two lines belongs to the latter.
+func lineAt(lines []editorLine, pos int) int {
+	for i := range lines {
+		if pos < lines[i].start+len(lines[i].text) {
+			return i
+		}
+	}
+	return len(lines) - 1
+}
+
+func (e *editor) text() string {
+	return string(e.bytes())
+}
+
+func (e *editor) accessibility(multiLine bool) node.Accessibility {
+	s := node.StateEditable
+	if multiLine {
+		s |= node.StateMultiLine
+	}
+	if e.focused {
+		s |= node.StateFocused
+	}
+	return node.Accessibility{
+		Role:  node.RoleTextField,
+		Value: e.text(),
+		State: s,
+	}
+}
+
+func (e *editor) setText(s string) {
+	e.caret.Seek(0, text.SeekSet)
+	e.caret.Delete(text.Forwards, e.frame.Len())
+	e.invalidate()
+	e.insert(s)
+	e.clampScroll(e.lines())
+}
+
+func (e *editor) pos(c *text.Caret) int {
+	pos, _ := c.Seek(0, text.SeekCur)
+	return int(pos)
+}
+
+// selection returns the start and end positions of the selected text.
+func (e *editor) selection() (i, j int) {
+	i, j = e.pos(e.anchor), e.pos(e.caret)
+	if i > j {
+		i, j = j, i
+	}
+	return i, j
+}
+
+// setCaret moves the caret to pos. If extend is false, it also moves the
+// anchor, emptying the selection.
+func (e *editor) setCaret(pos int, extend bool) {
+	e.caret.Seek(int64(pos), text.SeekSet)
+	if !extend {
+		e.anchor.Seek(int64(pos), text.SeekSet)
+	}
+}
+
+// deleteSelection deletes the selected text. It returns whether there was
+// any selected text.
+func (e *editor) deleteSelection() bool {
+	i, j := e.selection()
+	if i == j {
+		return false
+	}
+	e.caret.Seek(int64(i), text.SeekSet)
+	e.caret.Delete(text.Forwards, j-i)
+	e.invalidate()
+	e.setCaret(i, false)
+	return true
+}
+
+// insert replaces the selected text, if any, with s.
+func (e *editor) insert(s string) {
+	e.deleteSelection()
+	if !e.multiLine {
+		// TODO: should the '\r' in a "\r\n" be dropped instead?
+		s = strings.Map(func(r rune) rune {
+			if r == '\n' || r == '\r' {
+				return ' '
+			}
+			return r
+		}, s)
+	}
+	e.caret.Write([]byte(s))
+	e.invalidate()
+	e.setCaret(e.pos(e.caret), false)
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// nextWord returns the position of the end of the word after pos.
+func nextWord(b []byte, pos int) int {
+	for inWord := false; pos < len(b); {
+		r, n := utf8.DecodeRune(b[pos:])
+		if isWordRune(r) {
+			inWord = true
+		} else if inWord {
+			break
+		}
+		pos += n
+	}
+	return pos
+}
+
+// prevWord returns the position of the start of the word before pos.
+func prevWord(b []byte, pos int) int {
+	for inWord := false; pos > 0; {
+		r, n := utf8.DecodeLastRune(b[:pos])
+		if isWordRune(r) {
+			inWord = true
+		} else if inWord {
+			break
+		}
+		pos -= n
+	}
+	return pos
+}
+
+func nextRune(b []byte, pos int) int {
+	if pos < len(b) {
+		_, n := utf8.DecodeRune(b[pos:])
+		pos += n
+	}
+	return pos
+}
+
+func prevRune(b []byte, pos int) int {
+	if pos > 0 {
+		_, n := utf8.DecodeLastRune(b[:pos])
+		pos -= n
+	}
+	return pos
+}
+
+// caretPoint returns the index of the line containing pos, and pos'
+// horizontal offset within that line.
+func (e *editor) caretPoint(lines []editorLine, pos int) (i int, x fixed.Int26_6) {
+	i = lineAt(lines, pos)
+	return i, font.MeasureBytes(e.face, lines[i].text[:pos-lines[i].start])
+}
+
+// posIn returns the position in the i'th line that is closest to the
+// horizontal offset x.
+func (e *editor) posIn(lines []editorLine, i int, x fixed.Int26_6) int {
+	l := &lines[i]
+	s := l.text[:l.end()-l.start]
+	if len(s) == len(l.text) && len(s) > 0 && i+1 < len(lines) {
+		// The line was soft-wrapped. Its end position belongs to the next
+		// line, so stop before its last (white space) rune.
+		_, n := utf8.DecodeLastRune(s)
+		s = s[:len(s)-n]
+	}
+	return l.start + offsetAt(e.face, s, x)
+}
+
+// offsetAt returns the byte offset in s, at a rune boundary, that is closest
+// to the horizontal offset x when s is drawn in the face.
+func offsetAt(face font.Face, s []byte, x fixed.Int26_6) int {
+	adv, prevR := fixed.Int26_6(0), rune(-1)
+	for j := 0; j < len(s); {
+		r, n := utf8.DecodeRune(s[j:])
+		if prevR >= 0 {
+			adv += face.Kern(prevR, r)
+		}
+		a, _ := face.GlyphAdvance(r)
+		if x < adv+a/2 {
+			return j
+		}
+		adv, prevR, j = adv+a, r, j+n
+	}
+	return len(s)
+}
+
+// posAt returns the position closest to p, in content coordinates.
+func (e *editor) posAt(lines []editorLine, p image.Point) int {
+	i := 0
+	if p.Y > 0 {
+		i = p.Y / e.lineHeight
+	}
+	if i >= len(lines) {
+		i = len(lines) - 1
+	}
+	return e.posIn(lines, i, fixed.I(p.X))
+}
+
+// textSize returns the size, in pixels, of the laid out text.
+func (e *editor) textSize(lines []editorLine) image.Point {
+	size := image.Point{Y: len(lines) * e.lineHeight}
+	if !e.multiLine {
+		size.X = font.MeasureBytes(e.face, trimNewline(lines[0].text)).Ceil() + e.lineWidth
+	}
+	return size
+}
+
+func (e *editor) clampScroll(lines []editorLine) {
+	if e.face == nil {
+		return
+	}
+	max := e.textSize(lines).Sub(e.content.Size())
+	if e.scroll.X > max.X {
+		e.scroll.X = max.X
+	}
+	if e.scroll.Y > max.Y {
+		e.scroll.Y = max.Y
+	}
+	if e.scroll.X < 0 {
+		e.scroll.X = 0
+	}
+	if e.scroll.Y < 0 {
+		e.scroll.Y = 0
+	}
+}
+
+// scrollToCaret scrolls so that the caret is visible.
+func (e *editor) scrollToCaret(lines []editorLine) {
+	i, x := e.caretPoint(lines, e.pos(e.caret))
+	caret := image.Rect(x.Floor(), i*e.lineHeight, x.Floor()+e.lineWidth, (i+1)*e.lineHeight)
+	view := e.content.Sub(e.content.Min).Add(e.scroll)
+	if caret.Min.X < view.Min.X {
+		e.scroll.X = caret.Min.X
+	} else if caret.Max.X > view.Max.X {
+		e.scroll.X = caret.Max.X - view.Dx()
+	}
+	if caret.Min.Y < view.Min.Y {
+		e.scroll.Y = caret.Min.Y
+	} else if caret.Max.Y > view.Max.Y {
+		e.scroll.Y = caret.Max.Y - view.Dy()
+	}
+	e.clampScroll(lines)
+}
+
+// thumb returns the scrollbar's thumb, relative to the widget's Rect.Min. It
+// is empty if there is nothing to scroll.
+func (e *editor) thumb(lines []editorLine) image.Rectangle {
+	total, view, track := e.textSize(lines).Y, e.content.Dy(), e.scrollbar.Dy()
+	if !e.multiLine || total <= view || track <= 0 {
+		return image.Rectangle{}
+	}
+	h := track * view / total
+	if h < e.scrollbar.Dx() {
+		h = e.scrollbar.Dx()
+	}
+	y := e.scrollbar.Min.Y + (track-h)*e.scroll.Y/(total-view)
+	return image.Rect(e.scrollbar.Min.X, y, e.scrollbar.Max.X, y+h)
+}
+
+func (e *editor) padding(t *theme.Theme) int {
+	return t.Pixels(unit.Ems(0.5)).Ceil()
+}
+
+func (e *editor) setFace(t *theme.Theme) {
+	if e.face != nil && e.faceTheme == t {
+		return
+	}
+	if e.face != nil {
+		e.faceTheme.ReleaseFontFace(theme.FontFaceOptions{}, e.face)
+	}
+	e.face, e.faceTheme = t.AcquireFontFace(theme.FontFaceOptions{}), t
+	e.frame.SetFace(e.face)
+	e.invalidate()
+	m := e.face.Metrics()
+	e.ascent = m.Ascent.Ceil()
+	e.lineHeight = m.Ascent.Ceil() + m.Descent.Ceil()
+}
+
+func (e *editor) baseline(t *theme.Theme) int {
+	e.setFace(t)
+	return e.padding(t) + e.ascent
+}
+
+func (e *editor) measure(t *theme.Theme, widthHint int) image.Point {
+	e.setFace(t)
+	padding := e.padding(t)
+	if !e.multiLine {
+		if widthHint < 0 {
+			// TODO: make the default width configurable.
+			widthHint = t.Pixels(unit.Ems(16)).Ceil()
+		}
+		return image.Point{widthHint, e.lineHeight + 2*padding}
+	}
+
+	if widthHint < 0 {
+		// Without a width hint, lines are only broken at '\n's, and the
+		// widest line determines the width.
+		e.setMaxWidth(0)
+		width := fixed.Int26_6(0)
+		for _, l := range e.lines() {
+			if w := font.MeasureBytes(e.face, trimNewline(l.text)); width < w {
+				width = w
+			}
+		}
+		return image.Point{
+			width.Ceil() + lineWidth(t) + 2*padding + e.scrollbarWidth(t),
+			e.frame.Height() + 2*padding,
+		}
+	}
+	maxWidth := fixed.I(widthHint - 2*padding - e.scrollbarWidth(t))
+	if maxWidth <= 1 {
+		maxWidth = 1
+	}
+	e.setMaxWidth(maxWidth)
+	return image.Point{widthHint, e.frame.Height() + 2*padding}
+}
+
+func (e *editor) scrollbarWidth(t *theme.Theme) int {
+	if !e.multiLine {
+		return 0
+	}
+	return t.Pixels(unit.DIPs(6)).Ceil()
+}
+
+func (e *editor) layout(t *theme.Theme, size image.Point) {
+	e.setFace(t)
+	padding := e.padding(t)
+	e.lineWidth = lineWidth(t)
+
+	sbw := e.scrollbarWidth(t)
+	e.scrollbar = image.Rect(size.X-e.lineWidth-sbw, e.lineWidth, size.X-e.lineWidth, size.Y-e.lineWidth)
+	e.content = image.Rect(padding, padding, size.X-padding-sbw, size.Y-padding)
+	if e.content.Empty() {
+		e.content.Max = e.content.Min
+	}
+
+	if e.multiLine {
+		maxWidth := fixed.I(e.content.Dx())
+		if maxWidth <= 1 {
+			maxWidth = 1
+		}
+		e.setMaxWidth(maxWidth)
+	} else {
+		e.setMaxWidth(0)
+	}
+	e.clampScroll(e.lines())
+}
+
+// onInputEvent handles an input event for the widget n, whose Rect, in
+// window coordinates, is r. It returns whether the event was handled and
+// whether the text was changed.
+func (e *editor) onInputEvent(ev interface{}, r image.Rectangle, n *node.Embed) (handled node.EventHandled, changed bool) {
+	if e.face == nil {
+		return node.NotHandled, false
+	}
+	moved := false
+	switch ev := ev.(type) {
+	case node.FocusEvent:
+		e.focused = ev.Focused
+		e.drag = dragNone
+		handled, moved = node.Handled, true
+
+	case key.Event:
+		handled, moved, changed = e.onKeyEvent(ev)
+
+	case mouse.Event:
+		handled, moved = e.onMouseEvent(ev, image.Point{int(ev.X), int(ev.Y)}.Sub(r.Min))
+
+	case gesture.Event:
+		// A mouse drag that selects text, or moves the scrollbar, shouldn't
+		// also be treated as a scroll by an ancestor, such as a Scroller.
+		// Other gestures, such as touch drags, are left alone.
+		if e.drag != dragNone {
+			handled = node.Handled
+		}
+	}
+
+	if moved || changed {
+		e.blinkStart = time.Now()
+		n.Mark(node.MarkNeedsPaintBase)
+	}
+	return handled, changed
+}
+
+func (e *editor) onKeyEvent(ev key.Event) (handled node.EventHandled, moved, changed bool) {
+	if ev.Direction == key.DirRelease {
+		return node.NotHandled, false, false
+	}
+	// TODO: use key.ModAlt for word jumps, and key.ModMeta for Home and End,
+	// on macOS.
+	extend := ev.Modifiers&key.ModShift != 0
+	ctrl := ev.Modifiers&(key.ModControl|key.ModMeta) != 0
+
+	lines, b := e.lines(), e.bytes()
+	pos := e.pos(e.caret)
+
+	switch ev.Code {
+	case key.CodeLeftArrow, key.CodeRightArrow:
+		left := ev.Code == key.CodeLeftArrow
+		if i, j := e.selection(); i != j && !extend {
+			// Collapse the selection.
+			pos = j
+			if left {
+				pos = i
+			}
+		} else {
+			switch {
+			case left && ctrl:
+				pos = prevWord(b, pos)
+			case left:
+				pos = prevRune(b, pos)
+			case ctrl:
+				pos = nextWord(b, pos)
+			default:
+				pos = nextRune(b, pos)
+			}
+		}
+		e.setCaret(pos, extend)
+
+	case key.CodeUpArrow, key.CodeDownArrow, key.CodePageUp, key.CodePageDown:
+		if !e.multiLine {
+			return node.NotHandled, false, false
+		}
+		delta := 1
+		if ev.Code == key.CodePageUp || ev.Code == key.CodePageDown {
+			if delta = e.content.Dy() / e.lineHeight; delta < 1 {
+				delta = 1
+			}
+		}
+		if ev.Code == key.CodeUpArrow || ev.Code == key.CodePageUp {
+			delta = -delta
+		}
+		// TODO: remember the original x when moving through a sequence of
+		// shorter and longer lines.
+		i, x := e.caretPoint(lines, pos)
+		switch i += delta; {
+		case i < 0:
+			pos = 0
+		case i >= len(lines):
+			pos = len(b)
+		default:
+			pos = e.posIn(lines, i, x)
+		}
+		e.setCaret(pos, extend)
+
+	case key.CodeHome:
+		if ctrl {
+			pos = 0
+		} else {
+			pos = lines[lineAt(lines, pos)].start
+		}
+		e.setCaret(pos, extend)
+
+	case key.CodeEnd:
+		if ctrl {
+			pos = len(b)
+		} else {
+			pos = e.posIn(lines, lineAt(lines, pos), fixed.Int26_6(1<<31-1))
+		}
+		e.setCaret(pos, extend)
+
+	case key.CodeDeleteBackspace:
+		if !e.deleteSelection() {
+			if ctrl {
+				e.caret.Delete(text.Backwards, pos-prevWord(b, pos))
+			} else {
+				e.caret.DeleteRunes(text.Backwards, 1)
+			}
+			e.invalidate()
+			e.setCaret(e.pos(e.caret), false)
+		}
+		changed = e.frame.Len() != len(b)
+
+	case key.CodeDeleteForward:
+		if !e.deleteSelection() {
+			if ctrl {
+				e.caret.Delete(text.Forwards, nextWord(b, pos)-pos)
+			} else {
+				e.caret.DeleteRunes(text.Forwards, 1)
+			}
+			e.invalidate()
+			e.setCaret(e.pos(e.caret), false)
+		}
+		changed = e.frame.Len() != len(b)
+
+	case key.CodeReturnEnter, key.CodeKeypadEnter:
+		if !e.multiLine {
+			return node.NotHandled, false, false
+		}
+		e.insert("\n")
+		changed = true
+
+	case key.CodeTab:
+		// Let the focus move to the next widget.
+		return node.NotHandled, false, false
+
+	default:
+		if ctrl && ev.Code == key.CodeA {
+			e.anchor.Seek(0, text.SeekSet)
+			e.caret.Seek(0, text.SeekEnd)
+			break
+		}
+		if ctrl || ev.Rune < 0 || !unicode.IsPrint(ev.Rune) {
+			return node.NotHandled, false, false
+		}
+		e.insert(string(ev.Rune))
+		changed = true
+	}
+
+	e.scrollToCaret(e.lines())
+	return node.Handled, true, changed
+}
+
+// onMouseEvent handles a mouse event at p, relative to the widget's Rect.Min.
+func (e *editor) onMouseEvent(ev mouse.Event, p image.Point) (handled node.EventHandled, moved bool) {
+	lines := e.lines()
+	switch ev.Direction {
+	case mouse.DirStep:
+		if !e.multiLine {
+			return node.NotHandled, false
+		}
+		switch ev.Button {
+		case mouse.ButtonWheelUp:
+			e.scroll.Y -= wheelLines * e.lineHeight
+		case mouse.ButtonWheelDown:
+			e.scroll.Y += wheelLines * e.lineHeight
+		default:
+			return node.NotHandled, false
+		}
+		e.clampScroll(lines)
+		return node.Handled, true
+
+	case mouse.DirPress:
+		if ev.Button != mouse.ButtonLeft {
+			return node.NotHandled, false
+		}
+		if p.In(e.scrollbar) && e.multiLine {
+			e.drag, e.dragY, e.dragScrollY = dragScrollbar, p.Y, e.scroll.Y
+			return node.Handled, false
+		}
+		e.drag = dragText
+		extend := ev.Modifiers&key.ModShift != 0
+		e.setCaret(e.posAt(lines, p.Sub(e.content.Min).Add(e.scroll)), extend)
+
+	case mouse.DirNone:
+		switch e.drag {
+		case dragText:
+			e.setCaret(e.posAt(lines, p.Sub(e.content.Min).Add(e.scroll)), true)
+		case dragScrollbar:
+			total, view := e.textSize(lines).Y, e.content.Dy()
+			if track := e.scrollbar.Dy() - e.thumb(lines).Dy(); track > 0 {
+				e.scroll.Y = e.dragScrollY + (p.Y-e.dragY)*(total-view)/track
+				e.clampScroll(lines)
+			}
+			return node.Handled, true
+		default:
+			return node.NotHandled, false
+		}
+
+	case mouse.DirRelease:
+		if e.drag == dragNone {
+			return node.NotHandled, false
+		}
+		e.drag = dragNone
+		return node.Handled, false
+
+	default:
+		return node.NotHandled, false
+	}
+
+	e.scrollToCaret(lines)
+	return node.Handled, true
+}
+
+func (e *editor) paintBase(ctx *node.PaintBaseContext, r image.Rectangle) error {
+	dst := ctx.Dst.SubImage(r).(*image.RGBA)
+	if dst.Bounds().Empty() || e.face == nil {
+		return nil
+	}
+	pal := ctx.Theme.GetPalette()
+
+	draw.Draw(dst, dst.Bounds(), pal.Background(), image.Point{}, draw.Src)
+	border := pal.Neutral()
+	if e.focused {
+		border = pal.Accent()
+	}
+	drawBorder(dst, r, border, e.lineWidth)
+
+	lines := e.lines()
+	if thumb := e.thumb(lines); !thumb.Empty() {
+		draw.Draw(dst, thumb.Add(r.Min), pal.Neutral(), image.Point{}, draw.Src)
+	}
+
+	content := e.content.Add(r.Min)
+	d := font.Drawer{
+		Dst:  dst.SubImage(content).(*image.RGBA),
+		Src:  pal.Foreground(),
+		Face: e.face,
+	}
+	x0 := content.Min.X - e.scroll.X
+	selI, selJ := e.selection()
+	for i := e.scroll.Y / e.lineHeight; i < len(lines); i++ {
+		y := content.Min.Y + i*e.lineHeight - e.scroll.Y
+		if y >= content.Max.Y {
+			break
+		}
+		l := &lines[i]
+		s := trimNewline(l.text)
+		if selI < l.start+len(l.text) && selJ > l.start {
+			i, j := selI-l.start, selJ-l.start
+			if i < 0 {
+				i = 0
+			}
+			if j > len(s) {
+				j = len(s)
+			}
+			sel := image.Rect(
+				x0+font.MeasureBytes(e.face, s[:i]).Floor(), y,
+				x0+font.MeasureBytes(e.face, s[:j]).Ceil(), y+e.lineHeight,
+			)
+			if selJ > l.end() {
+				// Show that the selection continues past the end of the line.
+				sel.Max.X += e.lineHeight / 2
+			}
+			draw.Draw(d.Dst, sel, pal.Selection(), image.Point{}, draw.Src)
+		}
+		d.Dot = fixed.P(x0, y+e.ascent)
+		d.DrawBytes(s)
+	}
+	return nil
+}
+
+// paint paints the caret for the widget n, whose Rect, in window coordinates,
+// is r.
+func (e *editor) paint(ctx *node.PaintContext, r image.Rectangle, n node.Node) {
+	if !e.focused || e.face == nil {
+		return
+	}
+	phase := time.Since(e.blinkStart) / blinkPeriod
+	if ctx.Waker != nil {
+		if t := e.blinkStart.Add((phase + 1) * blinkPeriod); t != e.wakeAt {
+			e.wakeAt = t
+			ctx.Waker.WakeAt(t, n, node.MarkNeedsPaint)
+		}
+	}
+	if phase%2 != 0 {
+		return
+	}
+
+	i, x := e.caretPoint(e.lines(), e.pos(e.caret))
+	caret := image.Rect(x.Floor(), i*e.lineHeight, x.Floor()+e.lineWidth, (i+1)*e.lineHeight)
+	caret = caret.Sub(e.scroll).Add(e.content.Min).Add(r.Min)
+	if !caret.In(e.content.Add(r.Min)) {
+		return
+	}
+	src2dst := ctx.Src2Dst
+	translate(&src2dst, float64(caret.Min.X), float64(caret.Min.Y))
+	ctx.Drawer.DrawUniform(src2dst, ctx.Theme.GetPalette().Foreground().C,
+		image.Rect(0, 0, caret.Dx(), caret.Dy()), draw.Over, nil)
+}
@@ -0,0 +1,110 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"fmt"
+	"image"
+	"testing"
+
+	"golang.org/x/exp/shiny/gesture"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/mouse"
+)
+
+func lineStrings(lines []editorLine) string {
+	s := ""
+	for _, l := range lines {
+		s += fmt.Sprintf("%d:%q ", l.start, l.text)
+	}
+	return s
+}
+
+func TestEditorLines(t *testing.T) {
+	e := &editor{}
+	e.init(true, "ab\ncdef\n")
+	// The default theme's font face is 8 pixels wide per glyph.
+	e.setFace(nil)
+
+	check := func(desc, want string) {
+		t.Helper()
+		if got := lineStrings(e.lines()); got != want {
+			t.Errorf("%s: got %s, want %s", desc, got, want)
+		}
+		if got, want := e.text(), string(joinedText(e)); got != want {
+			t.Errorf("%s: text: got %q, want %q", desc, got, want)
+		}
+	}
+	check("init", `0:"ab\n" 3:"cdef\n" 8:"" `)
+
+	e.setCaret(8, false)
+	e.onKeyEvent(key.Event{Code: key.CodeDeleteBackspace, Direction: key.DirPress})
+	check("backspace", `0:"ab\n" 3:"cdef" `)
+
+	e.insert(" gh")
+	check("insert", `0:"ab\n" 3:"cdef gh" `)
+
+	// Wrapping the text changes the lines, too.
+	e.setMaxWidth(fixed.I(44))
+	check("wrapped", `0:"ab\n" 3:"cdef " 8:"gh" `)
+
+	e.setCaret(0, false)
+	e.setCaret(3, true)
+	e.deleteSelection()
+	check("deleteSelection", `0:"cdef " 5:"gh" `)
+
+	e.setText("x\ny")
+	check("setText", `0:"x\n" 2:"y" `)
+}
+
+func TestEditorMeasure(t *testing.T) {
+	e := &editor{}
+	e.init(true, "ab\ncdefg\nhi")
+	size := e.measure(nil, node.NoHint)
+	// The widest line, "cdefg", is 5*8 pixels wide, and there is room for
+	// the caret after it.
+	padding := e.padding(nil)
+	want := image.Point{
+		5*8 + lineWidth(nil) + 2*padding + e.scrollbarWidth(nil),
+		3*e.lineHeight + 2*padding,
+	}
+	if size != want {
+		t.Errorf("got %v, want %v", size, want)
+	}
+}
+
+func TestEditorGesture(t *testing.T) {
+	e := &editor{}
+	e.init(true, "ab\ncd")
+	e.layout(nil, image.Point{100, 50})
+	n := &node.Embed{}
+	r := image.Rect(0, 0, 100, 50)
+	drag := gesture.Event{Type: gesture.TypeDrag}
+
+	// A gesture outside of a mouse drag, such as a touch drag, is left to
+	// an ancestor, such as a Scroller.
+	if got, _ := e.onInputEvent(drag, r, n); got != node.NotHandled {
+		t.Errorf("no drag: got %v, want NotHandled", got)
+	}
+	e.onInputEvent(mouse.Event{X: 10, Y: 10, Button: mouse.ButtonLeft, Direction: mouse.DirPress}, r, n)
+	if got, _ := e.onInputEvent(drag, r, n); got != node.Handled {
+		t.Errorf("selecting: got %v, want Handled", got)
+	}
+	e.onInputEvent(mouse.Event{X: 10, Y: 10, Button: mouse.ButtonLeft, Direction: mouse.DirRelease}, r, n)
+	if got, _ := e.onInputEvent(drag, r, n); got != node.NotHandled {
+		t.Errorf("released: got %v, want NotHandled", got)
+	}
+}
+
+// joinedText returns the concatenation of e's lines' text.
+func joinedText(e *editor) []byte {
+	var b []byte
+	for _, l := range e.lines() {
+		b = append(b, l.text...)
+	}
+	return b
+}
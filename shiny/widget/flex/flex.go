@@ -7,16 +7,22 @@
 //
 // As the shiny widget model does not provide all of the layout features
 // of CSS, the flex package diverges in several ways. There is no item
-// inline-axis, no item margins or padding to be accounted for, and the
-// container size provided by the outer widget is taken as gospel and
-// never expanded.
+// inline-axis, no 'auto' margins, and the container size provided by the
+// outer widget is taken as gospel and never expanded.
+//
+// An item's node.Box, its margin, border and padding, is part of the item's
+// size along both axes. The sizes in its LayoutData, such as MinSize and
+// BasisSize, include that Box, as with the CSS 'box-sizing: border-box'
+// property extended to the margin.
 package flex
 
 import (
 	"fmt"
 	"image"
 	"math"
+	"sort"
 
+	"golang.org/x/exp/shiny/unit"
 	"golang.org/x/exp/shiny/widget/node"
 	"golang.org/x/exp/shiny/widget/theme"
 )
@@ -72,7 +78,7 @@ const (
 	AlignItemStart
 	AlignItemEnd
 	AlignItemCenter
-	AlignItemBaseline // TODO requires introducing inline-axis concept
+	AlignItemBaseline // same as AlignItemStart for Column directions
 	AlignItemStretch
 )
 
@@ -94,8 +100,9 @@ const (
 // Basis sets the base size of a flex item.
 //
 // A default basis of Auto means the flex container uses the
-// MeasuredSize of an item. Otherwise a Definite Basis will
-// override the MeasuredSize with BasisPx.
+// MeasuredSize of an item, plus its node.Box. Otherwise a Definite Basis will
+// override the MeasuredSize with BasisSize, and a Percentage
+// Basis with BasisPercent of the container's main size.
 //
 // TODO: do we (or will we )have a useful notion of Content in the
 // widget layout model that is separate from MeasuredSize? If not,
@@ -106,6 +113,7 @@ type Basis uint8
 const (
 	Auto Basis = iota
 	Definite
+	Percentage
 )
 
 // Flex is a container widget that lays out its children following the
@@ -118,6 +126,14 @@ type Flex struct {
 	Justify      Justify
 	AlignItems   AlignItem
 	AlignContent AlignContent
+
+	// RowGap and ColumnGap are the space between adjacent rows and columns,
+	// as the CSS 'row-gap' and 'column-gap' properties. For Row directions,
+	// ColumnGap is the main axis gap between items in a line and RowGap is
+	// the cross axis gap between lines. For Column directions, it is the
+	// other way around.
+	RowGap    unit.Value
+	ColumnGap unit.Value
 }
 
 // NewFlex returns a new Flex widget containing the given children.
@@ -136,7 +152,7 @@ func (w *Flex) Measure(t *theme.Theme, widthHint, heightHint int) {
 	// AlignItem, AlignContent.
 	for c := w.FirstChild; c != nil; c = c.NextSibling {
 		// TODO: pass down width/height hints?
-		c.Wrapper.Measure(t, node.NoHint, node.NoHint)
+		c.MeasureOuter(t, node.NoHint, node.NoHint)
 		if d, ok := c.LayoutData.(LayoutData); ok {
 			_ = d
 			// TODO Measure
@@ -145,16 +161,21 @@ func (w *Flex) Measure(t *theme.Theme, widthHint, heightHint int) {
 }
 
 func (w *Flex) Layout(t *theme.Theme) {
+	containerMainSize := float64(w.mainSize(w.Rect.Size()))
+	containerCrossSize := float64(w.crossSize(w.Rect.Size()))
+	mainGap, crossGap := w.gaps(t)
+
+	// §5.4 reorder children by their 'order' property.
 	var children []element
 	for c := w.FirstChild; c != nil; c = c.NextSibling {
 		children = append(children, element{
-			flexBaseSize: float64(w.flexBaseSize(c)),
+			flexBaseSize: w.flexBaseSize(t, c, containerMainSize),
 			n:            c,
 		})
 	}
-
-	containerMainSize := float64(w.mainSize(w.Rect.Size()))
-	containerCrossSize := float64(w.crossSize(w.Rect.Size()))
+	sort.SliceStable(children, func(i, j int) bool {
+		return order(children[i].n) < order(children[j].n)
+	})
 
 	// §9.3.5 collect children into flex lines
 	var lines []flexLine
@@ -165,6 +186,7 @@ func (w *Flex) Layout(t *theme.Theme) {
 			line.child[i] = child
 			line.mainSize += child.flexBaseSize
 		}
+		line.mainSize += gapsSize(len(line.child), mainGap)
 		lines = []flexLine{line}
 	} else {
 		var line flexLine
@@ -172,14 +194,18 @@ func (w *Flex) Layout(t *theme.Theme) {
 		for i := range children {
 			child := &children[i]
 
-			hypotheticalMainSize := w.clampSize(child.flexBaseSize, child.n)
+			hypotheticalMainSize := w.clampSize(t, child.flexBaseSize, child.n)
 
-			if line.mainSize > 0 && line.mainSize+hypotheticalMainSize > containerMainSize {
+			gap := 0.0
+			if len(line.child) > 0 {
+				gap = mainGap
+			}
+			if line.mainSize > 0 && line.mainSize+gap+hypotheticalMainSize > containerMainSize {
 				lines = append(lines, line)
-				line = flexLine{}
+				line, gap = flexLine{}, 0
 			}
 			line.child = append(line.child, child)
-			line.mainSize += hypotheticalMainSize
+			line.mainSize += gap + hypotheticalMainSize
 
 			if d, ok := child.n.LayoutData.(LayoutData); ok && d.BreakAfter {
 				lines = append(lines, line)
@@ -204,15 +230,15 @@ func (w *Flex) Layout(t *theme.Theme) {
 
 		// §9.7.2 freeze inflexible children.
 		for _, child := range line.child {
-			mainSize := float64(w.mainSize(child.n.MeasuredSize))
-			hypotheticalMainSize := w.clampSize(mainSize, child.n)
+			mainSize := w.contentMainSize(t, child.n)
+			hypotheticalMainSize := w.clampSize(t, mainSize, child.n)
 			if grow {
-				if growFactor(child.n) == 0 || float64(w.flexBaseSize(child.n)) > hypotheticalMainSize {
+				if growFactor(child.n) == 0 || child.flexBaseSize > hypotheticalMainSize {
 					child.frozen = true
 					child.mainSize = hypotheticalMainSize
 				}
 			} else {
-				if shrinkFactor(child.n) == 0 || float64(w.flexBaseSize(child.n)) < hypotheticalMainSize {
+				if shrinkFactor(child.n) == 0 || child.flexBaseSize < hypotheticalMainSize {
 					child.frozen = true
 					child.mainSize = hypotheticalMainSize
 				}
@@ -220,12 +246,12 @@ func (w *Flex) Layout(t *theme.Theme) {
 		}
 
 		// §9.7.3 calculate initial free space
-		initFreeSpace := float64(w.mainSize(w.Rect.Size()))
+		initFreeSpace := containerMainSize - gapsSize(len(line.child), mainGap)
 		for _, child := range line.child {
 			if child.frozen {
 				initFreeSpace -= child.mainSize
 			} else {
-				initFreeSpace -= float64(w.flexBaseSize(child.n))
+				initFreeSpace -= child.flexBaseSize
 			}
 		}
 
@@ -244,13 +270,13 @@ func (w *Flex) Layout(t *theme.Theme) {
 			}
 
 			// Calculate remaining free space.
-			remFreeSpace := float64(w.mainSize(w.Rect.Size()))
+			remFreeSpace := containerMainSize - gapsSize(len(line.child), mainGap)
 			unfrozenFlexFactor := 0.0
 			for _, child := range line.child {
 				if child.frozen {
 					remFreeSpace -= child.mainSize
 				} else {
-					remFreeSpace -= float64(w.flexBaseSize(child.n))
+					remFreeSpace -= child.flexBaseSize
 					if grow {
 						unfrozenFlexFactor += growFactor(child.n)
 					} else {
@@ -272,7 +298,7 @@ func (w *Flex) Layout(t *theme.Theme) {
 						continue
 					}
 					r := growFactor(child.n) / unfrozenFlexFactor
-					child.mainSize = float64(w.flexBaseSize(child.n)) + r*remFreeSpace
+					child.mainSize = child.flexBaseSize + r*remFreeSpace
 				}
 			} else {
 				sumScaledShrinkFactor := 0.0
@@ -280,16 +306,16 @@ func (w *Flex) Layout(t *theme.Theme) {
 					if child.frozen {
 						continue
 					}
-					scaledShrinkFactor := float64(w.flexBaseSize(child.n)) * shrinkFactor(child.n)
+					scaledShrinkFactor := child.flexBaseSize * shrinkFactor(child.n)
 					sumScaledShrinkFactor += scaledShrinkFactor
 				}
 				for _, child := range line.child {
 					if child.frozen {
 						continue
 					}
-					scaledShrinkFactor := float64(w.flexBaseSize(child.n)) * shrinkFactor(child.n)
+					scaledShrinkFactor := child.flexBaseSize * shrinkFactor(child.n)
 					r := float64(scaledShrinkFactor) / sumScaledShrinkFactor
-					child.mainSize = float64(w.flexBaseSize(child.n)) - r*math.Abs(float64(remFreeSpace))
+					child.mainSize = child.flexBaseSize - r*math.Abs(float64(remFreeSpace))
 				}
 			}
 
@@ -303,7 +329,7 @@ func (w *Flex) Layout(t *theme.Theme) {
 					continue
 				}
 				child.unclamped = child.mainSize
-				child.mainSize = w.clampSize(child.mainSize, child.n)
+				child.mainSize = w.clampSize(t, child.mainSize, child.n)
 
 				sumClampDiff += child.mainSize - child.unclamped
 			}
@@ -337,23 +363,32 @@ func (w *Flex) Layout(t *theme.Theme) {
 	// §9.4.7 calculate hypothetical cross size of each element
 	for l := range lines {
 		for _, child := range lines[l].child {
-			child.crossSize = float64(w.crossSize(child.n.MeasuredSize))
-			if child.mainSize < float64(w.mainSize(child.n.MeasuredSize)) {
-				if r, ok := aspectRatio(child.n); ok {
+			if w.Direction == Row || w.Direction == RowReverse {
+				// An item with a flexible width, such as wrapped text,
+				// can be taller when it is narrower than its natural
+				// width, so measure it again at its main size.
+				if min, max := child.n.OuterIntrinsicWidths(t); min < max {
+					child.n.MeasureOuter(t, round(math.Min(child.mainSize, float64(max))), node.NoHint)
+				}
+			}
+			child.crossSize = float64(w.crossSize(child.n.OuterSize(t)))
+			if child.mainSize < w.contentMainSize(t, child.n) {
+				if r, ok := aspectRatio(t, child.n); ok {
 					child.crossSize = child.mainSize / r
 				}
 			}
 			if d, ok := child.n.LayoutData.(LayoutData); ok {
-				minSize := float64(w.crossSize(d.MinSize))
+				minSize := px(t, w.crossValue(d.MinSize))
 				if minSize > child.crossSize {
 					child.crossSize = minSize
 				} else if d.MaxSize != nil {
-					maxSize := float64(w.crossSize(*d.MaxSize))
+					maxSize := px(t, w.crossValue(*d.MaxSize))
 					if child.crossSize > maxSize {
 						child.crossSize = maxSize
 					}
 				}
 			}
+			child.baseline = w.baseline(t, child)
 		}
 	}
 	if len(lines) == 1 {
@@ -368,22 +403,30 @@ func (w *Flex) Layout(t *theme.Theme) {
 		// §9.4.8 multi-line
 		for l := range lines {
 			line := &lines[l]
-			// TODO §9.4.8.1, no concept of inline-axis yet
-			max := 0.0
+			// §9.4.8.1 baseline-aligned items contribute their largest
+			// distances from their baselines to their cross-start and
+			// cross-end edges.
+			max, maxAbove, maxBelow := 0.0, 0.0, 0.0
 			for _, child := range line.child {
+				if w.alignsToBaseline(child.n) {
+					maxAbove = math.Max(maxAbove, child.baseline)
+					maxBelow = math.Max(maxBelow, child.crossSize-child.baseline)
+					continue
+				}
 				if child.crossSize > max {
 					max = child.crossSize
 				}
 			}
-			line.crossSize = max
+			line.crossSize = math.Max(max, maxAbove+maxBelow)
 		}
 	}
 	off := 0.0
 	for l := range lines {
 		line := &lines[l]
 		line.crossOffset = off
-		off += line.crossSize
+		off += line.crossSize + crossGap
 	}
+	off -= crossGap
 	// §9.4.9 align-content: stretch
 	remCrossSize := containerCrossSize - off
 	if w.AlignContent == AlignContentStretch && remCrossSize > 0 {
@@ -409,7 +452,7 @@ func (w *Flex) Layout(t *theme.Theme) {
 	// §9.5 main axis alignment
 	for l := range lines {
 		line := &lines[l]
-		total := 0.0
+		total := gapsSize(len(line.child), mainGap)
 		for _, child := range line.child {
 			total += child.mainSize
 		}
@@ -429,7 +472,7 @@ func (w *Flex) Layout(t *theme.Theme) {
 		}
 		for _, child := range line.child {
 			child.mainOffset = off
-			off += spacing + child.mainSize
+			off += spacing + mainGap + child.mainSize
 		}
 	}
 
@@ -438,8 +481,18 @@ func (w *Flex) Layout(t *theme.Theme) {
 	// §9.6.14 align items inside line, 'align-self'.
 	for l := range lines {
 		line := &lines[l]
+		maxBaseline := 0.0
+		for _, child := range line.child {
+			if w.alignsToBaseline(child.n) {
+				maxBaseline = math.Max(maxBaseline, child.baseline)
+			}
+		}
 		for _, child := range line.child {
 			child.crossOffset = line.crossOffset
+			if w.alignsToBaseline(child.n) {
+				child.crossOffset += maxBaseline - child.baseline
+				continue
+			}
 			if child.crossSize == line.crossSize {
 				continue
 			}
@@ -452,7 +505,7 @@ func (w *Flex) Layout(t *theme.Theme) {
 			case AlignItemCenter:
 				child.crossOffset = line.crossOffset + diff/2
 			case AlignItemBaseline:
-				// TODO requires introducing inline-axis concept
+				// handled above, or the same as AlignItemStart
 			case AlignItemStretch:
 				// handled earlier, so child.crossSize == line.crossSize
 			}
@@ -507,20 +560,22 @@ func (w *Flex) Layout(t *theme.Theme) {
 	for l := range lines {
 		line := &lines[l]
 		for _, child := range line.child {
+			var r image.Rectangle
 			switch w.Direction {
 			case Row, RowReverse:
-				child.n.Rect.Min.X = round(child.mainOffset)
-				child.n.Rect.Max.X = round(child.mainOffset + child.mainSize)
-				child.n.Rect.Min.Y = round(child.crossOffset)
-				child.n.Rect.Max.Y = round(child.crossOffset + child.crossSize)
+				r.Min.X = round(child.mainOffset)
+				r.Max.X = round(child.mainOffset + child.mainSize)
+				r.Min.Y = round(child.crossOffset)
+				r.Max.Y = round(child.crossOffset + child.crossSize)
 			case Column, ColumnReverse:
-				child.n.Rect.Min.Y = round(child.mainOffset)
-				child.n.Rect.Max.Y = round(child.mainOffset + child.mainSize)
-				child.n.Rect.Min.X = round(child.crossOffset)
-				child.n.Rect.Max.X = round(child.crossOffset + child.crossSize)
+				r.Min.Y = round(child.mainOffset)
+				r.Max.Y = round(child.mainOffset + child.mainSize)
+				r.Min.X = round(child.crossOffset)
+				r.Max.X = round(child.crossOffset + child.crossSize)
 			default:
 				panic(fmt.Sprint("flex: bad direction ", w.Direction))
 			}
+			child.n.SetOuterRect(t, r)
 			child.n.Wrapper.Layout(t)
 		}
 	}
@@ -539,6 +594,7 @@ type element struct {
 	mainOffset   float64
 	crossSize    float64
 	crossOffset  float64
+	baseline     float64
 }
 
 type flexLine struct {
@@ -550,14 +606,57 @@ type flexLine struct {
 
 func (w *Flex) alignItem(n *node.Embed) AlignItem {
 	align := w.AlignItems
-	if d, ok := n.LayoutData.(LayoutData); ok {
+	if d, ok := n.LayoutData.(LayoutData); ok && d.Align != AlignItemAuto {
 		align = d.Align
 	}
 	return align
 }
 
+// gaps returns the main and cross axis gaps.
+func (w *Flex) gaps(t *theme.Theme) (mainGap, crossGap float64) {
+	switch w.Direction {
+	case Row, RowReverse:
+		return px(t, w.ColumnGap), px(t, w.RowGap)
+	case Column, ColumnReverse:
+		return px(t, w.RowGap), px(t, w.ColumnGap)
+	default:
+		panic(fmt.Sprint("flex: bad direction ", w.Direction))
+	}
+}
+
+// gapsSize returns the total size of the gaps between n items or lines.
+func gapsSize(n int, gap float64) float64 {
+	if n < 2 {
+		return 0
+	}
+	return float64(n-1) * gap
+}
+
+// alignsToBaseline returns whether n participates in baseline alignment.
+// Baselines are horizontal, so that is only for Row directions.
+func (w *Flex) alignsToBaseline(n *node.Embed) bool {
+	switch w.Direction {
+	case Row, RowReverse:
+		return w.alignItem(n) == AlignItemBaseline
+	}
+	return false
+}
+
+// baseline returns the distance from the cross-start edge of an element to
+// its baseline. An element without a baseline has one synthesized from its
+// cross-end edge, as per §8.5.
+func (w *Flex) baseline(t *theme.Theme, e *element) float64 {
+	if !w.alignsToBaseline(e.n) {
+		return 0
+	}
+	if b := e.n.OuterBaseline(t); b != node.NoBaseline {
+		return float64(b)
+	}
+	return e.crossSize
+}
+
 // flexBaseSize calculates flex base size as per §9.2.3
-func (w *Flex) flexBaseSize(n *node.Embed) int {
+func (w *Flex) flexBaseSize(t *theme.Theme, n *node.Embed, containerMainSize float64) float64 {
 	basis := Auto
 	if d, ok := n.LayoutData.(LayoutData); ok {
 		basis = d.Basis
@@ -565,9 +664,11 @@ func (w *Flex) flexBaseSize(n *node.Embed) int {
 	// TODO Content §9.2.3.B, C, D
 	switch basis {
 	case Definite: // A
-		return n.LayoutData.(LayoutData).BasisPx
+		return px(t, n.LayoutData.(LayoutData).BasisSize)
+	case Percentage: // A, resolved against the container's main size
+		return containerMainSize * n.LayoutData.(LayoutData).BasisPercent / 100
 	case Auto: // E
-		return w.mainSize(n.MeasuredSize)
+		return w.contentMainSize(t, n)
 	default:
 		panic(fmt.Sprintf("flex: unknown flex-basis %v", basis))
 	}
@@ -587,33 +688,101 @@ func shrinkFactor(n *node.Embed) float64 {
 	return 1
 }
 
-func aspectRatio(n *node.Embed) (ratio float64, ok bool) {
+func order(n *node.Embed) int {
+	if d, ok := n.LayoutData.(LayoutData); ok {
+		return d.Order
+	}
+	return 0
+}
+
+func aspectRatio(t *theme.Theme, n *node.Embed) (ratio float64, ok bool) {
 	// TODO: source a formal description of "intrinsic aspect ratio"
 	d, ok := n.LayoutData.(LayoutData)
-	if ok && d.MinSize.X != 0 && d.MinSize.Y != 0 {
-		return float64(d.MinSize.X) / float64(d.MinSize.Y), true
+	if ok {
+		x, y := px(t, d.MinSize.Width), px(t, d.MinSize.Height)
+		if x != 0 && y != 0 {
+			return x / y, true
+		}
 	}
 	return 0, false
 }
 
-func (w *Flex) clampSize(size float64, n *node.Embed) float64 {
+func (w *Flex) clampSize(t *theme.Theme, size float64, n *node.Embed) float64 {
+	minSize, maxSize := 0.0, math.Inf(+1)
 	if d, ok := n.LayoutData.(LayoutData); ok {
-		minSize := float64(w.mainSize(d.MinSize))
-		if minSize > size {
-			size = minSize
-		} else if d.MaxSize != nil {
-			maxSize := float64(w.mainSize(*d.MaxSize))
-			if size > maxSize {
-				size = maxSize
-			}
+		minSize = px(t, w.mainValue(d.MinSize))
+		if d.MaxSize != nil {
+			maxSize = px(t, w.mainValue(*d.MaxSize))
 		}
 	}
+	if minSize == 0 {
+		minSize = math.Min(w.autoMinSize(t, n), maxSize)
+	}
+	if minSize > size {
+		size = minSize
+	} else if size > maxSize {
+		size = maxSize
+	}
 	if size < 0 {
 		return 0
 	}
 	return size
 }
 
+// contentMainSize returns n's outer max-content size along the main axis: its
+// natural size, even if n has since been measured to be narrower.
+func (w *Flex) contentMainSize(t *theme.Theme, n *node.Embed) float64 {
+	if w.Direction == Row || w.Direction == RowReverse {
+		_, max := n.OuterIntrinsicWidths(t)
+		return float64(max)
+	}
+	return float64(w.mainSize(n.OuterSize(t)))
+}
+
+// autoMinSize returns the automatic minimum main size of n, used when its
+// MinSize is zero along the main axis. It is n's outer min-content width, if
+// the main axis is horizontal and n is a node.IntrinsicWidther, so that
+// shrinking does not cut off a word of wrapped text. Otherwise, it is zero.
+//
+// https://www.w3.org/TR/css-flexbox-1/#min-size-auto
+func (w *Flex) autoMinSize(t *theme.Theme, n *node.Embed) float64 {
+	if w.Direction != Row && w.Direction != RowReverse {
+		return 0
+	}
+	if _, ok := n.Wrapper.(node.IntrinsicWidther); !ok {
+		return 0
+	}
+	min, _ := n.OuterIntrinsicWidths(t)
+	return float64(min)
+}
+
+// px converts v to a fractional number of pixels.
+func px(t *theme.Theme, v unit.Value) float64 {
+	return float64(t.Pixels(v)) / 64
+}
+
+func (w *Flex) mainValue(s Size) unit.Value {
+	switch w.Direction {
+	case Row, RowReverse:
+		return s.Width
+	case Column, ColumnReverse:
+		return s.Height
+	default:
+		panic(fmt.Sprint("flex: bad direction ", w.Direction))
+	}
+}
+
+func (w *Flex) crossValue(s Size) unit.Value {
+	switch w.Direction {
+	case Row, RowReverse:
+		return s.Height
+	case Column, ColumnReverse:
+		return s.Width
+	default:
+		panic(fmt.Sprint("flex: bad direction ", w.Direction))
+	}
+}
+
 func (w *Flex) mainSize(p image.Point) int {
 	switch w.Direction {
 	case Row, RowReverse:
@@ -636,10 +805,15 @@ func (w *Flex) crossSize(p image.Point) int {
 	}
 }
 
+// Size is a width and a height.
+type Size struct {
+	Width, Height unit.Value
+}
+
 // LayoutData is the node LayoutData type for a Flex's children.
 type LayoutData struct {
-	MinSize image.Point  // TODO use unit.Value
-	MaxSize *image.Point // TODO use unit.Value
+	MinSize Size
+	MaxSize *Size
 
 	// Grow determines how much a node will grow relative to its siblings.
 	Grow float64
@@ -652,12 +826,20 @@ type LayoutData struct {
 	// Basis determines the initial size of the node in the direction
 	// of the flex container (the main axis).
 	//
-	// If set to Definite, the value stored in BasisPx is used.
-	Basis   Basis
-	BasisPx int // TODO use unit package?
+	// If set to Definite, the value stored in BasisSize is used. If set
+	// to Percentage, BasisPercent percent of the container's main size
+	// is used.
+	Basis        Basis
+	BasisSize    unit.Value
+	BasisPercent float64
 
 	Align AlignItem
 
+	// Order controls the order in which the node is laid out, relative
+	// to its siblings. Nodes are laid out in increasing Order, and then in
+	// tree order.
+	Order int
+
 	// BreakAfter forces the next node onto the next flex line.
 	BreakAfter bool
 }
@@ -23,6 +23,9 @@ type layoutTest struct {
 	wrap         FlexWrap
 	alignContent AlignContent
 	justify      Justify
+	alignItems   AlignItem
+	rowGap       float64
+	columnGap    float64
 	size         image.Point       // size of container
 	measured     [][2]float64      // MeasuredSize of child elements
 	layoutData   []LayoutData      // LayoutData of child elements
@@ -56,6 +59,7 @@ func (t *layoutTest) html() string {
 	}
 	switch t.alignContent {
 	case AlignContentStart:
+		fmt.Fprintf(buf, "\talign-content: flex-start;\n")
 	case AlignContentEnd:
 		fmt.Fprintf(buf, "\talign-content: flex-end;\n")
 	case AlignContentCenter:
@@ -78,6 +82,15 @@ func (t *layoutTest) html() string {
 	case JustifySpaceAround:
 		fmt.Fprintf(buf, "\tjustify-content: space-around;\n")
 	}
+	if t.alignItems != AlignItemAuto {
+		fmt.Fprintf(buf, "\talign-items: %s;\n", alignItemCSS[t.alignItems])
+	}
+	if t.rowGap != 0 {
+		fmt.Fprintf(buf, "\trow-gap: %gpx;\n", t.rowGap)
+	}
+	if t.columnGap != 0 {
+		fmt.Fprintf(buf, "\tcolumn-gap: %gpx;\n", t.columnGap)
+	}
 	fmt.Fprintf(buf, "}\n")
 
 	for i, m := range t.measured {
@@ -89,15 +102,15 @@ func (t *layoutTest) html() string {
 		fmt.Fprintf(buf, "\tbackground-color: rgb(%d, %d, %d);\n", c.R, c.G, c.B)
 		if t.layoutData != nil {
 			d := t.layoutData[i]
-			if d.MinSize.X != 0 {
-				fmt.Fprintf(buf, "\tmin-width: %dpx;\n", d.MinSize.X)
+			if d.MinSize.Width.F != 0 {
+				fmt.Fprintf(buf, "\tmin-width: %gpx;\n", d.MinSize.Width.F)
 			}
-			if d.MinSize.Y != 0 {
-				fmt.Fprintf(buf, "\tmin-height: %dpx;\n", d.MinSize.Y)
+			if d.MinSize.Height.F != 0 {
+				fmt.Fprintf(buf, "\tmin-height: %gpx;\n", d.MinSize.Height.F)
 			}
 			if d.MaxSize != nil {
-				fmt.Fprintf(buf, "\tmax-width: %dpx;\n", d.MaxSize.X)
-				fmt.Fprintf(buf, "\tmax-height: %dpx;\n", d.MaxSize.Y)
+				fmt.Fprintf(buf, "\tmax-width: %gpx;\n", d.MaxSize.Width.F)
+				fmt.Fprintf(buf, "\tmax-height: %gpx;\n", d.MaxSize.Height.F)
 			}
 			if d.Grow != 0 {
 				fmt.Fprintf(buf, "\tflex-grow: %f;\n", d.Grow)
@@ -105,7 +118,19 @@ func (t *layoutTest) html() string {
 			if d.Shrink != nil {
 				fmt.Fprintf(buf, "\tflex-shrink: %f;\n", *d.Shrink)
 			}
-			// TODO: Basis, Align, BreakAfter
+			switch d.Basis {
+			case Definite:
+				fmt.Fprintf(buf, "\tflex-basis: %gpx;\n", d.BasisSize.F)
+			case Percentage:
+				fmt.Fprintf(buf, "\tflex-basis: %g%%;\n", d.BasisPercent)
+			}
+			if d.Align != AlignItemAuto {
+				fmt.Fprintf(buf, "\talign-self: %s;\n", alignItemCSS[d.Align])
+			}
+			if d.Order != 0 {
+				fmt.Fprintf(buf, "\torder: %d;\n", d.Order)
+			}
+			// TODO: BreakAfter
 		}
 		fmt.Fprintf(buf, "}\n")
 	}
@@ -135,6 +160,23 @@ for (var i = 0; i < container.children.length; i++) {
 	return buf.String()
 }
 
+var alignItemCSS = [...]string{
+	AlignItemAuto:     "auto",
+	AlignItemStart:    "flex-start",
+	AlignItemEnd:      "flex-end",
+	AlignItemCenter:   "center",
+	AlignItemBaseline: "baseline",
+	AlignItemStretch:  "stretch",
+}
+
+func minSize(x, y float64) Size {
+	return Size{unit.Pixels(x), unit.Pixels(y)}
+}
+
+func maxSize(x, y float64) *Size {
+	return &Size{unit.Pixels(x), unit.Pixels(y)}
+}
+
 var colors = []color.RGBA{
 	{0x00, 0x7f, 0x7f, 0xff}, // Cyan
 	{0x7f, 0x00, 0x7f, 0xff}, // Magenta
@@ -194,8 +236,8 @@ var layoutTests = []layoutTest{{
 		image.Rect(130, 0, 300, 100),
 	},
 	layoutData: []LayoutData{
-		{MaxSize: &image.Point{30, 100}, Grow: 1},
-		{MinSize: image.Point{100, 0}, Grow: 1},
+		{MaxSize: maxSize(30, 100), Grow: 1},
+		{MinSize: minSize(100, 0), Grow: 1},
 		{Grow: 4},
 	},
 }, {
@@ -209,8 +251,8 @@ var layoutTests = []layoutTest{{
 		image.Rect(280, 100, 300, 200),
 	},
 	layoutData: []LayoutData{
-		{MaxSize: &image.Point{30, 100}, Grow: 1},
-		{MinSize: image.Point{100, 0}, Grow: 1},
+		{MaxSize: maxSize(30, 100), Grow: 1},
+		{MinSize: minSize(100, 0), Grow: 1},
 		{Grow: 1},
 	},
 }, {
@@ -226,10 +268,10 @@ var layoutTests = []layoutTest{{
 		image.Rect(220, 195, 225, 200),
 	},
 	layoutData: []LayoutData{
-		{MaxSize: &image.Point{30, 100}, Grow: 1},
-		{MinSize: image.Point{100, 0}, Grow: 1},
+		{MaxSize: maxSize(30, 100), Grow: 1},
+		{MinSize: minSize(100, 0), Grow: 1},
 		{Grow: 1},
-		{MaxSize: &image.Point{5, 5}},
+		{MaxSize: maxSize(5, 5)},
 	},
 }, {
 	desc:         "align-content: space-around",
@@ -245,10 +287,10 @@ var layoutTests = []layoutTest{{
 		image.Rect(250, 195, 255, 200),
 	},
 	layoutData: []LayoutData{
-		{MaxSize: &image.Point{30, 100}, Grow: 1},
-		{MinSize: image.Point{100, 0}, Grow: 1},
+		{MaxSize: maxSize(30, 100), Grow: 1},
+		{MinSize: minSize(100, 0), Grow: 1},
 		{Grow: 1},
-		{MaxSize: &image.Point{5, 5}},
+		{MaxSize: maxSize(5, 5)},
 	},
 }, {
 	desc:         "align-content: space-between",
@@ -264,10 +306,10 @@ var layoutTests = []layoutTest{{
 		image.Rect(280, 195, 285, 200),
 	},
 	layoutData: []LayoutData{
-		{MaxSize: &image.Point{30, 100}, Grow: 1},
-		{MinSize: image.Point{100, 0}, Grow: 1},
+		{MaxSize: maxSize(30, 100), Grow: 1},
+		{MinSize: minSize(100, 0), Grow: 1},
 		{Grow: 1},
-		{MaxSize: &image.Point{5, 5}},
+		{MaxSize: maxSize(5, 5)},
 	},
 }, {
 	desc:         "align-content: end",
@@ -283,10 +325,10 @@ var layoutTests = []layoutTest{{
 		image.Rect(280, 195, 285, 200),
 	},
 	layoutData: []LayoutData{
-		{MaxSize: &image.Point{30, 100}, Grow: 1},
-		{MinSize: image.Point{100, 0}, Grow: 1},
+		{MaxSize: maxSize(30, 100), Grow: 1},
+		{MinSize: minSize(100, 0), Grow: 1},
 		{Grow: 1},
-		{MaxSize: &image.Point{5, 5}},
+		{MaxSize: maxSize(5, 5)},
 	},
 }, {
 	desc:         "align-content: center",
@@ -302,10 +344,10 @@ var layoutTests = []layoutTest{{
 		image.Rect(220, 195, 225, 200),
 	},
 	layoutData: []LayoutData{
-		{MaxSize: &image.Point{30, 100}, Grow: 1},
-		{MinSize: image.Point{100, 0}, Grow: 1},
+		{MaxSize: maxSize(30, 100), Grow: 1},
+		{MinSize: minSize(100, 0), Grow: 1},
 		{Grow: 1},
-		{MaxSize: &image.Point{5, 5}},
+		{MaxSize: maxSize(5, 5)},
 	},
 }, {
 	desc:      "column-reverse",
@@ -377,6 +419,76 @@ var layoutTests = []layoutTest{{
 		image.Rect(40, 0, 45, 10),
 		image.Rect(68, 0, 78, 10),
 	},
+}, {
+	desc:      "column-gap",
+	size:      image.Point{100, 50},
+	columnGap: 10,
+	measured:  [][2]float64{{20, 10}, {20, 10}, {20, 10}},
+	want: []image.Rectangle{
+		image.Rect(0, 0, 20, 10),
+		image.Rect(30, 0, 50, 10),
+		image.Rect(60, 0, 80, 10),
+	},
+}, {
+	desc:         "row-gap and column-gap wrapped",
+	size:         image.Point{100, 100},
+	wrap:         Wrap,
+	alignContent: AlignContentStart,
+	rowGap:       5,
+	columnGap:    10,
+	measured:     [][2]float64{{40, 20}, {40, 20}, {40, 20}, {40, 20}},
+	want: []image.Rectangle{
+		image.Rect(0, 0, 40, 20),
+		image.Rect(50, 0, 90, 20),
+		image.Rect(0, 25, 40, 45),
+		image.Rect(50, 25, 90, 45),
+	},
+}, {
+	desc:      "column gaps shrink growing items",
+	size:      image.Point{100, 10},
+	columnGap: 10,
+	measured:  [][2]float64{{0, 10}, {0, 10}, {0, 10}},
+	want: []image.Rectangle{
+		image.Rect(0, 0, 20, 10),
+		image.Rect(30, 0, 50, 10),
+		image.Rect(60, 0, 100, 10),
+	},
+	layoutData: []LayoutData{{Grow: 1}, {Grow: 1}, {Grow: 2}},
+}, {
+	desc:     "order",
+	size:     image.Point{100, 10},
+	measured: [][2]float64{{10, 10}, {20, 10}, {30, 10}},
+	want: []image.Rectangle{
+		image.Rect(50, 0, 60, 10),
+		image.Rect(0, 0, 20, 10),
+		image.Rect(20, 0, 50, 10),
+	},
+	layoutData: []LayoutData{{Order: 2}, {}, {Order: 1}},
+}, {
+	desc:     "flex-basis",
+	size:     image.Point{200, 10},
+	measured: [][2]float64{{10, 10}, {10, 10}, {10, 10}},
+	want: []image.Rectangle{
+		image.Rect(0, 0, 50, 10),
+		image.Rect(50, 0, 100, 10),
+		image.Rect(100, 0, 200, 10),
+	},
+	layoutData: []LayoutData{
+		{Basis: Definite, BasisSize: unit.Pixels(50)},
+		{Basis: Percentage, BasisPercent: 25},
+		{Basis: Percentage, BasisPercent: 50},
+	},
+}, {
+	desc:       "align-items: baseline without baselines",
+	size:       image.Point{100, 100},
+	alignItems: AlignItemBaseline,
+	measured:   [][2]float64{{10, 20}, {10, 40}, {10, 30}},
+	want: []image.Rectangle{
+		image.Rect(0, 20, 10, 40),
+		image.Rect(10, 0, 20, 40),
+		image.Rect(20, 0, 30, 30),
+	},
+	layoutData: []LayoutData{{}, {}, {Align: AlignItemStart}},
 }}
 
 func TestLayout(t *testing.T) {
@@ -396,6 +508,9 @@ func TestLayout(t *testing.T) {
 		w.Wrap = test.wrap
 		w.AlignContent = test.alignContent
 		w.Justify = test.justify
+		w.AlignItems = test.alignItems
+		w.RowGap = unit.Pixels(test.rowGap)
+		w.ColumnGap = unit.Pixels(test.columnGap)
 
 		w.Measure(nil, node.NoHint, node.NoHint)
 		w.Rect = image.Rectangle{Max: test.size}
@@ -418,3 +533,164 @@ func TestLayout(t *testing.T) {
 		}
 	}
 }
+
+// baseliner is a leaf node with a fixed size and text baseline.
+type baseliner struct {
+	node.LeafEmbed
+	size     image.Point
+	baseline int
+}
+
+func newBaseliner(x, y, baseline int) *baseliner {
+	w := &baseliner{size: image.Point{x, y}, baseline: baseline}
+	w.Wrapper = w
+	return w
+}
+
+func (w *baseliner) Measure(t *theme.Theme, widthHint, heightHint int) { w.MeasuredSize = w.size }
+
+func (w *baseliner) Baseline(t *theme.Theme) int { return w.baseline }
+
+func TestBaseline(t *testing.T) {
+	children := []node.Node{
+		newBaseliner(30, 20, 15),
+		newBaseliner(30, 30, 10),
+		newBaseliner(30, 10, node.NoBaseline),
+		newBaseliner(80, 10, 5),
+	}
+	want := []image.Rectangle{
+		// The first line's cross size is the largest distance above a
+		// baseline, 15, plus the largest distance below one, 20. The third
+		// child has no baseline, so its baseline is its bottom edge.
+		image.Rect(0, 0, 30, 20),
+		image.Rect(30, 5, 60, 35),
+		image.Rect(60, 5, 90, 15),
+		image.Rect(0, 35, 80, 45),
+	}
+
+	w := NewFlex(children...)
+	w.Wrap = Wrap
+	w.AlignItems = AlignItemBaseline
+	w.AlignContent = AlignContentStart
+	w.Measure(nil, node.NoHint, node.NoHint)
+	w.Rect = image.Rectangle{Max: image.Point{100, 100}}
+	w.Layout(nil)
+
+	for i, n := range children {
+		if got := n.Wrappee().Rect; got != want[i] {
+			t.Errorf("[%d].Rect=%v, want %v", i, got, want[i])
+		}
+	}
+
+	// Baselines are horizontal, so baseline alignment in a column is start
+	// alignment.
+	w.Direction = Column
+	w.Wrap = NoWrap
+	w.Layout(nil)
+	for i, n := range children {
+		if got := n.Wrappee().Rect.Min.X; got != 0 {
+			t.Errorf("column: [%d].Rect.Min.X=%d, want 0", i, got)
+		}
+	}
+}
+
+func TestBox(t *testing.T) {
+	a := newBaseliner(30, 20, 15)
+	a.Box = &node.Box{
+		Margin:  node.Insets{Left: unit.Pixels(5)},
+		Padding: node.UniformInsets(unit.Pixels(2)),
+	}
+	b := newBaseliner(40, 10, 5)
+	b.Box = &node.Box{
+		Margin: node.Insets{Top: unit.Pixels(10), Bottom: unit.Pixels(4)},
+		Border: node.Border{Width: node.UniformInsets(unit.Pixels(1))},
+	}
+	children := []node.Node{a, b}
+
+	w := NewFlex(children...)
+	w.AlignItems = AlignItemStart
+	w.Measure(nil, node.NoHint, node.NoHint)
+	if got, want := a.OuterSize(nil), (image.Point{39, 24}); got != want {
+		t.Errorf("a.OuterSize: got %v, want %v", got, want)
+	}
+	if got, want := b.OuterSize(nil), (image.Point{42, 26}); got != want {
+		t.Errorf("b.OuterSize: got %v, want %v", got, want)
+	}
+	w.Rect = image.Rectangle{Max: image.Point{100, 100}}
+	w.Layout(nil)
+	want := []image.Rectangle{
+		image.Rect(7, 2, 37, 22),
+		image.Rect(40, 11, 80, 21),
+	}
+	for i, n := range children {
+		if got := n.Wrappee().Rect; got != want[i] {
+			t.Errorf("[%d].Rect=%v, want %v", i, got, want[i])
+		}
+	}
+
+	// The outer baselines are 17 and 16, so aligning them moves the second
+	// child down by 1 pixel.
+	w.AlignItems = AlignItemBaseline
+	w.Layout(nil)
+	want[1] = want[1].Add(image.Point{0, 1})
+	for i, n := range children {
+		if got := n.Wrappee().Rect; got != want[i] {
+			t.Errorf("baseline: [%d].Rect=%v, want %v", i, got, want[i])
+		}
+	}
+}
+
+// wrapper is a leaf node that, like a wrapped Text, gets taller as it gets
+// narrower, down to its minimum content width.
+type wrapper struct {
+	node.LeafEmbed
+	min, max, area int
+}
+
+func newWrapper(min, max, area int) *wrapper {
+	w := &wrapper{min: min, max: max, area: area}
+	w.Wrapper = w
+	return w
+}
+
+func (w *wrapper) Measure(t *theme.Theme, widthHint, heightHint int) {
+	x := w.max
+	if widthHint >= 0 && widthHint < x {
+		x = widthHint
+	}
+	if x < w.min {
+		x = w.min
+	}
+	w.MeasuredSize = image.Point{x, (w.area + x - 1) / x}
+}
+
+func (w *wrapper) IntrinsicWidths(t *theme.Theme) (min, max int) { return w.min, w.max }
+
+func TestIntrinsicWidths(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		direction Direction
+		size      image.Point
+		want      image.Rectangle
+	}{
+		{"fits", Row, image.Point{200, 100}, image.Rect(0, 0, 120, 10)},
+		{"shrunk", Row, image.Point{60, 100}, image.Rect(0, 0, 60, 20)},
+		// The automatic minimum size is the minimum content width.
+		{"too narrow", Row, image.Point{20, 100}, image.Rect(0, 0, 30, 40)},
+		{"too narrow, reversed", RowReverse, image.Point{20, 100}, image.Rect(-10, 0, 20, 40)},
+		// A column's main axis is vertical, so its items are not wrapped.
+		{"column", Column, image.Point{200, 100}, image.Rect(0, 0, 120, 10)},
+	}
+	for _, tc := range testCases {
+		n := newWrapper(30, 120, 1200)
+		w := NewFlex(n)
+		w.Direction = tc.direction
+		w.AlignItems = AlignItemStart
+		w.Measure(nil, node.NoHint, node.NoHint)
+		w.Rect = image.Rectangle{Max: tc.size}
+		w.Layout(nil)
+		if got := n.Rect; got != tc.want {
+			t.Errorf("%s: Rect=%v, want %v", tc.desc, got, tc.want)
+		}
+	}
+}
@@ -11,7 +11,7 @@ import (
 	"golang.org/x/exp/shiny/widget/theme"
 )
 
-// TODO: padding, alignment.
+// TODO: alignment.
 
 // Flow is a container widget that lays out its children in sequence along an
 // axis, either horizontally or vertically. The children's laid out size may
@@ -49,16 +49,17 @@ func (w *Flow) Measure(t *theme.Theme, widthHint, heightHint int) {
 
 	mSize := image.Point{}
 	for c := w.FirstChild; c != nil; c = c.NextSibling {
-		c.Wrapper.Measure(t, widthHint, heightHint)
+		c.MeasureOuter(t, widthHint, heightHint)
+		size := c.OuterSize(t)
 		if w.Axis == AxisHorizontal {
-			mSize.X += c.MeasuredSize.X
-			if mSize.Y < c.MeasuredSize.Y {
-				mSize.Y = c.MeasuredSize.Y
+			mSize.X += size.X
+			if mSize.Y < size.Y {
+				mSize.Y = size.Y
 			}
 		} else {
-			mSize.Y += c.MeasuredSize.Y
-			if mSize.X < c.MeasuredSize.X {
-				mSize.X = c.MeasuredSize.X
+			mSize.Y += size.Y
+			if mSize.X < size.X {
+				mSize.X = size.X
 			}
 		}
 	}
@@ -90,9 +91,9 @@ func (w *Flow) Layout(t *theme.Theme) {
 			}
 		}
 		if w.Axis == AxisHorizontal {
-			extra -= c.MeasuredSize.X
+			extra -= c.OuterSize(t).X
 		} else {
-			extra -= c.MeasuredSize.Y
+			extra -= c.OuterSize(t).Y
 		}
 	}
 	expand, shrink, totalWeight := extra > 0, extra < 0, 0
@@ -113,7 +114,7 @@ func (w *Flow) Layout(t *theme.Theme) {
 
 	p := image.Point{}
 	for c := w.FirstChild; c != nil; c = c.NextSibling {
-		q := p.Add(c.MeasuredSize)
+		q := p.Add(c.OuterSize(t))
 		if d, ok := c.LayoutData.(FlowLayoutData); ok {
 			if d.AlongWeight > 0 {
 				if (expand && d.ExpandAlong) || (shrink && d.ShrinkAlong) {
@@ -140,10 +141,10 @@ func (w *Flow) Layout(t *theme.Theme) {
 				q.X = stretchAcross(q.X, w.Rect.Dx(), d.ExpandAcross, d.ShrinkAcross)
 			}
 		}
-		c.Rect = image.Rectangle{
+		c.SetOuterRect(t, image.Rectangle{
 			Min: p,
 			Max: q,
-		}
+		})
 		c.Wrapper.Layout(t)
 		if w.Axis == AxisHorizontal {
 			p.X = q.X
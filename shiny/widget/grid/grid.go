@@ -0,0 +1,670 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package grid provides a container widget that lays out its children in
+// rows and columns, following the CSS grid layout algorithm.
+//
+// As with the flex package, the grid package diverges from CSS in several
+// ways. There are no named lines or areas, no dense packing and no subgrids.
+// Item margins are given by a child's node.Box. A node's min-content and
+// max-content widths are given by its OuterIntrinsicWidths, so that a node
+// such as wrapped text can be narrower than its natural width, and is then
+// measured again, for its height, at the width of its grid area. Otherwise,
+// a node's outer size, its MeasuredSize plus its Box's insets, is both its
+// min-content and its max-content size. The container size provided by the
+// outer widget is taken as gospel and never expanded.
+package grid
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"sort"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+)
+
+// SizeKind is the kind of a track's minimum or maximum size.
+type SizeKind uint8
+
+const (
+	// SizeAuto sizes a track to fit its content.
+	SizeAuto SizeKind = iota
+	// SizeFixed sizes a track to a Length.
+	SizeFixed
+	// SizeFr sizes a track to a fraction of the free space. It is only
+	// valid as a track's maximum size.
+	SizeFr
+)
+
+// Size is one bound of a track's size.
+//
+// https://www.w3.org/TR/css-grid-1/#track-sizing
+type Size struct {
+	Kind SizeKind
+
+	// Length is the size, if Kind is SizeFixed.
+	Length unit.Value
+
+	// Fr is the flex factor, if Kind is SizeFr.
+	Fr float64
+}
+
+// Length returns a fixed Size.
+func Length(v unit.Value) Size { return Size{Kind: SizeFixed, Length: v} }
+
+// Fraction returns a flexible Size, the CSS 'fr' unit.
+func Fraction(fr float64) Size { return Size{Kind: SizeFr, Fr: fr} }
+
+// Track is the sizing function of a row or column, the CSS minmax(Min, Max).
+//
+// The zero value is an auto track, sized to fit its content.
+type Track struct {
+	Min, Max Size
+}
+
+// Fixed returns a Track of the given size.
+func Fixed(v unit.Value) Track { return Track{Min: Length(v), Max: Length(v)} }
+
+// Fr returns a Track that takes the given fraction of the free space, but is
+// no smaller than its content. It is the CSS '1fr'.
+func Fr(fr float64) Track { return Track{Max: Fraction(fr)} }
+
+// Auto returns a Track that is sized to fit its content.
+func Auto() Track { return Track{} }
+
+// MinMax returns a Track that is no smaller than min and no larger than max.
+func MinMax(min, max Size) Track { return Track{Min: min, Max: max} }
+
+// Align aligns an item inside its grid area, along one axis.
+//
+// It is the 'justify-items' and 'align-items' properties when applied to a
+// Grid container, and the 'justify-self' and 'align-self' properties when
+// applied to an item in LayoutData.
+//
+// https://www.w3.org/TR/css-align-3/#propdef-justify-self
+type Align uint8
+
+const (
+	AlignAuto Align = iota
+	AlignStart
+	AlignEnd
+	AlignCenter
+	AlignStretch
+)
+
+// Grid is a container widget that lays out its children in rows and columns,
+// following the CSS grid layout algorithm.
+//
+// Children without LayoutData, or with zero Row and Column, are placed in the
+// next free cells in row-major order. Rows and columns beyond the explicit
+// Rows and Columns are created as needed, and are auto tracks.
+type Grid struct {
+	node.ContainerEmbed
+
+	// Columns and Rows are the explicit tracks, the CSS
+	// 'grid-template-columns' and 'grid-template-rows'.
+	Columns []Track
+	Rows    []Track
+
+	// ColumnGap and RowGap are the space between adjacent columns and rows.
+	ColumnGap unit.Value
+	RowGap    unit.Value
+
+	// JustifyItems and AlignItems align the children inside their grid
+	// areas, horizontally and vertically. The zero value, AlignAuto, means
+	// AlignStretch.
+	JustifyItems Align
+	AlignItems   Align
+}
+
+// NewGrid returns a new Grid widget containing the given children.
+func NewGrid(columns []Track, children ...node.Node) *Grid {
+	w := &Grid{Columns: columns}
+	w.Wrapper = w
+	for _, c := range children {
+		w.Insert(c, nil)
+	}
+	return w
+}
+
+func (w *Grid) Measure(t *theme.Theme, widthHint, heightHint int) {
+	for c := w.FirstChild; c != nil; c = c.NextSibling {
+		// TODO: pass down width/height hints?
+		c.MeasureOuter(t, node.NoHint, node.NoHint)
+	}
+	items, nRows, nCols := w.place(t)
+	cols := w.sizeTracks(t, w.Columns, nCols, items, horizontal, -1)
+	rows := w.sizeTracks(t, w.Rows, nRows, items, vertical, -1)
+	w.MeasuredSize = image.Point{
+		X: round(sum(cols) + float64(maxInt(nCols-1, 0))*w.gap(t, horizontal)),
+		Y: round(sum(rows) + float64(maxInt(nRows-1, 0))*w.gap(t, vertical)),
+	}
+}
+
+func (w *Grid) Layout(t *theme.Theme) {
+	items, nRows, nCols := w.place(t)
+	size := w.Rect.Size()
+	cols := w.sizeTracks(t, w.Columns, nCols, items, horizontal, float64(size.X))
+	colOffsets := offsets(cols, w.gap(t, horizontal))
+	// An item with a flexible width, such as wrapped text, can be taller when
+	// it is narrower than its max-content width, so measure it again at its
+	// grid area's width.
+	for _, it := range items {
+		if it.minWidth == it.maxWidth {
+			continue
+		}
+		i, j := it.start[horizontal], it.start[horizontal]+it.span[horizontal]-1
+		width := round(colOffsets[j] + cols[j] - colOffsets[i])
+		if width > it.maxWidth {
+			width = it.maxWidth
+		}
+		it.n.MeasureOuter(t, width, node.NoHint)
+		it.outer = it.n.OuterSize(t)
+		if it.outer.X > width {
+			it.outer.X = width
+		}
+	}
+	rows := w.sizeTracks(t, w.Rows, nRows, items, vertical, float64(size.Y))
+	rowOffsets := offsets(rows, w.gap(t, vertical))
+
+	for _, it := range items {
+		x0, x1 := w.align(it, horizontal, colOffsets, cols)
+		y0, y1 := w.align(it, vertical, rowOffsets, rows)
+		it.n.SetOuterRect(t, image.Rect(round(x0), round(y0), round(x1), round(y1)))
+		it.n.Wrapper.Layout(t)
+	}
+}
+
+// axis is a horizontal or vertical axis.
+type axis uint8
+
+const (
+	horizontal axis = iota
+	vertical
+)
+
+// item is a child node and its grid area, in zero-based track indexes.
+type item struct {
+	n *node.Embed
+	// outer is the node's outer size, including its Box. minWidth and
+	// maxWidth are its outer min-content and max-content widths.
+	outer              image.Point
+	minWidth, maxWidth int
+	// start and span are indexed by axis.
+	start, span [2]int
+}
+
+func (it *item) size(a axis) float64 {
+	if a == horizontal {
+		return float64(it.outer.X)
+	}
+	return float64(it.outer.Y)
+}
+
+// minContent and maxContent return the item's min-content and max-content
+// sizes along the axis a.
+func (it *item) minContent(a axis) float64 {
+	if a == horizontal {
+		return float64(it.minWidth)
+	}
+	return float64(it.outer.Y)
+}
+
+func (it *item) maxContent(a axis) float64 {
+	if a == horizontal {
+		return float64(it.maxWidth)
+	}
+	return float64(it.outer.Y)
+}
+
+func (w *Grid) gap(t *theme.Theme, a axis) float64 {
+	if a == horizontal {
+		return t.Convert(w.ColumnGap, unit.Px).F
+	}
+	return t.Convert(w.RowGap, unit.Px).F
+}
+
+// place assigns a grid area to each child, following the CSS grid item
+// placement algorithm in its sparse mode. It returns the items and the number
+// of rows and columns in the implicit grid.
+//
+// https://www.w3.org/TR/css-grid-1/#auto-placement-algo
+func (w *Grid) place(t *theme.Theme) (items []*item, nRows, nCols int) {
+	nCols = len(w.Columns)
+	for c := w.FirstChild; c != nil; c = c.NextSibling {
+		d, _ := c.LayoutData.(LayoutData)
+		it := &item{
+			n:     c,
+			outer: c.OuterSize(t),
+			span:  [2]int{maxInt(d.ColumnSpan, 1), maxInt(d.RowSpan, 1)},
+		}
+		it.minWidth, it.maxWidth = c.OuterIntrinsicWidths(t)
+		it.start[horizontal] = d.Column - 1
+		it.start[vertical] = d.Row - 1
+		if d.Column > 0 {
+			nCols = maxInt(nCols, it.start[horizontal]+it.span[horizontal])
+		} else {
+			nCols = maxInt(nCols, it.span[horizontal])
+		}
+		items = append(items, it)
+	}
+
+	occupied := map[image.Point]bool{}
+	fits := func(it *item, col, row int) bool {
+		for y := row; y < row+it.span[vertical]; y++ {
+			for x := col; x < col+it.span[horizontal]; x++ {
+				if occupied[image.Point{x, y}] {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	occupy := func(it *item, col, row int) {
+		it.start = [2]int{col, row}
+		for y := row; y < row+it.span[vertical]; y++ {
+			for x := col; x < col+it.span[horizontal]; x++ {
+				occupied[image.Point{x, y}] = true
+			}
+		}
+		nCols = maxInt(nCols, col+it.span[horizontal])
+		nRows = maxInt(nRows, row+it.span[vertical])
+	}
+
+	// §8.5 step 1: position anything that is not auto-positioned.
+	for _, it := range items {
+		if col, row := it.start[horizontal], it.start[vertical]; col >= 0 && row >= 0 {
+			occupy(it, col, row)
+		}
+	}
+	// §8.5 step 2: process the items locked to a given row.
+	rowCursors := map[int]int{}
+	for _, it := range items {
+		col, row := it.start[horizontal], it.start[vertical]
+		if col >= 0 || row < 0 {
+			continue
+		}
+		for col = rowCursors[row]; !fits(it, col, row); col++ {
+		}
+		occupy(it, col, row)
+		rowCursors[row] = col + it.span[horizontal]
+	}
+	// §8.5 step 4: position the remaining grid items.
+	cursorCol, cursorRow := 0, 0
+	for _, it := range items {
+		col, row := it.start[horizontal], it.start[vertical]
+		if row >= 0 {
+			continue
+		}
+		if col >= 0 {
+			if col < cursorCol {
+				cursorRow++
+			}
+			for !fits(it, col, cursorRow) {
+				cursorRow++
+			}
+		} else {
+			for {
+				for col = cursorCol; col+it.span[horizontal] <= nCols && !fits(it, col, cursorRow); col++ {
+				}
+				if col+it.span[horizontal] <= nCols {
+					break
+				}
+				cursorCol, cursorRow = 0, cursorRow+1
+			}
+		}
+		occupy(it, col, cursorRow)
+		cursorCol = col + it.span[horizontal]
+	}
+
+	nRows = maxInt(nRows, len(w.Rows))
+	return items, nRows, nCols
+}
+
+// track is a row or column being sized.
+type track struct {
+	Track
+	base  float64
+	limit float64
+}
+
+func (k *track) flexible() bool { return k.Max.Kind == SizeFr }
+
+// sizeTracks returns the sizes of the n tracks along the axis a, following
+// the CSS grid track sizing algorithm. The first tracks are given by explicit
+// and the rest are auto tracks. avail is the size of the container, or
+// negative if it is indefinite.
+//
+// https://www.w3.org/TR/css-grid-1/#algo-track-sizing
+func (w *Grid) sizeTracks(t *theme.Theme, explicit []Track, n int, items []*item, a axis, avail float64) []float64 {
+	gap := w.gap(t, a)
+	tracks := make([]track, n)
+	for i := range tracks {
+		k := &tracks[i]
+		if i < len(explicit) {
+			k.Track = explicit[i]
+		}
+		if k.Min.Kind == SizeFr {
+			panic(fmt.Sprintf("grid: flexible minimum size in track %d", i))
+		}
+		// §11.4 initialize track sizes.
+		if k.Min.Kind == SizeFixed {
+			k.base = t.Convert(k.Min.Length, unit.Px).F
+		}
+		k.limit = math.Inf(+1)
+		if k.Max.Kind == SizeFixed {
+			k.limit = math.Max(k.base, t.Convert(k.Max.Length, unit.Px).F)
+		}
+	}
+
+	// §11.5 resolve intrinsic track sizes.
+	//
+	// An item's contribution to an auto minimum size is its min-content size
+	// or, when measuring the grid's natural size, its max-content size, as
+	// for sizing under a max-content constraint.
+	minContribution := func(it *item) float64 {
+		if avail < 0 {
+			return it.maxContent(a)
+		}
+		return it.minContent(a)
+	}
+
+	// Step 2: size tracks to fit non-spanning items.
+	maxContent := make([]float64, n)
+	hasItems := make([]bool, n)
+	for _, it := range items {
+		if it.span[a] != 1 {
+			continue
+		}
+		i := it.start[a]
+		if tracks[i].Min.Kind == SizeAuto {
+			tracks[i].base = math.Max(tracks[i].base, minContribution(it))
+		}
+		maxContent[i] = math.Max(maxContent[i], it.maxContent(a))
+		hasItems[i] = true
+	}
+	for i := range tracks {
+		if k := &tracks[i]; k.Max.Kind == SizeAuto && hasItems[i] {
+			k.limit = math.Max(k.base, maxContent[i])
+		}
+	}
+
+	// Steps 3 and 4: increase sizes to accommodate spanning items, first
+	// those that do not cross flexible tracks, then those that do, each in
+	// order of increasing span.
+	//
+	// TODO: also distribute the items' max-content contributions to the
+	// growth limits of the spanned tracks.
+	spanning := make([]*item, 0, len(items))
+	for _, it := range items {
+		if it.span[a] > 1 {
+			spanning = append(spanning, it)
+		}
+	}
+	sort.SliceStable(spanning, func(i, j int) bool {
+		return spanning[i].span[a] < spanning[j].span[a]
+	})
+	for _, crossesFlexible := range []bool{false, true} {
+		for _, it := range spanning {
+			span := tracks[it.start[a] : it.start[a]+it.span[a]]
+			flexible, fr := false, 0.0
+			for _, k := range span {
+				if k.flexible() {
+					flexible, fr = true, fr+k.Max.Fr
+				}
+			}
+			if flexible != crossesFlexible {
+				continue
+			}
+			extra := minContribution(it) - float64(len(span)-1)*gap
+			var grow []*track
+			for i := range span {
+				k := &span[i]
+				extra -= k.base
+				if k.Min.Kind == SizeAuto && (!flexible || k.flexible()) {
+					grow = append(grow, k)
+				}
+			}
+			if extra <= 0 || len(grow) == 0 {
+				continue
+			}
+			if flexible {
+				for _, k := range grow {
+					if fr > 0 {
+						k.base += extra * k.Max.Fr / fr
+					} else {
+						k.base += extra / float64(len(grow))
+					}
+				}
+				continue
+			}
+			distribute(grow, extra)
+		}
+	}
+
+	// §11.5 step 5: if any track still has an infinite growth limit, set it
+	// to its base size.
+	for i := range tracks {
+		k := &tracks[i]
+		if math.IsInf(k.limit, +1) || k.flexible() || k.limit < k.base {
+			k.limit = k.base
+		}
+	}
+
+	free := func() float64 {
+		f := avail - float64(maxInt(n-1, 0))*gap
+		for _, k := range tracks {
+			f -= k.base
+		}
+		return f
+	}
+
+	// §11.6 maximize tracks.
+	if avail >= 0 {
+		if f := free(); f > 0 {
+			var grow []*track
+			for i := range tracks {
+				if k := &tracks[i]; k.base < k.limit {
+					grow = append(grow, k)
+				}
+			}
+			for f > 0 && len(grow) > 0 {
+				share, rest := f/float64(len(grow)), grow[:0]
+				for _, k := range grow {
+					d := math.Min(share, k.limit-k.base)
+					k.base += d
+					f -= d
+					if k.base < k.limit {
+						rest = append(rest, k)
+					}
+				}
+				grow = rest
+			}
+		}
+	}
+
+	// §11.7 expand flexible tracks.
+	frSize := 0.0
+	if avail >= 0 {
+		// §11.7.1 find the size of an fr.
+		inflexible := make([]bool, n)
+		for {
+			leftover, flexSum := avail-float64(maxInt(n-1, 0))*gap, 0.0
+			for i, k := range tracks {
+				if k.flexible() && !inflexible[i] {
+					flexSum += k.Max.Fr
+				} else {
+					leftover -= k.base
+				}
+			}
+			frSize = leftover / math.Max(flexSum, 1)
+			restart := false
+			for i, k := range tracks {
+				if k.flexible() && !inflexible[i] && k.base > frSize*k.Max.Fr {
+					inflexible[i], restart = true, true
+				}
+			}
+			if !restart {
+				break
+			}
+		}
+	} else {
+		for _, k := range tracks {
+			if k.flexible() {
+				frSize = math.Max(frSize, k.base/math.Max(k.Max.Fr, 1))
+			}
+		}
+	}
+	for i := range tracks {
+		if k := &tracks[i]; k.flexible() {
+			k.base = math.Max(k.base, frSize*k.Max.Fr)
+		}
+	}
+
+	// §11.8 stretch auto tracks.
+	if avail >= 0 {
+		if f := free(); f > 0 {
+			var grow []*track
+			for i := range tracks {
+				if k := &tracks[i]; k.Max.Kind == SizeAuto {
+					grow = append(grow, k)
+				}
+			}
+			for _, k := range grow {
+				k.base += f / float64(len(grow))
+			}
+		}
+	}
+
+	sizes := make([]float64, n)
+	for i, k := range tracks {
+		sizes[i] = k.base
+	}
+	return sizes
+}
+
+// distribute distributes extra space to the base sizes of the tracks, equally
+// until they reach their growth limits, and then equally beyond those limits
+// to the tracks with an auto maximum size, or to all the tracks if there are
+// none.
+//
+// https://www.w3.org/TR/css-grid-1/#extra-space
+func distribute(tracks []*track, extra float64) {
+	for extra > 0 {
+		var grow []*track
+		for _, k := range tracks {
+			if k.base < k.limit {
+				grow = append(grow, k)
+			}
+		}
+		if len(grow) == 0 {
+			break
+		}
+		share := extra / float64(len(grow))
+		for _, k := range grow {
+			d := math.Min(share, k.limit-k.base)
+			k.base += d
+			extra -= d
+		}
+	}
+	if extra <= 0 {
+		return
+	}
+	var grow []*track
+	for _, k := range tracks {
+		if k.Max.Kind == SizeAuto {
+			grow = append(grow, k)
+		}
+	}
+	if len(grow) == 0 {
+		grow = tracks
+	}
+	for _, k := range grow {
+		k.base += extra / float64(len(grow))
+		k.limit = math.Max(k.limit, k.base)
+	}
+}
+
+// align returns the start and end of an item along the axis a.
+func (w *Grid) align(it *item, a axis, offsets, sizes []float64) (start, end float64) {
+	first, last := it.start[a], it.start[a]+it.span[a]-1
+	start = offsets[first]
+	area := offsets[last] + sizes[last] - start
+
+	d, _ := it.n.LayoutData.(LayoutData)
+	align := w.JustifyItems
+	if d.Justify != AlignAuto {
+		align = d.Justify
+	}
+	if a == vertical {
+		align = w.AlignItems
+		if d.Align != AlignAuto {
+			align = d.Align
+		}
+	}
+
+	size := it.size(a)
+	switch align {
+	case AlignAuto, AlignStretch:
+		size = math.Max(size, area)
+	case AlignStart:
+		// already laid out correctly
+	case AlignEnd:
+		start += area - size
+	case AlignCenter:
+		start += (area - size) / 2
+	default:
+		panic(fmt.Sprintf("grid: unknown alignment %v", align))
+	}
+	return start, start + size
+}
+
+func offsets(sizes []float64, gap float64) []float64 {
+	offsets := make([]float64, len(sizes))
+	off := 0.0
+	for i, s := range sizes {
+		offsets[i] = off
+		off += s + gap
+	}
+	return offsets
+}
+
+func sum(x []float64) float64 {
+	s := 0.0
+	for _, v := range x {
+		s += v
+	}
+	return s
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func round(f float64) int {
+	return int(math.Floor(f + .5))
+}
+
+// LayoutData is the node LayoutData type for a Grid's children.
+type LayoutData struct {
+	// Row and Column are the 1-based lines at which the child's grid area
+	// starts, the CSS 'grid-row-start' and 'grid-column-start'. Zero means
+	// that the child is placed automatically.
+	Row, Column int
+
+	// RowSpan and ColumnSpan are the number of rows and columns that the
+	// child's grid area spans. Zero means one.
+	RowSpan, ColumnSpan int
+
+	// Justify and Align align the child inside its grid area, horizontally
+	// and vertically. AlignAuto means the Grid's JustifyItems or AlignItems.
+	Justify Align
+	Align   Align
+}
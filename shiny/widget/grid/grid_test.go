@@ -0,0 +1,449 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grid
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+)
+
+type layoutTest struct {
+	desc         string
+	columns      []Track
+	rows         []Track
+	columnGap    float64
+	rowGap       float64
+	justifyItems Align
+	alignItems   Align
+	size         image.Point       // size of container
+	measured     [][2]float64      // MeasuredSize of child elements
+	layoutData   []LayoutData      // LayoutData of child elements
+	want         []image.Rectangle // final Rect of child elements
+}
+
+func (s Size) css() string {
+	switch s.Kind {
+	case SizeFixed:
+		return fmt.Sprintf("%gpx", s.Length.F)
+	case SizeFr:
+		return fmt.Sprintf("%gfr", s.Fr)
+	}
+	return "auto"
+}
+
+func (k Track) css() string {
+	switch {
+	case k.Min == k.Max:
+		return k.Min.css()
+	case k.Min.Kind == SizeAuto && k.Max.Kind == SizeFr:
+		return k.Max.css()
+	}
+	return fmt.Sprintf("minmax(%s, %s)", k.Min.css(), k.Max.css())
+}
+
+func tracksCSS(tracks []Track) string {
+	var s []string
+	for _, k := range tracks {
+		s = append(s, k.css())
+	}
+	return strings.Join(s, " ")
+}
+
+var alignCSS = [...]string{
+	AlignAuto:    "auto",
+	AlignStart:   "start",
+	AlignEnd:     "end",
+	AlignCenter:  "center",
+	AlignStretch: "stretch",
+}
+
+func (t *layoutTest) html() string {
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, `<style>
+#container {
+	display: grid;
+	width:   %dpx;
+	height:  %dpx;
+`, t.size.X, t.size.Y)
+
+	if t.columns != nil {
+		fmt.Fprintf(buf, "\tgrid-template-columns: %s;\n", tracksCSS(t.columns))
+	}
+	if t.rows != nil {
+		fmt.Fprintf(buf, "\tgrid-template-rows: %s;\n", tracksCSS(t.rows))
+	}
+	fmt.Fprintf(buf, "\tcolumn-gap: %gpx;\n", t.columnGap)
+	fmt.Fprintf(buf, "\trow-gap: %gpx;\n", t.rowGap)
+	if t.justifyItems != AlignAuto {
+		fmt.Fprintf(buf, "\tjustify-items: %s;\n", alignCSS[t.justifyItems])
+	}
+	if t.alignItems != AlignAuto {
+		fmt.Fprintf(buf, "\talign-items: %s;\n", alignCSS[t.alignItems])
+	}
+	fmt.Fprintf(buf, "}\n")
+
+	for i, m := range t.measured {
+		// A min-width and min-height make the child's min-content and
+		// max-content sizes its MeasuredSize, while still letting it stretch.
+		fmt.Fprintf(buf, `#child%d {
+	min-width: %.2fpx;
+	min-height: %.2fpx;
+`, i, m[0], m[1])
+		c := colors[i%len(colors)]
+		fmt.Fprintf(buf, "\tbackground-color: rgb(%d, %d, %d);\n", c.R, c.G, c.B)
+		if t.layoutData != nil {
+			d := t.layoutData[i]
+			if d.Column != 0 {
+				fmt.Fprintf(buf, "\tgrid-column-start: %d;\n", d.Column)
+			}
+			if d.ColumnSpan != 0 {
+				fmt.Fprintf(buf, "\tgrid-column-end: span %d;\n", d.ColumnSpan)
+			}
+			if d.Row != 0 {
+				fmt.Fprintf(buf, "\tgrid-row-start: %d;\n", d.Row)
+			}
+			if d.RowSpan != 0 {
+				fmt.Fprintf(buf, "\tgrid-row-end: span %d;\n", d.RowSpan)
+			}
+			if d.Justify != AlignAuto {
+				fmt.Fprintf(buf, "\tjustify-self: %s;\n", alignCSS[d.Justify])
+			}
+			if d.Align != AlignAuto {
+				fmt.Fprintf(buf, "\talign-self: %s;\n", alignCSS[d.Align])
+			}
+		}
+		fmt.Fprintf(buf, "}\n")
+	}
+	fmt.Fprintf(buf, `</style>
+<div id="container">
+`)
+	for i := range t.measured {
+		fmt.Fprintf(buf, "\t<div id=\"child%d\"></div>\n", i)
+	}
+	fmt.Fprintf(buf, `</div>
+<pre id="out"></pre>
+<script>
+var out = document.getElementById("out");
+var container = document.getElementById("container");
+for (var i = 0; i < container.children.length; i++) {
+	var c = container.children[i];
+	var ctop = c.offsetTop - container.offsetTop;
+	var cleft = c.offsetLeft - container.offsetLeft;
+	var cbottom = ctop + c.offsetHeight;
+	var cright = cleft + c.offsetWidth;
+
+	out.innerHTML += "\timage.Rect(" + cleft + ", " + ctop + ", " + cright + ", " + cbottom + "),\n";
+}
+</script>
+`)
+
+	return buf.String()
+}
+
+var colors = []color.RGBA{
+	{0x00, 0x7f, 0x7f, 0xff}, // Cyan
+	{0x7f, 0x00, 0x7f, 0xff}, // Magenta
+	{0x7f, 0x7f, 0x00, 0xff}, // Yellow
+	{0xff, 0x00, 0x00, 0xff}, // Red
+	{0x00, 0xff, 0x00, 0xff}, // Green
+	{0x00, 0x00, 0xff, 0xff}, // Blue
+}
+
+func px(f float64) Track { return Fixed(unit.Pixels(f)) }
+
+var layoutTests = []layoutTest{{
+	desc: "no children",
+}, {
+	desc:     "fixed tracks",
+	size:     image.Point{150, 70},
+	columns:  []Track{px(50), px(100)},
+	rows:     []Track{px(30), px(40)},
+	measured: [][2]float64{{10, 10}, {10, 10}, {10, 10}, {10, 10}},
+	want: []image.Rectangle{
+		image.Rect(0, 0, 50, 30),
+		image.Rect(50, 0, 150, 30),
+		image.Rect(0, 30, 50, 70),
+		image.Rect(50, 30, 150, 70),
+	},
+}, {
+	desc:     "fr tracks",
+	size:     image.Point{300, 100},
+	columns:  []Track{Fr(1), Fr(2)},
+	rows:     []Track{Fr(1)},
+	measured: [][2]float64{{10, 10}, {10, 10}},
+	want: []image.Rectangle{
+		image.Rect(0, 0, 100, 100),
+		image.Rect(100, 0, 300, 100),
+	},
+}, {
+	desc:     "auto and fr tracks",
+	size:     image.Point{300, 100},
+	columns:  []Track{Auto(), Fr(1)},
+	measured: [][2]float64{{40, 20}, {60, 20}},
+	want: []image.Rectangle{
+		image.Rect(0, 0, 40, 100),
+		image.Rect(40, 0, 300, 100),
+	},
+}, {
+	desc:     "fr tracks no smaller than their content",
+	size:     image.Point{100, 10},
+	columns:  []Track{Fr(1), Fr(1)},
+	rows:     []Track{px(10)},
+	measured: [][2]float64{{80, 10}, {0, 0}},
+	want: []image.Rectangle{
+		image.Rect(0, 0, 80, 10),
+		image.Rect(80, 0, 100, 10),
+	},
+}, {
+	desc:     "minmax",
+	size:     image.Point{150, 10},
+	columns:  []Track{MinMax(Length(unit.Pixels(100)), Fraction(1)), Fr(1)},
+	rows:     []Track{px(10)},
+	measured: [][2]float64{{0, 0}, {0, 0}},
+	want: []image.Rectangle{
+		image.Rect(0, 0, 100, 10),
+		image.Rect(100, 0, 150, 10),
+	},
+}, {
+	desc:      "gaps",
+	size:      image.Point{320, 45},
+	columns:   []Track{Fr(1), Fr(1), Fr(1)},
+	rows:      []Track{px(20), px(20)},
+	columnGap: 10,
+	rowGap:    5,
+	measured:  [][2]float64{{0, 0}, {0, 0}, {0, 0}, {0, 0}},
+	want: []image.Rectangle{
+		image.Rect(0, 0, 100, 20),
+		image.Rect(110, 0, 210, 20),
+		image.Rect(220, 0, 320, 20),
+		image.Rect(0, 25, 100, 45),
+	},
+}, {
+	desc:     "implicit rows",
+	size:     image.Point{100, 100},
+	columns:  []Track{px(50), px(50)},
+	measured: [][2]float64{{10, 20}, {10, 20}, {10, 20}},
+	want: []image.Rectangle{
+		image.Rect(0, 0, 50, 50),
+		image.Rect(50, 0, 100, 50),
+		image.Rect(0, 50, 50, 100),
+	},
+}, {
+	desc:     "spans",
+	size:     image.Point{150, 60},
+	columns:  []Track{px(50), px(50), px(50)},
+	rows:     []Track{px(30), px(30)},
+	measured: [][2]float64{{0, 0}, {0, 0}, {0, 0}, {0, 0}},
+	layoutData: []LayoutData{
+		{ColumnSpan: 2},
+		{RowSpan: 2},
+		{},
+		{},
+	},
+	want: []image.Rectangle{
+		image.Rect(0, 0, 100, 30),
+		image.Rect(100, 0, 150, 60),
+		image.Rect(0, 30, 50, 60),
+		image.Rect(50, 30, 100, 60),
+	},
+}, {
+	desc:     "spanning item grows auto tracks",
+	size:     image.Point{150, 10},
+	columns:  []Track{Auto(), Auto(), px(10)},
+	rows:     []Track{px(10), px(10)},
+	measured: [][2]float64{{100, 0}, {20, 0}, {0, 0}},
+	layoutData: []LayoutData{
+		{ColumnSpan: 2},
+		{Row: 2},
+		{Row: 2, Column: 3},
+	},
+	want: []image.Rectangle{
+		image.Rect(0, 0, 140, 10),
+		image.Rect(0, 10, 40, 20),
+		image.Rect(140, 10, 150, 20),
+	},
+}, {
+	desc:     "explicit placement",
+	size:     image.Point{120, 120},
+	columns:  []Track{px(40), px(40), px(40)},
+	rows:     []Track{px(40), px(40), px(40)},
+	measured: [][2]float64{{0, 0}, {0, 0}, {0, 0}, {0, 0}},
+	layoutData: []LayoutData{
+		{Row: 2, Column: 3},
+		{},
+		{Row: 1},
+		{Column: 1},
+	},
+	want: []image.Rectangle{
+		image.Rect(80, 40, 120, 80),
+		image.Rect(40, 0, 80, 40),
+		image.Rect(0, 0, 40, 40),
+		image.Rect(0, 40, 40, 80),
+	},
+}, {
+	desc:         "alignment",
+	size:         image.Point{100, 200},
+	columns:      []Track{px(100)},
+	rows:         []Track{px(100), px(100)},
+	justifyItems: AlignCenter,
+	alignItems:   AlignCenter,
+	measured:     [][2]float64{{20, 10}, {20, 10}},
+	layoutData: []LayoutData{
+		{},
+		{Justify: AlignEnd, Align: AlignStart},
+	},
+	want: []image.Rectangle{
+		image.Rect(40, 45, 60, 55),
+		image.Rect(80, 100, 100, 110),
+	},
+}}
+
+func TestLayout(t *testing.T) {
+	for testNum, test := range layoutTests {
+		var children []node.Node
+		for i, sz := range test.measured {
+			u := widget.NewUniform(theme.StaticColor(colors[i%len(colors)]), nil)
+			n := widget.NewSizer(unit.Pixels(sz[0]), unit.Pixels(sz[1]), u)
+			if test.layoutData != nil {
+				n.LayoutData = test.layoutData[i]
+			}
+			children = append(children, n)
+		}
+
+		w := NewGrid(test.columns, children...)
+		w.Rows = test.rows
+		w.ColumnGap = unit.Pixels(test.columnGap)
+		w.RowGap = unit.Pixels(test.rowGap)
+		w.JustifyItems = test.justifyItems
+		w.AlignItems = test.alignItems
+
+		w.Measure(nil, node.NoHint, node.NoHint)
+		w.Rect = image.Rectangle{Max: test.size}
+		w.Layout(nil)
+
+		bad := false
+		for i, n := range children {
+			if n.Wrappee().Rect != test.want[i] {
+				bad = true
+				break
+			}
+		}
+		if bad {
+			t.Logf("Bad test %d, %q:\n%s", testNum, test.desc, test.html())
+		}
+		for i, n := range children {
+			if got, want := n.Wrappee().Rect, test.want[i]; got != want {
+				t.Errorf("[%d].Rect=%v, want %v", i, got, want)
+			}
+		}
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	children := []node.Node{
+		widget.NewSizer(unit.Pixels(40), unit.Pixels(10), nil),
+		widget.NewSizer(unit.Pixels(60), unit.Pixels(30), nil),
+		widget.NewSizer(unit.Pixels(20), unit.Pixels(20), nil),
+	}
+	w := NewGrid([]Track{Auto(), Fr(1)}, children...)
+	w.ColumnGap = unit.Pixels(5)
+	w.RowGap = unit.Pixels(5)
+	w.Measure(nil, node.NoHint, node.NoHint)
+
+	// The columns are 40 and 60 pixels wide, and the rows are 30 and 20
+	// pixels high.
+	if got, want := w.MeasuredSize, (image.Point{105, 55}); got != want {
+		t.Errorf("MeasuredSize=%v, want %v", got, want)
+	}
+}
+
+func TestBox(t *testing.T) {
+	a := widget.NewSizer(unit.Pixels(40), unit.Pixels(10), nil)
+	a.Box = &node.Box{Margin: node.Insets{
+		Top:    unit.Pixels(1),
+		Right:  unit.Pixels(2),
+		Bottom: unit.Pixels(3),
+		Left:   unit.Pixels(4),
+	}}
+	b := widget.NewSizer(unit.Pixels(20), unit.Pixels(20), nil)
+	w := NewGrid([]Track{Auto(), Auto()}, a, b)
+	w.AlignItems = AlignStart
+	w.Measure(nil, node.NoHint, node.NoHint)
+
+	// The first column is a's outer width, 4+40+2, and the row is the taller
+	// of a's outer height, 1+10+3, and b's height.
+	if got, want := w.MeasuredSize, (image.Point{66, 20}); got != want {
+		t.Errorf("MeasuredSize=%v, want %v", got, want)
+	}
+	w.Rect = image.Rectangle{Max: w.MeasuredSize}
+	w.Layout(nil)
+	if got, want := a.Rect, image.Rect(4, 1, 44, 11); got != want {
+		t.Errorf("a.Rect=%v, want %v", got, want)
+	}
+	if got, want := b.Rect, image.Rect(46, 0, 66, 20); got != want {
+		t.Errorf("b.Rect=%v, want %v", got, want)
+	}
+}
+
+// wrapper is a leaf node that, like a wrapped Text, gets taller as it gets
+// narrower, down to its minimum content width.
+type wrapper struct {
+	node.LeafEmbed
+	min, max, area int
+}
+
+func newWrapper(min, max, area int) *wrapper {
+	w := &wrapper{min: min, max: max, area: area}
+	w.Wrapper = w
+	return w
+}
+
+func (w *wrapper) Measure(t *theme.Theme, widthHint, heightHint int) {
+	x := w.max
+	if widthHint >= 0 && widthHint < x {
+		x = widthHint
+	}
+	if x < w.min {
+		x = w.min
+	}
+	w.MeasuredSize = image.Point{x, (w.area + x - 1) / x}
+}
+
+func (w *wrapper) IntrinsicWidths(t *theme.Theme) (min, max int) { return w.min, w.max }
+
+func TestIntrinsicWidths(t *testing.T) {
+	testCases := []struct {
+		width int
+		want  image.Rectangle
+	}{
+		{200, image.Rect(0, 0, 100, 10)},
+		// The auto column shrinks, and the wrapper gets taller, until the
+		// column is the wrapper's minimum content width.
+		{100, image.Rect(0, 0, 60, 17)},
+		{50, image.Rect(0, 0, 20, 50)},
+	}
+	for _, tc := range testCases {
+		a := newWrapper(20, 100, 1000)
+		b := widget.NewSizer(unit.Pixels(40), unit.Pixels(10), nil)
+		w := NewGrid([]Track{Auto(), px(40)}, a, b)
+		w.AlignItems = AlignStart
+		w.JustifyItems = AlignStart
+		w.Measure(nil, node.NoHint, node.NoHint)
+		w.Rect = image.Rectangle{Max: image.Point{tc.width, 100}}
+		w.Layout(nil)
+		if got := a.Rect; got != tc.want {
+			t.Errorf("width %d: Rect=%v, want %v", tc.width, got, tc.want)
+		}
+	}
+}
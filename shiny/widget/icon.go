@@ -0,0 +1,184 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"golang.org/x/exp/shiny/iconvg"
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+)
+
+// TODO: an accessible name, for icons that are not decorative.
+
+// maxIconCache is the maximum number of rasterizations that an Icon caches.
+const maxIconCache = 4
+
+// Icon is a leaf widget that paints an IconVG graphic, such as one of the
+// golang.org/x/exp/shiny/materialdesign/icons, at any size.
+//
+// The graphic is rasterized, at the Icon's laid out size, by PaintBase, and
+// the results for the most recent few sizes and palettes are cached. The
+// graphic keeps its aspect ratio, and is centered in the Icon's Rect.
+type Icon struct {
+	node.LeafEmbed
+
+	// Size is the Icon's natural height. Its natural width follows from the
+	// graphic's aspect ratio. A zero Size means unit.Ems(1), so that the Icon
+	// matches the height of text.
+	Size unit.Value
+
+	// Colors override the graphic's custom palette: if the i'th element is
+	// non-nil, it replaces the i'th color of the graphic's suggested palette.
+	// If Colors is empty, the first color is replaced by theme.Foreground, so
+	// that single color icons, such as the Material Design icons, match the
+	// text around them.
+	Colors []theme.Color
+
+	src      []byte
+	metadata iconvg.Metadata
+
+	// cache holds the most recently used rasterizations first.
+	cache []iconRaster
+	z     iconvg.Rasterizer
+}
+
+// iconRaster is a rasterization of an Icon's graphic.
+type iconRaster struct {
+	size    image.Point
+	palette iconvg.Palette
+	rgba    *image.RGBA
+}
+
+// NewIcon returns a new Icon widget for the given IconVG graphic. It returns
+// an error if src is not valid IconVG data.
+func NewIcon(src []byte, size unit.Value) (*Icon, error) {
+	w := &Icon{
+		Size: size,
+	}
+	w.Wrapper = w
+	if err := w.SetSrc(src); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Src returns the IconVG graphic, or nil if it has none.
+func (w *Icon) Src() []byte { return w.src }
+
+// SetSrc changes the IconVG graphic. If src is not valid IconVG data, SetSrc
+// returns the decoding error, and the Icon paints nothing until it is given
+// a valid graphic.
+func (w *Icon) SetSrc(src []byte) error {
+	m, err := iconvg.DecodeMetadata(src)
+	if err == nil {
+		// Decoding to a nil Destination checks the whole graphic, not just
+		// its metadata, so that PaintBase does not fail later.
+		err = iconvg.Decode(nil, src, nil)
+	}
+	if err != nil {
+		src, m = nil, iconvg.Metadata{}
+	}
+	w.src = src
+	w.metadata = m
+	w.cache = nil
+	w.Mark(node.MarkNeedsMeasureLayout | node.MarkNeedsPaintBase)
+	return err
+}
+
+func (w *Icon) Measure(t *theme.Theme, widthHint, heightHint int) {
+	size := w.Size
+	if size == (unit.Value{}) {
+		size = unit.Ems(1)
+	}
+	h := t.Pixels(size).Ceil()
+	w.MeasuredSize = image.Point{h, h}
+	if dx, dy := w.metadata.ViewBox.AspectRatio(); dx > 0 && dy > 0 {
+		w.MeasuredSize.X = int(float32(h)*dx/dy + 0.5)
+	}
+}
+
+// fit returns the largest rectangle, with the graphic's aspect ratio, that is
+// centered in r.
+func (w *Icon) fit(r image.Rectangle) image.Rectangle {
+	dx, dy := w.metadata.ViewBox.AspectRatio()
+	if dx <= 0 || dy <= 0 {
+		return r
+	}
+	size := r.Size()
+	if float32(size.X)*dy > float32(size.Y)*dx {
+		x := int(float32(size.Y)*dx/dy + 0.5)
+		r.Min.X += (size.X - x) / 2
+		r.Max.X = r.Min.X + x
+	} else {
+		y := int(float32(size.X)*dy/dx + 0.5)
+		r.Min.Y += (size.Y - y) / 2
+		r.Max.Y = r.Min.Y + y
+	}
+	return r
+}
+
+// palette returns the custom palette for the theme.
+func (w *Icon) palette(t *theme.Theme) iconvg.Palette {
+	p := w.metadata.Palette
+	if len(w.Colors) == 0 {
+		p[0] = color.RGBAModel.Convert(theme.Foreground.Color(t)).(color.RGBA)
+		return p
+	}
+	for i, c := range w.Colors {
+		if c != nil && i < len(p) {
+			p[i] = color.RGBAModel.Convert(c.Color(t)).(color.RGBA)
+		}
+	}
+	return p
+}
+
+// raster returns the rasterization for the given size and palette, from the
+// cache if possible.
+func (w *Icon) raster(size image.Point, palette *iconvg.Palette) (*image.RGBA, error) {
+	for i, c := range w.cache {
+		if c.size == size && c.palette == *palette {
+			copy(w.cache[1:i+1], w.cache[:i])
+			w.cache[0] = c
+			return c.rgba, nil
+		}
+	}
+
+	rgba := image.NewRGBA(image.Rectangle{Max: size})
+	w.z.SetDstImage(rgba, rgba.Bounds(), draw.Over)
+	if err := iconvg.Decode(&w.z, w.src, &iconvg.DecodeOptions{Palette: palette}); err != nil {
+		return nil, err
+	}
+	w.z.SetDstImage(nil, image.Rectangle{}, draw.Over)
+
+	if len(w.cache) < maxIconCache {
+		w.cache = append(w.cache, iconRaster{})
+	}
+	copy(w.cache[1:], w.cache)
+	w.cache[0] = iconRaster{size, *palette, rgba}
+	return rgba, nil
+}
+
+func (w *Icon) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
+	w.Marks.UnmarkNeedsPaintBase()
+	if w.src == nil {
+		return nil
+	}
+	r := w.fit(w.Rect.Add(origin))
+	if r.Empty() {
+		return nil
+	}
+	palette := w.palette(ctx.Theme)
+	rgba, err := w.raster(r.Size(), &palette)
+	if err != nil {
+		return err
+	}
+	draw.Draw(ctx.Dst, r, rgba, image.Point{}, draw.Over)
+	return nil
+}
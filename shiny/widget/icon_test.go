@@ -0,0 +1,230 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/exp/shiny/iconvg"
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+	"golang.org/x/image/math/f32"
+)
+
+var (
+	iconRed   = color.RGBA{0xff, 0x00, 0x00, 0xff}
+	iconGreen = color.RGBA{0x00, 0xff, 0x00, 0xff}
+	iconBlue  = color.RGBA{0x00, 0x00, 0xff, 0xff}
+)
+
+// testIconSrc returns an IconVG graphic, twice as wide as it is high, that is
+// filled with the first color of its red and green suggested palette.
+func testIconSrc(t *testing.T) []byte {
+	m := iconvg.Metadata{
+		ViewBox: iconvg.Rectangle{Min: f32.Vec2{-32, -16}, Max: f32.Vec2{+32, +16}},
+		Palette: iconvg.DefaultPalette,
+	}
+	m.Palette[0] = iconRed
+	m.Palette[1] = iconGreen
+
+	var e iconvg.Encoder
+	e.Reset(m)
+	e.SetCReg(0, false, iconvg.PaletteIndexColor(0))
+	e.StartPath(0, -32, -16)
+	e.AbsHLineTo(+32)
+	e.AbsVLineTo(+16)
+	e.AbsHLineTo(-32)
+	e.ClosePathEndPath()
+	src, err := e.Bytes()
+	if err != nil {
+		t.Fatalf("encoding: %v", err)
+	}
+	return src
+}
+
+func TestIconMeasure(t *testing.T) {
+	w, err := NewIcon(testIconSrc(t), unit.Value{})
+	if err != nil {
+		t.Fatalf("NewIcon: %v", err)
+	}
+	var th *theme.Theme
+	h := th.Pixels(unit.Ems(1)).Ceil()
+
+	w.Measure(nil, node.NoHint, node.NoHint)
+	if got, want := w.MeasuredSize, (image.Point{2 * h, h}); got != want {
+		t.Errorf("default Size: got %v, want %v", got, want)
+	}
+	w.Size = unit.Pixels(15)
+	w.Measure(nil, node.NoHint, node.NoHint)
+	if got, want := w.MeasuredSize, (image.Point{30, 15}); got != want {
+		t.Errorf("Size 15px: got %v, want %v", got, want)
+	}
+}
+
+func TestIconInvalid(t *testing.T) {
+	bad := []byte("not an IconVG graphic")
+	if w, err := NewIcon(bad, unit.Value{}); err == nil || w != nil {
+		t.Errorf("NewIcon: got %v, %v, want an error", w, err)
+	}
+	// A graphic whose metadata is valid, but whose drawing is truncated in
+	// the middle of a coordinate.
+	src := testIconSrc(t)
+	if _, err := NewIcon(src[:len(src)-2], unit.Value{}); err == nil {
+		t.Error("NewIcon, truncated: got no error")
+	}
+
+	w, _ := NewIcon(src, unit.Pixels(10))
+	if err := w.SetSrc(bad); err == nil {
+		t.Fatal("SetSrc: got no error")
+	}
+	if w.Src() != nil {
+		t.Errorf("SetSrc: Src is %q, want nil", w.Src())
+	}
+	// An Icon with no graphic is square, and paints nothing.
+	w.Measure(nil, node.NoHint, node.NoHint)
+	if got, want := w.MeasuredSize, (image.Point{10, 10}); got != want {
+		t.Errorf("Measure: got %v, want %v", got, want)
+	}
+	w.Rect = image.Rect(0, 0, 10, 10)
+	dst := image.NewRGBA(w.Rect)
+	for i := 0; i < 2; i++ {
+		if err := w.PaintBase(&node.PaintBaseContext{Dst: dst}, image.Point{}); err != nil {
+			t.Fatalf("PaintBase #%d: %v", i, err)
+		}
+	}
+	if got := dst.RGBAAt(5, 5); got != (color.RGBA{}) {
+		t.Errorf("PaintBase: painted %v", got)
+	}
+
+	if err := w.SetSrc(src); err != nil || w.Src() == nil {
+		t.Errorf("SetSrc, valid: %v", err)
+	}
+}
+
+func TestIconFit(t *testing.T) {
+	w, _ := NewIcon(testIconSrc(t), unit.Value{})
+	testCases := []struct {
+		r, want image.Rectangle
+	}{
+		{image.Rect(0, 0, 20, 10), image.Rect(0, 0, 20, 10)},
+		// Too wide, so centered horizontally.
+		{image.Rect(0, 0, 40, 10), image.Rect(10, 0, 30, 10)},
+		// Too high, so centered vertically.
+		{image.Rect(0, 0, 20, 20), image.Rect(0, 5, 20, 15)},
+		{image.Rect(5, 5, 25, 25), image.Rect(5, 10, 25, 20)},
+	}
+	for _, tc := range testCases {
+		if got := w.fit(tc.r); got != tc.want {
+			t.Errorf("fit(%v): got %v, want %v", tc.r, got, tc.want)
+		}
+	}
+}
+
+func TestIconPalette(t *testing.T) {
+	w, _ := NewIcon(testIconSrc(t), unit.Value{})
+	var th *theme.Theme
+	fg := color.RGBAModel.Convert(theme.Foreground.Color(th)).(color.RGBA)
+
+	// With no Colors, the first color is the theme's foreground.
+	p := w.palette(th)
+	if p[0] != fg || p[1] != iconGreen {
+		t.Errorf("no Colors: got %v, %v, want %v, %v", p[0], p[1], fg, iconGreen)
+	}
+
+	// Nil Colors keep the suggested palette's colors.
+	w.Colors = []theme.Color{nil, theme.StaticColor(iconBlue)}
+	p = w.palette(th)
+	if p[0] != iconRed || p[1] != iconBlue {
+		t.Errorf("Colors: got %v, %v, want %v, %v", p[0], p[1], iconRed, iconBlue)
+	}
+
+	// PaintBase fills the fitted rectangle with the first color.
+	w.Colors = nil
+	w.Rect = image.Rect(0, 0, 40, 10)
+	dst := image.NewRGBA(w.Rect)
+	if err := w.PaintBase(&node.PaintBaseContext{Dst: dst}, image.Point{}); err != nil {
+		t.Fatalf("PaintBase: %v", err)
+	}
+	if got := dst.RGBAAt(20, 5); got != fg {
+		t.Errorf("PaintBase: inside: got %v, want %v", got, fg)
+	}
+	if got := dst.RGBAAt(5, 5); got != (color.RGBA{}) {
+		t.Errorf("PaintBase: outside: got %v, want none", got)
+	}
+}
+
+func TestIconCache(t *testing.T) {
+	w, _ := NewIcon(testIconSrc(t), unit.Value{})
+	p := w.palette(nil)
+	raster := func(size int, p iconvg.Palette) *image.RGBA {
+		t.Helper()
+		rgba, err := w.raster(image.Point{size, size}, &p)
+		if err != nil {
+			t.Fatalf("raster(%d): %v", size, err)
+		}
+		return rgba
+	}
+	cached := func() (sizes []int) {
+		for _, c := range w.cache {
+			sizes = append(sizes, c.size.X)
+		}
+		return sizes
+	}
+
+	// An unchanged size and palette isn't rasterized again.
+	r1 := raster(1, p)
+	if raster(1, p) != r1 {
+		t.Error("same size and palette: rasterized again")
+	}
+	q := p
+	q[0] = iconBlue
+	if raster(1, q) == r1 {
+		t.Error("different palette: not rasterized again")
+	}
+
+	w.cache = nil
+	for size := 1; size <= maxIconCache; size++ {
+		raster(size, p)
+	}
+	// Using the least recently used size moves it to the front, so that the
+	// next least recently used one is evicted.
+	r1 = raster(1, p)
+	raster(maxIconCache+1, p)
+	got := cached()
+	want := []int{maxIconCache + 1, 1}
+	for size := maxIconCache; len(want) < maxIconCache; size-- {
+		want = append(want, size)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("cache: got sizes %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("cache: got sizes %v, want %v", got, want)
+		}
+	}
+	if raster(1, p) != r1 {
+		t.Error("size 1: evicted")
+	}
+
+	// PaintBase reuses the cache, and SetSrc clears it.
+	w.cache = nil
+	w.Rect = image.Rect(0, 0, 20, 10)
+	dst := image.NewRGBA(w.Rect)
+	ctx := &node.PaintBaseContext{Dst: dst}
+	w.PaintBase(ctx, image.Point{})
+	r := w.cache[0].rgba
+	w.PaintBase(ctx, image.Point{})
+	if len(w.cache) != 1 || w.cache[0].rgba != r {
+		t.Errorf("PaintBase: got %d cached rasterizations, want 1, the same", len(w.cache))
+	}
+	w.SetSrc(w.Src())
+	if len(w.cache) != 0 {
+		t.Errorf("SetSrc: got %d cached rasterizations, want none", len(w.cache))
+	}
+}
@@ -60,3 +60,9 @@ func (w *Image) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error
 	draw.Draw(ctx.Dst, wRect.Intersect(sRect), w.Src, w.SrcRect.Min, draw.Over)
 	return nil
 }
+
+func (w *Image) Accessibility() node.Accessibility {
+	return node.Accessibility{
+		Role: node.RoleImage,
+	}
+}
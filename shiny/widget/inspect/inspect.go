@@ -0,0 +1,120 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package inspect describes a widget tree's nodes, for debugging layouts.
+//
+// WriteText and WriteJSON dump a whole tree, giving each node's type, Rect,
+// MeasuredSize, Marks and LayoutData. The widget package's Inspector shows the
+// same information, for the node under the mouse pointer, in a window.
+package inspect // import "golang.org/x/exp/shiny/widget/inspect"
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+	"strings"
+
+	"golang.org/x/exp/shiny/widget/node"
+)
+
+// TODO: also describe each node's Baseline, which needs a theme.
+
+var markNames = [...]struct {
+	m    node.Marks
+	name string
+}{
+	{node.MarkNeedsMeasureLayout, "measure-layout"},
+	{node.MarkNeedsPaint, "paint"},
+	{node.MarkNeedsPaintBase, "paint-base"},
+}
+
+// MarkNames returns the names of the marks in m, such as "paint".
+func MarkNames(m node.Marks) []string {
+	var names []string
+	for _, x := range markNames {
+		if m&x.m != 0 {
+			names = append(names, x.name)
+		}
+	}
+	return names
+}
+
+// Describe returns a one line description of n, such as:
+//
+//	*widget.Label rect=(0,0)-(40,12) measured=(40,12) marks=paint
+//
+// The marks and layout data are omitted if they are zero.
+func Describe(n node.Node) string {
+	e := n.Wrappee()
+	s := fmt.Sprintf("%T rect=%v measured=%v", n, e.Rect, e.MeasuredSize)
+	if e.Marks != 0 {
+		s += " marks=" + strings.Join(MarkNames(e.Marks), "|")
+	}
+	if e.LayoutData != nil {
+		s += fmt.Sprintf(" layoutData=%+v", e.LayoutData)
+	}
+	return s
+}
+
+// WriteText writes the widget tree rooted at root to w, as one line per node,
+// given by Describe, indented by one tab per level of depth.
+func WriteText(w io.Writer, root node.Node) error {
+	return writeText(w, root.Wrappee(), 0)
+}
+
+func writeText(w io.Writer, e *node.Embed, depth int) error {
+	if _, err := fmt.Fprintf(w, "%s%s\n", strings.Repeat("\t", depth), Describe(e.Wrapper)); err != nil {
+		return err
+	}
+	for c := e.FirstChild; c != nil; c = c.NextSibling {
+		if err := writeText(w, c, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type jsonNode struct {
+	Type         string      `json:"type"`
+	Rect         [4]int      `json:"rect"`
+	MeasuredSize [2]int      `json:"measuredSize"`
+	Marks        []string    `json:"marks,omitempty"`
+	LayoutData   string      `json:"layoutData,omitempty"`
+	Children     []*jsonNode `json:"children,omitempty"`
+}
+
+func toJSON(e *node.Embed) *jsonNode {
+	j := &jsonNode{
+		Type:         fmt.Sprintf("%T", e.Wrapper),
+		Rect:         rectArray(e.Rect),
+		MeasuredSize: [2]int{e.MeasuredSize.X, e.MeasuredSize.Y},
+		Marks:        MarkNames(e.Marks),
+	}
+	if e.LayoutData != nil {
+		// LayoutData can hold anything, including values that encoding/json
+		// cannot marshal, so it is given as formatted by the fmt package.
+		j.LayoutData = fmt.Sprintf("%+v", e.LayoutData)
+	}
+	for c := e.FirstChild; c != nil; c = c.NextSibling {
+		j.Children = append(j.Children, toJSON(c))
+	}
+	return j
+}
+
+func rectArray(r image.Rectangle) [4]int {
+	return [4]int{r.Min.X, r.Min.Y, r.Max.X, r.Max.Y}
+}
+
+// WriteJSON writes the widget tree rooted at root to w, as indented JSON.
+// Rects are given as [minX, minY, maxX, maxY], relative to the parent node,
+// and MeasuredSizes as [width, height].
+func WriteJSON(w io.Writer, root node.Node) error {
+	b, err := json.MarshalIndent(toJSON(root.Wrappee()), "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
@@ -0,0 +1,83 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package inspect
+
+import (
+	"bytes"
+	"image"
+	"testing"
+
+	"golang.org/x/exp/shiny/widget/node"
+)
+
+type testLeaf struct {
+	node.LeafEmbed
+}
+
+type testContainer struct {
+	node.ContainerEmbed
+}
+
+type testLayoutData struct {
+	Grow int
+}
+
+func newTestTree() node.Node {
+	a := &testLeaf{}
+	a.Wrapper = a
+	a.Rect = image.Rect(0, 0, 10, 5)
+	a.MeasuredSize = image.Point{10, 5}
+	a.LayoutData = testLayoutData{Grow: 1}
+	b := &testLeaf{}
+	b.Wrapper = b
+	b.Rect = image.Rect(0, 5, 20, 15)
+	b.MeasuredSize = image.Point{20, 10}
+	root := &testContainer{}
+	root.Wrapper = root
+	root.Rect = image.Rect(0, 0, 20, 15)
+	root.Insert(a, nil)
+	root.Insert(b, nil)
+	b.Mark(node.MarkNeedsPaint | node.MarkNeedsPaintBase)
+	return root
+}
+
+func TestWriteText(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := WriteText(buf, newTestTree()); err != nil {
+		t.Fatal(err)
+	}
+	const want = `*inspect.testContainer rect=(0,0)-(20,15) measured=(0,0) marks=paint|paint-base
+	*inspect.testLeaf rect=(0,0)-(10,5) measured=(10,5) layoutData={Grow:1}
+	*inspect.testLeaf rect=(0,5)-(20,15) measured=(20,10) marks=paint|paint-base
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := WriteJSON(buf, newTestTree().Wrappee().FirstChild.Wrapper); err != nil {
+		t.Fatal(err)
+	}
+	const want = `{
+	"type": "*inspect.testLeaf",
+	"rect": [
+		0,
+		0,
+		10,
+		5
+	],
+	"measuredSize": [
+		10,
+		5
+	],
+	"layoutData": "{Grow:1}"
+}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,339 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"time"
+
+	"golang.org/x/exp/shiny/screen"
+	"golang.org/x/exp/shiny/widget/inspect"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/lifecycle"
+	"golang.org/x/mobile/event/mouse"
+)
+
+// TODO: a key to dump the widget tree, via the inspect package, to standard
+// error.
+
+// TODO: show the hovered node's ancestors, not just the node itself.
+
+// The inspector overlay's colors, which do not depend on the theme, so that
+// they stand out from the widgets whatever the palette. They are
+// alpha-premultiplied.
+var (
+	inspectOutlineColor = color.RGBA{0x80, 0x00, 0x80, 0x80}
+	inspectHoverColor   = color.RGBA{0x00, 0x20, 0x40, 0x40}
+	inspectHoverOutline = color.RGBA{0x00, 0x40, 0xff, 0xff}
+	inspectFlashColor   = color.RGBA{0x60, 0x00, 0x00, 0x60}
+	inspectInfoBG       = color.RGBA{0x20, 0x20, 0x20, 0xf0}
+	inspectInfoFG       = color.RGBA{0xff, 0xff, 0xff, 0xff}
+)
+
+// inspectFlashDuration is how long a repainted node stays highlighted.
+const inspectFlashDuration = 300 * time.Millisecond
+
+// Inspector is a shell widget that can show a debugging overlay above its
+// child, for finding out why a layout went wrong. The overlay outlines every
+// node's Rect, highlights the nodes that are repainted, and describes the node
+// under the mouse pointer: its type, Rect, MeasuredSize, Marks and LayoutData.
+//
+// The overlay is hidden until the Key is pressed, and pressing it again hides
+// the overlay, unless the node with the keyboard focus handles that key. It
+// is drawn with the screen.Drawer of each Paint call, so it works with any
+// screen.Screen driver.
+//
+// If the root of a RunWindow widget tree is an Inspector, the window's Overlay
+// is inserted below it, so that the Inspector also shows the Popups.
+//
+// The inspect package can dump the same information, for a whole widget tree,
+// as text or JSON.
+type Inspector struct {
+	node.ShellEmbed
+
+	// Key is the key that shows and hides the overlay. Its zero value means
+	// key.CodeF12.
+	Key key.Code
+
+	enabled bool
+
+	// mouse is the position of the mouse pointer, relative to the Inspector,
+	// from the most recent mouse event. hasMouse is whether there was one.
+	mouse    image.Point
+	hasMouse bool
+
+	// flashes are the highlighted nodes' Rects, relative to the Inspector,
+	// and when they stop being highlighted.
+	flashes []inspectFlash
+
+	// info is the rendered description of the hovered node, whose text is
+	// infoText.
+	infoText string
+	infoBuf  screen.Buffer
+	infoTex  screen.Texture
+}
+
+type inspectFlash struct {
+	r     image.Rectangle
+	until time.Time
+}
+
+// NewInspector returns a new Inspector widget for the given inner widget
+// tree, with the overlay hidden.
+func NewInspector(inner node.Node) *Inspector {
+	w := &Inspector{}
+	w.Wrapper = w
+	if inner != nil {
+		w.Insert(inner, nil)
+	}
+	return w
+}
+
+// Enabled returns whether the overlay is shown.
+func (w *Inspector) Enabled() bool { return w.enabled }
+
+// SetEnabled shows or hides the overlay.
+func (w *Inspector) SetEnabled(enabled bool) {
+	if w.enabled == enabled {
+		return
+	}
+	w.enabled = enabled
+	w.flashes = nil
+	if !enabled {
+		w.release()
+	}
+	w.Mark(node.MarkNeedsPaint)
+}
+
+func (w *Inspector) key() key.Code {
+	if w.Key == key.CodeUnknown {
+		return key.CodeF12
+	}
+	return w.Key
+}
+
+func (w *Inspector) release() {
+	w.infoText = ""
+	if w.infoBuf != nil {
+		w.infoBuf.Release()
+		w.infoBuf = nil
+	}
+	if w.infoTex != nil {
+		w.infoTex.Release()
+		w.infoTex = nil
+	}
+}
+
+func (w *Inspector) OnLifecycleEvent(e lifecycle.Event) {
+	if e.Crosses(lifecycle.StageVisible) == lifecycle.CrossOff {
+		w.release()
+	}
+	w.ShellEmbed.OnLifecycleEvent(e)
+}
+
+func (w *Inspector) OnInputEvent(e interface{}, origin image.Point) node.EventHandled {
+	switch e := e.(type) {
+	case key.Event:
+		// Key events bubble up from the focused node, so this only sees those
+		// that no other node handled.
+		if e.Code == w.key() {
+			if e.Direction == key.DirPress {
+				w.SetEnabled(!w.enabled)
+			}
+			return node.Handled
+		}
+	case mouse.Event:
+		p := image.Point{int(e.X), int(e.Y)}.Sub(origin.Add(w.Rect.Min))
+		if w.enabled && (!w.hasMouse || p != w.mouse) {
+			w.Mark(node.MarkNeedsPaint)
+		}
+		w.mouse, w.hasMouse = p, true
+	}
+	return w.ShellEmbed.OnInputEvent(e, origin)
+}
+
+func (w *Inspector) Paint(ctx *node.PaintContext, origin image.Point) error {
+	w.Marks.UnmarkNeedsPaint()
+	c := w.FirstChild
+	if c == nil {
+		return nil
+	}
+	origin = origin.Add(w.Rect.Min)
+	if !w.enabled {
+		return c.Wrapper.Paint(ctx, origin)
+	}
+
+	// Find the repainted nodes before painting, as painting clears their
+	// marks.
+	now := time.Now()
+	until := now.Add(inspectFlashDuration)
+	n := len(w.flashes)
+	w.addFlashes(c, image.Point{}, until)
+	if len(w.flashes) > n && ctx.Waker != nil {
+		ctx.Waker.WakeAt(until, w, node.MarkNeedsPaint)
+	}
+
+	if err := c.Wrapper.Paint(ctx, origin); err != nil {
+		return err
+	}
+
+	src2dst := ctx.Src2Dst
+	translate(&src2dst, float64(origin.X), float64(origin.Y))
+	fill := func(r image.Rectangle, c color.Color) {
+		ctx.Drawer.DrawUniform(src2dst, c, r, draw.Over, nil)
+	}
+	outline := func(r image.Rectangle, c color.Color) {
+		fill(image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+1), c)
+		fill(image.Rect(r.Min.X, r.Max.Y-1, r.Max.X, r.Max.Y), c)
+		fill(image.Rect(r.Min.X, r.Min.Y+1, r.Min.X+1, r.Max.Y-1), c)
+		fill(image.Rect(r.Max.X-1, r.Min.Y+1, r.Max.X, r.Max.Y-1), c)
+	}
+
+	var outlineAll func(e *node.Embed, o image.Point)
+	outlineAll = func(e *node.Embed, o image.Point) {
+		r := e.Rect.Add(o)
+		outline(r, inspectOutlineColor)
+		for c := e.FirstChild; c != nil; c = c.NextSibling {
+			outlineAll(c, r.Min)
+		}
+	}
+	outlineAll(c, image.Point{})
+
+	flashes := w.flashes[:0]
+	for _, f := range w.flashes {
+		if now.Before(f.until) {
+			fill(f.r, inspectFlashColor)
+			flashes = append(flashes, f)
+		}
+	}
+	w.flashes = flashes
+
+	h, hr := w.hovered()
+	if h == nil {
+		return nil
+	}
+	fill(hr, inspectHoverColor)
+	outline(hr, inspectHoverOutline)
+	if err := w.updateInfo(ctx.Screen, ctx.Theme, inspect.Describe(h.Wrapper)); err != nil {
+		return err
+	}
+	// Show the description below and to the right of the pointer, but within
+	// the Inspector.
+	size, bounds := w.infoBuf.Size(), w.Rect.Size()
+	p := w.mouse.Add(image.Point{12, 16})
+	if p.X+size.X > bounds.X {
+		p.X = bounds.X - size.X
+	}
+	if p.X < 0 {
+		p.X = 0
+	}
+	if p.Y+size.Y > bounds.Y {
+		p.Y = w.mouse.Y - 4 - size.Y
+	}
+	if p.Y < 0 {
+		p.Y = 0
+	}
+	translate(&src2dst, float64(p.X), float64(p.Y))
+	ctx.Drawer.Draw(src2dst, w.infoTex, w.infoTex.Bounds(), draw.Over, nil)
+	return nil
+}
+
+// addFlashes highlights, until the given time, the nodes that are about to be
+// repainted. As marks propagate up the widget tree, only the deepest marked
+// nodes are highlighted, not their ancestors. origin is e's parent's origin,
+// relative to the Inspector.
+func (w *Inspector) addFlashes(e *node.Embed, origin image.Point, until time.Time) {
+	if !needsRepaint(e) {
+		return
+	}
+	r := e.Rect.Add(origin)
+	deepest := true
+	for c := e.FirstChild; c != nil; c = c.NextSibling {
+		if needsRepaint(c) {
+			deepest = false
+			w.addFlashes(c, r.Min, until)
+		}
+	}
+	if deepest {
+		w.flashes = append(w.flashes, inspectFlash{r, until})
+	}
+}
+
+func needsRepaint(e *node.Embed) bool {
+	return e.Marks.NeedsPaint() || e.Marks.NeedsPaintBase()
+}
+
+// hovered returns the deepest node under the mouse pointer, other than the
+// Inspector itself, and its Rect relative to the Inspector.
+func (w *Inspector) hovered() (*node.Embed, image.Rectangle) {
+	if !w.hasMouse {
+		return nil, image.Rectangle{}
+	}
+	var (
+		h  *node.Embed
+		hr image.Rectangle
+	)
+	origin := image.Point{}
+	for e := w.FirstChild; e != nil; {
+		r := e.Rect.Add(origin)
+		if !w.mouse.In(r) {
+			break
+		}
+		h, hr, origin = e, r, r.Min
+		// Later children are above earlier ones.
+		c := e.LastChild
+		for c != nil && !w.mouse.In(c.Rect.Add(origin)) {
+			c = c.PrevSibling
+		}
+		e = c
+	}
+	return h, hr
+}
+
+// updateInfo renders the description of the hovered node, if it changed.
+func (w *Inspector) updateInfo(s screen.Screen, t *theme.Theme, text string) error {
+	if text == w.infoText && w.infoTex != nil {
+		return nil
+	}
+	w.release()
+
+	face := t.AcquireFontFace(theme.FontFaceOptions{})
+	defer t.ReleaseFontFace(theme.FontFaceOptions{}, face)
+	m := face.Metrics()
+	const pad = 4
+	size := image.Point{
+		X: font.MeasureString(face, text).Ceil() + 2*pad,
+		Y: m.Ascent.Ceil() + m.Descent.Ceil() + 2*pad,
+	}
+
+	buf, err := s.NewBuffer(size)
+	if err != nil {
+		return err
+	}
+	tex, err := s.NewTexture(size)
+	if err != nil {
+		buf.Release()
+		return err
+	}
+	rgba := buf.RGBA()
+	draw.Draw(rgba, rgba.Bounds(), image.NewUniform(inspectInfoBG), image.Point{}, draw.Src)
+	d := font.Drawer{
+		Dst:  rgba,
+		Src:  image.NewUniform(inspectInfoFG),
+		Face: face,
+		Dot:  fixed.P(pad, pad+m.Ascent.Ceil()),
+	}
+	d.DrawString(text)
+	tex.Upload(image.Point{}, buf, buf.Bounds())
+
+	w.infoText, w.infoBuf, w.infoTex = text, buf, tex
+	return nil
+}
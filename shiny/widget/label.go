@@ -5,7 +5,9 @@
 package widget
 
 import (
+	"bytes"
 	"image"
+	"unicode/utf8"
 
 	"golang.org/x/exp/shiny/widget/node"
 	"golang.org/x/exp/shiny/widget/theme"
@@ -13,11 +15,20 @@ import (
 	"golang.org/x/image/math/fixed"
 )
 
-// Label is a leaf widget that holds a text label.
+// Label is a leaf widget that holds a single line text label.
 type Label struct {
 	node.LeafEmbed
 	Text       string
 	ThemeColor theme.Color
+
+	// HAlign and VAlign are how the text is aligned horizontally and
+	// vertically within the Label's Rect, if it is larger than the text.
+	// AlignStretch is equivalent to AlignStart.
+	HAlign, VAlign Alignment
+
+	// Ellipsis is whether text that is wider than the Label's Rect is
+	// truncated and ends with an ellipsis, instead of being cut off.
+	Ellipsis bool
 }
 
 // NewLabel returns a new Label widget.
@@ -34,15 +45,80 @@ func (w *Label) Measure(t *theme.Theme, widthHint, heightHint int) {
 	defer t.ReleaseFontFace(theme.FontFaceOptions{}, face)
 	m := face.Metrics()
 
-	// TODO: padding, to match a Text widget?
+	// TODO: padding, to match a Text widget? Or give each Label a node.Box?
 
 	w.MeasuredSize.X = font.MeasureString(face, w.Text).Ceil()
 	w.MeasuredSize.Y = m.Ascent.Ceil() + m.Descent.Ceil()
 }
 
+// IntrinsicWidths returns the Label's minimum and maximum content widths: the
+// narrowest that it can be without its text being cut off, and its natural
+// width. The two are the same unless the text can be truncated by an
+// Ellipsis.
+func (w *Label) IntrinsicWidths(t *theme.Theme) (min, max int) {
+	face := t.AcquireFontFace(theme.FontFaceOptions{})
+	defer t.ReleaseFontFace(theme.FontFaceOptions{}, face)
+	max = font.MeasureString(face, w.Text).Ceil()
+	if !w.Ellipsis {
+		return max, max
+	}
+	min = font.MeasureString(face, ellipsis(face)).Ceil()
+	if min > max {
+		min = max
+	}
+	return min, max
+}
+
+func (w *Label) Baseline(t *theme.Theme) int {
+	return fontAscent(t)
+}
+
+// fontAscent returns the ascent, in whole pixels, of the theme's default font
+// face.
+func fontAscent(t *theme.Theme) int {
+	face := t.AcquireFontFace(theme.FontFaceOptions{})
+	defer t.ReleaseFontFace(theme.FontFaceOptions{}, face)
+	return face.Metrics().Ascent.Ceil()
+}
+
+// ellipsis returns the text that marks truncated text: a horizontal ellipsis
+// if the face has that glyph, otherwise three periods.
+func ellipsis(face font.Face) string {
+	if _, ok := face.GlyphAdvance('…'); ok {
+		return "…"
+	}
+	return "..."
+}
+
+// ellipsize returns s, truncated so that it and an ellipsis fit in width,
+// followed by that ellipsis, and the number of bytes of s that were kept. If
+// force is false and s already fits, s is returned unchanged.
+func ellipsize(face font.Face, s []byte, width fixed.Int26_6, force bool) (t []byte, n int) {
+	if !force && font.MeasureBytes(face, s) <= width {
+		return s, len(s)
+	}
+	e := ellipsis(face)
+	width -= font.MeasureString(face, e)
+	adv, prevR := fixed.Int26_6(0), rune(-1)
+	for n < len(s) {
+		r, size := utf8.DecodeRune(s[n:])
+		if prevR >= 0 {
+			adv += face.Kern(prevR, r)
+		}
+		a, _ := face.GlyphAdvance(r)
+		if adv+a > width {
+			break
+		}
+		adv, prevR, n = adv+a, r, n+size
+	}
+	prefix := bytes.TrimRight(s[:n], " \t")
+	return append(prefix[:len(prefix):len(prefix)], e...), len(prefix)
+}
+
 func (w *Label) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
 	w.Marks.UnmarkNeedsPaintBase()
-	dst := ctx.Dst.SubImage(w.Rect.Add(origin)).(*image.RGBA)
+	r := w.Rect.Add(origin)
+	dst := ctx.Dst.SubImage(r).(*image.RGBA)
 	if dst.Bounds().Empty() {
 		return nil
 	}
@@ -57,15 +133,29 @@ func (w *Label) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error
 		tc = theme.Foreground
 	}
 
+	s := []byte(w.Text)
+	if w.Ellipsis {
+		s, _ = ellipsize(face, s, fixed.I(r.Dx()), false)
+	}
+	x, _ := w.HAlign.align(font.MeasureBytes(face, s).Ceil(), r.Dx())
+	y, _ := w.VAlign.align(ascent+m.Descent.Ceil(), r.Dy())
+
 	d := font.Drawer{
 		Dst:  dst,
 		Src:  tc.Uniform(ctx.Theme),
 		Face: face,
 		Dot: fixed.Point26_6{
-			X: fixed.I(origin.X + w.Rect.Min.X),
-			Y: fixed.I(origin.Y + w.Rect.Min.Y + ascent),
+			X: fixed.I(r.Min.X + x),
+			Y: fixed.I(r.Min.Y + y + ascent),
 		},
 	}
-	d.DrawString(w.Text)
+	d.DrawBytes(s)
 	return nil
 }
+
+func (w *Label) Accessibility() node.Accessibility {
+	return node.Accessibility{
+		Role: node.RoleLabel,
+		Name: w.Text,
+	}
+}
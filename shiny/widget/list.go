@@ -0,0 +1,607 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/draw"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/mouse"
+)
+
+// ListSource provides the rows of a List.
+type ListSource interface {
+	// RowCount returns the number of rows.
+	RowCount() int
+
+	// RowHeight returns the height, in pixels, of every row.
+	RowHeight(t *theme.Theme) int
+
+	// Row returns a node that shows the i'th row. Only visible rows are
+	// asked for.
+	//
+	// If recycled is non-nil, it is a node, previously returned by Row, that
+	// is no longer visible. Row can update and return it instead of creating
+	// a new node. Either way, the returned node must not have a parent.
+	Row(i int, recycled node.Node) node.Node
+}
+
+// ListColumn is a column of a List.
+type ListColumn struct {
+	// Title is the column's header text.
+	Title string
+
+	// Width is the column's initial width. The user can resize a column by
+	// dragging the right edge of its header.
+	Width unit.Value
+
+	// width is the column's width in pixels, or zero if not yet laid out.
+	width int
+}
+
+// List is a container widget that shows the rows of a ListSource, one below
+// the other. Its rows are virtualized: only the rows that intersect the List's
+// Rect are child nodes, and only those are measured, laid out and painted. Row
+// nodes that are scrolled out of view are recycled.
+//
+// If the List has Columns, a header row shows their titles, and the children
+// of each row node are laid out as that row's cells, one per column. The row
+// node's own Layout method is not called. Otherwise, each row node is laid
+// out to the List's full width.
+//
+// The List can be focused. Clicking on a row or moving the cursor by the arrow,
+// page, Home and End keys selects it. If Multiple is set, Control-clicking or
+// the space bar toggles a row's selection, and Shift-clicking or Shift with a
+// cursor key selects a range.
+//
+// Like other widgets that paint in PaintBase, a List should be inside a Sheet.
+type List struct {
+	node.ContainerEmbed
+	Source  ListSource
+	Columns []ListColumn
+
+	// Multiple is whether more than one row can be selected.
+	Multiple bool
+
+	// OnSelectionChange, if non-nil, is called when the user changes the
+	// selection.
+	OnSelectionChange func()
+
+	// theme is the theme from the most recent Layout call. Scrolling lays
+	// out newly visible rows without a Layout call on the List.
+	theme *theme.Theme
+
+	rowHeight    int
+	headerHeight int
+	barWidth     int
+
+	// offset is the scroll position, in pixels, of the top of the rows area
+	// relative to the top of the first row.
+	offset int
+
+	// first is the index of the row shown by rows[0]. spare holds recycled
+	// row nodes.
+	first int
+	rows  []node.Node
+	spare []node.Node
+
+	// selected is the set of selected rows.
+	selected rowSet
+	// cursor is the row that the keyboard acts on. anchor is the other end
+	// of a range selection.
+	cursor, anchor int
+	focused        bool
+
+	// resizing is the index of the column being resized, or -1. resizeX and
+	// resizeWidth are the mouse position and the column's width when the
+	// resizing started.
+	resizing    int
+	resizeX     int
+	resizeWidth int
+
+	// dragBar is whether the scrollbar's thumb is being dragged, and dragY and
+	// dragOffset are the mouse position and offset when that drag started.
+	dragBar    bool
+	dragY      int
+	dragOffset int
+}
+
+// NewList returns a new List widget.
+func NewList(src ListSource, columns ...ListColumn) *List {
+	w := &List{
+		Source:   src,
+		Columns:  columns,
+		resizing: -1,
+	}
+	w.Wrapper = w
+	w.Focusable = true
+	return w
+}
+
+// Reload discards the row nodes and asks the Source for them again, such as
+// after the underlying data has changed. Selected rows that no longer exist
+// are deselected.
+func (w *List) Reload() {
+	w.selected.set(w.Source.RowCount(), maxRow, false)
+	w.recycle(0, len(w.rows))
+	w.rows = w.rows[:0]
+	w.layoutRows()
+}
+
+// Cursor returns the index of the row that the keyboard acts on.
+func (w *List) Cursor() int { return w.cursor }
+
+// Selected returns whether the i'th row is selected.
+func (w *List) Selected(i int) bool { return w.selected.contains(i) }
+
+// SelectedRows returns the indexes of the selected rows, in increasing order.
+func (w *List) SelectedRows() []int {
+	return w.selected.rows(w.Source.RowCount())
+}
+
+// SetSelected sets whether the i'th row is selected. It does not call
+// OnSelectionChange.
+func (w *List) SetSelected(i int, selected bool) {
+	if w.selected.contains(i) == selected {
+		return
+	}
+	if selected && !w.Multiple {
+		w.selected.reset(i, i+1)
+	} else {
+		w.selected.set(i, i+1, selected)
+	}
+	w.Mark(node.MarkNeedsPaintBase)
+}
+
+// ScrollToRow scrolls by the least amount that makes the i'th row visible.
+func (w *List) ScrollToRow(i int) {
+	if w.rowHeight <= 0 {
+		return
+	}
+	top, view := i*w.rowHeight, w.Rect.Dy()-w.headerHeight
+	switch {
+	case top < w.offset:
+		w.scrollTo(top)
+	case top+w.rowHeight > w.offset+view:
+		w.scrollTo(top + w.rowHeight - view)
+	}
+}
+
+func (w *List) scrollTo(offset int) {
+	if w.offset != offset {
+		w.offset = offset
+		w.layoutRows()
+	}
+}
+
+func (w *List) Measure(t *theme.Theme, widthHint, heightHint int) {
+	// The rows are virtualized, so the List does not measure them all. Its
+	// natural size is the sum of the column widths (if any) by ten rows.
+	//
+	// TODO: make the natural number of rows configurable.
+	w.measureHeader(t)
+	w.MeasuredSize = image.Point{
+		Y: w.headerHeight + 10*w.Source.RowHeight(t),
+	}
+	for _, c := range w.Columns {
+		w.MeasuredSize.X += t.Pixels(c.Width).Ceil()
+	}
+}
+
+func (w *List) measureHeader(t *theme.Theme) {
+	w.headerHeight = 0
+	if len(w.Columns) == 0 {
+		return
+	}
+	face := t.AcquireFontFace(theme.FontFaceOptions{})
+	defer t.ReleaseFontFace(theme.FontFaceOptions{}, face)
+	m := face.Metrics()
+	w.headerHeight = m.Ascent.Ceil() + m.Descent.Ceil() + 2*w.cellPadding(t)
+}
+
+func (w *List) cellPadding(t *theme.Theme) int {
+	return t.Pixels(unit.Ems(0.25)).Ceil()
+}
+
+func (w *List) Layout(t *theme.Theme) {
+	w.theme = t
+	w.measureHeader(t)
+	w.rowHeight = w.Source.RowHeight(t)
+	w.barWidth = t.Pixels(unit.DIPs(6)).Ceil()
+	for i := range w.Columns {
+		if c := &w.Columns[i]; c.width == 0 {
+			c.width = t.Pixels(c.Width).Ceil()
+		}
+	}
+	w.layoutRows()
+}
+
+// recycle removes the rows[i:j] nodes from the tree and adds them to the
+// spare nodes.
+func (w *List) recycle(i, j int) {
+	for _, r := range w.rows[i:j] {
+		w.Remove(r)
+		w.spare = append(w.spare, r)
+	}
+}
+
+// layoutRows updates which rows are visible, asking the Source for the newly
+// visible ones, and lays them out.
+func (w *List) layoutRows() {
+	t := w.theme
+	n := w.Source.RowCount()
+	view := w.Rect.Dy() - w.headerHeight
+	if max := n*w.rowHeight - view; w.offset > max {
+		w.offset = max
+	}
+	if w.offset < 0 {
+		w.offset = 0
+	}
+
+	first, last := 0, 0
+	if w.rowHeight > 0 && view > 0 {
+		first = w.offset / w.rowHeight
+		last = (w.offset + view + w.rowHeight - 1) / w.rowHeight
+		if last > n {
+			last = n
+		}
+	}
+
+	// Keep the rows that are still visible, and recycle the others.
+	rows := make([]node.Node, last-first)
+	for k, r := range w.rows {
+		if i := w.first + k; first <= i && i < last {
+			rows[i-first] = r
+		} else {
+			w.recycle(k, k+1)
+		}
+	}
+	for k := range rows {
+		if rows[k] != nil {
+			continue
+		}
+		var recycled node.Node
+		if s := len(w.spare); s > 0 {
+			recycled, w.spare = w.spare[s-1], w.spare[:s-1]
+		}
+		rows[k] = w.Source.Row(first+k, recycled)
+		w.Insert(rows[k], nil)
+	}
+	w.first, w.rows = first, rows
+
+	width := w.Rect.Dx() - w.barWidth
+	for k, r := range rows {
+		e := r.Wrappee()
+		y := w.headerHeight + (first+k)*w.rowHeight - w.offset
+		if len(w.Columns) == 0 {
+			e.MeasureOuter(t, width, w.rowHeight)
+			e.SetOuterRect(t, image.Rect(0, y, width, y+w.rowHeight))
+			r.Layout(t)
+			continue
+		}
+		e.SetOuterRect(t, image.Rect(0, y, width, y+w.rowHeight))
+		x, col := 0, 0
+		for c := e.FirstChild; c != nil; c = c.NextSibling {
+			cw := 0
+			if col < len(w.Columns) {
+				cw = w.Columns[col].width
+			}
+			c.MeasureOuter(t, cw, w.rowHeight)
+			c.SetOuterRect(t, image.Rect(x, 0, x+cw, w.rowHeight))
+			c.Wrapper.Layout(t)
+			x, col = x+cw, col+1
+		}
+	}
+	w.Mark(node.MarkNeedsPaintBase)
+}
+
+// thumb returns the scrollbar's thumb, relative to the List's Rect.Min. It is
+// empty if there is nothing to scroll.
+func (w *List) thumb() image.Rectangle {
+	total, view := w.Source.RowCount()*w.rowHeight, w.Rect.Dy()-w.headerHeight
+	if total <= view || view <= 0 {
+		return image.Rectangle{}
+	}
+	h := view * view / total
+	if h < w.barWidth {
+		h = w.barWidth
+	}
+	y := w.headerHeight + (view-h)*w.offset/(total-view)
+	return image.Rect(w.Rect.Dx()-w.barWidth, y, w.Rect.Dx(), y+h)
+}
+
+func (w *List) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
+	w.Marks.UnmarkNeedsPaintBase()
+	r := w.Rect.Add(origin)
+	dst := ctx.Dst.SubImage(r).(*image.RGBA)
+	if dst.Bounds().Empty() {
+		return nil
+	}
+	pal := ctx.Theme.GetPalette()
+	draw.Draw(dst, r, pal.Background(), image.Point{}, draw.Src)
+
+	// Paint the rows, clipped to the rows area, on top of their selection
+	// highlight.
+	rowsArea := image.Rect(r.Min.X, r.Min.Y+w.headerHeight, r.Max.X, r.Max.Y)
+	rowsCtx := &node.PaintBaseContext{
+		Theme: ctx.Theme,
+		Dst:   ctx.Dst.SubImage(rowsArea).(*image.RGBA),
+	}
+	for k, row := range w.rows {
+		e := row.Wrappee()
+		i, rr := w.first+k, e.OuterRect(ctx.Theme).Add(r.Min)
+		if w.selected.contains(i) {
+			draw.Draw(rowsCtx.Dst, rr, pal.Selection(), image.Point{}, draw.Src)
+		}
+		e.PaintBox(rowsCtx, r.Min)
+		if err := row.PaintBase(rowsCtx, r.Min); err != nil {
+			return err
+		}
+		if i == w.cursor && w.focused {
+			drawBorder(rowsCtx.Dst, rr, pal.Accent(), lineWidth(ctx.Theme))
+		}
+	}
+
+	if thumb := w.thumb(); !thumb.Empty() {
+		draw.Draw(dst, thumb.Add(r.Min), pal.Neutral(), image.Point{}, draw.Src)
+	}
+	if len(w.Columns) > 0 {
+		w.paintHeader(ctx, dst, r)
+	}
+	return nil
+}
+
+func (w *List) paintHeader(ctx *node.PaintBaseContext, dst *image.RGBA, r image.Rectangle) {
+	t := ctx.Theme
+	pal := t.GetPalette()
+	face := t.AcquireFontFace(theme.FontFaceOptions{})
+	defer t.ReleaseFontFace(theme.FontFaceOptions{}, face)
+	ascent := face.Metrics().Ascent.Ceil()
+	padding := w.cellPadding(t)
+	lw := lineWidth(t)
+
+	header := image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+w.headerHeight)
+	draw.Draw(dst, header, pal.Neutral(), image.Point{}, draw.Src)
+	draw.Draw(dst, image.Rect(header.Min.X, header.Max.Y-lw, header.Max.X, header.Max.Y),
+		pal.Dark(), image.Point{}, draw.Src)
+
+	x := header.Min.X
+	for _, c := range w.Columns {
+		cell := image.Rect(x, header.Min.Y, x+c.width, header.Max.Y)
+		d := font.Drawer{
+			Dst:  dst.SubImage(cell.Inset(padding)).(*image.RGBA),
+			Src:  pal.Foreground(),
+			Face: face,
+			Dot:  fixed.P(cell.Min.X+padding, cell.Min.Y+padding+ascent),
+		}
+		d.DrawString(c.Title)
+		x += c.width
+		draw.Draw(dst, image.Rect(x-lw, header.Min.Y, x, header.Max.Y), pal.Dark(), image.Point{}, draw.Src)
+	}
+}
+
+// columnEdgeAt returns the index of the column whose right edge is near x,
+// relative to the List's Rect.Min, or -1.
+func (w *List) columnEdgeAt(x int) int {
+	slop := 2 * w.barWidth
+	edge := 0
+	for i, c := range w.Columns {
+		edge += c.width
+		if edge-slop <= x && x < edge+slop {
+			return i
+		}
+	}
+	return -1
+}
+
+func (w *List) OnInputEvent(e interface{}, origin image.Point) node.EventHandled {
+	switch e := e.(type) {
+	case node.FocusEvent:
+		w.focused = e.Focused
+		w.Mark(node.MarkNeedsPaintBase)
+		return node.Handled
+
+	case key.Event:
+		return w.onKeyEvent(e)
+
+	case mouse.Event:
+		if w.onMouseEvent(e, origin) {
+			return node.Handled
+		}
+	}
+	// The rows get any other events, such as clicks on a row that is a
+	// button.
+	return w.ContainerEmbed.OnInputEvent(e, origin)
+}
+
+// onMouseEvent handles a mouse event that is not for a row. It returns whether
+// the event was handled.
+func (w *List) onMouseEvent(e mouse.Event, origin image.Point) bool {
+	p := image.Point{int(e.X), int(e.Y)}.Sub(origin).Sub(w.Rect.Min)
+	switch e.Direction {
+	case mouse.DirStep:
+		switch e.Button {
+		case mouse.ButtonWheelUp:
+			w.scrollTo(w.offset - wheelLines*w.rowHeight)
+		case mouse.ButtonWheelDown:
+			w.scrollTo(w.offset + wheelLines*w.rowHeight)
+		default:
+			return false
+		}
+		return true
+
+	case mouse.DirPress:
+		if e.Button != mouse.ButtonLeft {
+			return false
+		}
+		if p.Y < w.headerHeight {
+			if i := w.columnEdgeAt(p.X); i >= 0 {
+				w.resizing, w.resizeX, w.resizeWidth = i, p.X, w.Columns[i].width
+			}
+			return true
+		}
+		if thumb := w.thumb(); !thumb.Empty() && p.X >= thumb.Min.X {
+			w.dragBar, w.dragY, w.dragOffset = true, p.Y, w.offset
+			return true
+		}
+		// Let an interactive row, such as a checkbox, have the press.
+		if w.ContainerEmbed.OnInputEvent(e, origin) == node.Handled {
+			return true
+		}
+		if w.rowHeight <= 0 {
+			return true
+		}
+		if i := (p.Y - w.headerHeight + w.offset) / w.rowHeight; i < w.Source.RowCount() {
+			w.click(i, e.Modifiers)
+		}
+		return true
+
+	case mouse.DirNone:
+		switch {
+		case w.resizing >= 0:
+			width := w.resizeWidth + p.X - w.resizeX
+			if min := 2 * w.cellPadding(w.theme); width < min {
+				width = min
+			}
+			w.Columns[w.resizing].width = width
+			w.layoutRows()
+			return true
+		case w.dragBar:
+			total, view := w.Source.RowCount()*w.rowHeight, w.Rect.Dy()-w.headerHeight
+			if track := view - w.thumb().Dy(); track > 0 {
+				w.scrollTo(w.dragOffset + (p.Y-w.dragY)*(total-view)/track)
+			}
+			return true
+		}
+
+	case mouse.DirRelease:
+		if w.resizing >= 0 || w.dragBar {
+			w.resizing, w.dragBar = -1, false
+			return true
+		}
+	}
+	return false
+}
+
+// click handles a mouse click on the i'th row.
+func (w *List) click(i int, mods key.Modifiers) {
+	switch {
+	case w.Multiple && mods&key.ModShift != 0:
+		w.selectRange(w.anchor, i)
+		w.cursor = i
+	case w.Multiple && mods&(key.ModControl|key.ModMeta) != 0:
+		w.SetSelected(i, !w.selected.contains(i))
+		w.cursor, w.anchor = i, i
+	default:
+		w.selectOnly(i)
+	}
+	w.changed()
+}
+
+func (w *List) changed() {
+	w.Mark(node.MarkNeedsPaintBase)
+	if w.OnSelectionChange != nil {
+		w.OnSelectionChange()
+	}
+}
+
+// selectOnly moves the cursor and anchor to the i'th row and selects only it.
+func (w *List) selectOnly(i int) {
+	w.selected.reset(i, i+1)
+	w.cursor, w.anchor = i, i
+}
+
+// selectRange selects only the rows from i to j, inclusive.
+func (w *List) selectRange(i, j int) {
+	if i > j {
+		i, j = j, i
+	}
+	w.selected.reset(i, j+1)
+}
+
+func (w *List) onKeyEvent(e key.Event) node.EventHandled {
+	n := w.Source.RowCount()
+	if e.Direction == key.DirRelease || n == 0 {
+		return node.NotHandled
+	}
+	page := 1
+	if w.rowHeight > 0 {
+		if page = (w.Rect.Dy() - w.headerHeight) / w.rowHeight; page < 1 {
+			page = 1
+		}
+	}
+
+	i := w.cursor
+	switch e.Code {
+	case key.CodeUpArrow:
+		i--
+	case key.CodeDownArrow:
+		i++
+	case key.CodePageUp:
+		i -= page
+	case key.CodePageDown:
+		i += page
+	case key.CodeHome:
+		i = 0
+	case key.CodeEnd:
+		i = n - 1
+	case key.CodeSpacebar:
+		if !w.Multiple {
+			return node.NotHandled
+		}
+		w.SetSelected(i, !w.selected.contains(i))
+		w.anchor = i
+		w.changed()
+		return node.Handled
+	case key.CodeA:
+		if !w.Multiple || e.Modifiers&(key.ModControl|key.ModMeta) == 0 {
+			return node.NotHandled
+		}
+		w.selected.setAll()
+		w.changed()
+		return node.Handled
+	default:
+		return node.NotHandled
+	}
+
+	if i < 0 {
+		i = 0
+	}
+	if i >= n {
+		i = n - 1
+	}
+	if w.Multiple && e.Modifiers&key.ModShift != 0 {
+		w.selectRange(w.anchor, i)
+		w.cursor = i
+	} else {
+		w.selectOnly(i)
+	}
+	w.ScrollToRow(i)
+	w.changed()
+	return node.Handled
+}
+
+// TODO: expose the rows' selected states. The row nodes are provided by the
+// ListSource, so the List does not choose their Accessibility.
+
+func (w *List) Accessibility() node.Accessibility {
+	s := node.State(0)
+	if w.focused {
+		s |= node.StateFocused
+	}
+	if w.Multiple {
+		s |= node.StateMultiSelectable
+	}
+	return node.Accessibility{
+		Role:  node.RoleList,
+		State: s,
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"fmt"
+	"image"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/mouse"
+)
+
+// testListRow is a row node of a testListSource.
+type testListRow struct {
+	node.ContainerEmbed
+	i int
+}
+
+// testListSource is a ListSource of n rows, 10 pixels high, that logs the rows
+// that it is asked for. If cells is positive, each row has that many cells.
+type testListSource struct {
+	n, cells int
+	log      []string
+}
+
+func (s *testListSource) RowCount() int                { return s.n }
+func (s *testListSource) RowHeight(t *theme.Theme) int { return 10 }
+
+func (s *testListSource) Row(i int, recycled node.Node) node.Node {
+	if r, ok := recycled.(*testListRow); ok {
+		s.log = append(s.log, fmt.Sprintf("%d(%d)", i, r.i))
+		r.i = i
+		return r
+	}
+	s.log = append(s.log, fmt.Sprint(i))
+	r := &testListRow{i: i}
+	r.Wrapper = r
+	for k := 0; k < s.cells; k++ {
+		r.Insert(NewSpace(), nil)
+	}
+	return r
+}
+
+// newTestList returns a laid out List, 100 by 50 pixels, of src's rows.
+func newTestList(src *testListSource, columns ...ListColumn) *List {
+	w := NewList(src, columns...)
+	w.Measure(nil, node.NoHint, node.NoHint)
+	w.Rect = image.Rect(0, 0, 100, 50)
+	w.Layout(nil)
+	return w
+}
+
+// shownRows returns the indexes of w's row nodes, which must be
+// testListRows, in tree order.
+func shownRows(w *List) []int {
+	var rows []int
+	for c := w.FirstChild; c != nil; c = c.NextSibling {
+		rows = append(rows, c.Wrapper.(*testListRow).i)
+	}
+	return rows
+}
+
+func TestListRows(t *testing.T) {
+	src := &testListSource{n: 100}
+	w := newTestList(src)
+
+	check := func(desc, wantLog string, wantRows []int) {
+		t.Helper()
+		if got := strings.Join(src.log, " "); got != wantLog {
+			t.Errorf("%s: requested rows: got %q, want %q", desc, got, wantLog)
+		}
+		if got := shownRows(w); !reflect.DeepEqual(got, wantRows) {
+			t.Errorf("%s: shown rows: got %v, want %v", desc, got, wantRows)
+		}
+		src.log = src.log[:0]
+	}
+	check("layout", "0 1 2 3 4", []int{0, 1, 2, 3, 4})
+
+	// Scrolling by 2.5 rows shows parts of rows 2 and 7. Rows 0 and 1 are
+	// recycled for the newly visible rows.
+	w.scrollTo(25)
+	check("scroll", "5(1) 6(0) 7", []int{2, 3, 4, 5, 6, 7})
+	if got, want := w.rows[0].Wrappee().Rect, image.Rect(0, -5, 100-w.barWidth, 5); got != want {
+		t.Errorf("scroll: first row's Rect: got %v, want %v", got, want)
+	}
+
+	// The wheel scrolls by wheelLines rows.
+	w.OnInputEvent(mouse.Event{X: 10, Y: 10, Button: mouse.ButtonWheelUp, Direction: mouse.DirStep}, image.Point{})
+	if w.offset != 0 {
+		t.Errorf("wheel: offset: got %d, want 0", w.offset)
+	}
+	src.log = src.log[:0]
+
+	// Scrolling far away recycles every row, and doesn't scroll past the end.
+	w.ScrollToRow(99)
+	if got, want := w.offset, 100*10-50; got != want {
+		t.Errorf("ScrollToRow: offset: got %d, want %d", got, want)
+	}
+	if got := shownRows(w); !reflect.DeepEqual(got, []int{95, 96, 97, 98, 99}) {
+		t.Errorf("ScrollToRow: shown rows: got %v", got)
+	}
+	if got, want := len(src.log), 5; got != want || !strings.Contains(src.log[0], "(") {
+		t.Errorf("ScrollToRow: requested rows: got %q, want %d recycled rows", src.log, want)
+	}
+
+	// Reloading asks for the visible rows again.
+	src.n = 97
+	src.log = src.log[:0]
+	w.Reload()
+	if got := shownRows(w); !reflect.DeepEqual(got, []int{92, 93, 94, 95, 96}) {
+		t.Errorf("Reload: shown rows: got %v", got)
+	}
+}
+
+func TestListSelection(t *testing.T) {
+	src := &testListSource{n: 20}
+	w := newTestList(src)
+	w.Multiple = true
+	changes := 0
+	w.OnSelectionChange = func() { changes++ }
+
+	// click returns a mouse press on the i'th row.
+	click := func(i int, mods key.Modifiers) mouse.Event {
+		return mouse.Event{
+			X:         10,
+			Y:         float32(i*10 + 5),
+			Button:    mouse.ButtonLeft,
+			Direction: mouse.DirPress,
+			Modifiers: mods,
+		}
+	}
+	press := func(code key.Code, mods key.Modifiers) key.Event {
+		return key.Event{Code: code, Direction: key.DirPress, Modifiers: mods}
+	}
+
+	testCases := []struct {
+		desc       string
+		e          interface{}
+		want       []int
+		wantCursor int
+	}{
+		{"click", click(1, 0), []int{1}, 1},
+		{"shift-click", click(3, key.ModShift), []int{1, 2, 3}, 3},
+		{"ctrl-click", click(2, key.ModControl), []int{1, 3}, 2},
+		{"ctrl-click again", click(2, key.ModControl), []int{1, 2, 3}, 2},
+		{"shift-click above", click(0, key.ModShift), []int{0, 1, 2}, 0},
+		{"down", press(key.CodeDownArrow, 0), []int{1}, 1},
+		{"shift-down", press(key.CodeDownArrow, key.ModShift), []int{1, 2}, 2},
+		{"shift-down again", press(key.CodeDownArrow, key.ModShift), []int{1, 2, 3}, 3},
+		{"space", press(key.CodeSpacebar, 0), []int{1, 2}, 3},
+		{"page down", press(key.CodePageDown, 0), []int{8}, 8},
+		{"end", press(key.CodeEnd, 0), []int{19}, 19},
+		{"down at end", press(key.CodeDownArrow, 0), []int{19}, 19},
+		{"shift-page up", press(key.CodePageUp, key.ModShift), []int{14, 15, 16, 17, 18, 19}, 14},
+		{"home", press(key.CodeHome, 0), []int{0}, 0},
+		{"up at start", press(key.CodeUpArrow, 0), []int{0}, 0},
+		{"ctrl-a", press(key.CodeA, key.ModControl), []int{
+			0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19,
+		}, 0},
+	}
+	for i, tc := range testCases {
+		if w.OnInputEvent(tc.e, image.Point{}) != node.Handled {
+			t.Errorf("%s: not handled", tc.desc)
+		}
+		if got := w.SelectedRows(); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.desc, got, tc.want)
+		}
+		if got := w.Cursor(); got != tc.wantCursor {
+			t.Errorf("%s: cursor: got %d, want %d", tc.desc, got, tc.wantCursor)
+		}
+		if changes != i+1 {
+			t.Errorf("%s: OnSelectionChange called %d times, want %d", tc.desc, changes, i+1)
+			changes = i + 1
+		}
+	}
+
+	// Moving the cursor scrolls to it.
+	w.OnInputEvent(press(key.CodeEnd, 0), image.Point{})
+	if got, want := w.offset, 20*10-50; got != want {
+		t.Errorf("end: offset: got %d, want %d", got, want)
+	}
+
+	// Without Multiple, modifiers are ignored.
+	w.Multiple = false
+	w.ScrollToRow(0)
+	w.OnInputEvent(click(1, 0), image.Point{})
+	w.OnInputEvent(click(3, key.ModShift), image.Point{})
+	w.OnInputEvent(click(2, key.ModControl), image.Point{})
+	if got := w.SelectedRows(); !reflect.DeepEqual(got, []int{2}) {
+		t.Errorf("single: got %v, want [2]", got)
+	}
+	if w.OnInputEvent(press(key.CodeSpacebar, 0), image.Point{}) != node.NotHandled {
+		t.Error("single: space handled")
+	}
+}
+
+func TestListColumnResize(t *testing.T) {
+	src := &testListSource{n: 10, cells: 2}
+	w := newTestList(src,
+		ListColumn{Title: "A", Width: unit.Pixels(30)},
+		ListColumn{Title: "B", Width: unit.Pixels(40)},
+	)
+	cellX := func() (x0, x1 int) {
+		row := w.rows[0].Wrappee()
+		return row.FirstChild.Rect.Min.X, row.LastChild.Rect.Min.X
+	}
+	if x0, x1 := cellX(); x0 != 0 || x1 != 30 {
+		t.Fatalf("cells: got x %d, %d, want 0, 30", x0, x1)
+	}
+	if got, want := w.rows[0].Wrappee().Rect.Min.Y, w.headerHeight; got != want {
+		t.Errorf("first row: got y %d, want %d", got, want)
+	}
+
+	y := float32(w.headerHeight / 2)
+	drag := func(x0, x1 float32) {
+		w.OnInputEvent(mouse.Event{X: x0, Y: y, Button: mouse.ButtonLeft, Direction: mouse.DirPress}, image.Point{})
+		w.OnInputEvent(mouse.Event{X: x1, Y: y, Direction: mouse.DirNone}, image.Point{})
+		w.OnInputEvent(mouse.Event{X: x1, Y: y, Button: mouse.ButtonLeft, Direction: mouse.DirRelease}, image.Point{})
+	}
+
+	// Dragging the first column's right edge resizes it, moving the second.
+	drag(31, 51)
+	if got := w.Columns[0].width; got != 50 {
+		t.Errorf("drag: width: got %d, want 50", got)
+	}
+	if _, x1 := cellX(); x1 != 50 {
+		t.Errorf("drag: second cell: got x %d, want 50", x1)
+	}
+
+	// Moving the mouse after the release does nothing.
+	w.OnInputEvent(mouse.Event{X: 90, Y: y, Direction: mouse.DirNone}, image.Point{})
+	if got := w.Columns[0].width; got != 50 {
+		t.Errorf("move: width: got %d, want 50", got)
+	}
+
+	// A column is no narrower than its padding.
+	drag(50, 0)
+	if got, want := w.Columns[0].width, 2*w.cellPadding(nil); got != want {
+		t.Errorf("shrink: width: got %d, want %d", got, want)
+	}
+
+	// Pressing in the header away from a column's edge selects nothing.
+	w.OnInputEvent(mouse.Event{X: 99 - float32(w.barWidth), Y: y, Button: mouse.ButtonLeft, Direction: mouse.DirPress}, image.Point{})
+	if w.resizing >= 0 || len(w.SelectedRows()) != 0 {
+		t.Errorf("header press: resizing %d, selected %v", w.resizing, w.SelectedRows())
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package markup
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/theme"
+)
+
+// Attrs are an element's attributes, or its layout attributes, as given to a
+// Kind's functions.
+//
+// Its methods return the value of an attribute, or a default value if the
+// element does not have that attribute. If the attribute's value is invalid,
+// they also return the default value, and the first such error is reported
+// when the widget tree is built. It is also an error for an element to have
+// attributes that are not looked up.
+type Attrs struct {
+	// desc describes the element, for error messages.
+	desc string
+	m    map[string]string
+	used map[string]bool
+	err  error
+	tree *Tree
+}
+
+func newAttrs(desc string, m map[string]string, t *Tree) *Attrs {
+	return &Attrs{
+		desc: desc,
+		m:    m,
+		used: map[string]bool{},
+		tree: t,
+	}
+}
+
+// Tree returns the Tree that is being built. Kinds can use it to share state
+// between elements, such as a RadioGroup.
+func (a *Attrs) Tree() *Tree { return a.tree }
+
+// Has returns whether the element has the given attribute.
+func (a *Attrs) Has(key string) bool {
+	_, ok := a.m[key]
+	return ok
+}
+
+func (a *Attrs) lookup(key string) (string, bool) {
+	v, ok := a.m[key]
+	if ok {
+		a.used[key] = true
+	}
+	return v, ok
+}
+
+// Errorf reports an error with the element, unless one was already reported.
+func (a *Attrs) Errorf(format string, args ...interface{}) {
+	if a.err == nil {
+		a.err = fmt.Errorf("markup: %s: %s", a.desc, fmt.Sprintf(format, args...))
+	}
+}
+
+func (a *Attrs) invalid(key, value, want string) {
+	a.Errorf("invalid %s %q: want %s", key, value, want)
+}
+
+// check returns the first error reported, or an error if an attribute was not
+// looked up.
+func (a *Attrs) check() error {
+	if a.err != nil {
+		return a.err
+	}
+	var unused []string
+	for k := range a.m {
+		if !a.used[k] {
+			unused = append(unused, k)
+		}
+	}
+	if len(unused) > 0 {
+		sort.Strings(unused)
+		return fmt.Errorf("markup: %s: unknown attribute %s", a.desc, unused[0])
+	}
+	return nil
+}
+
+// String returns the value of a string attribute.
+func (a *Attrs) String(key, def string) string {
+	if v, ok := a.lookup(key); ok {
+		return v
+	}
+	return def
+}
+
+// Bool returns the value of a boolean attribute.
+func (a *Attrs) Bool(key string, def bool) bool {
+	v, ok := a.lookup(key)
+	if !ok {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		a.invalid(key, v, "a boolean")
+		return def
+	}
+	return b
+}
+
+// Int returns the value of an integer attribute.
+func (a *Attrs) Int(key string, def int) int {
+	v, ok := a.lookup(key)
+	if !ok {
+		return def
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		a.invalid(key, v, "an integer")
+		return def
+	}
+	return i
+}
+
+// Float returns the value of a number attribute.
+func (a *Attrs) Float(key string, def float64) float64 {
+	v, ok := a.lookup(key)
+	if !ok {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		a.invalid(key, v, "a number")
+		return def
+	}
+	return f
+}
+
+// Value returns the value of a length attribute, such as "4dp".
+func (a *Attrs) Value(key string, def unit.Value) unit.Value {
+	v, ok := a.lookup(key)
+	if !ok {
+		return def
+	}
+	u, ok := parseValue(v)
+	if !ok {
+		a.invalid(key, v, "a length")
+		return def
+	}
+	return u
+}
+
+var unitSuffixes = [...]struct {
+	suffix string
+	u      unit.Unit
+}{
+	{"px", unit.Px},
+	{"dp", unit.Dp},
+	{"pt", unit.Pt},
+	{"mm", unit.Mm},
+	{"in", unit.In},
+	{"em", unit.Em},
+	{"ex", unit.Ex},
+	{"ch", unit.Ch},
+}
+
+// parseValue parses a length, such as "4dp". A number without a unit suffix
+// is in pixels.
+func parseValue(s string) (unit.Value, bool) {
+	u := unit.Px
+	for _, x := range unitSuffixes {
+		if strings.HasSuffix(s, x.suffix) {
+			s, u = s[:len(s)-len(x.suffix)], x.u
+			break
+		}
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return unit.Value{}, false
+	}
+	return unit.Value{F: f, U: u}, true
+}
+
+// Enum returns the value of an attribute that is one of the given names. The
+// value returned is the index of the name, so that the names of a Go enum
+// type's constants, in order from zero, can be given to look up a value of
+// that type.
+func (a *Attrs) Enum(key string, def int, names ...string) int {
+	v, ok := a.lookup(key)
+	if !ok {
+		return def
+	}
+	for i, name := range names {
+		if v == name {
+			return i
+		}
+	}
+	a.invalid(key, v, "one of "+strings.Join(names, ", "))
+	return def
+}
+
+var paletteNames = [theme.PaletteLen]string{
+	theme.Light:      "light",
+	theme.Neutral:    "neutral",
+	theme.Dark:       "dark",
+	theme.Accent:     "accent",
+	theme.Foreground: "foreground",
+	theme.Background: "background",
+	theme.Error:      "error",
+	theme.Surface:    "surface",
+	theme.OnSurface:  "on-surface",
+	theme.Disabled:   "disabled",
+	theme.Selection:  "selection",
+}
+
+// Color returns the value of a color attribute: the name of a palette color,
+// such as "accent", or a hexadecimal "#rgb", "#rrggbb" or "#rrggbbaa" color.
+func (a *Attrs) Color(key string, def theme.Color) theme.Color {
+	v, ok := a.lookup(key)
+	if !ok {
+		return def
+	}
+	for i, name := range paletteNames {
+		if v == name {
+			return theme.PaletteIndex(i)
+		}
+	}
+	c, ok := parseHexColor(v)
+	if !ok {
+		a.invalid(key, v, "a palette color name or #rrggbb")
+		return def
+	}
+	return theme.StaticColor(c)
+}
+
+func parseHexColor(s string) (color.Color, bool) {
+	if len(s) == 0 || s[0] != '#' {
+		return nil, false
+	}
+	s = s[1:]
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) == 6 {
+		s += "ff"
+	}
+	if len(s) != 8 {
+		return nil, false
+	}
+	x, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return nil, false
+	}
+	return color.NRGBA{uint8(x >> 24), uint8(x >> 16), uint8(x >> 8), uint8(x)}, true
+}
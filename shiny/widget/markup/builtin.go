@@ -0,0 +1,371 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package markup
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+	"golang.org/x/exp/shiny/widget/flex"
+	"golang.org/x/exp/shiny/widget/grid"
+	"golang.org/x/exp/shiny/widget/node"
+)
+
+// TODO: Image, List and Menu Kinds, which need data that is not text, such as
+// an image.Image or a ListSource, perhaps given by the program by name.
+
+// NewRegistry returns a new Registry of the built-in Kinds, which are named
+// after the widgets' types. Their attributes are named after the widgets'
+// fields and constructor arguments, in lower case and hyphenated, such as
+// "align-items" for a Flex's AlignItems field.
+//
+// Containers:
+//
+//	Flow      axis (none, horizontal, vertical, both)
+//	          layout: along-weight, expand-along, shrink-along, expand-across,
+//	          shrink-across
+//	Flex      direction (row, row-reverse, column, column-reverse),
+//	          wrap (no-wrap, wrap, wrap-reverse),
+//	          justify (start, end, center, space-between, space-around),
+//	          align-items (auto, start, end, center, baseline, stretch),
+//	          align-content (stretch, start, end, center, space-between,
+//	          space-around), row-gap, column-gap
+//	          layout: grow, shrink, basis (auto, a length or a percentage),
+//	          align, order, break-after, min-width, min-height, max-width,
+//	          max-height
+//	Grid      columns, rows (space-separated tracks: auto, a length, a
+//	          fraction such as 1fr, or minmax(min,max)), column-gap, row-gap,
+//	          justify-items, align-items (auto, start, end, center, stretch)
+//	          layout: row, column, row-span, column-span, justify, align
+//	Stack     layout: h-align, v-align (start, center, end, stretch), top,
+//	          right, bottom, left
+//	Absolute  layout: x, y, width, height
+//
+// Shells, with at most one child:
+//
+//	Padder    axis (default both), margin
+//	Sizer     width, height
+//	Uniform   color
+//	Sheet
+//	Scroller  axis (default vertical)
+//
+// Leaves:
+//
+//	Label     text, h-align, v-align (start, center, end), ellipsis
+//	Text      text, h-align, v-align (start, center, end), max-lines,
+//	          ellipsis, selectable
+//	TextField text
+//	TextArea  text
+//	Button    text, disabled
+//	Checkbox  text, checked, disabled
+//	Radio     text, group, selected, disabled
+//	Switch    on, disabled
+//	Slider    min, max, step, value, disabled
+//	Space
+//
+// A Radio's group is the name of a RadioGroup in the Tree, so that Radios
+// with the same group are mutually exclusive.
+func NewRegistry() *Registry {
+	r := &Registry{kinds: map[string]Kind{}}
+	for typ, k := range builtins {
+		r.kinds[typ] = k
+	}
+	return r
+}
+
+var axisNames = []string{"none", "horizontal", "vertical", "both"}
+
+var builtins = map[string]Kind{
+	"Flow": {
+		New: func(a *Attrs, children []node.Node) (node.Node, error) {
+			axis := widget.Axis(a.Enum("axis", int(widget.AxisHorizontal), axisNames...))
+			return widget.NewFlow(axis, children...), nil
+		},
+		LayoutData: func(a *Attrs) interface{} {
+			return widget.FlowLayoutData{
+				AlongWeight:  a.Int("along-weight", 0),
+				ExpandAlong:  a.Bool("expand-along", false),
+				ShrinkAlong:  a.Bool("shrink-along", false),
+				ExpandAcross: a.Bool("expand-across", false),
+				ShrinkAcross: a.Bool("shrink-across", false),
+			}
+		},
+	},
+
+	"Flex": {
+		New: func(a *Attrs, children []node.Node) (node.Node, error) {
+			w := flex.NewFlex(children...)
+			w.Direction = flex.Direction(a.Enum("direction", 0,
+				"row", "row-reverse", "column", "column-reverse"))
+			w.Wrap = flex.FlexWrap(a.Enum("wrap", 0,
+				"no-wrap", "wrap", "wrap-reverse"))
+			w.Justify = flex.Justify(a.Enum("justify", 0,
+				"start", "end", "center", "space-between", "space-around"))
+			w.AlignItems = flex.AlignItem(a.Enum("align-items", 0, flexAlignNames...))
+			w.AlignContent = flex.AlignContent(a.Enum("align-content", 0,
+				"stretch", "start", "end", "center", "space-between", "space-around"))
+			w.RowGap = a.Value("row-gap", unit.Value{})
+			w.ColumnGap = a.Value("column-gap", unit.Value{})
+			return w, nil
+		},
+		LayoutData: flexLayoutData,
+	},
+
+	"Grid": {
+		New: func(a *Attrs, children []node.Node) (node.Node, error) {
+			w := grid.NewGrid(tracks(a, "columns"), children...)
+			w.Rows = tracks(a, "rows")
+			w.ColumnGap = a.Value("column-gap", unit.Value{})
+			w.RowGap = a.Value("row-gap", unit.Value{})
+			w.JustifyItems = grid.Align(a.Enum("justify-items", 0, gridAlignNames...))
+			w.AlignItems = grid.Align(a.Enum("align-items", 0, gridAlignNames...))
+			return w, nil
+		},
+		LayoutData: func(a *Attrs) interface{} {
+			return grid.LayoutData{
+				Row:        a.Int("row", 0),
+				Column:     a.Int("column", 0),
+				RowSpan:    a.Int("row-span", 0),
+				ColumnSpan: a.Int("column-span", 0),
+				Justify:    grid.Align(a.Enum("justify", 0, gridAlignNames...)),
+				Align:      grid.Align(a.Enum("align", 0, gridAlignNames...)),
+			}
+		},
+	},
+
+	"Stack": {
+		New: func(a *Attrs, children []node.Node) (node.Node, error) {
+			return widget.NewStack(children...), nil
+		},
+		LayoutData: func(a *Attrs) interface{} {
+			return widget.StackLayoutData{
+				HAlign: widget.Alignment(a.Enum("h-align", 0, stackAlignNames...)),
+				VAlign: widget.Alignment(a.Enum("v-align", 0, stackAlignNames...)),
+				Top:    a.Value("top", unit.Value{}),
+				Right:  a.Value("right", unit.Value{}),
+				Bottom: a.Value("bottom", unit.Value{}),
+				Left:   a.Value("left", unit.Value{}),
+			}
+		},
+	},
+
+	"Absolute": {
+		New: func(a *Attrs, children []node.Node) (node.Node, error) {
+			return widget.NewAbsolute(children...), nil
+		},
+		LayoutData: func(a *Attrs) interface{} {
+			return widget.AbsoluteLayoutData{
+				X:      a.Value("x", unit.Value{}),
+				Y:      a.Value("y", unit.Value{}),
+				Width:  a.Value("width", unit.Value{}),
+				Height: a.Value("height", unit.Value{}),
+			}
+		},
+	},
+
+	"Padder": shell(func(a *Attrs, inner node.Node) node.Node {
+		axis := widget.Axis(a.Enum("axis", int(widget.AxisBoth), axisNames...))
+		return widget.NewPadder(axis, a.Value("margin", unit.Value{}), inner)
+	}),
+	"Sizer": shell(func(a *Attrs, inner node.Node) node.Node {
+		return widget.NewSizer(a.Value("width", unit.Value{}), a.Value("height", unit.Value{}), inner)
+	}),
+	"Uniform": shell(func(a *Attrs, inner node.Node) node.Node {
+		return widget.NewUniform(a.Color("color", nil), inner)
+	}),
+	"Sheet": shell(func(a *Attrs, inner node.Node) node.Node {
+		return widget.NewSheet(inner)
+	}),
+	"Scroller": shell(func(a *Attrs, inner node.Node) node.Node {
+		axis := widget.Axis(a.Enum("axis", int(widget.AxisVertical), axisNames...))
+		return widget.NewScroller(axis, inner)
+	}),
+
+	"Label": leaf(func(a *Attrs) node.Node {
+		w := widget.NewLabel(a.String("text", ""))
+		w.HAlign = widget.Alignment(a.Enum("h-align", 0, stackAlignNames...))
+		w.VAlign = widget.Alignment(a.Enum("v-align", 0, stackAlignNames...))
+		w.Ellipsis = a.Bool("ellipsis", false)
+		return w
+	}),
+	"Text": leaf(func(a *Attrs) node.Node {
+		w := widget.NewText(a.String("text", ""))
+		w.HAlign = widget.Alignment(a.Enum("h-align", 0, stackAlignNames...))
+		w.VAlign = widget.Alignment(a.Enum("v-align", 0, stackAlignNames...))
+		w.MaxLines = a.Int("max-lines", 0)
+		w.Ellipsis = a.Bool("ellipsis", false)
+		if a.Bool("selectable", false) {
+			w.SetSelectable(true)
+		}
+		return w
+	}),
+	"TextField": leaf(func(a *Attrs) node.Node {
+		return widget.NewTextField(a.String("text", ""))
+	}),
+	"TextArea": leaf(func(a *Attrs) node.Node {
+		return widget.NewTextArea(a.String("text", ""))
+	}),
+	"Button": leaf(func(a *Attrs) node.Node {
+		w := widget.NewButton(a.String("text", ""), nil)
+		w.SetDisabled(a.Bool("disabled", false))
+		return w
+	}),
+	"Checkbox": leaf(func(a *Attrs) node.Node {
+		w := widget.NewCheckbox(a.String("text", ""), a.Bool("checked", false))
+		w.SetDisabled(a.Bool("disabled", false))
+		return w
+	}),
+	"Radio": leaf(func(a *Attrs) node.Node {
+		var g *widget.RadioGroup
+		if name, ok := a.lookup("group"); ok {
+			g = a.Tree().RadioGroup(name)
+		}
+		w := widget.NewRadio(a.String("text", ""), g)
+		if a.Bool("selected", false) {
+			w.Group().Select(w)
+		}
+		w.SetDisabled(a.Bool("disabled", false))
+		return w
+	}),
+	"Switch": leaf(func(a *Attrs) node.Node {
+		w := widget.NewSwitch(a.Bool("on", false))
+		w.SetDisabled(a.Bool("disabled", false))
+		return w
+	}),
+	"Slider": leaf(func(a *Attrs) node.Node {
+		w := widget.NewSlider(a.Float("min", 0), a.Float("max", 1), 0)
+		w.Step = a.Float("step", 0)
+		w.SetValue(a.Float("value", w.Min))
+		w.SetDisabled(a.Bool("disabled", false))
+		return w
+	}),
+	"Space": leaf(func(a *Attrs) node.Node {
+		return widget.NewSpace()
+	}),
+}
+
+// shell returns a Kind of widget with at most one child.
+func shell(f func(a *Attrs, inner node.Node) node.Node) Kind {
+	return Kind{
+		New: func(a *Attrs, children []node.Node) (node.Node, error) {
+			if len(children) > 1 {
+				return nil, fmt.Errorf("markup: %s: more than one child", a.desc)
+			}
+			var inner node.Node
+			if len(children) == 1 {
+				inner = children[0]
+			}
+			return f(a, inner), nil
+		},
+	}
+}
+
+// leaf returns a Kind of widget with no children.
+func leaf(f func(a *Attrs) node.Node) Kind {
+	return Kind{
+		New: func(a *Attrs, children []node.Node) (node.Node, error) {
+			if len(children) != 0 {
+				return nil, fmt.Errorf("markup: %s: children in a leaf widget", a.desc)
+			}
+			return f(a), nil
+		},
+	}
+}
+
+var (
+	flexAlignNames  = []string{"auto", "start", "end", "center", "baseline", "stretch"}
+	gridAlignNames  = []string{"auto", "start", "end", "center", "stretch"}
+	stackAlignNames = []string{"start", "center", "end", "stretch"}
+)
+
+func flexLayoutData(a *Attrs) interface{} {
+	d := flex.LayoutData{
+		MinSize: flex.Size{
+			Width:  a.Value("min-width", unit.Value{}),
+			Height: a.Value("min-height", unit.Value{}),
+		},
+		Grow:       a.Float("grow", 0),
+		Align:      flex.AlignItem(a.Enum("align", 0, flexAlignNames...)),
+		Order:      a.Int("order", 0),
+		BreakAfter: a.Bool("break-after", false),
+	}
+	if a.Has("max-width") || a.Has("max-height") {
+		d.MaxSize = &flex.Size{
+			Width:  a.Value("max-width", unit.Value{}),
+			Height: a.Value("max-height", unit.Value{}),
+		}
+	}
+	if a.Has("shrink") {
+		shrink := a.Float("shrink", 1)
+		d.Shrink = &shrink
+	}
+	switch v, _ := a.lookup("basis"); {
+	case v == "" || v == "auto":
+	case strings.HasSuffix(v, "%"):
+		d.Basis = flex.Percentage
+		if _, err := fmt.Sscanf(v, "%g%%", &d.BasisPercent); err != nil {
+			a.invalid("basis", v, "auto, a length or a percentage")
+		}
+	default:
+		d.Basis = flex.Definite
+		d.BasisSize = a.Value("basis", unit.Value{})
+	}
+	return d
+}
+
+// tracks returns the value of an attribute that is a list of grid tracks.
+func tracks(a *Attrs, key string) []grid.Track {
+	v, ok := a.lookup(key)
+	if !ok {
+		return nil
+	}
+	var ts []grid.Track
+	for _, f := range strings.Fields(v) {
+		t, ok := parseTrack(f)
+		if !ok {
+			a.invalid(key, v, "a list of auto, lengths, fractions or minmax(min,max)")
+			return nil
+		}
+		ts = append(ts, t)
+	}
+	return ts
+}
+
+func parseTrack(s string) (grid.Track, bool) {
+	if strings.HasPrefix(s, "minmax(") && strings.HasSuffix(s, ")") {
+		args := strings.Split(s[len("minmax("):len(s)-1], ",")
+		if len(args) != 2 {
+			return grid.Track{}, false
+		}
+		min, ok0 := parseTrackSize(args[0])
+		max, ok1 := parseTrackSize(args[1])
+		return grid.MinMax(min, max), ok0 && ok1 && min.Kind != grid.SizeFr
+	}
+	sz, ok := parseTrackSize(s)
+	switch sz.Kind {
+	case grid.SizeFixed:
+		return grid.Fixed(sz.Length), ok
+	case grid.SizeFr:
+		return grid.Fr(sz.Fr), ok
+	}
+	return grid.Auto(), ok
+}
+
+func parseTrackSize(s string) (grid.Size, bool) {
+	switch {
+	case s == "auto":
+		return grid.Size{}, true
+	case strings.HasSuffix(s, "fr"):
+		var fr float64
+		if _, err := fmt.Sscanf(s, "%gfr", &fr); err != nil {
+			return grid.Size{}, false
+		}
+		return grid.Fraction(fr), true
+	}
+	v, ok := parseValue(s)
+	return grid.Length(v), ok
+}
@@ -0,0 +1,144 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package markup
+
+import (
+	"image"
+	"os"
+	"time"
+
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/mobile/event/lifecycle"
+)
+
+// TODO: watch the file with the operating system's file change notifications,
+// instead of polling it.
+
+// DefaultLiveInterval is the default interval between a Live widget's checks
+// for a changed file.
+const DefaultLiveInterval = time.Second
+
+// Live is a shell widget that shows the widget tree described by a file, and
+// rebuilds that tree whenever the file changes, so that a layout can be
+// tweaked while the program is running.
+//
+// The file is checked for changes, by its modification time and size, when
+// the Live widget is painted, at most once per Interval. The widget asks its
+// node.Waker to paint it again after each Interval, so that the checks
+// continue while nothing else in the window changes.
+type Live struct {
+	node.ShellEmbed
+
+	// Interval is the interval between checks for a changed file. Zero means
+	// DefaultLiveInterval.
+	Interval time.Duration
+
+	// OnLoad, if non-nil, is called with every Tree built from the file,
+	// including the first one, before the Tree is shown. It typically wires
+	// the Tree's named widgets to the program, such as by setting a Button's
+	// OnClick function. If it returns an error, the Tree is not shown.
+	OnLoad func(t *Tree) error
+
+	// OnError, if non-nil, is called when reloading the changed file fails,
+	// in which case the previous Tree is still shown.
+	OnError func(err error)
+
+	registry *Registry
+	filename string
+	modTime  time.Time
+	size     int64
+	tree     *Tree
+	stage    lifecycle.Stage
+	next     time.Time
+}
+
+// NewLive returns a new Live widget that shows the widget tree described by
+// the named file, using r's Kinds. A nil r means DefaultRegistry. onLoad is
+// the Live widget's OnLoad field.
+//
+// It returns an error if the file can not be loaded.
+func NewLive(r *Registry, filename string, onLoad func(t *Tree) error) (*Live, error) {
+	if r == nil {
+		r = DefaultRegistry
+	}
+	w := &Live{
+		OnLoad:   onLoad,
+		registry: r,
+		filename: filename,
+	}
+	w.Wrapper = w
+	if _, err := w.Reload(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Tree returns the Tree that is shown.
+func (w *Live) Tree() *Tree { return w.tree }
+
+// Reload loads the file, and shows its Tree instead of the previous one. It
+// returns whether the file changed since it was last loaded. If it changed
+// but could not be loaded, the previous Tree is still shown.
+func (w *Live) Reload() (changed bool, err error) {
+	fi, err := os.Stat(w.filename)
+	if err != nil {
+		return false, err
+	}
+	if w.tree != nil && fi.ModTime().Equal(w.modTime) && fi.Size() == w.size {
+		return false, nil
+	}
+	w.modTime, w.size = fi.ModTime(), fi.Size()
+
+	t, err := w.registry.LoadFile(w.filename)
+	if err != nil {
+		return true, err
+	}
+	if w.OnLoad != nil {
+		if err := w.OnLoad(t); err != nil {
+			return true, err
+		}
+	}
+
+	if c := w.FirstChild; c != nil {
+		// Let the previous tree release any resources, such as a Sheet's
+		// buffers.
+		c.Wrapper.OnLifecycleEvent(lifecycle.Event{From: w.stage, To: lifecycle.StageDead})
+		w.Remove(c.Wrapper)
+	}
+	w.tree = t
+	w.Insert(t.Root, nil)
+	if w.stage != lifecycle.StageDead {
+		t.Root.OnLifecycleEvent(lifecycle.Event{From: lifecycle.StageDead, To: w.stage})
+	}
+	w.Mark(node.MarkNeedsMeasureLayout | node.MarkNeedsPaint)
+	return true, nil
+}
+
+func (w *Live) OnLifecycleEvent(e lifecycle.Event) {
+	w.stage = e.To
+	w.ShellEmbed.OnLifecycleEvent(e)
+}
+
+func (w *Live) Paint(ctx *node.PaintContext, origin image.Point) error {
+	if err := w.ShellEmbed.Paint(ctx, origin); err != nil {
+		return err
+	}
+	// Check for changes after painting, as a new Tree needs to be laid out
+	// before it is painted.
+	if now := time.Now(); !now.Before(w.next) {
+		if _, err := w.Reload(); err != nil && w.OnError != nil {
+			w.OnError(err)
+		}
+		interval := w.Interval
+		if interval <= 0 {
+			interval = DefaultLiveInterval
+		}
+		w.next = now.Add(interval)
+		if ctx.Waker != nil {
+			ctx.Waker.WakeAt(w.next, w, node.MarkNeedsPaint)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package markup builds widget trees from text descriptions, so that a
+// program's layout can be changed without recompiling it.
+//
+// A description is a tree of elements, in JSON or in a small XML dialect. Each
+// element has a type, such as "Flex" or "Label", that names a Kind of widget
+// in a Registry, optional attributes that configure the widget, and optional
+// layout attributes that give the widget's LayoutData, which is interpreted
+// by its parent. An element may also have a name, for finding its widget
+// after the tree is built, such as to set a button's OnClick function.
+//
+// In JSON, an element is an object. Its "type", "name", "layout" and
+// "children" keys are special, and any other keys are attributes:
+//
+//	{
+//		"type": "Flex", "direction": "column",
+//		"children": [
+//			{"type": "Label", "text": "Name:"},
+//			{"type": "TextField", "name": "name", "layout": {"grow": 1}},
+//			{"type": "Button", "name": "ok", "text": "OK"}
+//		]
+//	}
+//
+// In XML, an element's type is its tag, layout attributes have a "layout."
+// prefix, and any non-blank character data is the "text" attribute:
+//
+//	<Flex direction="column">
+//		<Label>Name:</Label>
+//		<TextField name="name" layout.grow="1"/>
+//		<Button name="ok">OK</Button>
+//	</Flex>
+//
+// Attribute values are strings, numbers or booleans. Lengths, such as a
+// Padder's margin, are numbers with a unit suffix, such as "4dp" or "1.5em",
+// and a number without a suffix is in pixels. Colors are either the name of a
+// theme palette color, such as "accent", or hexadecimal, such as "#ff8000".
+//
+// The built-in Kinds are documented by NewRegistry.
+package markup // import "golang.org/x/exp/shiny/widget/markup"
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Element is an element of a parsed description.
+type Element struct {
+	// Type is the name of the element's Kind, such as "Label".
+	Type string
+
+	// Name, if non-empty, identifies the element's widget in the Tree.
+	Name string
+
+	// Attrs and Layout are the element's attributes and layout attributes.
+	Attrs  map[string]string
+	Layout map[string]string
+
+	Children []*Element
+}
+
+// Parse parses a description in either format, JSON or XML, depending on
+// whether its first non-blank byte is '{' or '<'.
+func Parse(data []byte) (*Element, error) {
+	switch b := bytes.TrimSpace(data); {
+	case len(b) > 0 && b[0] == '{':
+		return ParseJSON(data)
+	case len(b) > 0 && b[0] == '<':
+		return ParseXML(data)
+	}
+	return nil, errors.New("markup: unknown format")
+}
+
+// ParseJSON parses a description in JSON.
+func ParseJSON(data []byte) (*Element, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, fmt.Errorf("markup: %v", err)
+	}
+	if _, err := d.Token(); err != io.EOF {
+		return nil, errors.New("markup: extra data after the root element")
+	}
+	return jsonElement(v)
+}
+
+func jsonElement(v interface{}) (*Element, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("markup: element is a %s, not an object", jsonKind(v))
+	}
+	e := &Element{}
+	typ, ok := m["type"].(string)
+	if !ok || typ == "" {
+		return nil, errors.New("markup: element has no type")
+	}
+	e.Type = typ
+	for k, x := range m {
+		var err error
+		switch k {
+		case "type":
+		case "name":
+			if e.Name, ok = x.(string); !ok {
+				err = errors.New("name is not a string")
+			}
+		case "layout":
+			lm, ok := x.(map[string]interface{})
+			if !ok {
+				err = errors.New("layout is not an object")
+				break
+			}
+			e.Layout = map[string]string{}
+			for lk, lx := range lm {
+				if e.Layout[lk], err = jsonScalar(lk, lx); err != nil {
+					break
+				}
+			}
+		case "children":
+			cs, ok := x.([]interface{})
+			if !ok {
+				err = errors.New("children is not an array")
+				break
+			}
+			for _, cx := range cs {
+				c, err := jsonElement(cx)
+				if err != nil {
+					return nil, err
+				}
+				e.Children = append(e.Children, c)
+			}
+		default:
+			if e.Attrs == nil {
+				e.Attrs = map[string]string{}
+			}
+			e.Attrs[k], err = jsonScalar(k, x)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("markup: %s: %v", e.Type, err)
+		}
+	}
+	return e, nil
+}
+
+func jsonScalar(key string, v interface{}) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		if v {
+			return "true", nil
+		}
+		return "false", nil
+	}
+	return "", fmt.Errorf("attribute %s is a %s, not a string, number or boolean", key, jsonKind(v))
+}
+
+func jsonKind(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// layoutPrefix is the prefix of an XML element's layout attributes.
+const layoutPrefix = "layout."
+
+// ParseXML parses a description in XML.
+func ParseXML(data []byte) (*Element, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var (
+		root  *Element
+		stack []*Element
+		texts []string
+	)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("markup: %v", err)
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			if root != nil && len(stack) == 0 {
+				return nil, errors.New("markup: more than one root element")
+			}
+			e := &Element{Type: tok.Name.Local}
+			for _, a := range tok.Attr {
+				switch k := a.Name.Local; {
+				case k == "name" && a.Name.Space == "":
+					e.Name = a.Value
+				case strings.HasPrefix(k, layoutPrefix):
+					if e.Layout == nil {
+						e.Layout = map[string]string{}
+					}
+					e.Layout[k[len(layoutPrefix):]] = a.Value
+				default:
+					if e.Attrs == nil {
+						e.Attrs = map[string]string{}
+					}
+					e.Attrs[k] = a.Value
+				}
+			}
+			if n := len(stack); n > 0 {
+				stack[n-1].Children = append(stack[n-1].Children, e)
+			} else {
+				root = e
+			}
+			stack, texts = append(stack, e), append(texts, "")
+		case xml.EndElement:
+			n := len(stack)
+			e := stack[n-1]
+			if text := strings.TrimSpace(texts[n-1]); text != "" {
+				if _, ok := e.Attrs["text"]; ok {
+					return nil, fmt.Errorf("markup: %s: both a text attribute and text", e.Type)
+				}
+				if e.Attrs == nil {
+					e.Attrs = map[string]string{}
+				}
+				e.Attrs["text"] = text
+			}
+			stack, texts = stack[:n-1], texts[:n-1]
+		case xml.CharData:
+			if n := len(texts); n > 0 {
+				texts[n-1] += string(tok)
+			} else if len(bytes.TrimSpace(tok)) != 0 {
+				return nil, errors.New("markup: text outside of the root element")
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.New("markup: no root element")
+	}
+	return root, nil
+}
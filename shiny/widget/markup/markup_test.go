@@ -0,0 +1,312 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package markup
+
+import (
+	"image"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget"
+	"golang.org/x/exp/shiny/widget/flex"
+	"golang.org/x/exp/shiny/widget/grid"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+)
+
+const testJSON = `{
+	"type": "Flex", "direction": "column", "row-gap": "4dp",
+	"children": [
+		{"type": "Label", "text": "Name:"},
+		{"type": "TextField", "name": "name", "layout": {"grow": 1, "basis": "50%", "max-width": "2em"}},
+		{"type": "Padder", "margin": 8, "children": [
+			{"type": "Button", "name": "ok", "text": "OK", "disabled": true}
+		]}
+	]
+}`
+
+const testXML = `<?xml version="1.0"?>
+<!-- The same as testJSON. -->
+<Flex direction="column" row-gap="4dp">
+	<Label>Name:</Label>
+	<TextField name="name" layout.grow="1" layout.basis="50%" layout.max-width="2em"/>
+	<Padder margin="8">
+		<Button name="ok" text="OK" disabled="true"/>
+	</Padder>
+</Flex>
+`
+
+func TestParse(t *testing.T) {
+	want := &Element{
+		Type:  "Flex",
+		Attrs: map[string]string{"direction": "column", "row-gap": "4dp"},
+		Children: []*Element{{
+			Type:  "Label",
+			Attrs: map[string]string{"text": "Name:"},
+		}, {
+			Type:   "TextField",
+			Name:   "name",
+			Layout: map[string]string{"grow": "1", "basis": "50%", "max-width": "2em"},
+		}, {
+			Type:  "Padder",
+			Attrs: map[string]string{"margin": "8"},
+			Children: []*Element{{
+				Type:  "Button",
+				Name:  "ok",
+				Attrs: map[string]string{"text": "OK", "disabled": "true"},
+			}},
+		}},
+	}
+	for _, data := range []string{testJSON, testXML} {
+		got, err := Parse([]byte(data))
+		if err != nil {
+			t.Errorf("Parse: %v\n%s", err, data)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Parse:\n%s\ngot  %+v\nwant %+v", data, got, want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tree, err := Load([]byte(testJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, ok := tree.Root.(*flex.Flex)
+	if !ok {
+		t.Fatalf("root: got %T, want *flex.Flex", tree.Root)
+	}
+	if f.Direction != flex.Column || f.RowGap != unit.DIPs(4) {
+		t.Errorf("flex: got direction %v, row gap %v", f.Direction, f.RowGap)
+	}
+
+	name, ok := tree.Node("name").(*widget.TextField)
+	if !ok {
+		t.Fatalf(`Node("name"): got %T, want *widget.TextField`, tree.Node("name"))
+	}
+	d, _ := name.LayoutData.(flex.LayoutData)
+	if d.Grow != 1 || d.Basis != flex.Percentage || d.BasisPercent != 50 ||
+		d.MaxSize == nil || d.MaxSize.Width != unit.Ems(2) {
+		t.Errorf("layout data: got %+v", d)
+	}
+	if _, ok := f.FirstChild.LayoutData.(flex.LayoutData); !ok {
+		t.Errorf("label layout data: got %T, want flex.LayoutData", f.FirstChild.LayoutData)
+	}
+
+	ok1, _ := tree.Node("ok").(*widget.Button)
+	if ok1 == nil || ok1.Text != "OK" || !ok1.Disabled() {
+		t.Errorf(`Node("ok"): got %+v`, tree.Node("ok"))
+	}
+	if p, _ := ok1.Parent.Wrapper.(*widget.Padder); p == nil || p.Margin != unit.Pixels(8) || p.Axis != widget.AxisBoth {
+		t.Errorf("padder: got %+v", ok1.Parent.Wrapper)
+	}
+	if tree.Node("missing") != nil {
+		t.Errorf(`Node("missing"): got non-nil`)
+	}
+}
+
+func TestLoadGridAndRadios(t *testing.T) {
+	tree, err := Load([]byte(`
+<Grid columns="100dp minmax(auto,1fr) 2fr" column-gap="1mm">
+	<Radio name="a" group="g" selected="true">A</Radio>
+	<Radio name="b" group="g" layout.column="3" layout.row-span="2">B</Radio>
+	<Uniform color="accent"><Space/></Uniform>
+	<Uniform color="#ff000080"/>
+</Grid>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := tree.Root.(*grid.Grid)
+	want := []grid.Track{
+		grid.Fixed(unit.DIPs(100)),
+		grid.MinMax(grid.Size{}, grid.Fraction(1)),
+		grid.Fr(2),
+	}
+	if !reflect.DeepEqual(g.Columns, want) || g.ColumnGap != unit.Millimetres(1) {
+		t.Errorf("grid: got columns %v, gap %v", g.Columns, g.ColumnGap)
+	}
+	a, b := tree.Node("a").(*widget.Radio), tree.Node("b").(*widget.Radio)
+	if a.Group() != b.Group() || a.Group() != tree.RadioGroup("g") || !a.Selected() {
+		t.Errorf("radios: not in the same group, or a not selected")
+	}
+	if d := b.LayoutData.(grid.LayoutData); d.Column != 3 || d.RowSpan != 2 {
+		t.Errorf("b layout data: got %+v", d)
+	}
+	u := b.NextSibling.Wrapper.(*widget.Uniform)
+	if u.ThemeColor != theme.Accent || u.FirstChild == nil {
+		t.Errorf("uniform: got %+v", u)
+	}
+}
+
+func TestLoadText(t *testing.T) {
+	tree, err := Load([]byte(`
+<Flow axis="vertical">
+	<Label name="l" h-align="end" ellipsis="true">Title</Label>
+	<Text name="t" v-align="center" max-lines="3" selectable="true">Body</Text>
+</Flow>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := tree.Node("l").(*widget.Label)
+	if l.Text != "Title" || l.HAlign != widget.AlignEnd || l.VAlign != widget.AlignStart || !l.Ellipsis {
+		t.Errorf("label: got %+v", l)
+	}
+	x := tree.Node("t").(*widget.Text)
+	if x.VAlign != widget.AlignCenter || x.MaxLines != 3 || x.Ellipsis || !x.Selectable() {
+		t.Errorf("text: got %+v", x)
+	}
+}
+
+func TestLoadStack(t *testing.T) {
+	tree, err := Load([]byte(`
+<Stack>
+	<Label name="a" layout.h-align="end" layout.top="2" layout.left="3">A</Label>
+	<Label name="b">B</Label>
+</Stack>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := tree.Node("a").Wrappee()
+	want := widget.StackLayoutData{
+		HAlign: widget.AlignEnd,
+		Top:    unit.Pixels(2),
+		Left:   unit.Pixels(3),
+	}
+	if d, ok := a.LayoutData.(widget.StackLayoutData); !ok || d != want {
+		t.Errorf("a: got %#v, want %#v", a.LayoutData, want)
+	}
+	b := tree.Node("b").Wrappee()
+	if d, ok := b.LayoutData.(widget.StackLayoutData); !ok || d != (widget.StackLayoutData{}) {
+		t.Errorf("b: got %#v", b.LayoutData)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	testCases := []struct {
+		data, want string
+	}{
+		{`{"type": "Nope"}`, "Nope: unknown type"},
+		{`{"type": "Label", "txet": "x"}`, "Label: unknown attribute txet"},
+		{`{"type": "Flex", "direction": "up"}`, `invalid direction "up"`},
+		{`{"type": "Label", "text": ["x"]}`, "attribute text is a array"},
+		{`<Flow><Label layout.grow="1"/></Flow>`, "unknown attribute grow"},
+		{`<Sheet><Label/><Label/></Sheet>`, "more than one child"},
+		{`<Label><Label/></Label>`, "children in a leaf widget"},
+		{`<Stack><Label layout.top="x"/></Stack>`, `invalid top "x"`},
+		{`<Sheet><Label layout.top="1"/></Sheet>`, "layout attributes in a Sheet"},
+		{`<Flow><Label name="x"/><Label name="x"/></Flow>`, `Label "x": duplicate name`},
+		{`<Label/><Label/>`, "more than one root element"},
+		{`[]`, "unknown format"},
+	}
+	for _, tc := range testCases {
+		_, err := Load([]byte(tc.data))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: got error %v, want %q", tc.data, err, tc.want)
+		}
+	}
+}
+
+type testWidget struct {
+	node.LeafEmbed
+	label string
+}
+
+func TestRegister(t *testing.T) {
+	r := NewRegistry()
+	r.Register("Test", Kind{
+		New: func(a *Attrs, children []node.Node) (node.Node, error) {
+			w := &testWidget{label: a.String("label", "default")}
+			w.Wrapper = w
+			return w, nil
+		},
+	})
+	tree, err := r.Load([]byte(`<Flow><Test name="t" label="hi"/></Flow>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w, _ := tree.Node("t").(*testWidget); w == nil || w.label != "hi" {
+		t.Errorf("got %+v", tree.Node("t"))
+	}
+	if _, err := Load([]byte(`<Test/>`)); err == nil {
+		t.Errorf("DefaultRegistry: got nil error for an unregistered type")
+	}
+}
+
+type testWaker struct {
+	t time.Time
+}
+
+func (w *testWaker) WakeAt(t time.Time, n node.Node, m node.Marks) { w.t = t }
+
+func TestLive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "markup-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "ui.xml")
+	write := func(s string, modTime time.Time) {
+		if err := ioutil.WriteFile(filename, []byte(s), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(filename, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t0 := time.Now().Add(-time.Hour)
+	write(`<Label name="l">one</Label>`, t0)
+
+	var loaded []string
+	w, err := NewLive(nil, filename, func(tree *Tree) error {
+		loaded = append(loaded, tree.Node("l").(*widget.Label).Text)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var errs []error
+	w.OnError = func(err error) { errs = append(errs, err) }
+	w.Interval = time.Hour
+	waker := &testWaker{}
+	ctx := &node.PaintContext{Waker: waker}
+
+	paint := func() {
+		w.next = time.Time{}
+		if err := w.Paint(ctx, image.Point{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	paint()
+	if waker.t.IsZero() {
+		t.Errorf("Paint did not ask to be woken")
+	}
+
+	write(`<Label name="l">two</Label>`, t0.Add(time.Minute))
+	paint()
+	if got := w.FirstChild.Wrapper.(*widget.Label).Text; got != "two" || w.Tree().Root != w.FirstChild.Wrapper {
+		t.Errorf("after reloading: got %q", got)
+	}
+	if !w.Marks.NeedsMeasureLayout() {
+		t.Errorf("after reloading: not marked as needing layout")
+	}
+
+	write(`<Label name="l">three`, t0.Add(2*time.Minute))
+	paint()
+	paint()
+	if got := w.FirstChild.Wrapper.(*widget.Label).Text; got != "two" || len(errs) != 1 {
+		t.Errorf("after a bad reload: got %q, %d errors", got, len(errs))
+	}
+	if want := []string{"one", "two"}; !reflect.DeepEqual(loaded, want) {
+		t.Errorf("loaded: got %q, want %q", loaded, want)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package markup
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"golang.org/x/exp/shiny/widget"
+	"golang.org/x/exp/shiny/widget/node"
+)
+
+// Kind is a kind of widget that elements can describe.
+type Kind struct {
+	// New returns a new widget for an element with the given attributes and
+	// children's widgets.
+	New func(a *Attrs, children []node.Node) (node.Node, error)
+
+	// LayoutData, if non-nil, returns the LayoutData of a child of this Kind
+	// of widget, from the child's layout attributes. If it is nil, this Kind
+	// of widget's children can not have layout attributes.
+	LayoutData func(a *Attrs) interface{}
+}
+
+// Registry maps element types to Kinds of widgets.
+type Registry struct {
+	kinds map[string]Kind
+}
+
+// DefaultRegistry is the Registry used by the Load and LoadFile functions.
+var DefaultRegistry = NewRegistry()
+
+// Register adds a Kind of widget to r, replacing any Kind with the same type
+// name.
+func (r *Registry) Register(typ string, k Kind) {
+	r.kinds[typ] = k
+}
+
+// Tree is a widget tree built from a description.
+type Tree struct {
+	// Root is the widget for the description's root element.
+	Root node.Node
+
+	names  map[string]node.Node
+	groups map[string]*widget.RadioGroup
+}
+
+// Node returns the widget for the element with the given name, or nil if
+// there is no such element.
+func (t *Tree) Node(name string) node.Node {
+	return t.names[name]
+}
+
+// RadioGroup returns the RadioGroup with the given name, for the Radio
+// elements whose group attribute is that name. It is created if necessary.
+func (t *Tree) RadioGroup(name string) *widget.RadioGroup {
+	g := t.groups[name]
+	if g == nil {
+		if t.groups == nil {
+			t.groups = map[string]*widget.RadioGroup{}
+		}
+		g = &widget.RadioGroup{}
+		t.groups[name] = g
+	}
+	return g
+}
+
+// Build returns the widget tree for the description rooted at e.
+func (r *Registry) Build(e *Element) (*Tree, error) {
+	t := &Tree{names: map[string]node.Node{}}
+	if len(e.Layout) != 0 {
+		return nil, fmt.Errorf("markup: %s: layout attributes for the root element", describe(e))
+	}
+	n, err := r.build(t, e)
+	if err != nil {
+		return nil, err
+	}
+	t.Root = n
+	return t, nil
+}
+
+func (r *Registry) build(t *Tree, e *Element) (node.Node, error) {
+	k, ok := r.kinds[e.Type]
+	if !ok {
+		return nil, fmt.Errorf("markup: %s: unknown type", describe(e))
+	}
+
+	var children []node.Node
+	for _, c := range e.Children {
+		n, err := r.build(t, c)
+		if err != nil {
+			return nil, err
+		}
+		if len(c.Layout) != 0 {
+			if k.LayoutData == nil {
+				return nil, fmt.Errorf("markup: %s: layout attributes in a %s", describe(c), e.Type)
+			}
+			a := newAttrs(describe(c)+" layout", c.Layout, t)
+			n.Wrappee().LayoutData = k.LayoutData(a)
+			if err := a.check(); err != nil {
+				return nil, err
+			}
+		} else if k.LayoutData != nil {
+			// Give every child the same type of LayoutData, so that a
+			// program can modify it without checking its type.
+			n.Wrappee().LayoutData = k.LayoutData(newAttrs("", nil, t))
+		}
+		children = append(children, n)
+	}
+
+	a := newAttrs(describe(e), e.Attrs, t)
+	n, err := k.New(a, children)
+	if err == nil {
+		err = a.check()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if e.Name != "" {
+		if _, ok := t.names[e.Name]; ok {
+			return nil, fmt.Errorf("markup: %s: duplicate name", describe(e))
+		}
+		t.names[e.Name] = n
+	}
+	return n, nil
+}
+
+// describe describes an element for error messages.
+func describe(e *Element) string {
+	if e.Name != "" {
+		return fmt.Sprintf("%s %q", e.Type, e.Name)
+	}
+	return e.Type
+}
+
+// Load parses a description, in either format, and builds its widget tree.
+func (r *Registry) Load(data []byte) (*Tree, error) {
+	e, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return r.Build(e)
+}
+
+// LoadFile is like Load but reads the description from the named file.
+func (r *Registry) LoadFile(filename string) (*Tree, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return r.Load(data)
+}
+
+// Load is like DefaultRegistry.Load.
+func Load(data []byte) (*Tree, error) { return DefaultRegistry.Load(data) }
+
+// LoadFile is like DefaultRegistry.LoadFile.
+func LoadFile(filename string) (*Tree, error) { return DefaultRegistry.LoadFile(filename) }
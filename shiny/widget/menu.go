@@ -0,0 +1,289 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/draw"
+
+	"golang.org/x/exp/shiny/gesture"
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/mouse"
+)
+
+// TODO: separators, sub-menus, check marks and keyboard shortcut hints.
+
+// MenuItem is an entry in a Menu.
+type MenuItem struct {
+	Text string
+
+	// OnSelect, if non-nil, is called when the item is selected, after the
+	// menu is dismissed.
+	OnSelect func()
+
+	// Disabled is whether the item cannot be selected.
+	Disabled bool
+}
+
+// Menu is a leaf widget that shows a list of items in a Popup, such as a
+// context menu or a drop-down menu. An item is selected by a tap or, since the
+// menu takes the keyboard focus when it is shown, by the arrow keys and the
+// space bar or the enter key. Selecting an item dismisses the menu.
+type Menu struct {
+	node.LeafEmbed
+	Items []MenuItem
+
+	// highlighted is the index of the highlighted item, or -1.
+	highlighted int
+
+	// theme is the theme from the most recent Layout call, used to map mouse
+	// positions to items.
+	theme *theme.Theme
+
+	popup *Popup
+}
+
+// NewMenu returns a new Menu widget.
+func NewMenu(items ...MenuItem) *Menu {
+	w := &Menu{
+		Items:       items,
+		highlighted: -1,
+	}
+	w.Wrapper = w
+	w.Focusable = true
+	w.popup = NewPopup(w)
+	return w
+}
+
+// ShowAt shows the menu below, or if there is no room, above the anchor node,
+// which must be in a widget tree that has an Overlay.
+func (w *Menu) ShowAt(anchor node.Node) {
+	w.popup.Anchor = anchor
+	w.popup.Placement = PlaceBelow
+	w.show(anchor)
+}
+
+// ShowAtPoint shows the menu at p, in the coordinate space of the Overlay of
+// n's widget tree. For example, a context menu is shown at the position of
+// the mouse event that asked for it.
+func (w *Menu) ShowAtPoint(n node.Node, p image.Point) {
+	w.popup.Anchor = nil
+	w.popup.AnchorRect = image.Rectangle{p, p}
+	w.popup.Placement = PlaceBelow
+	w.show(n)
+}
+
+func (w *Menu) show(n node.Node) {
+	o := FindOverlay(n)
+	if o == nil {
+		panic("widget: Menu shown for a node with no Overlay ancestor")
+	}
+	w.highlighted = -1
+	o.Show(w.popup)
+}
+
+// Shown returns whether the menu is shown.
+func (w *Menu) Shown() bool { return w.popup.Shown() }
+
+// Dismiss dismisses the menu, if it is shown.
+func (w *Menu) Dismiss() { w.popup.Dismiss() }
+
+// padding returns the horizontal and vertical padding around each item's
+// text.
+func (w *Menu) padding(t *theme.Theme) image.Point {
+	return image.Point{
+		t.Pixels(unit.Ems(1)).Ceil(),
+		t.Pixels(unit.Ems(0.25)).Ceil(),
+	}
+}
+
+// itemHeight returns the height of each item.
+func (w *Menu) itemHeight(t *theme.Theme) int {
+	face := t.AcquireFontFace(theme.FontFaceOptions{})
+	defer t.ReleaseFontFace(theme.FontFaceOptions{}, face)
+	m := face.Metrics()
+	return m.Ascent.Ceil() + m.Descent.Ceil() + 2*w.padding(t).Y
+}
+
+func (w *Menu) Measure(t *theme.Theme, widthHint, heightHint int) {
+	face := t.AcquireFontFace(theme.FontFaceOptions{})
+	defer t.ReleaseFontFace(theme.FontFaceOptions{}, face)
+
+	width := 0
+	for _, item := range w.Items {
+		if x := font.MeasureString(face, item.Text).Ceil(); width < x {
+			width = x
+		}
+	}
+	w.MeasuredSize.X = width + 2*w.padding(t).X
+	w.MeasuredSize.Y = len(w.Items) * w.itemHeight(t)
+}
+
+func (w *Menu) Layout(t *theme.Theme) {
+	w.theme = t
+}
+
+func (w *Menu) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
+	w.Marks.UnmarkNeedsPaintBase()
+	r := w.Rect.Add(origin)
+	dst := ctx.Dst.SubImage(r).(*image.RGBA)
+	if dst.Bounds().Empty() {
+		return nil
+	}
+
+	pal := ctx.Theme.GetPalette()
+	face := ctx.Theme.AcquireFontFace(theme.FontFaceOptions{})
+	defer ctx.Theme.ReleaseFontFace(theme.FontFaceOptions{}, face)
+	m := face.Metrics()
+	padding := w.padding(ctx.Theme)
+	h := w.itemHeight(ctx.Theme)
+
+	draw.Draw(dst, r, pal.Surface(), image.Point{}, draw.Src)
+	d := font.Drawer{
+		Dst:  dst,
+		Face: face,
+	}
+	for i, item := range w.Items {
+		y := r.Min.Y + i*h
+		d.Src = pal.OnSurface()
+		switch {
+		case item.Disabled:
+			d.Src = pal.Disabled()
+		case i == w.highlighted:
+			draw.Draw(dst, image.Rect(r.Min.X, y, r.Max.X, y+h), pal.Accent(), image.Point{}, draw.Src)
+			d.Src = pal.Background()
+		}
+		d.Dot = fixed.P(r.Min.X+padding.X, y+padding.Y+m.Ascent.Ceil())
+		d.DrawString(item.Text)
+	}
+	return nil
+}
+
+// itemAt returns the index of the item at p, in the menu's parent's coordinate
+// space, or -1.
+func (w *Menu) itemAt(t *theme.Theme, p image.Point) int {
+	h := w.itemHeight(t)
+	if !p.In(w.Rect) || h <= 0 {
+		return -1
+	}
+	if i := (p.Y - w.Rect.Min.Y) / h; i < len(w.Items) {
+		return i
+	}
+	return -1
+}
+
+func (w *Menu) setHighlighted(i int) {
+	if w.highlighted != i {
+		w.highlighted = i
+		w.Mark(node.MarkNeedsPaintBase)
+	}
+}
+
+// move highlights the next enabled item after the i'th item, searching
+// forwards if delta is +1, or backwards if delta is -1.
+func (w *Menu) move(i, delta int) {
+	n := len(w.Items)
+	for range w.Items {
+		i += delta
+		if i < 0 {
+			i = n - 1
+		} else if i >= n {
+			i = 0
+		}
+		if !w.Items[i].Disabled {
+			w.setHighlighted(i)
+			return
+		}
+	}
+}
+
+// selectItem dismisses the menu and then calls the i'th item's OnSelect.
+func (w *Menu) selectItem(i int) {
+	if i < 0 || i >= len(w.Items) || w.Items[i].Disabled {
+		return
+	}
+	w.Dismiss()
+	if f := w.Items[i].OnSelect; f != nil {
+		f()
+	}
+}
+
+func (w *Menu) OnInputEvent(e interface{}, origin image.Point) node.EventHandled {
+	t := w.theme
+	switch e := e.(type) {
+	case node.FocusEvent:
+		return node.Handled
+
+	case node.PointerEvent:
+		if !e.Entered {
+			w.setHighlighted(-1)
+		}
+		return node.Handled
+
+	case mouse.Event:
+		i := w.itemAt(t, image.Point{int(e.X), int(e.Y)}.Sub(origin))
+		if i >= 0 && w.Items[i].Disabled {
+			i = -1
+		}
+		w.setHighlighted(i)
+		return node.Handled
+
+	case gesture.Event:
+		if e.Type == gesture.TypeTap {
+			w.selectItem(w.itemAt(t, image.Point{int(e.CurrentPos.X), int(e.CurrentPos.Y)}.Sub(origin)))
+		}
+		return node.Handled
+
+	case key.Event:
+		if e.Direction == key.DirRelease {
+			break
+		}
+		switch e.Code {
+		case key.CodeUpArrow:
+			w.move(w.highlighted, -1)
+		case key.CodeDownArrow:
+			w.move(w.highlighted, +1)
+		case key.CodeHome:
+			w.move(-1, +1)
+		case key.CodeEnd:
+			w.move(len(w.Items), -1)
+		case key.CodeSpacebar, key.CodeReturnEnter, key.CodeKeypadEnter:
+			w.selectItem(w.highlighted)
+		default:
+			return node.NotHandled
+		}
+		return node.Handled
+	}
+	return node.NotHandled
+}
+
+func (w *Menu) Accessibility() node.Accessibility {
+	a := node.Accessibility{
+		Role:  node.RoleMenu,
+		Items: make([]node.Item, len(w.Items)),
+	}
+	h := w.itemHeight(w.theme)
+	for i, item := range w.Items {
+		x := &a.Items[i]
+		x.Role = node.RoleMenuItem
+		x.Name = item.Text
+		x.Bounds = image.Rect(0, i*h, w.Rect.Dx(), (i+1)*h)
+		switch {
+		case item.Disabled:
+			x.State = node.StateDisabled
+			continue
+		case i == w.highlighted:
+			x.State = node.StateSelected
+		}
+		i := i
+		x.Actions = []node.Action{{Name: node.ActionClick, Do: func() { w.selectItem(i) }}}
+	}
+	return a
+}
@@ -0,0 +1,177 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package node
+
+import (
+	"image"
+)
+
+// Accessibility describes a node to assistive technologies, such as screen
+// readers. It is returned by the Node.Accessibility method.
+//
+// A node's bounds are not part of its Accessibility, as they are given by its
+// Embed.Rect.
+type Accessibility struct {
+	// Role is the kind of user interface element that the node is. A node
+	// whose Role is RoleNone is not exposed to assistive technologies, but its
+	// children are, as if they were children of the node's nearest exposed
+	// ancestor.
+	Role Role
+
+	// Name is the node's human-readable name, such as a button's text.
+	Name string
+
+	// Value is the node's human-readable value, such as a text field's text
+	// or a slider's position.
+	Value string
+
+	// Range, if non-nil, is the numeric range of the node's Value, such as a
+	// slider's.
+	Range *Range
+
+	// State holds the node's states, such as whether it is checked. The
+	// StateFocusable state is also implied by the node's Embed.Focusable
+	// field.
+	State State
+
+	// Actions are what an assistive technology can do with the node, such as
+	// clicking a button. A node that can not currently be acted on, such as a
+	// disabled button, should have no Actions.
+	Actions []Action
+
+	// Items are children that are not nodes, such as a menu's items. They
+	// follow any of the node's exposed children.
+	Items []Item
+}
+
+// Item is a child, in the accessibility tree, that is not a node.
+type Item struct {
+	Accessibility
+
+	// Bounds is the item's position and size, relative to its node's
+	// Embed.Rect.Min.
+	Bounds image.Rectangle
+}
+
+// Range is the numeric range of a node's value.
+type Range struct {
+	Min, Max, Step, Value float64
+}
+
+// Action is something that an assistive technology can do with a node.
+type Action struct {
+	// Name is the action's name, such as ActionClick.
+	Name string
+
+	// Do performs the action. It is called on the same goroutine as the
+	// node's other methods, such as OnInputEvent.
+	Do func()
+}
+
+// Action names for common actions.
+const (
+	ActionClick     = "click"
+	ActionToggle    = "toggle"
+	ActionSelect    = "select"
+	ActionIncrement = "increment"
+	ActionDecrement = "decrement"
+	ActionDismiss   = "dismiss"
+)
+
+// Role is the kind of user interface element that a node is.
+type Role uint8
+
+const (
+	RoleNone Role = iota
+	RoleWindow
+	RoleGroup
+	RoleLabel
+	RoleText
+	RoleTextField
+	RoleButton
+	RoleCheckBox
+	RoleRadioButton
+	RoleSwitch
+	RoleSlider
+	RoleList
+	RoleListItem
+	RoleMenu
+	RoleMenuItem
+	RoleDialog
+	RoleScrollPane
+	RoleImage
+
+	numRoles
+)
+
+var roleNames = [...]string{
+	RoleNone:        "none",
+	RoleWindow:      "window",
+	RoleGroup:       "group",
+	RoleLabel:       "label",
+	RoleText:        "text",
+	RoleTextField:   "text field",
+	RoleButton:      "button",
+	RoleCheckBox:    "check box",
+	RoleRadioButton: "radio button",
+	RoleSwitch:      "switch",
+	RoleSlider:      "slider",
+	RoleList:        "list",
+	RoleListItem:    "list item",
+	RoleMenu:        "menu",
+	RoleMenuItem:    "menu item",
+	RoleDialog:      "dialog",
+	RoleScrollPane:  "scroll pane",
+	RoleImage:       "image",
+}
+
+func (r Role) String() string {
+	if r < numRoles {
+		return roleNames[r]
+	}
+	return "unknown"
+}
+
+// State is a bitfield of a node's accessibility states.
+type State uint32
+
+const (
+	StateFocusable = State(1 << iota)
+	StateFocused
+	StateDisabled
+	StateChecked
+	StateSelected
+	StatePressed
+	StateEditable
+	StateMultiLine
+	StateMultiSelectable
+	StateModal
+
+	numStates = iota
+)
+
+var stateNames = [numStates]string{
+	"focusable",
+	"focused",
+	"disabled",
+	"checked",
+	"selected",
+	"pressed",
+	"editable",
+	"multi-line",
+	"multi-selectable",
+	"modal",
+}
+
+// Names returns the names of the states in s, such as "focused".
+func (s State) Names() []string {
+	var names []string
+	for i, name := range stateNames {
+		if s&(1<<uint(i)) != 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
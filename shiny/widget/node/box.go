@@ -0,0 +1,236 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package node
+
+import (
+	"image"
+	"image/draw"
+
+	"golang.org/x/exp/shiny/imageutil"
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/theme"
+)
+
+// TODO: let a node's margin, border or padding depend on its state, such as
+// being hovered or focused, without its widget having to replace its Box.
+
+// TODO: make the padding box, not just the Rect, receive input events.
+
+// Insets are lengths, one per side, inside or outside of a rectangle.
+type Insets struct {
+	Top, Right, Bottom, Left unit.Value
+}
+
+// UniformInsets returns Insets whose four sides are all v.
+func UniformInsets(v unit.Value) Insets {
+	return Insets{v, v, v, v}
+}
+
+// Pixels returns the insets in pixels. min holds the left and top insets and
+// max holds the right and bottom insets.
+func (i *Insets) Pixels(t *theme.Theme) (min, max image.Point) {
+	min = image.Point{t.Pixels(i.Left).Round(), t.Pixels(i.Top).Round()}
+	max = image.Point{t.Pixels(i.Right).Round(), t.Pixels(i.Bottom).Round()}
+	return min, max
+}
+
+// Border is the border around a node's padding.
+type Border struct {
+	// Width is the border's width on each side.
+	Width Insets
+
+	// Color is the border's color. If nil, the border is not painted, but
+	// still takes up space.
+	Color theme.Color
+
+	// Radius is the radius of the border's outer corners. Its inner corners'
+	// radii are that less the adjacent sides' widths.
+	Radius unit.Value
+}
+
+// Box is a node's box model, as in CSS: the node's Rect is its content box,
+// which is surrounded by its padding, then its border, then its margin.
+//
+// A node's Box is honored by its parent, not by the node itself, so that
+// every widget's Measure, Layout and Paint methods can ignore it. A parent
+// that honors its children's Boxes, such as the ShellEmbed and ContainerEmbed
+// default implementations and the containers in the widget package and its
+// subpackages, measures and lays out each child with the Embed
+// methods MeasureOuter, OuterSize and SetOuterRect, instead of using
+// MeasuredSize and setting Rect directly, and paints each child's background
+// and border with PaintBox before calling the child's PaintBase.
+//
+// Only the Rect receives input events. As with the widget package's Padder,
+// to make the padding clickable, put the node inside of the event handling
+// node, and give the inner node the Box.
+type Box struct {
+	Margin  Insets
+	Border  Border
+	Padding Insets
+
+	// Background, if non-nil, fills the border box: the Rect, its padding
+	// and its border, under the border's Color. If the Border's Radius is
+	// non-zero, it is rounded to match.
+	Background theme.Color
+}
+
+// Insets returns the total width of b's margin, border and padding on each
+// side, in pixels, as for Insets.Pixels. A nil b has no insets.
+func (b *Box) Insets(t *theme.Theme) (min, max image.Point) {
+	if b == nil {
+		return image.Point{}, image.Point{}
+	}
+	min0, max0 := b.Margin.Pixels(t)
+	min1, max1 := b.Border.Width.Pixels(t)
+	min2, max2 := b.Padding.Pixels(t)
+	return min0.Add(min1).Add(min2), max0.Add(max1).Add(max2)
+}
+
+// MeasureOuter calls the node's Measure method, with width and height hints
+// for its outer size: its MeasuredSize plus the insets of its Box. The hints
+// passed on to Measure are reduced by those insets.
+func (m *Embed) MeasureOuter(t *theme.Theme, widthHint, heightHint int) {
+	min, max := m.Box.Insets(t)
+	if widthHint != NoHint {
+		widthHint = shrinkHint(widthHint, min.X+max.X)
+	}
+	if heightHint != NoHint {
+		heightHint = shrinkHint(heightHint, min.Y+max.Y)
+	}
+	m.Wrapper.Measure(t, widthHint, heightHint)
+}
+
+func shrinkHint(hint, inset int) int {
+	if hint -= inset; hint < 0 {
+		return 0
+	}
+	return hint
+}
+
+// OuterSize returns the node's MeasuredSize plus the insets of its Box.
+func (m *Embed) OuterSize(t *theme.Theme) image.Point {
+	min, max := m.Box.Insets(t)
+	return m.MeasuredSize.Add(min).Add(max)
+}
+
+// IntrinsicWidther is an optional interface for a Node whose height depends
+// on its width, such as wrapped text. Containers that size their children to
+// fit their content, such as the flex and grid packages' containers, use it
+// to find how narrow a child can be.
+type IntrinsicWidther interface {
+	// IntrinsicWidths returns the node's minimum and maximum content widths,
+	// excluding its Box: the narrowest that it can be without its content
+	// being cut off, and its natural width.
+	IntrinsicWidths(t *theme.Theme) (min, max int)
+}
+
+// OuterIntrinsicWidths returns the node's minimum and maximum content widths
+// plus the insets of its Box. If the node is not an IntrinsicWidther, both are
+// the width of its OuterSize, so the node must have been measured.
+func (m *Embed) OuterIntrinsicWidths(t *theme.Theme) (min, max int) {
+	iw, ok := m.Wrapper.(IntrinsicWidther)
+	if !ok {
+		x := m.OuterSize(t).X
+		return x, x
+	}
+	min, max = iw.IntrinsicWidths(t)
+	inMin, inMax := m.Box.Insets(t)
+	return min + inMin.X + inMax.X, max + inMin.X + inMax.X
+}
+
+// OuterBaseline returns the node's Baseline, relative to the top of its outer
+// rectangle, or NoBaseline.
+func (m *Embed) OuterBaseline(t *theme.Theme) int {
+	b := m.Wrapper.Baseline(t)
+	if b != NoBaseline {
+		min, _ := m.Box.Insets(t)
+		b += min.Y
+	}
+	return b
+}
+
+// SetOuterRect sets the node's Rect to r less the insets of its Box. If r is
+// too small for those insets, the Rect is empty.
+func (m *Embed) SetOuterRect(t *theme.Theme, r image.Rectangle) {
+	min, max := m.Box.Insets(t)
+	m.Rect = insetRect(r, min, max)
+}
+
+// OuterRect returns the node's Rect plus the insets of its Box, in the same
+// coordinate space as the Rect.
+func (m *Embed) OuterRect(t *theme.Theme) image.Rectangle {
+	min, max := m.Box.Insets(t)
+	return insetRect(m.Rect, min.Mul(-1), max.Mul(-1))
+}
+
+// insetRect returns r with its minimum and maximum points moved inwards by min
+// and max, which may be negative.
+func insetRect(r image.Rectangle, min, max image.Point) image.Rectangle {
+	r.Min = r.Min.Add(min)
+	r.Max = r.Max.Sub(max)
+	if r.Max.X < r.Min.X {
+		r.Max.X = r.Min.X
+	}
+	if r.Max.Y < r.Min.Y {
+		r.Max.Y = r.Min.Y
+	}
+	return r
+}
+
+// PaintBox paints the background and border of the node's Box. As with
+// PaintBase, origin is the node's parent's origin in ctx.Dst's coordinate
+// space.
+func (m *Embed) PaintBox(ctx *PaintBaseContext, origin image.Point) {
+	b := m.Box
+	if b == nil || (b.Background == nil && b.Border.Color == nil) {
+		return
+	}
+	t := ctx.Theme
+	pMin, pMax := b.Padding.Pixels(t)
+	bMin, bMax := b.Border.Width.Pixels(t)
+	inner := insetRect(m.Rect.Add(origin), pMin.Mul(-1), pMax.Mul(-1))
+	outer := insetRect(inner, bMin.Mul(-1), bMax.Mul(-1))
+	radius := t.Pixels(b.Border.Radius).Round()
+	dst := ctx.Dst
+
+	if radius <= 0 {
+		if b.Background != nil {
+			draw.Draw(dst, outer, b.Background.Uniform(t), image.Point{}, draw.Over)
+		}
+		if b.Border.Color != nil {
+			src := b.Border.Color.Uniform(t)
+			for _, r := range borderRects(outer, inner) {
+				draw.Draw(dst, r, src, image.Point{}, draw.Over)
+			}
+		}
+		return
+	}
+
+	// Rounded corners are drawn through masks, which are clipped to dst.
+	clip := outer.Intersect(dst.Bounds())
+	if clip.Empty() {
+		return
+	}
+	if b.Background != nil {
+		mask := imageutil.RoundedRect(outer, radius)
+		draw.DrawMask(dst, clip, b.Background.Uniform(t), image.Point{}, mask, clip.Min, draw.Over)
+	}
+	if b.Border.Color != nil {
+		mask := imageutil.RoundedBorder(outer, inner, radius)
+		draw.DrawMask(dst, clip, b.Border.Color.Uniform(t), image.Point{}, mask, clip.Min, draw.Over)
+	}
+}
+
+// borderRects returns four rectangles that together contain those points
+// between outer and inner, which is inside outer. Like imageutil.Border, the
+// top and bottom rectangles include the corners.
+func borderRects(outer, inner image.Rectangle) [4]image.Rectangle {
+	return [4]image.Rectangle{
+		image.Rect(outer.Min.X, outer.Min.Y, outer.Max.X, inner.Min.Y),
+		image.Rect(outer.Min.X, inner.Min.Y, inner.Min.X, inner.Max.Y),
+		image.Rect(inner.Max.X, inner.Min.Y, outer.Max.X, inner.Max.Y),
+		image.Rect(outer.Min.X, inner.Max.Y, outer.Max.X, outer.Max.Y),
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package node
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/theme"
+)
+
+// sizedLeaf is a leaf node with a fixed natural size and baseline.
+type sizedLeaf struct {
+	LeafEmbed
+	size      image.Point
+	baseline  int
+	widthHint int
+}
+
+func newSizedLeaf(x, y, baseline int) *sizedLeaf {
+	w := &sizedLeaf{size: image.Point{x, y}, baseline: baseline}
+	w.Wrapper = w
+	return w
+}
+
+func (w *sizedLeaf) Measure(t *theme.Theme, widthHint, heightHint int) {
+	w.MeasuredSize, w.widthHint = w.size, widthHint
+}
+
+func (w *sizedLeaf) Baseline(t *theme.Theme) int { return w.baseline }
+
+func TestBoxLayout(t *testing.T) {
+	c := newSizedLeaf(30, 20, 15)
+	c.Box = &Box{
+		Margin:  Insets{Top: unit.Pixels(1), Right: unit.Pixels(2), Bottom: unit.Pixels(3), Left: unit.Pixels(4)},
+		Border:  Border{Width: UniformInsets(unit.Pixels(1))},
+		Padding: Insets{Left: unit.Pixels(10)},
+	}
+	shell := &ShellEmbed{}
+	shell.Wrapper = shell
+	shell.Insert(c, nil)
+
+	shell.Measure(nil, 100, NoHint)
+	if got, want := c.widthHint, 100-18; got != want {
+		t.Errorf("width hint: got %d, want %d", got, want)
+	}
+	if got, want := shell.MeasuredSize, (image.Point{48, 26}); got != want {
+		t.Errorf("MeasuredSize: got %v, want %v", got, want)
+	}
+	if got, want := shell.Baseline(nil), 17; got != want {
+		t.Errorf("Baseline: got %d, want %d", got, want)
+	}
+
+	shell.Rect = image.Rect(50, 50, 150, 100)
+	shell.Layout(nil)
+	if got, want := c.Rect, image.Rect(15, 2, 97, 46); got != want {
+		t.Errorf("Rect: got %v, want %v", got, want)
+	}
+	if got, want := c.OuterRect(nil), image.Rect(0, 0, 100, 50); got != want {
+		t.Errorf("OuterRect: got %v, want %v", got, want)
+	}
+
+	// A Rect too small for the Box is empty.
+	c.SetOuterRect(nil, image.Rect(0, 0, 10, 10))
+	if !c.Rect.Empty() {
+		t.Errorf("small Rect: got %v, want empty", c.Rect)
+	}
+}
+
+func TestBoxPaint(t *testing.T) {
+	var (
+		red  = color.RGBA{0xff, 0x00, 0x00, 0xff}
+		blue = color.RGBA{0x00, 0x00, 0xff, 0xff}
+	)
+	c := newSizedLeaf(0, 0, NoBaseline)
+	c.Rect = image.Rect(10, 10, 30, 20)
+	c.Box = &Box{
+		Margin:     UniformInsets(unit.Pixels(100)),
+		Border:     Border{Width: Insets{Top: unit.Pixels(2)}, Color: theme.StaticColor(red)},
+		Padding:    UniformInsets(unit.Pixels(1)),
+		Background: theme.StaticColor(blue),
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, 50, 50))
+	ctx := &PaintBaseContext{Dst: dst}
+	c.PaintBox(ctx, image.Point{5, 0})
+
+	testCases := []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{20, 6, color.RGBA{}},
+		{20, 7, red},
+		{20, 8, red},
+		{13, 9, color.RGBA{}},
+		{14, 9, blue},
+		{35, 20, blue},
+		{36, 20, color.RGBA{}},
+		{20, 20, blue},
+		{20, 21, color.RGBA{}},
+	}
+	for _, tc := range testCases {
+		if got := dst.RGBAAt(tc.x, tc.y); got != tc.want {
+			t.Errorf("(%d, %d): got %v, want %v", tc.x, tc.y, got, tc.want)
+		}
+	}
+
+	// With rounded corners, the corners are not painted.
+	dst = image.NewRGBA(image.Rect(0, 0, 50, 50))
+	ctx.Dst = dst
+	c.Box.Border.Radius = unit.Pixels(4)
+	c.PaintBox(ctx, image.Point{5, 0})
+	if got := dst.RGBAAt(14, 7); got != (color.RGBA{}) {
+		t.Errorf("rounded corner: got %v, want transparent", got)
+	}
+	if got := dst.RGBAAt(25, 7); got != red {
+		t.Errorf("rounded top: got %v, want %v", got, red)
+	}
+	if got := dst.RGBAAt(25, 15); got != blue {
+		t.Errorf("rounded center: got %v, want %v", got, blue)
+	}
+}
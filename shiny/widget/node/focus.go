@@ -0,0 +1,188 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package node
+
+import (
+	"image"
+
+	"golang.org/x/mobile/event/key"
+)
+
+// FocusEvent is sent to a node, via its OnInputEvent method, when it gains or
+// loses the keyboard focus.
+//
+// Like key events, focus events are sent directly to the node concerned. They
+// are not propagated down the widget tree by the default OnInputEvent
+// implementations of ShellEmbed and ContainerEmbed.
+type FocusEvent struct {
+	// Focused is whether the node gained, as opposed to lost, the focus.
+	Focused bool
+}
+
+// Focus tracks which node in a widget tree has the keyboard focus. There is
+// typically one Focus per window. Its zero value is usable and has no focused
+// node.
+//
+// Only nodes whose Embed.Focusable field is set can be focused.
+type Focus struct {
+	focused *Embed
+}
+
+// Node returns the focused node, or nil.
+func (f *Focus) Node() Node {
+	if f.focused == nil {
+		return nil
+	}
+	return f.focused.Wrapper
+}
+
+// Set gives the focus to n, which may be nil to clear the focus. It sends a
+// FocusEvent to the previously and the newly focused nodes.
+//
+// Setting the focus to a node whose Focusable field is not set clears the
+// focus.
+func (f *Focus) Set(n Node) {
+	var e *Embed
+	if n != nil {
+		e = n.Wrappee()
+		if !e.Focusable {
+			e = nil
+		}
+	}
+	if f.focused == e {
+		return
+	}
+	if old := f.focused; old != nil {
+		f.focused = nil
+		old.Wrapper.OnInputEvent(FocusEvent{Focused: false}, parentOrigin(old))
+	}
+	if e != nil {
+		f.focused = e
+		e.Wrapper.OnInputEvent(FocusEvent{Focused: true}, parentOrigin(e))
+	}
+}
+
+// Validate clears the focus if the focused node is no longer focusable or is
+// no longer in the widget tree rooted at root.
+func (f *Focus) Validate(root Node) {
+	if f.focused == nil {
+		return
+	}
+	if !f.focused.Focusable || !isDescendant(f.focused, root.Wrappee()) {
+		f.Set(nil)
+	}
+}
+
+// Next moves the focus to the next focusable node after the focused one, in
+// tree order, wrapping around at the end. If no node is focused, it focuses
+// the first focusable node.
+func (f *Focus) Next(root Node) {
+	f.move(root, +1)
+}
+
+// Prev moves the focus to the previous focusable node before the focused one,
+// in tree order, wrapping around at the start. If no node is focused, it
+// focuses the last focusable node.
+func (f *Focus) Prev(root Node) {
+	f.move(root, -1)
+}
+
+func (f *Focus) move(root Node, delta int) {
+	var nodes []*Embed
+	walk(root.Wrappee(), func(e *Embed) {
+		if e.Focusable {
+			nodes = append(nodes, e)
+		}
+	})
+	if len(nodes) == 0 {
+		f.Set(nil)
+		return
+	}
+	i := -1
+	for j, e := range nodes {
+		if e == f.focused {
+			i = j
+			break
+		}
+	}
+	switch {
+	case i >= 0:
+		i = (i + delta + len(nodes)) % len(nodes)
+	case delta > 0:
+		i = 0
+	default:
+		i = len(nodes) - 1
+	}
+	f.Set(nodes[i].Wrapper)
+}
+
+// SetAt gives the focus to the deepest focusable node in the tree rooted at
+// root that contains the point p, in root's parent's coordinate space. If no
+// such node contains p, the focus is cleared. Later children have priority
+// over earlier children, as with ContainerEmbed.OnInputEvent.
+//
+// It is typically called when a mouse button is pressed or the screen is
+// touched.
+func (f *Focus) SetAt(root Node, p image.Point) {
+	f.Set(focusableAt(root.Wrappee(), p))
+}
+
+func focusableAt(e *Embed, p image.Point) Node {
+	if !p.In(e.Rect) {
+		return nil
+	}
+	p = p.Sub(e.Rect.Min)
+	for c := e.LastChild; c != nil; c = c.PrevSibling {
+		if n := focusableAt(c, p); n != nil {
+			return n
+		}
+	}
+	if e.Focusable {
+		return e.Wrapper
+	}
+	return nil
+}
+
+// OnKeyEvent delivers a key event to the focused node. If that node does not
+// handle it, the event bubbles up to the node's ancestors, in turn, until one
+// of them handles it. If no node is focused, the event is delivered to root.
+func (f *Focus) OnKeyEvent(root Node, e key.Event) EventHandled {
+	n := f.focused
+	if n == nil {
+		n = root.Wrappee()
+	}
+	for ; n != nil; n = n.Parent {
+		if n.Wrapper.OnInputEvent(e, parentOrigin(n)) == Handled {
+			return Handled
+		}
+	}
+	return NotHandled
+}
+
+// parentOrigin returns the origin of e's parent, with respect to the root of
+// the widget tree.
+func parentOrigin(e *Embed) (origin image.Point) {
+	for p := e.Parent; p != nil; p = p.Parent {
+		origin = origin.Add(p.Rect.Min)
+	}
+	return origin
+}
+
+func isDescendant(e, root *Embed) bool {
+	for ; e != nil; e = e.Parent {
+		if e == root {
+			return true
+		}
+	}
+	return false
+}
+
+// walk calls f for e and its descendants, in tree order.
+func walk(e *Embed, f func(*Embed)) {
+	f(e)
+	for c := e.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, f)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package node
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"testing"
+
+	"golang.org/x/mobile/event/key"
+)
+
+// logLeaf is a leaf node that logs the events it receives.
+type logLeaf struct {
+	LeafEmbed
+	name   string
+	log    *[]string
+	handle bool
+}
+
+func (w *logLeaf) OnInputEvent(e interface{}, origin image.Point) EventHandled {
+	*w.log = append(*w.log, fmt.Sprintf("%s:%v@%v", w.name, e, origin))
+	return EventHandled(w.handle)
+}
+
+type logContainer struct {
+	ContainerEmbed
+	name string
+	log  *[]string
+}
+
+func (w *logContainer) OnInputEvent(e interface{}, origin image.Point) EventHandled {
+	switch e.(type) {
+	case key.Event, PointerEvent:
+		*w.log = append(*w.log, fmt.Sprintf("%s:%v@%v", w.name, e, origin))
+		return Handled
+	}
+	return w.ContainerEmbed.OnInputEvent(e, origin)
+}
+
+func TestFocus(t *testing.T) {
+	var log []string
+	newLeaf := func(name string, r image.Rectangle, focusable bool) *logLeaf {
+		w := &logLeaf{name: name, log: &log}
+		w.Wrapper = w
+		w.Rect = r
+		w.Focusable = focusable
+		return w
+	}
+	root := &logContainer{name: "root", log: &log}
+	root.Wrapper = root
+	root.Rect = image.Rect(0, 0, 100, 100)
+	inner := &logContainer{name: "inner", log: &log}
+	inner.Wrapper = inner
+	inner.Rect = image.Rect(50, 0, 100, 100)
+
+	a := newLeaf("a", image.Rect(0, 0, 50, 50), true)
+	b := newLeaf("b", image.Rect(0, 50, 50, 100), false)
+	c := newLeaf("c", image.Rect(0, 0, 50, 50), true)
+	root.Insert(a, nil)
+	root.Insert(b, nil)
+	root.Insert(inner, nil)
+	inner.Insert(c, nil)
+
+	check := func(desc string, want ...string) {
+		t.Helper()
+		if got, want := strings.Join(log, " "), strings.Join(want, " "); got != want {
+			t.Errorf("%s:\ngot  %s\nwant %s", desc, got, want)
+		}
+		log = nil
+	}
+
+	f := &Focus{}
+	f.Next(root)
+	check("Next", "a:{true}@(0,0)")
+	f.Next(root)
+	check("Next", "a:{false}@(0,0) c:{true}@(50,0)")
+	f.Next(root)
+	check("Next wraps", "c:{false}@(50,0) a:{true}@(0,0)")
+	f.Prev(root)
+	check("Prev wraps", "a:{false}@(0,0) c:{true}@(50,0)")
+
+	f.SetAt(root, image.Point{10, 60})
+	check("SetAt non-focusable", "c:{false}@(50,0)")
+	if f.Node() != nil {
+		t.Errorf("SetAt non-focusable: got %v, want nil", f.Node())
+	}
+	f.SetAt(root, image.Point{60, 10})
+	check("SetAt focusable", "c:{true}@(50,0)")
+
+	// c does not handle key events, so they bubble up to inner.
+	e := key.Event{Rune: 'x'}
+	if got := f.OnKeyEvent(root, e); got != Handled {
+		t.Errorf("OnKeyEvent: got %v, want %v", got, Handled)
+	}
+	check("OnKeyEvent", fmt.Sprintf("c:%v@(50,0)", e), fmt.Sprintf("inner:%v@(0,0)", e))
+
+	inner.Remove(c)
+	f.Validate(root)
+	check("Validate", "c:{false}@(0,0)")
+	if f.Node() != nil {
+		t.Errorf("Validate: got %v, want nil", f.Node())
+	}
+}
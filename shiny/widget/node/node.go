@@ -43,11 +43,14 @@ package node // import "golang.org/x/exp/shiny/widget/node"
 
 import (
 	"image"
+	"time"
 
+	"golang.org/x/exp/shiny/dnd"
 	"golang.org/x/exp/shiny/gesture"
 	"golang.org/x/exp/shiny/screen"
 	"golang.org/x/exp/shiny/widget/theme"
 	"golang.org/x/image/math/f64"
+	"golang.org/x/mobile/event/key"
 	"golang.org/x/mobile/event/lifecycle"
 	"golang.org/x/mobile/event/mouse"
 )
@@ -65,6 +68,9 @@ const (
 // NoHint means that there is no width or height hint in a Measure call.
 const NoHint = -1
 
+// NoBaseline means that a node has no text baseline. See Node.Baseline.
+const NoBaseline = -1
+
 // Node is a node in the widget tree.
 type Node interface {
 	// Wrappee returns the inner (embedded) type that is wrapped by this type.
@@ -94,6 +100,15 @@ type Node interface {
 	// width, and could pass that width as the widthHint argument.
 	Measure(t *theme.Theme, widthHint, heightHint int)
 
+	// Baseline returns the distance, in pixels, from the top of this node to
+	// its first line of text's baseline, if laid out at its MeasuredSize. It
+	// returns NoBaseline if the node has no text. It should only be called
+	// after Measure.
+	//
+	// Containers can use it to align their children's text, such as the flex
+	// package's AlignItemBaseline.
+	Baseline(t *theme.Theme) int
+
 	// Layout lays out this node (and its children), setting the Embed.Rect
 	// fields of each child. This node's Embed.Rect field should have
 	// previously been set during the parent node's layout.
@@ -157,13 +172,20 @@ type Node interface {
 	// children).
 	OnLifecycleEvent(e lifecycle.Event)
 
-	// OnInputEvent handles a key, mouse, touch or gesture event.
+	// OnInputEvent handles a key, mouse, touch, gesture or drag and drop
+	// event.
 	//
 	// origin is the parent widget's origin with respect to the event origin;
 	// this node's Embed.Rect.Add(origin) will be its position and size in
 	// event coordinate space.
 	OnInputEvent(e interface{}, origin image.Point) EventHandled
 
+	// Accessibility describes this node to assistive technologies, such as
+	// screen readers. The default implementation returns a zero
+	// Accessibility, whose RoleNone means that this node is not exposed but
+	// its children are.
+	Accessibility() Accessibility
+
 	// TODO: other OnXxxEvent methods?
 
 }
@@ -175,11 +197,22 @@ type PaintContext struct {
 	Drawer  screen.Drawer
 	Src2Dst f64.Aff3
 
+	// Waker, if non-nil, lets a node ask to be marked again at a later time,
+	// such as to blink a text caret.
+	Waker Waker
+
 	// TODO: add a clip rectangle?
 
 	// TODO: add the DrawContext from the lifecycle event?
 }
 
+// Waker lets a node ask to be marked at a later time. It is typically
+// implemented by the event loop that runs the widget tree.
+type Waker interface {
+	// WakeAt gives the node n the marks m at, or shortly after, the time t.
+	WakeAt(t time.Time, n Node, m Marks)
+}
+
 // PaintBaseContext is the context for the Node.PaintBase method.
 type PaintBaseContext struct {
 	Theme *theme.Theme
@@ -201,6 +234,8 @@ func (m *LeafEmbed) Remove(c Node) { m.remove(c) }
 
 func (m *LeafEmbed) Measure(t *theme.Theme, widthHint, heightHint int) { m.MeasuredSize = image.Point{} }
 
+func (m *LeafEmbed) Baseline(t *theme.Theme) int { return NoBaseline }
+
 func (m *LeafEmbed) Layout(t *theme.Theme) {}
 
 func (m *LeafEmbed) Paint(ctx *PaintContext, origin image.Point) error {
@@ -234,16 +269,23 @@ func (m *ShellEmbed) Remove(c Node) { m.remove(c) }
 
 func (m *ShellEmbed) Measure(t *theme.Theme, widthHint, heightHint int) {
 	if c := m.FirstChild; c != nil {
-		c.Wrapper.Measure(t, widthHint, heightHint)
-		m.MeasuredSize = c.MeasuredSize
+		c.MeasureOuter(t, widthHint, heightHint)
+		m.MeasuredSize = c.OuterSize(t)
 	} else {
 		m.MeasuredSize = image.Point{}
 	}
 }
 
+func (m *ShellEmbed) Baseline(t *theme.Theme) int {
+	if c := m.FirstChild; c != nil {
+		return c.OuterBaseline(t)
+	}
+	return NoBaseline
+}
+
 func (m *ShellEmbed) Layout(t *theme.Theme) {
 	if c := m.FirstChild; c != nil {
-		c.Rect = m.Rect.Sub(m.Rect.Min)
+		c.SetOuterRect(t, m.Rect.Sub(m.Rect.Min))
 		c.Wrapper.Layout(t)
 	}
 }
@@ -259,7 +301,9 @@ func (m *ShellEmbed) Paint(ctx *PaintContext, origin image.Point) error {
 func (m *ShellEmbed) PaintBase(ctx *PaintBaseContext, origin image.Point) error {
 	m.Marks.UnmarkNeedsPaintBase()
 	if c := m.FirstChild; c != nil {
-		return c.Wrapper.PaintBase(ctx, origin.Add(m.Rect.Min))
+		origin = origin.Add(m.Rect.Min)
+		c.PaintBox(ctx, origin)
+		return c.Wrapper.PaintBase(ctx, origin)
 	}
 	return nil
 }
@@ -275,8 +319,11 @@ func (m *ShellEmbed) OnLifecycleEvent(e lifecycle.Event) {
 }
 
 func (m *ShellEmbed) OnInputEvent(e interface{}, origin image.Point) EventHandled {
+	if !propagatesDown(e) {
+		return NotHandled
+	}
 	if c := m.FirstChild; c != nil {
-		return c.Wrapper.OnInputEvent(e, origin.Add(m.Rect.Min))
+		return sendDown(c, e, origin.Add(m.Rect.Min))
 	}
 	return NotHandled
 }
@@ -292,20 +339,24 @@ func (m *ContainerEmbed) Remove(c Node) { m.remove(c) }
 func (m *ContainerEmbed) Measure(t *theme.Theme, widthHint, heightHint int) {
 	mSize := image.Point{}
 	for c := m.FirstChild; c != nil; c = c.NextSibling {
-		c.Wrapper.Measure(t, NoHint, NoHint)
-		if mSize.X < c.MeasuredSize.X {
-			mSize.X = c.MeasuredSize.X
+		c.MeasureOuter(t, NoHint, NoHint)
+		size := c.OuterSize(t)
+		if mSize.X < size.X {
+			mSize.X = size.X
 		}
-		if mSize.Y < c.MeasuredSize.Y {
-			mSize.Y = c.MeasuredSize.Y
+		if mSize.Y < size.Y {
+			mSize.Y = size.Y
 		}
 	}
 	m.MeasuredSize = mSize
 }
 
+// TODO: should a container's default baseline be that of its first child?
+func (m *ContainerEmbed) Baseline(t *theme.Theme) int { return NoBaseline }
+
 func (m *ContainerEmbed) Layout(t *theme.Theme) {
 	for c := m.FirstChild; c != nil; c = c.NextSibling {
-		c.Rect = image.Rectangle{Max: c.MeasuredSize}
+		c.SetOuterRect(t, image.Rectangle{Max: c.OuterSize(t)})
 		c.Wrapper.Layout(t)
 	}
 }
@@ -325,6 +376,7 @@ func (m *ContainerEmbed) PaintBase(ctx *PaintBaseContext, origin image.Point) er
 	m.Marks.UnmarkNeedsPaintBase()
 	origin = origin.Add(m.Rect.Min)
 	for c := m.FirstChild; c != nil; c = c.NextSibling {
+		c.PaintBox(ctx, origin)
 		if err := c.Wrapper.PaintBase(ctx, origin); err != nil {
 			return err
 		}
@@ -343,9 +395,17 @@ func (m *ContainerEmbed) OnLifecycleEvent(e lifecycle.Event) {
 }
 
 func (m *ContainerEmbed) OnInputEvent(e interface{}, origin image.Point) EventHandled {
+	if !propagatesDown(e) {
+		return NotHandled
+	}
 	origin = origin.Add(m.Rect.Min)
 	var p image.Point
 	switch e := e.(type) {
+	case dnd.Event:
+		p = image.Point{
+			X: int(e.X) - origin.X,
+			Y: int(e.Y) - origin.Y,
+		}
 	case gesture.Event:
 		p = image.Point{
 			X: int(e.CurrentPos.X) - origin.X,
@@ -360,13 +420,36 @@ func (m *ContainerEmbed) OnInputEvent(e interface{}, origin image.Point) EventHa
 	// Iterate backwards. Later children have priority over earlier children,
 	// as later ones are usually drawn over earlier ones.
 	for c := m.LastChild; c != nil; c = c.PrevSibling {
-		if p.In(c.Rect) && c.Wrapper.OnInputEvent(e, origin) == Handled {
+		if p.In(c.Rect) && sendDown(c, e, origin) == Handled {
 			return Handled
 		}
 	}
 	return NotHandled
 }
 
+// sendDown sends the event e to the node c, recording whether c handled it if
+// it is a mouse press.
+func sendDown(c *Embed, e interface{}, origin image.Point) EventHandled {
+	handled := c.Wrapper.OnInputEvent(e, origin)
+	if e, ok := e.(mouse.Event); ok && e.Direction == mouse.DirPress {
+		c.handledPress = handled == Handled
+	}
+	return handled
+}
+
+// propagatesDown returns whether the default OnInputEvent implementations
+// propagate the event from a node to its children. Key and focus events are
+// not, as they are sent to the focused node and then bubble up towards the
+// root. See the Focus type. Pointer events are not either, as they are sent
+// to each node that the pointer enters or leaves. See the Pointer type.
+func propagatesDown(e interface{}) bool {
+	switch e.(type) {
+	case key.Event, FocusEvent, PointerEvent:
+		return false
+	}
+	return true
+}
+
 // Embed is the common data structure for each node in a widget tree.
 type Embed struct {
 	// Wrapper is the outer type that wraps (embeds) this type. It should not
@@ -386,6 +469,10 @@ type Embed struct {
 	// FlowLayoutData in this field.
 	LayoutData interface{}
 
+	// Box, if non-nil, is the node's margin, border and padding, which its
+	// parent lays out and paints around its Rect. See the Box type.
+	Box *Box
+
 	// TODO: add commentary about the Measure / Layout / Paint model, and about
 	// the lifetime of the MeasuredSize and Rect fields, and when user code can
 	// access and/or modify them. At some point a new cycle begins, a call to
@@ -410,6 +497,17 @@ type Embed struct {
 	// Marks are a bitfield of node state, such as whether it needs measure,
 	// layout or paint.
 	Marks Marks
+
+	// Focusable is whether this node can have the keyboard focus. A focused
+	// node is sent key events, and is sent FocusEvents when it gains or loses
+	// the focus. See the Focus type.
+	Focusable bool
+
+	// handledPress is whether this node handled the last mouse press that
+	// was sent to it by its parent's default OnInputEvent implementation, or
+	// by a Pointer. The Pointer uses it to find the node that captures the
+	// pointer.
+	handledPress bool
 }
 
 func (m *Embed) Wrappee() *Embed { return m }
@@ -483,6 +581,8 @@ func (m *Embed) Mark(marks Marks) {
 	}
 }
 
+func (m *Embed) Accessibility() Accessibility { return Accessibility{} }
+
 // Marks are a bitfield of node state, such as whether it needs measure, layout
 // or paint.
 type Marks uint32
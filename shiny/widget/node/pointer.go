@@ -0,0 +1,215 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package node
+
+import (
+	"image"
+
+	"golang.org/x/mobile/event/mouse"
+)
+
+// TODO: let a container, such as one showing a modal dialog, hide some of its
+// children from hit testing, so that they are not hovered.
+
+// PointerEvent is sent to a node, via its OnInputEvent method, when the mouse
+// pointer enters or leaves it.
+//
+// Like focus events, pointer events are sent directly to the node concerned.
+// They are not propagated down the widget tree by the default OnInputEvent
+// implementations of ShellEmbed and ContainerEmbed.
+type PointerEvent struct {
+	// Entered is whether the pointer entered, as opposed to left, the node.
+	Entered bool
+}
+
+// Pointer tracks which nodes in a widget tree are under the mouse pointer, and
+// which node, if any, has captured the pointer. There is typically one Pointer
+// per window. Its zero value is usable and has no hovered nodes.
+//
+// A node is hovered if it contains the pointer and none of its later siblings
+// do, and if its parent is hovered. Later children have priority over earlier
+// children, as with ContainerEmbed.OnInputEvent.
+//
+// Pressing a mouse button captures the pointer for the deepest hovered node
+// that handled the press, until every button is released. While the pointer
+// is captured, later mouse events are sent to the capturing node, wherever the
+// pointer is, and the hovered nodes do not change. For example, a slider's
+// thumb keeps receiving mouse events when it is dragged outside of the slider,
+// and a scroll bar over other widgets keeps receiving the events of a drag
+// that started on it.
+//
+// Which nodes handled the press is recorded by the default OnInputEvent
+// implementations of ShellEmbed and ContainerEmbed, as they propagate the
+// press down the widget tree. A node whose parent propagates events some
+// other way is not recorded, so if it handles the press, the pointer is
+// captured by its nearest recorded ancestor instead.
+type Pointer struct {
+	// hovered is the path from the root to the deepest hovered node.
+	hovered []*Embed
+
+	captured *Embed
+
+	// buttons is a bit mask of the mouse buttons that are pressed.
+	buttons uint32
+}
+
+// Hovered returns the deepest hovered node, or nil.
+func (p *Pointer) Hovered() Node {
+	if len(p.hovered) == 0 {
+		return nil
+	}
+	return p.hovered[len(p.hovered)-1].Wrapper
+}
+
+// IsHovered returns whether n is hovered: whether n or one of its
+// descendants is the deepest hovered node.
+func (p *Pointer) IsHovered(n Node) bool {
+	e := n.Wrappee()
+	for _, h := range p.hovered {
+		if h == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Captured returns the node that has captured the pointer, or nil.
+func (p *Pointer) Captured() Node {
+	if p.captured == nil {
+		return nil
+	}
+	return p.captured.Wrapper
+}
+
+// Capture makes n capture the pointer until the next time that every mouse
+// button is released, or until Release is called. A nil n is equivalent to
+// calling Release.
+//
+// A node that has access to the Pointer, such as a window's Overlay, can call
+// Capture while handling a mouse press, to capture the pointer instead of the
+// node that handled the press.
+func (p *Pointer) Capture(n Node) {
+	if n == nil {
+		p.captured = nil
+		return
+	}
+	p.captured = n.Wrappee()
+}
+
+// Release releases any capture of the pointer. The hovered nodes are updated
+// by the next mouse event.
+func (p *Pointer) Release() {
+	p.captured = nil
+}
+
+// Validate releases the pointer if the capturing node is no longer in the
+// widget tree rooted at root, and forgets any hovered nodes that are no longer
+// in that tree. Those nodes are not sent PointerEvents.
+func (p *Pointer) Validate(root Node) {
+	r := root.Wrappee()
+	if p.captured != nil && !isDescendant(p.captured, r) {
+		p.captured = nil
+	}
+	for i, e := range p.hovered {
+		if (i == 0 && e != r) || (i > 0 && e.Parent != p.hovered[i-1]) {
+			p.hovered = p.hovered[:i]
+			break
+		}
+	}
+}
+
+// OnMouseEvent delivers a mouse event to the widget tree rooted at root,
+// updating the hovered nodes and sending PointerEvents to the nodes that the
+// pointer entered or left.
+//
+// If the pointer is not captured, or if the event is the mouse press that
+// captures it, the event is sent to root, which typically propagates it down
+// the widget tree. Otherwise, it is sent to the capturing node and, if that
+// node does not handle it, bubbles up to the node's ancestors, in turn, until
+// one of them handles it.
+func (p *Pointer) OnMouseEvent(root Node, e mouse.Event) EventHandled {
+	bit := uint32(0)
+	if e.Button > 0 && e.Button < 32 {
+		bit = 1 << uint(e.Button)
+	}
+
+	capturing := false
+	if p.captured == nil {
+		p.setHovered(hoveredPath(root.Wrappee(), image.Point{int(e.X), int(e.Y)}))
+		if e.Direction == mouse.DirPress && bit != 0 && len(p.hovered) > 0 {
+			capturing = true
+			for _, h := range p.hovered {
+				h.handledPress = false
+			}
+		}
+	}
+	switch e.Direction {
+	case mouse.DirPress:
+		p.buttons |= bit
+	case mouse.DirRelease:
+		p.buttons &^= bit
+	}
+
+	handled := NotHandled
+	if c := p.captured; c != nil && !capturing {
+		for n := c; n != nil; n = n.Parent {
+			if n.Wrapper.OnInputEvent(e, parentOrigin(n)) == Handled {
+				handled = Handled
+				break
+			}
+		}
+	} else {
+		handled = sendDown(root.Wrappee(), e, image.Point{})
+	}
+	if capturing && p.captured == nil {
+		// Capture the deepest hovered node that handled the press, unless a
+		// node called Capture while handling it.
+		for i := len(p.hovered) - 1; i >= 0; i-- {
+			if p.hovered[i].handledPress {
+				p.captured = p.hovered[i]
+				break
+			}
+		}
+	}
+
+	if p.captured != nil && e.Direction == mouse.DirRelease && p.buttons == 0 {
+		p.captured = nil
+		p.setHovered(hoveredPath(root.Wrappee(), image.Point{int(e.X), int(e.Y)}))
+	}
+	return handled
+}
+
+// setHovered updates the hovered path, sending PointerEvents to the nodes
+// that are no longer hovered, deepest first, and then to the nodes that are
+// newly hovered, shallowest first.
+func (p *Pointer) setHovered(path []*Embed) {
+	i := 0
+	for i < len(path) && i < len(p.hovered) && path[i] == p.hovered[i] {
+		i++
+	}
+	old := p.hovered
+	p.hovered = path
+	for j := len(old) - 1; j >= i; j-- {
+		old[j].Wrapper.OnInputEvent(PointerEvent{Entered: false}, parentOrigin(old[j]))
+	}
+	for _, e := range path[i:] {
+		e.Wrapper.OnInputEvent(PointerEvent{Entered: true}, parentOrigin(e))
+	}
+}
+
+// hoveredPath returns the path from e to the deepest node that contains the
+// point p, in e's parent's coordinate space, or nil if e does not contain p.
+func hoveredPath(e *Embed, p image.Point) (path []*Embed) {
+	for e != nil && p.In(e.Rect) {
+		path = append(path, e)
+		p = p.Sub(e.Rect.Min)
+		c := e.LastChild
+		for c != nil && !p.In(c.Rect) {
+			c = c.PrevSibling
+		}
+		e = c
+	}
+	return path
+}
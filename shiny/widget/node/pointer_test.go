@@ -0,0 +1,188 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package node
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"testing"
+
+	"golang.org/x/mobile/event/mouse"
+)
+
+func TestPointer(t *testing.T) {
+	var log []string
+	newLeaf := func(name string, r image.Rectangle) *logLeaf {
+		w := &logLeaf{name: name, log: &log, handle: true}
+		w.Wrapper = w
+		w.Rect = r
+		return w
+	}
+	root := &logContainer{name: "root", log: &log}
+	root.Wrapper = root
+	root.Rect = image.Rect(0, 0, 100, 100)
+	inner := &logContainer{name: "inner", log: &log}
+	inner.Wrapper = inner
+	inner.Rect = image.Rect(50, 0, 100, 100)
+
+	a := newLeaf("a", image.Rect(0, 0, 50, 50))
+	b := newLeaf("b", image.Rect(0, 50, 50, 100))
+	c := newLeaf("c", image.Rect(0, 0, 50, 50))
+	root.Insert(a, nil)
+	root.Insert(b, nil)
+	root.Insert(inner, nil)
+	inner.Insert(c, nil)
+
+	check := func(desc string, want ...string) {
+		t.Helper()
+		if got, want := strings.Join(log, " "), strings.Join(want, " "); got != want {
+			t.Errorf("%s:\ngot  %s\nwant %s", desc, got, want)
+		}
+		log = nil
+	}
+	p := &Pointer{}
+	hovered := func(desc string, want Node) {
+		t.Helper()
+		if got := p.Hovered(); got != want {
+			t.Errorf("%s: Hovered: got %v, want %v", desc, got, want)
+		}
+	}
+	mouseEvent := func(x, y float32, button mouse.Button, dir mouse.Direction) mouse.Event {
+		return mouse.Event{X: x, Y: y, Button: button, Direction: dir}
+	}
+
+	e := mouseEvent(10, 10, mouse.ButtonNone, mouse.DirNone)
+	p.OnMouseEvent(root, e)
+	check("move to a", "root:{true}@(0,0)", "a:{true}@(0,0)", fmt.Sprintf("a:%v@(0,0)", e))
+	hovered("move to a", a)
+
+	e = mouseEvent(60, 10, mouse.ButtonNone, mouse.DirNone)
+	p.OnMouseEvent(root, e)
+	check("move to c", "a:{false}@(0,0)", "inner:{true}@(0,0)", "c:{true}@(50,0)", fmt.Sprintf("c:%v@(50,0)", e))
+	hovered("move to c", c)
+
+	// Pressing a button captures the pointer, so that c gets the events
+	// outside of its Rect.
+	e = mouseEvent(60, 10, mouse.ButtonLeft, mouse.DirPress)
+	p.OnMouseEvent(root, e)
+	check("press on c", fmt.Sprintf("c:%v@(50,0)", e))
+	if got := p.Captured(); got != c {
+		t.Errorf("press on c: Captured: got %v, want %v", got, c)
+	}
+
+	e = mouseEvent(10, 60, mouse.ButtonNone, mouse.DirNone)
+	p.OnMouseEvent(root, e)
+	check("drag to b", fmt.Sprintf("c:%v@(50,0)", e))
+	hovered("drag to b", c)
+
+	// Releasing the button releases the capture, and the pointer leaves c.
+	e = mouseEvent(10, 60, mouse.ButtonLeft, mouse.DirRelease)
+	p.OnMouseEvent(root, e)
+	check("release on b", fmt.Sprintf("c:%v@(50,0)", e),
+		"c:{false}@(50,0)", "inner:{false}@(0,0)", "b:{true}@(0,0)")
+	hovered("release on b", b)
+	if got := p.Captured(); got != nil {
+		t.Errorf("release on b: Captured: got %v, want nil", got)
+	}
+
+	// Unhandled events bubble up from the capturing node.
+	b.handle = false
+	p.Capture(b)
+	e = mouseEvent(60, 60, mouse.ButtonNone, mouse.DirNone)
+	p.OnMouseEvent(root, e)
+	check("capture b", fmt.Sprintf("b:%v@(0,0)", e))
+	p.Release()
+
+	root.Remove(b)
+	p.Validate(root)
+	check("Validate")
+	hovered("Validate", root)
+	if !p.IsHovered(root) || p.IsHovered(b) {
+		t.Errorf("Validate: IsHovered: got %t, %t, want true, false", p.IsHovered(root), p.IsHovered(b))
+	}
+}
+
+// barContainer is a container that, like a scroll bar over its children,
+// handles mouse presses in its bar itself, without propagating them, and then
+// handles the rest of the drag.
+type barContainer struct {
+	ContainerEmbed
+	name     string
+	log      *[]string
+	bar      image.Rectangle
+	dragging bool
+}
+
+func (w *barContainer) OnInputEvent(e interface{}, origin image.Point) EventHandled {
+	if e, ok := e.(mouse.Event); ok {
+		p := image.Point{int(e.X), int(e.Y)}.Sub(origin.Add(w.Rect.Min))
+		if w.dragging || (e.Direction == mouse.DirPress && p.In(w.bar)) {
+			*w.log = append(*w.log, fmt.Sprintf("%s:%v", w.name, e.Direction))
+			w.dragging = e.Direction != mouse.DirRelease
+			return Handled
+		}
+	}
+	return w.ContainerEmbed.OnInputEvent(e, origin)
+}
+
+func TestPointerCapturesHandler(t *testing.T) {
+	var log []string
+	root := &logContainer{name: "root", log: &log}
+	root.Wrapper = root
+	root.Rect = image.Rect(0, 0, 100, 100)
+	bar := &barContainer{name: "bar", log: &log, bar: image.Rect(90, 0, 100, 100)}
+	bar.Wrapper = bar
+	bar.Rect = image.Rect(0, 0, 100, 100)
+	a := &barContainer{name: "a", log: &log, bar: image.Rect(0, 0, 90, 100)}
+	a.Wrapper = a
+	a.Rect = image.Rect(0, 0, 100, 100)
+	root.Insert(bar, nil)
+	bar.Insert(a, nil)
+
+	p := &Pointer{}
+	check := func(desc string, x, y float32, button mouse.Button, dir mouse.Direction, want ...string) {
+		t.Helper()
+		log = nil
+		p.OnMouseEvent(root, mouse.Event{X: x, Y: y, Button: button, Direction: dir})
+		var got []string
+		for _, s := range log {
+			// Ignore the PointerEvents.
+			if !strings.Contains(s, ":{true}") && !strings.Contains(s, ":{false}") {
+				got = append(got, s)
+			}
+		}
+		if got, want := strings.Join(got, " "), strings.Join(want, " "); got != want {
+			t.Errorf("%s:\ngot  %s\nwant %s", desc, got, want)
+		}
+	}
+
+	// The press on the bar is handled by bar, not by a, the deepest hovered
+	// node, so bar captures the pointer and gets the rest of the drag, even
+	// though a would handle those events.
+	check("press on bar", 95, 10, mouse.ButtonLeft, mouse.DirPress, "bar:Press")
+	if got := p.Captured(); got != bar {
+		t.Errorf("press on bar: Captured: got %v, want %v", got, bar)
+	}
+	check("drag", 50, 50, mouse.ButtonNone, mouse.DirNone, "bar:None")
+	check("release", 50, 50, mouse.ButtonLeft, mouse.DirRelease, "bar:Release")
+	if got := p.Captured(); got != nil {
+		t.Errorf("release: Captured: got %v, want nil", got)
+	}
+
+	// A press elsewhere is handled by a, which captures the pointer.
+	check("press on a", 50, 50, mouse.ButtonLeft, mouse.DirPress, "a:Press")
+	if got := p.Captured(); got != a {
+		t.Errorf("press on a: Captured: got %v, want %v", got, a)
+	}
+	check("release on a", 95, 10, mouse.ButtonLeft, mouse.DirRelease, "a:Release")
+
+	// A press that no node handles captures nothing.
+	a.bar = image.Rectangle{}
+	check("unhandled press", 50, 50, mouse.ButtonLeft, mouse.DirPress)
+	if got := p.Captured(); got != nil {
+		t.Errorf("unhandled press: Captured: got %v, want nil", got)
+	}
+}
@@ -0,0 +1,476 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"golang.org/x/exp/shiny/dnd"
+	"golang.org/x/exp/shiny/gesture"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/mouse"
+)
+
+// TODO: animate showing and dismissing popups.
+
+// scrimColor dims the widgets below a modal popup.
+var scrimColor = color.RGBA{0x00, 0x00, 0x00, 0x40}
+
+// Placement is where a Popup is placed relative to its anchor.
+type Placement uint8
+
+const (
+	// PlaceBelow places a popup below its anchor, aligned with its left edge,
+	// or above it if there is not enough room below.
+	PlaceBelow Placement = iota
+	// PlaceAbove places a popup above its anchor, aligned with its left edge,
+	// or below it if there is not enough room above.
+	PlaceAbove
+	// PlaceRight places a popup to the right of its anchor, aligned with its
+	// top edge, or to the left if there is not enough room to the right.
+	PlaceRight
+	// PlaceCenter centers a popup in its Overlay, ignoring its anchor.
+	PlaceCenter
+)
+
+// Overlay is a container widget that shows Popups, such as menus, tooltips
+// and dialogs, above its first child, the base widget tree. The base widget
+// tree is laid out to fill the Overlay. Each Popup is laid out at its natural
+// size, next to its anchor, but constrained to the Overlay.
+//
+// RunWindow puts an Overlay at the root of its widget tree, unless the root
+// is already an Overlay, so that most programs need not create one.
+//
+// Pressing the mouse outside of the top Popup dismisses it, if it is not
+// modal, as does the escape key. While a modal Popup is shown, the widgets
+// below it do not receive any input events.
+type Overlay struct {
+	node.ContainerEmbed
+
+	// theme is the theme from the most recent Layout call. Popups are laid
+	// out when they are shown, without a Layout call on the Overlay.
+	theme *theme.Theme
+
+	// focus, if non-nil, is the keyboard focus of the window that the Overlay
+	// is in. Showing and dismissing Popups moves the focus.
+	focus *node.Focus
+
+	// pointer, if non-nil, is the mouse pointer of the window that the
+	// Overlay is in. The Overlay captures the pointer for mouse presses that
+	// it swallows, so that the widgets below do not get the rest of the press.
+	pointer *node.Pointer
+
+	// swallowing is whether the mouse and gesture events that follow a mouse
+	// press that dismissed a Popup should be ignored, so that the press does
+	// not also, for example, click a button below that Popup.
+	swallowing bool
+}
+
+// NewOverlay returns a new Overlay widget whose base widget tree is base.
+func NewOverlay(base node.Node) *Overlay {
+	w := &Overlay{}
+	w.Wrapper = w
+	if base != nil {
+		w.Insert(base, nil)
+	}
+	return w
+}
+
+// FindOverlay returns the nearest Overlay that is an ancestor of n, or nil if
+// there is no such Overlay.
+func FindOverlay(n node.Node) *Overlay {
+	for e := n.Wrappee(); e != nil; e = e.Parent {
+		if w, ok := e.Wrapper.(*Overlay); ok {
+			return w
+		}
+	}
+	return nil
+}
+
+// Top returns the top-most Popup, or nil if no Popup is shown.
+func (w *Overlay) Top() *Popup {
+	if c := w.LastChild; c != nil && c != w.FirstChild {
+		return c.Wrapper.(*Popup)
+	}
+	return nil
+}
+
+// Show shows the Popup p above the base widget tree and any other Popups. If
+// p contains a focusable node, the first such node gets the keyboard focus.
+func (w *Overlay) Show(p *Popup) {
+	if p.overlay != nil {
+		p.overlay.Dismiss(p)
+	}
+	if w.FirstChild == nil {
+		panic("widget: Overlay.Show called for an Overlay with no base widget tree")
+	}
+	p.overlay = w
+	w.Insert(p, nil)
+	w.layoutPopup(p)
+
+	if w.focus != nil && hasFocusable(p.Wrappee()) {
+		p.prevFocus = w.focus.Node()
+		w.focus.Next(p)
+	}
+	w.Mark(node.MarkNeedsPaint)
+}
+
+// Dismiss dismisses the Popup p, and any Popups shown after it, and then
+// calls their OnDismiss functions. It does nothing if p is not shown by w.
+func (w *Overlay) Dismiss(p *Popup) {
+	if p.overlay != w {
+		return
+	}
+	var dismissed []*Popup
+	for {
+		top := w.Top()
+		w.Remove(top)
+		top.overlay = nil
+		top.release()
+		dismissed = append(dismissed, top)
+		if top == p {
+			break
+		}
+	}
+	if w.focus != nil {
+		// Return the focus to where it was before p was shown.
+		if prev := p.prevFocus; prev != nil {
+			w.focus.Set(prev)
+		}
+		w.focus.Validate(w)
+	}
+	w.Mark(node.MarkNeedsPaint)
+
+	for _, d := range dismissed {
+		d.prevFocus = nil
+		if d.OnDismiss != nil {
+			d.OnDismiss()
+		}
+	}
+}
+
+// hasFocusable returns whether e or any of its descendants is focusable.
+func hasFocusable(e *node.Embed) bool {
+	if e.Focusable {
+		return true
+	}
+	for c := e.FirstChild; c != nil; c = c.NextSibling {
+		if hasFocusable(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// focusScope returns the node whose descendants can have the keyboard focus:
+// the top-most modal Popup, if any, or else the Overlay itself.
+func (w *Overlay) focusScope() node.Node {
+	if p := w.modal(); p != nil {
+		return p
+	}
+	return w
+}
+
+// modal returns the top-most modal Popup, or nil.
+func (w *Overlay) modal() *Popup {
+	for c := w.LastChild; c != nil && c != w.FirstChild; c = c.PrevSibling {
+		if p := c.Wrapper.(*Popup); p.Modal {
+			return p
+		}
+	}
+	return nil
+}
+
+func (w *Overlay) Measure(t *theme.Theme, widthHint, heightHint int) {
+	if c := w.FirstChild; c != nil {
+		c.MeasureOuter(t, widthHint, heightHint)
+		w.MeasuredSize = c.OuterSize(t)
+	} else {
+		w.MeasuredSize = image.Point{}
+	}
+}
+
+func (w *Overlay) Layout(t *theme.Theme) {
+	w.theme = t
+	c := w.FirstChild
+	if c == nil {
+		return
+	}
+	c.SetOuterRect(t, w.Rect.Sub(w.Rect.Min))
+	c.Wrapper.Layout(t)
+	for c = c.NextSibling; c != nil; c = c.NextSibling {
+		w.layoutPopup(c.Wrapper.(*Popup))
+	}
+}
+
+// layoutPopup measures and lays out p next to its anchor. It is p's outer
+// rectangle, including its Box, that is placed.
+func (w *Overlay) layoutPopup(p *Popup) {
+	p.MeasureOuter(w.theme, node.NoHint, node.NoHint)
+	bounds := w.Rect.Sub(w.Rect.Min)
+	size := p.OuterSize(w.theme)
+	if size.X > bounds.Dx() {
+		size.X = bounds.Dx()
+	}
+	if size.Y > bounds.Dy() {
+		size.Y = bounds.Dy()
+	}
+
+	a := p.anchorRect(w)
+	var pos image.Point
+	switch p.Placement {
+	case PlaceBelow, PlaceAbove:
+		pos.X = a.Min.X
+		below, above := a.Max.Y, a.Min.Y-size.Y
+		fitsBelow, fitsAbove := below+size.Y <= bounds.Max.Y, above >= bounds.Min.Y
+		if p.Placement == PlaceBelow && (fitsBelow || !fitsAbove) ||
+			p.Placement == PlaceAbove && !fitsAbove && fitsBelow {
+			pos.Y = below
+		} else {
+			pos.Y = above
+		}
+	case PlaceRight:
+		pos.Y = a.Min.Y
+		pos.X = a.Max.X
+		if pos.X+size.X > bounds.Max.X && a.Min.X-size.X >= bounds.Min.X {
+			pos.X = a.Min.X - size.X
+		}
+	case PlaceCenter:
+		pos = bounds.Min.Add(bounds.Size().Sub(size).Div(2))
+	}
+
+	// Constrain the popup to the Overlay.
+	if pos.X+size.X > bounds.Max.X {
+		pos.X = bounds.Max.X - size.X
+	}
+	if pos.Y+size.Y > bounds.Max.Y {
+		pos.Y = bounds.Max.Y - size.Y
+	}
+	if pos.X < bounds.Min.X {
+		pos.X = bounds.Min.X
+	}
+	if pos.Y < bounds.Min.Y {
+		pos.Y = bounds.Min.Y
+	}
+	p.SetOuterRect(w.theme, image.Rectangle{pos, pos.Add(size)})
+	p.Layout(w.theme)
+}
+
+func (w *Overlay) Paint(ctx *node.PaintContext, origin image.Point) error {
+	w.Marks.UnmarkNeedsPaint()
+	origin = origin.Add(w.Rect.Min)
+	for c := w.FirstChild; c != nil; c = c.NextSibling {
+		if p, ok := c.Wrapper.(*Popup); ok && p.Modal {
+			src2dst := ctx.Src2Dst
+			translate(&src2dst, float64(origin.X), float64(origin.Y))
+			ctx.Drawer.DrawUniform(src2dst, scrimColor, w.Rect.Sub(w.Rect.Min), draw.Over, nil)
+		}
+		if err := c.Wrapper.Paint(ctx, origin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Overlay) OnInputEvent(e interface{}, origin image.Point) node.EventHandled {
+	var p image.Point
+	press := false
+	switch e := e.(type) {
+	case key.Event:
+		// Key events bubble up from the focused node.
+		if e.Code == key.CodeEscape && e.Direction != key.DirRelease {
+			if top := w.Top(); top != nil {
+				w.Dismiss(top)
+				return node.Handled
+			}
+		}
+		// If no node has the focus, key events are sent to the root of the
+		// widget tree, so pass them on to the base widget tree, as if it was
+		// that root.
+		if w.focus != nil && w.focus.Node() != nil || w.modal() != nil {
+			return node.NotHandled
+		}
+		if c := w.FirstChild; c != nil {
+			return c.Wrapper.OnInputEvent(e, origin.Add(w.Rect.Min))
+		}
+		return node.NotHandled
+
+	case node.FocusEvent, node.PointerEvent:
+		return node.NotHandled
+
+	case dnd.Event:
+		p = image.Point{int(e.X), int(e.Y)}
+
+	case gesture.Event:
+		if w.swallowing {
+			if e.Type == gesture.TypeEnd {
+				w.swallowing = false
+			}
+			return node.Handled
+		}
+		p = image.Point{int(e.CurrentPos.X), int(e.CurrentPos.Y)}
+
+	case mouse.Event:
+		press = e.Direction == mouse.DirPress
+		if w.swallowing && !press {
+			return node.Handled
+		}
+		w.swallowing = false
+		p = image.Point{int(e.X), int(e.Y)}
+	}
+	origin = origin.Add(w.Rect.Min)
+	p = p.Sub(origin)
+
+	// Popups shown later have priority over earlier ones. A popup gets all
+	// of the events inside it, whether or not it handles them. Pressing
+	// outside of non-modal popups dismisses them, and the rest of that press
+	// is ignored.
+	var outside *Popup
+	for c := w.LastChild; c != nil && c != w.FirstChild; c = c.PrevSibling {
+		if p.In(c.Rect) {
+			if outside != nil {
+				break
+			}
+			c.Wrapper.OnInputEvent(e, origin)
+			return node.Handled
+		}
+		if c.Wrapper.(*Popup).Modal {
+			if outside != nil {
+				break
+			}
+			if press && w.pointer != nil {
+				w.pointer.Capture(w)
+			}
+			return node.Handled
+		}
+		if press {
+			outside = c.Wrapper.(*Popup)
+		}
+	}
+	if outside != nil {
+		w.Dismiss(outside)
+		w.swallowing = true
+		if w.pointer != nil {
+			w.pointer.Capture(w)
+		}
+		return node.Handled
+	}
+	if c := w.FirstChild; c != nil {
+		return c.Wrapper.OnInputEvent(e, origin)
+	}
+	return node.NotHandled
+}
+
+// Popup is a shell widget that an Overlay shows above its base widget tree.
+// It provides its own pixel buffers, like a Sheet, and paints a background
+// and border around its child.
+type Popup struct {
+	Sheet
+
+	// Anchor, if non-nil, is the node that the Popup is placed next to.
+	// Otherwise, the Popup is placed next to AnchorRect, in the Overlay's
+	// coordinate space. For example, a context menu's AnchorRect may be an
+	// empty rectangle at the mouse pointer's position.
+	Anchor     node.Node
+	AnchorRect image.Rectangle
+
+	Placement Placement
+
+	// Modal is whether the Popup captures all input events while it is shown.
+	// A modal Popup is not dismissed by pressing the mouse outside of it.
+	Modal bool
+
+	// OnDismiss, if non-nil, is called after the Popup is dismissed.
+	OnDismiss func()
+
+	overlay   *Overlay
+	prevFocus node.Node
+}
+
+// NewPopup returns a new Popup widget containing inner.
+func NewPopup(inner node.Node) *Popup {
+	w := &Popup{}
+	w.Wrapper = w
+	w.Insert(newPopupFrame(inner), nil)
+	return w
+}
+
+// Shown returns whether the Popup is shown by an Overlay.
+func (w *Popup) Shown() bool { return w.overlay != nil }
+
+// Dismiss dismisses the Popup, if it is shown.
+func (w *Popup) Dismiss() {
+	if w.overlay != nil {
+		w.overlay.Dismiss(w)
+	}
+}
+
+// anchorRect returns the Popup's anchor in the Overlay o's coordinate space.
+func (w *Popup) anchorRect(o *Overlay) image.Rectangle {
+	if w.Anchor == nil {
+		return w.AnchorRect
+	}
+	e := w.Anchor.Wrappee()
+	r := e.Rect
+	for a := e.Parent; a != nil && a != &o.Embed; a = a.Parent {
+		r = r.Add(a.Rect.Min)
+	}
+	return r
+}
+
+// popupFrame is a shell widget that paints a Popup's background and border.
+type popupFrame struct {
+	node.ShellEmbed
+}
+
+func newPopupFrame(inner node.Node) *popupFrame {
+	w := &popupFrame{}
+	w.Wrapper = w
+	if inner != nil {
+		w.Insert(inner, nil)
+	}
+	return w
+}
+
+func (w *popupFrame) Measure(t *theme.Theme, widthHint, heightHint int) {
+	lw2 := 2 * lineWidth(t)
+	if widthHint >= 0 {
+		if widthHint -= lw2; widthHint < 0 {
+			widthHint = 0
+		}
+	}
+	if heightHint >= 0 {
+		if heightHint -= lw2; heightHint < 0 {
+			heightHint = 0
+		}
+	}
+	w.ShellEmbed.Measure(t, widthHint, heightHint)
+	w.MeasuredSize = w.MeasuredSize.Add(image.Point{lw2, lw2})
+}
+
+func (w *popupFrame) Layout(t *theme.Theme) {
+	if c := w.FirstChild; c != nil {
+		c.SetOuterRect(t, w.Rect.Sub(w.Rect.Min).Inset(lineWidth(t)))
+		c.Wrapper.Layout(t)
+	}
+}
+
+func (w *popupFrame) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
+	w.Marks.UnmarkNeedsPaintBase()
+	r := w.Rect.Add(origin)
+	pal := ctx.Theme.GetPalette()
+	draw.Draw(ctx.Dst, r, pal.Surface(), image.Point{}, draw.Src)
+	drawBorder(ctx.Dst, r, pal.Dark(), lineWidth(ctx.Theme))
+	if c := w.FirstChild; c != nil {
+		origin = origin.Add(w.Rect.Min)
+		c.PaintBox(ctx, origin)
+		return c.Wrapper.PaintBase(ctx, origin)
+	}
+	return nil
+}
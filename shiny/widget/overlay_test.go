@@ -0,0 +1,305 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/mouse"
+)
+
+// newLogPopup returns a Popup, 20 by 10 pixels plus its border, whose child
+// logs the mouse events that it receives.
+func newLogPopup(name string, log *[]string) *Popup {
+	return NewPopup(NewSizer(unit.Pixels(20), unit.Pixels(10), newLogLeaf(name, log, true)))
+}
+
+// newTestOverlay returns a laid out Overlay, 100 by 100 pixels, whose base
+// widget tree is base, with its own keyboard focus.
+func newTestOverlay(base node.Node) *Overlay {
+	w := NewOverlay(base)
+	w.focus = &node.Focus{}
+	w.Measure(nil, node.NoHint, node.NoHint)
+	w.Rect = image.Rect(0, 0, 100, 100)
+	w.Layout(nil)
+	return w
+}
+
+func escape() key.Event {
+	return key.Event{Code: key.CodeEscape, Direction: key.DirPress}
+}
+
+func TestLayoutPopup(t *testing.T) {
+	// The popups are 20 by 10 pixels, plus their border.
+	lw := lineWidth(nil)
+	size := image.Point{20 + 2*lw, 10 + 2*lw}
+
+	testCases := []struct {
+		desc      string
+		placement Placement
+		anchor    image.Rectangle
+		want      image.Point
+	}{
+		{"below", PlaceBelow, image.Rect(10, 10, 30, 20), image.Point{10, 20}},
+		{"below, flipped", PlaceBelow, image.Rect(10, 90, 30, 100), image.Point{10, 90 - size.Y}},
+		{"above", PlaceAbove, image.Rect(10, 50, 30, 60), image.Point{10, 50 - size.Y}},
+		{"above, flipped", PlaceAbove, image.Rect(10, 0, 30, 5), image.Point{10, 5}},
+		{"right", PlaceRight, image.Rect(10, 10, 30, 20), image.Point{30, 10}},
+		{"right, flipped", PlaceRight, image.Rect(90, 10, 100, 20), image.Point{90 - size.X, 10}},
+		{"center", PlaceCenter, image.Rect(10, 10, 30, 20), image.Point{50, 50}.Sub(size.Div(2))},
+		// Popups are constrained to the Overlay.
+		{"constrained", PlaceBelow, image.Rect(95, 10, 99, 20), image.Point{100 - size.X, 20}},
+		{"outside", PlaceBelow, image.Rect(-50, -50, -40, -40), image.Point{0, 0}},
+	}
+	for _, tc := range testCases {
+		w := newTestOverlay(NewSpace())
+		p := NewPopup(NewSizer(unit.Pixels(20), unit.Pixels(10), nil))
+		p.AnchorRect = tc.anchor
+		p.Placement = tc.placement
+		w.Show(p)
+		if got, want := p.Rect, (image.Rectangle{tc.want, tc.want.Add(size)}); got != want {
+			t.Errorf("%s: got %v, want %v", tc.desc, got, want)
+		}
+	}
+
+	// An Anchor node is placed in the Overlay's coordinate space.
+	anchor := NewSizer(unit.Pixels(20), unit.Pixels(10), nil)
+	w := newTestOverlay(NewPadder(AxisBoth, unit.Pixels(10), NewStack(anchor)))
+	p := NewPopup(NewSizer(unit.Pixels(20), unit.Pixels(10), nil))
+	p.Anchor = anchor
+	w.Show(p)
+	if got, want := p.Rect.Min, (image.Point{10, 20}); got != want {
+		t.Errorf("anchor node: got %v, want %v", got, want)
+	}
+}
+
+func TestOverlayDismiss(t *testing.T) {
+	var log []string
+	w := newTestOverlay(newLogLeaf("base", &log, true))
+	dismissed := 0
+	p := newLogPopup("popup", &log)
+	p.AnchorRect = image.Rect(10, 10, 10, 10)
+	p.OnDismiss = func() { dismissed++ }
+
+	check := func(desc string, want string, wantShown bool) {
+		t.Helper()
+		if got := strings.Join(log, " "); got != want {
+			t.Errorf("%s: got %q, want %q", desc, got, want)
+		}
+		if got := p.Shown(); got != wantShown {
+			t.Errorf("%s: Shown: got %t, want %t", desc, got, wantShown)
+		}
+		log = log[:0]
+	}
+
+	w.Show(p)
+	w.OnInputEvent(press(15, 15), image.Point{})
+	check("press inside", "popup", true)
+
+	// Pressing outside the popup dismisses it, and the rest of that press
+	// is swallowed, rather than sent to the base widget tree.
+	w.OnInputEvent(press(80, 80), image.Point{})
+	check("press outside", "", false)
+	if dismissed != 1 {
+		t.Errorf("press outside: OnDismiss called %d times, want 1", dismissed)
+	}
+	w.OnInputEvent(mouse.Event{X: 85, Y: 85, Direction: mouse.DirNone}, image.Point{})
+	w.OnInputEvent(mouse.Event{X: 85, Y: 85, Button: mouse.ButtonLeft, Direction: mouse.DirRelease}, image.Point{})
+	check("swallowed", "", false)
+	w.OnInputEvent(press(80, 80), image.Point{})
+	check("next press", "base", false)
+
+	// Pressing inside a lower popup dismisses only the popups above it.
+	q := newLogPopup("q", &log)
+	q.AnchorRect = image.Rect(60, 60, 60, 60)
+	w.Show(p)
+	w.Show(q)
+	w.OnInputEvent(press(15, 15), image.Point{})
+	check("press inside lower popup", "", true)
+	if q.Shown() {
+		t.Error("press inside lower popup: upper popup still shown")
+	}
+
+	// Escape dismisses the top popup.
+	if w.OnInputEvent(escape(), image.Point{}) != node.Handled {
+		t.Error("escape: not handled")
+	}
+	check("escape", "", false)
+
+	// Dismissing a popup also dismisses those shown after it.
+	w.Show(p)
+	w.Show(q)
+	p.Dismiss()
+	if p.Shown() || q.Shown() || w.Top() != nil {
+		t.Error("Dismiss: popups still shown")
+	}
+}
+
+func TestOverlayModal(t *testing.T) {
+	var log []string
+	w := newTestOverlay(newLogLeaf("base", &log, true))
+	p := newLogPopup("popup", &log)
+	p.Placement = PlaceCenter
+	p.Modal = true
+	w.Show(p)
+
+	// A modal popup is not dismissed by pressing outside of it, and the base
+	// widget tree does not get the press.
+	if w.OnInputEvent(press(5, 5), image.Point{}) != node.Handled {
+		t.Error("press outside: not handled")
+	}
+	if !p.Shown() {
+		t.Error("press outside: modal popup dismissed")
+	}
+	w.OnInputEvent(press(50, 50), image.Point{})
+	if got, want := strings.Join(log, " "), "popup"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// Key events are not passed on to the base widget tree, either.
+	if w.OnInputEvent(key.Event{Code: key.CodeA, Direction: key.DirPress}, image.Point{}) != node.NotHandled {
+		t.Error("key event: handled")
+	}
+	if got, want := w.focusScope(), node.Node(p); got != want {
+		t.Errorf("focusScope: got %v, want the modal popup", got)
+	}
+
+	// A non-modal popup above a modal one is dismissed by pressing outside
+	// of both, but the modal one is not.
+	q := NewPopup(NewSizer(unit.Pixels(10), unit.Pixels(10), nil))
+	w.Show(q)
+	w.OnInputEvent(press(95, 95), image.Point{})
+	if q.Shown() || !p.Shown() {
+		t.Errorf("press outside both: Shown: got %t, %t, want false, true", q.Shown(), p.Shown())
+	}
+
+	// Escape dismisses even a modal popup.
+	w.OnInputEvent(escape(), image.Point{})
+	if p.Shown() {
+		t.Error("escape: modal popup still shown")
+	}
+	if got, want := w.focusScope(), node.Node(w); got != want {
+		t.Errorf("focusScope: got %v, want the Overlay", got)
+	}
+}
+
+func TestOverlayFocus(t *testing.T) {
+	var log []string
+	base := newLogLeaf("base", &log, true)
+	base.Focusable = true
+	w := newTestOverlay(base)
+	w.focus.Set(base)
+
+	inner := newLogLeaf("popup", &log, true)
+	inner.Focusable = true
+	p := NewPopup(inner)
+	w.Show(p)
+	if got := w.focus.Node(); got != node.Node(inner) {
+		t.Errorf("shown: focus is %v, want the popup's child", got)
+	}
+	p.Dismiss()
+	if got := w.focus.Node(); got != node.Node(base) {
+		t.Errorf("dismissed: focus is %v, want the base", got)
+	}
+
+	// A popup with nothing focusable leaves the focus where it is.
+	q := NewPopup(NewSizer(unit.Pixels(10), unit.Pixels(10), nil))
+	w.Show(q)
+	if got := w.focus.Node(); got != node.Node(base) {
+		t.Errorf("unfocusable: focus is %v, want the base", got)
+	}
+	q.Dismiss()
+	if got := w.focus.Node(); got != node.Node(base) {
+		t.Errorf("unfocusable, dismissed: focus is %v, want the base", got)
+	}
+}
+
+func TestMenu(t *testing.T) {
+	anchor := NewSizer(unit.Pixels(20), unit.Pixels(10), nil)
+	w := newTestOverlay(NewPadder(AxisBoth, unit.Pixels(10), NewStack(anchor)))
+
+	var selected []string
+	item := func(s string, disabled bool) MenuItem {
+		return MenuItem{
+			Text:     s,
+			OnSelect: func() { selected = append(selected, s) },
+			Disabled: disabled,
+		}
+	}
+	m := NewMenu(item("a", false), item("b", true), item("c", false))
+	m.ShowAt(anchor)
+	if !m.Shown() {
+		t.Fatal("ShowAt: not shown")
+	}
+	if got := m.popup.Rect.Min; got != (image.Point{10, 20}) {
+		t.Errorf("ShowAt: popup at %v, want (10,20)", got)
+	}
+	if got := w.focus.Node(); got != node.Node(m) {
+		t.Errorf("ShowAt: focus is %v, want the menu", got)
+	}
+
+	// The down arrow skips the disabled item.
+	down := key.Event{Code: key.CodeDownArrow, Direction: key.DirPress}
+	m.OnInputEvent(down, image.Point{})
+	m.OnInputEvent(down, image.Point{})
+	if m.highlighted != 2 {
+		t.Errorf("down arrow: highlighted %d, want 2", m.highlighted)
+	}
+	m.OnInputEvent(key.Event{Code: key.CodeReturnEnter, Direction: key.DirPress}, image.Point{})
+	if got, want := strings.Join(selected, " "), "c"; got != want {
+		t.Errorf("enter: selected %q, want %q", got, want)
+	}
+	if m.Shown() {
+		t.Error("enter: menu still shown")
+	}
+
+	// The menu is shown afresh, with no item highlighted.
+	m.ShowAtPoint(anchor, image.Point{50, 10})
+	if m.highlighted != -1 {
+		t.Errorf("ShowAtPoint: highlighted %d, want -1", m.highlighted)
+	}
+	if got := m.popup.Rect.Min; got != (image.Point{50, 10}) {
+		t.Errorf("ShowAtPoint: popup at %v, want (50,10)", got)
+	}
+	m.selectItem(1)
+	if !m.Shown() || len(selected) != 1 {
+		t.Error("selecting a disabled item: menu dismissed or item selected")
+	}
+	w.OnInputEvent(escape(), image.Point{})
+	if m.Shown() {
+		t.Error("escape: menu still shown")
+	}
+}
+
+func TestDialog(t *testing.T) {
+	var log []string
+	w := newTestOverlay(newLogLeaf("base", &log, true))
+	dismissed := false
+	d := NewDialog("Title", nil, NewLabel("OK"))
+	d.OnDismiss = func() { dismissed = true }
+	d.Show(w)
+
+	if !d.Shown() {
+		t.Fatal("Show: not shown")
+	}
+	// The dialog is centered.
+	r := d.popup.Rect
+	if got, want := r.Min, (image.Point{100, 100}).Sub(r.Size()).Div(2); got != want {
+		t.Errorf("Rect.Min: got %v, want %v", got, want)
+	}
+	w.OnInputEvent(press(1, 1), image.Point{})
+	if !d.Shown() || len(log) != 0 {
+		t.Errorf("press outside: Shown %t, log %q, want true, none", d.Shown(), log)
+	}
+	w.OnInputEvent(escape(), image.Point{})
+	if d.Shown() || !dismissed {
+		t.Errorf("escape: Shown %t, OnDismiss called %t, want false, true", d.Shown(), dismissed)
+	}
+}
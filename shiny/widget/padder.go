@@ -17,6 +17,9 @@ import (
 // That marginal space is not considered part of the inner widget's geometry.
 // For example, to make that space 'clickable', construct the Padder inside of
 // an event handling widget instead of vice versa.
+//
+// Any node can instead have a per-side margin, border and padding, set by its
+// node.Box, if its parent honors it.
 type Padder struct {
 	node.ShellEmbed
 	Axis   Axis
@@ -59,6 +62,14 @@ func (w *Padder) Measure(t *theme.Theme, widthHint, heightHint int) {
 	}
 }
 
+func (w *Padder) Baseline(t *theme.Theme) int {
+	b := w.ShellEmbed.Baseline(t)
+	if b != node.NoBaseline && w.Axis.Vertical() {
+		b += t.Pixels(w.Margin).Round()
+	}
+	return b
+}
+
 func (w *Padder) Layout(t *theme.Theme) {
 	if c := w.FirstChild; c != nil {
 		r := w.Rect.Sub(w.Rect.Min)
@@ -71,7 +82,7 @@ func (w *Padder) Layout(t *theme.Theme) {
 			r.Min.Y = inset.Min.Y
 			r.Max.Y = inset.Max.Y
 		}
-		c.Rect = r
+		c.SetOuterRect(t, r)
 		c.Wrapper.Layout(t)
 	}
 }
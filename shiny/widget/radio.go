@@ -0,0 +1,143 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+)
+
+// RadioGroup is a set of Radio widgets, at most one of which is selected. Its
+// zero value is usable and has no selected Radio.
+type RadioGroup struct {
+	// OnChange, if non-nil, is called when the user selects a Radio in the
+	// group.
+	OnChange func(selected *Radio)
+
+	selected *Radio
+}
+
+// Selected returns the selected Radio, or nil.
+func (g *RadioGroup) Selected() *Radio { return g.selected }
+
+// Select selects r, which may be nil to clear the selection. It does not call
+// OnChange.
+func (g *RadioGroup) Select(r *Radio) {
+	if g.selected == r {
+		return
+	}
+	if old := g.selected; old != nil {
+		old.Mark(node.MarkNeedsPaintBase)
+	}
+	g.selected = r
+	if r != nil {
+		r.Mark(node.MarkNeedsPaintBase)
+	}
+}
+
+// Radio is a leaf widget that is one of the options of a RadioGroup. Clicking
+// it, by a tap or, when it has the keyboard focus, by the space bar or the
+// enter key, selects it.
+//
+// TODO: move the selection within a group with the arrow keys.
+type Radio struct {
+	node.LeafEmbed
+	Text string
+
+	group *RadioGroup
+	c     control
+}
+
+// NewRadio returns a new Radio widget in the given group. A nil group means a
+// new group of its own.
+func NewRadio(text string, group *RadioGroup) *Radio {
+	if group == nil {
+		group = &RadioGroup{}
+	}
+	w := &Radio{
+		Text:  text,
+		group: group,
+	}
+	w.Wrapper = w
+	w.Focusable = true
+	return w
+}
+
+// Group returns the RadioGroup that the radio is in.
+func (w *Radio) Group() *RadioGroup { return w.group }
+
+// Selected returns whether the radio is the selected one in its group.
+func (w *Radio) Selected() bool { return w.group.selected == w }
+
+// Disabled returns whether the radio is disabled.
+func (w *Radio) Disabled() bool { return w.c.disabled }
+
+// SetDisabled sets whether the radio is disabled. A disabled radio cannot be
+// selected by the user or focused.
+func (w *Radio) SetDisabled(disabled bool) { w.c.setDisabled(&w.Embed, disabled) }
+
+func (w *Radio) Measure(t *theme.Theme, widthHint, heightHint int) {
+	w.MeasuredSize = measureMarked(t, w.Text)
+}
+
+func (w *Radio) Baseline(t *theme.Theme) int { return fontAscent(t) }
+
+func (w *Radio) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
+	w.Marks.UnmarkNeedsPaintBase()
+	r := w.Rect.Add(origin)
+	dst := ctx.Dst.SubImage(r).(*image.RGBA)
+	if dst.Bounds().Empty() {
+		return nil
+	}
+
+	t := ctx.Theme
+	lw := lineWidth(t)
+	circle := paintMarked(dst, t, r, w.Text, w.c.labelInk(t))
+	fillRoundRect(dst, circle, w.c.labelInk(t))
+	fillRoundRect(dst, circle.Inset(lw), w.c.fill(t))
+	if w.Selected() {
+		dot := t.GetPalette().Accent()
+		if w.c.disabled {
+			dot = w.c.labelInk(t)
+		}
+		fillRoundRect(dst, circle.Inset(circle.Dx()/4), dot)
+	}
+	w.c.paintFocus(dst, t, r)
+	return nil
+}
+
+func (w *Radio) OnInputEvent(e interface{}, origin image.Point) node.EventHandled {
+	handled, activate := w.c.onInputEvent(&w.Embed, e)
+	if activate {
+		w.choose()
+	}
+	return handled
+}
+
+// choose selects w, and calls the group's OnChange, unless w is already
+// selected.
+func (w *Radio) choose() {
+	if !w.Selected() {
+		w.group.Select(w)
+		if w.group.OnChange != nil {
+			w.group.OnChange(w)
+		}
+	}
+}
+
+func (w *Radio) Accessibility() node.Accessibility {
+	s := w.c.state()
+	if w.Selected() {
+		s |= node.StateChecked
+	}
+	return node.Accessibility{
+		Role:    node.RoleRadioButton,
+		Name:    w.Text,
+		State:   s,
+		Actions: w.c.actions(node.Action{Name: node.ActionSelect, Do: w.choose}),
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"sort"
+)
+
+// maxRow is larger than any row index.
+const maxRow = int(^uint(0) >> 1)
+
+// rowRange is the rows from i up to but not including j.
+type rowRange struct {
+	i, j int
+}
+
+// rowSet is a set of row indexes, such as a List's selected rows.
+//
+// The rows are held as sorted ranges, so that selecting a range of rows does
+// not take time proportional to the number of rows in that range. The ranges
+// are non-empty, and neither overlap nor touch. If inverted is set, the set
+// holds the rows that are not in the ranges, so that selecting every row
+// takes constant time, too.
+//
+// The zero value is an empty set.
+type rowSet struct {
+	ranges   []rowRange
+	inverted bool
+}
+
+// contains returns whether the i'th row is in the set.
+func (s *rowSet) contains(i int) bool {
+	k := sort.Search(len(s.ranges), func(k int) bool { return s.ranges[k].j > i })
+	in := k < len(s.ranges) && s.ranges[k].i <= i
+	return in != s.inverted
+}
+
+// reset sets s to hold only the rows from i up to but not including j.
+func (s *rowSet) reset(i, j int) {
+	s.ranges, s.inverted = s.ranges[:0], false
+	if i < j {
+		s.ranges = append(s.ranges, rowRange{i, j})
+	}
+}
+
+// setAll sets s to hold every row.
+func (s *rowSet) setAll() {
+	s.ranges, s.inverted = s.ranges[:0], true
+}
+
+// set adds the rows from i up to but not including j to s, or removes them
+// if in is false.
+func (s *rowSet) set(i, j int, in bool) {
+	if i >= j {
+		return
+	}
+	// The ranges in s.ranges[lo:hi] overlap or touch [i, j).
+	lo := sort.Search(len(s.ranges), func(k int) bool { return s.ranges[k].j >= i })
+	hi := sort.Search(len(s.ranges), func(k int) bool { return s.ranges[k].i > j })
+
+	var middle []rowRange
+	if in != s.inverted {
+		// Merge [i, j) with the ranges that it overlaps or touches.
+		if lo < hi {
+			if r := s.ranges[lo]; r.i < i {
+				i = r.i
+			}
+			if r := s.ranges[hi-1]; r.j > j {
+				j = r.j
+			}
+		}
+		middle = []rowRange{{i, j}}
+	} else if lo < hi {
+		// Cut [i, j) out of those ranges.
+		if r := s.ranges[lo]; r.i < i {
+			middle = append(middle, rowRange{r.i, i})
+		}
+		if r := s.ranges[hi-1]; r.j > j {
+			middle = append(middle, rowRange{j, r.j})
+		}
+	}
+	s.ranges = append(s.ranges[:lo], append(middle, s.ranges[hi:]...)...)
+}
+
+// rows returns the rows in s that are less than n, in increasing order.
+func (s *rowSet) rows(n int) []int {
+	var rows []int
+	add := func(i, j int) {
+		if j > n {
+			j = n
+		}
+		for ; i < j; i++ {
+			rows = append(rows, i)
+		}
+	}
+	if !s.inverted {
+		for _, r := range s.ranges {
+			add(r.i, r.j)
+		}
+		return rows
+	}
+	i := 0
+	for _, r := range s.ranges {
+		add(i, r.i)
+		i = r.j
+	}
+	add(i, n)
+	return rows
+}
@@ -0,0 +1,76 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRowSet(t *testing.T) {
+	const n = 20
+	s := rowSet{}
+	want := map[int]bool{}
+	check := func(desc string) {
+		t.Helper()
+		var wantRows []int
+		for i := 0; i < n; i++ {
+			if got := s.contains(i); got != want[i] {
+				t.Errorf("%s: contains(%d): got %t, want %t", desc, i, got, want[i])
+			}
+			if want[i] {
+				wantRows = append(wantRows, i)
+			}
+		}
+		if got := s.rows(n); !reflect.DeepEqual(got, wantRows) {
+			t.Errorf("%s: rows: got %v, want %v", desc, got, wantRows)
+		}
+		for k, r := range s.ranges {
+			if r.i >= r.j || k > 0 && s.ranges[k-1].j >= r.i {
+				t.Errorf("%s: ranges are not sorted, non-empty and apart: %v", desc, s.ranges)
+				break
+			}
+		}
+	}
+	set := func(i, j int, in bool) {
+		t.Helper()
+		s.set(i, j, in)
+		for ; i < j; i++ {
+			want[i] = in
+		}
+		check("set")
+	}
+
+	check("zero value")
+	set(3, 5, true)
+	set(8, 10, true)
+	// Touching ranges are merged.
+	set(5, 8, true)
+	if len(s.ranges) != 1 {
+		t.Errorf("merged ranges: got %v, want one range", s.ranges)
+	}
+	set(12, 13, true)
+	// Removing from the middle of a range splits it.
+	set(6, 7, false)
+	// Spanning several ranges.
+	set(4, 12, false)
+	set(2, 15, true)
+	set(0, 1, false)
+
+	s.setAll()
+	for i := 0; i < n; i++ {
+		want[i] = true
+	}
+	check("setAll")
+	set(5, 7, false)
+	set(6, 9, false)
+	set(8, 9, true)
+	set(0, 1, false)
+	set(0, 20, true)
+
+	s.reset(4, 6)
+	want = map[int]bool{4: true, 5: true}
+	check("reset")
+}
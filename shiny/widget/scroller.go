@@ -0,0 +1,546 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"time"
+
+	"golang.org/x/exp/shiny/gesture"
+	"golang.org/x/exp/shiny/screen"
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/lifecycle"
+	"golang.org/x/mobile/event/mouse"
+)
+
+const (
+	// flingTimeConstant is how quickly a fling's velocity decays.
+	flingTimeConstant = 325 * time.Millisecond
+
+	// minFlingSpeed is the speed, in pixels per second, below which a fling
+	// stops.
+	minFlingSpeed = 20
+)
+
+// Scroller is a shell widget that shows a viewport onto its inner widget,
+// which can be larger than the Scroller along the Scroller's axis. The inner
+// widget is measured without a size hint along that axis, and is scrolled by
+// the mouse wheel, by dragging, with inertia, by dragging the scrollbars, by
+// the arrow and page keys and programmatically.
+//
+// Like a Sheet, a Scroller provides a pixel buffer for its descendent widgets
+// to paint on. The buffer is the size of the Scroller, not of the inner
+// widget. Scrolling shifts the buffer's contents, and calls PaintBase only to
+// paint the newly exposed parts. The inner widget's Paint effects are clipped
+// to the Scroller.
+type Scroller struct {
+	node.ShellEmbed
+	Axis Axis
+
+	buf screen.Buffer
+	tex screen.Texture
+
+	// painted is the offset at which buf was painted.
+	painted image.Point
+
+	// offset is the scroll position: the inner widget's pixel that is shown
+	// at the Scroller's top-left. The inner widget's coordinate space
+	// includes its Box, so its outer rectangle starts at the zero point.
+	offset image.Point
+
+	// content is the size of the inner widget's outer rectangle, including
+	// its Box. It is set by Layout.
+	content image.Point
+
+	// barWidth and step are the scrollbar width and the distance scrolled by
+	// an arrow key or a mouse wheel step, in pixels. They are set by Layout.
+	barWidth int
+	step     int
+
+	// dragging is whether a drag gesture is scrolling the inner widget, and
+	// dragOffset is the offset at the start of that gesture or of dragging a
+	// scrollbar. dragBar is the scrollbar, if any, being dragged by the
+	// mouse, and dragPos is the mouse position when that drag started.
+	dragging   bool
+	dragOffset image.Point
+	dragBar    Axis
+	dragPos    image.Point
+
+	// velocity is the drag or fling velocity, in pixels per second, and
+	// lastTime is the time of the most recent drag event or fling step.
+	velocity [2]float64
+	lastPos  gesture.Point
+	lastTime time.Time
+	flinging bool
+}
+
+// NewScroller returns a new Scroller widget that scrolls along the given
+// axis or axes.
+func NewScroller(a Axis, inner node.Node) *Scroller {
+	w := &Scroller{
+		Axis: a,
+	}
+	w.Wrapper = w
+	if inner != nil {
+		w.Insert(inner, nil)
+	}
+	return w
+}
+
+// Offset returns the scroll position: the point in the inner widget's
+// coordinate space that is shown at the Scroller's top-left.
+func (w *Scroller) Offset() image.Point { return w.offset }
+
+// ScrollTo sets the scroll position, clamped so that the inner widget fills
+// the Scroller.
+func (w *Scroller) ScrollTo(offset image.Point) {
+	w.flinging = false
+	w.scrollTo(offset)
+}
+
+// ScrollToRect scrolls by the least amount that makes r, in the inner
+// widget's coordinate space, visible. If r is larger than the Scroller, its
+// top-left is made visible.
+func (w *Scroller) ScrollToRect(r image.Rectangle) {
+	offset, size := w.offset, w.Rect.Size()
+	if r.Max.X > offset.X+size.X {
+		offset.X = r.Max.X - size.X
+	}
+	if r.Min.X < offset.X {
+		offset.X = r.Min.X
+	}
+	if r.Max.Y > offset.Y+size.Y {
+		offset.Y = r.Max.Y - size.Y
+	}
+	if r.Min.Y < offset.Y {
+		offset.Y = r.Min.Y
+	}
+	w.ScrollTo(offset)
+}
+
+// scrollTo sets the scroll position, clamped to the valid range, and returns
+// whether it changed.
+func (w *Scroller) scrollTo(offset image.Point) bool {
+	c := w.FirstChild
+	if c == nil {
+		return false
+	}
+	max := w.content.Sub(w.Rect.Size())
+	if !w.Axis.Horizontal() || offset.X > max.X {
+		offset.X = max.X
+	}
+	if !w.Axis.Horizontal() || offset.X < 0 {
+		offset.X = 0
+	}
+	if !w.Axis.Vertical() || offset.Y > max.Y {
+		offset.Y = max.Y
+	}
+	if !w.Axis.Vertical() || offset.Y < 0 {
+		offset.Y = 0
+	}
+	if w.offset == offset {
+		return false
+	}
+	c.Rect = c.Rect.Add(w.offset).Sub(offset)
+	w.offset = offset
+	// Scrolling does not need a PaintBase, only a Paint.
+	w.Mark(node.MarkNeedsPaint)
+	return true
+}
+
+func (w *Scroller) release() {
+	if w.buf != nil {
+		w.buf.Release()
+		w.buf = nil
+	}
+	if w.tex != nil {
+		w.tex.Release()
+		w.tex = nil
+	}
+}
+
+func (w *Scroller) Measure(t *theme.Theme, widthHint, heightHint int) {
+	if w.Axis.Horizontal() {
+		widthHint = node.NoHint
+	}
+	if w.Axis.Vertical() {
+		heightHint = node.NoHint
+	}
+	w.ShellEmbed.Measure(t, widthHint, heightHint)
+}
+
+func (w *Scroller) Layout(t *theme.Theme) {
+	w.barWidth = t.Pixels(unit.DIPs(6)).Ceil()
+	w.step = t.Pixels(unit.Ems(wheelLines)).Ceil()
+
+	c := w.FirstChild
+	if c == nil {
+		return
+	}
+	size, outer := w.Rect.Size(), c.OuterSize(t)
+	if w.Axis.Horizontal() && size.X < outer.X {
+		size.X = outer.X
+	}
+	if w.Axis.Vertical() && size.Y < outer.Y {
+		size.Y = outer.Y
+	}
+	w.content = size
+	c.SetOuterRect(t, image.Rectangle{Max: size}.Sub(w.offset))
+	c.Wrapper.Layout(t)
+
+	// Re-clamp the offset, as the sizes may have changed.
+	w.scrollTo(w.offset)
+}
+
+// bars returns the scrollbars' tracks and thumbs, relative to the Scroller's
+// Rect.Min. A track is empty if there is nothing to scroll along that axis.
+func (w *Scroller) bars() (tracks, thumbs [2]image.Rectangle) {
+	c := w.FirstChild
+	if c == nil {
+		return tracks, thumbs
+	}
+	size, inner := w.Rect.Size(), w.content
+	if w.Axis.Horizontal() && inner.X > size.X {
+		tracks[0] = image.Rect(0, size.Y-w.barWidth, size.X, size.Y)
+		n := size.X * size.X / inner.X
+		x := (size.X - n) * w.offset.X / (inner.X - size.X)
+		thumbs[0] = image.Rect(x, size.Y-w.barWidth, x+n, size.Y)
+	}
+	if w.Axis.Vertical() && inner.Y > size.Y {
+		tracks[1] = image.Rect(size.X-w.barWidth, 0, size.X, size.Y)
+		n := size.Y * size.Y / inner.Y
+		y := (size.Y - n) * w.offset.Y / (inner.Y - size.Y)
+		thumbs[1] = image.Rect(size.X-w.barWidth, y, size.X, y+n)
+	}
+	return tracks, thumbs
+}
+
+func (w *Scroller) Paint(ctx *node.PaintContext, origin image.Point) (retErr error) {
+	w.Marks.UnmarkNeedsPaint()
+	c := w.FirstChild
+	if c == nil {
+		w.release()
+		return nil
+	}
+
+	fresh, size := false, w.Rect.Size()
+	if w.buf != nil && w.buf.Size() != size {
+		w.release()
+	}
+	if w.buf == nil {
+		if size.X <= 0 || size.Y <= 0 {
+			return nil
+		}
+		w.buf, retErr = ctx.Screen.NewBuffer(size)
+		if retErr != nil {
+			w.release()
+			return retErr
+		}
+		w.tex, retErr = ctx.Screen.NewTexture(size)
+		if retErr != nil {
+			w.release()
+			return retErr
+		}
+		fresh = true
+	}
+	var dirty []image.Rectangle
+	switch {
+	case fresh || c.Marks.NeedsPaintBase():
+		dirty = []image.Rectangle{w.buf.Bounds()}
+	case w.painted != w.offset:
+		dirty = scrollBuffer(w.buf.RGBA(), w.offset.Sub(w.painted))
+	}
+	w.painted = w.offset
+	if len(dirty) != 0 {
+		// The buffer's origin is the Scroller's Rect.Min, and the inner
+		// widget's Rect is already offset by the scroll position.
+		buf := w.buf.RGBA()
+		for _, r := range dirty {
+			pbc := &node.PaintBaseContext{
+				Theme: ctx.Theme,
+				Dst:   buf.SubImage(r).(*image.RGBA),
+			}
+			draw.Draw(pbc.Dst, r, image.Transparent, image.Point{}, draw.Src)
+			c.PaintBox(pbc, image.Point{})
+			if err := c.Wrapper.PaintBase(pbc, image.Point{}); err != nil {
+				return err
+			}
+		}
+		w.tex.Upload(image.Point{}, w.buf, w.buf.Bounds())
+	}
+
+	r := w.Rect.Add(origin)
+	src2dst := ctx.Src2Dst
+	translate(&src2dst, float64(r.Min.X), float64(r.Min.Y))
+	ctx.Drawer.Draw(src2dst, w.tex, w.tex.Bounds(), draw.Over, nil)
+
+	// Clip the inner widget's effects to the Scroller.
+	cctx := *ctx
+	cctx.Drawer = newClipDrawer(ctx.Drawer, ctx.Src2Dst, r)
+	if err := c.Wrapper.Paint(&cctx, r.Min); err != nil {
+		return err
+	}
+
+	// Draw the scrollbars on top of the inner widget.
+	pal := ctx.Theme.GetPalette()
+	tracks, thumbs := w.bars()
+	for i := range tracks {
+		if tracks[i].Empty() {
+			continue
+		}
+		thumb := pal.Neutral().C
+		if w.dragBar == Axis(i+1) {
+			thumb = pal.Accent().C
+		}
+		w.drawUniform(ctx, origin, tracks[i], pal.Light().C)
+		w.drawUniform(ctx, origin, thumbs[i], thumb)
+	}
+
+	if w.flinging {
+		w.fling(time.Now())
+	}
+	return nil
+}
+
+// scrollBuffer shifts the contents of dst for a scroll by delta, so that the
+// pixel at p moves to p.Sub(delta), and returns the parts of dst that need to
+// be painted again.
+func scrollBuffer(dst *image.RGBA, delta image.Point) (dirty []image.Rectangle) {
+	b := dst.Bounds()
+	if delta.X >= b.Dx() || -delta.X >= b.Dx() || delta.Y >= b.Dy() || -delta.Y >= b.Dy() {
+		return []image.Rectangle{b}
+	}
+	draw.Draw(dst, b, dst, b.Min.Add(delta), draw.Src)
+	switch {
+	case delta.X > 0:
+		dirty = append(dirty, image.Rect(b.Max.X-delta.X, b.Min.Y, b.Max.X, b.Max.Y))
+	case delta.X < 0:
+		dirty = append(dirty, image.Rect(b.Min.X, b.Min.Y, b.Min.X-delta.X, b.Max.Y))
+	}
+	switch {
+	case delta.Y > 0:
+		dirty = append(dirty, image.Rect(b.Min.X, b.Max.Y-delta.Y, b.Max.X, b.Max.Y))
+	case delta.Y < 0:
+		dirty = append(dirty, image.Rect(b.Min.X, b.Min.Y, b.Max.X, b.Min.Y-delta.Y))
+	}
+	return dirty
+}
+
+// drawUniform fills r, relative to the Scroller's Rect.Min, with src.
+func (w *Scroller) drawUniform(ctx *node.PaintContext, origin image.Point, r image.Rectangle, src color.Color) {
+	r = r.Add(origin).Add(w.Rect.Min)
+	src2dst := ctx.Src2Dst
+	translate(&src2dst, float64(r.Min.X), float64(r.Min.Y))
+	ctx.Drawer.DrawUniform(src2dst, src, image.Rect(0, 0, r.Dx(), r.Dy()), draw.Src, nil)
+}
+
+// fling advances a fling animation to the time now.
+func (w *Scroller) fling(now time.Time) {
+	dt := now.Sub(w.lastTime).Seconds()
+	w.lastTime = now
+	decay := math.Exp(-dt / flingTimeConstant.Seconds())
+	w.velocity[0] *= decay
+	w.velocity[1] *= decay
+	offset := w.offset.Sub(image.Point{
+		int(math.Round(w.velocity[0] * dt)),
+		int(math.Round(w.velocity[1] * dt)),
+	})
+	moved := w.scrollTo(offset)
+	if !moved || math.Hypot(w.velocity[0], w.velocity[1]) < minFlingSpeed {
+		w.flinging = false
+		return
+	}
+	// Marking the Scroller during its Paint asks for another paint, on the
+	// next frame.
+	w.Mark(node.MarkNeedsPaint)
+}
+
+func (w *Scroller) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
+	w.Marks.UnmarkNeedsPaintBase()
+	// Do not recursively call PaintBase on our children. We create our own
+	// buffers, and Scroller.Paint will call PaintBase with our
+	// PaintBaseContext instead of our ancestor's.
+	return nil
+}
+
+func (w *Scroller) OnChildMarked(child node.Node, newMarks node.Marks) {
+	if newMarks&node.MarkNeedsPaintBase != 0 {
+		newMarks &^= node.MarkNeedsPaintBase
+		newMarks |= node.MarkNeedsPaint
+	}
+	w.Mark(newMarks)
+}
+
+func (w *Scroller) OnLifecycleEvent(e lifecycle.Event) {
+	if e.Crosses(lifecycle.StageVisible) == lifecycle.CrossOff {
+		w.release()
+	}
+	w.ShellEmbed.OnLifecycleEvent(e)
+}
+
+func (w *Scroller) OnInputEvent(e interface{}, origin image.Point) node.EventHandled {
+	// The scrollbars are on top of the inner widget, so they get the first
+	// look at mouse events.
+	if e, ok := e.(mouse.Event); ok && w.onBarEvent(e, origin) {
+		return node.Handled
+	}
+	if w.ShellEmbed.OnInputEvent(e, origin) == node.Handled {
+		return node.Handled
+	}
+
+	switch e := e.(type) {
+	case mouse.Event:
+		if e.Direction != mouse.DirStep {
+			break
+		}
+		d := image.Point{}
+		switch e.Button {
+		case mouse.ButtonWheelUp:
+			d.Y = -w.step
+		case mouse.ButtonWheelDown:
+			d.Y = +w.step
+		case mouse.ButtonWheelLeft:
+			d.X = -w.step
+		case mouse.ButtonWheelRight:
+			d.X = +w.step
+		}
+		if !w.Axis.Vertical() && d.X == 0 {
+			// Let a vertical wheel scroll a horizontal Scroller.
+			d.X, d.Y = d.Y, 0
+		}
+		w.ScrollTo(w.offset.Add(d))
+		return node.Handled
+
+	case gesture.Event:
+		return w.onGestureEvent(e)
+
+	case key.Event:
+		if e.Direction == key.DirRelease {
+			break
+		}
+		d, page := image.Point{}, w.Rect.Size()
+		switch e.Code {
+		case key.CodeUpArrow:
+			d.Y = -w.step
+		case key.CodeDownArrow:
+			d.Y = +w.step
+		case key.CodeLeftArrow:
+			d.X = -w.step
+		case key.CodeRightArrow:
+			d.X = +w.step
+		case key.CodePageUp:
+			d.Y = -page.Y
+		case key.CodePageDown:
+			d.Y = +page.Y
+		default:
+			return node.NotHandled
+		}
+		w.ScrollTo(w.offset.Add(d))
+		return node.Handled
+	}
+	return node.NotHandled
+}
+
+// onBarEvent handles dragging a scrollbar's thumb. It returns whether the
+// event was handled.
+func (w *Scroller) onBarEvent(e mouse.Event, origin image.Point) bool {
+	p := image.Point{int(e.X), int(e.Y)}.Sub(origin).Sub(w.Rect.Min)
+	switch e.Direction {
+	case mouse.DirPress:
+		if e.Button != mouse.ButtonLeft {
+			return false
+		}
+		tracks, _ := w.bars()
+		for i, track := range tracks {
+			if p.In(track) {
+				w.dragBar, w.dragPos, w.dragOffset = Axis(i+1), p, w.offset
+				w.flinging = false
+				w.Mark(node.MarkNeedsPaint)
+				return true
+			}
+		}
+	case mouse.DirNone:
+		if w.dragBar == AxisNone {
+			return false
+		}
+		tracks, thumbs := w.bars()
+		inner, size := w.content, w.Rect.Size()
+		offset, d := w.dragOffset, p.Sub(w.dragPos)
+		if w.dragBar == AxisHorizontal {
+			if n := tracks[0].Dx() - thumbs[0].Dx(); n > 0 {
+				offset.X += d.X * (inner.X - size.X) / n
+			}
+		} else {
+			if n := tracks[1].Dy() - thumbs[1].Dy(); n > 0 {
+				offset.Y += d.Y * (inner.Y - size.Y) / n
+			}
+		}
+		w.scrollTo(offset)
+		return true
+	case mouse.DirRelease:
+		if w.dragBar == AxisNone {
+			return false
+		}
+		w.dragBar = AxisNone
+		w.Mark(node.MarkNeedsPaint)
+		return true
+	}
+	return false
+}
+
+func (w *Scroller) onGestureEvent(e gesture.Event) node.EventHandled {
+	switch e.Type {
+	case gesture.TypeStart:
+		w.flinging = false
+		w.velocity = [2]float64{}
+		w.dragOffset = w.offset
+		w.lastPos, w.lastTime = e.CurrentPos, e.Time
+		return node.Handled
+
+	case gesture.TypeDrag:
+		w.dragging = true
+		w.scrollTo(w.dragOffset.Sub(image.Point{
+			int(e.CurrentPos.X - e.InitialPos.X),
+			int(e.CurrentPos.Y - e.InitialPos.Y),
+		}))
+		// Estimate the velocity, smoothing out jitter between events.
+		if dt := e.Time.Sub(w.lastTime).Seconds(); dt > 0 {
+			const smoothing = 0.8
+			vx := float64(e.CurrentPos.X-w.lastPos.X) / dt
+			vy := float64(e.CurrentPos.Y-w.lastPos.Y) / dt
+			w.velocity[0] = smoothing*vx + (1-smoothing)*w.velocity[0]
+			w.velocity[1] = smoothing*vy + (1-smoothing)*w.velocity[1]
+		}
+		w.lastPos, w.lastTime = e.CurrentPos, e.Time
+		return node.Handled
+
+	case gesture.TypeEnd:
+		if !w.dragging {
+			return node.NotHandled
+		}
+		w.dragging = false
+		// A drag that paused before ending does not fling.
+		if e.Time.Sub(w.lastTime) < 100*time.Millisecond &&
+			math.Hypot(w.velocity[0], w.velocity[1]) >= minFlingSpeed {
+			w.flinging = true
+			w.lastTime = time.Now()
+			w.Mark(node.MarkNeedsPaint)
+		}
+		return node.Handled
+	}
+	return node.NotHandled
+}
+
+func (w *Scroller) Accessibility() node.Accessibility {
+	return node.Accessibility{
+		Role: node.RoleScrollPane,
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/shiny/screen"
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/image/math/f64"
+)
+
+// newTestScroller returns a laid out Scroller, 100 by 50 pixels, whose inner
+// widget is 300 by 200 pixels.
+func newTestScroller(a Axis) (*Scroller, *Sizer) {
+	inner := NewSizer(unit.Pixels(300), unit.Pixels(200), nil)
+	w := NewScroller(a, inner)
+	w.Measure(nil, 100, 50)
+	inner.Measure(nil, 300, 200)
+	w.Rect = image.Rect(0, 0, 100, 50)
+	w.Layout(nil)
+	return w, inner
+}
+
+func TestScrollerScrollTo(t *testing.T) {
+	testCases := []struct {
+		axis   Axis
+		offset image.Point
+		want   image.Point
+	}{
+		{AxisBoth, image.Point{10, 20}, image.Point{10, 20}},
+		{AxisBoth, image.Point{-10, -20}, image.Point{0, 0}},
+		{AxisBoth, image.Point{1000, 1000}, image.Point{200, 150}},
+		{AxisBoth, image.Point{200, 150}, image.Point{200, 150}},
+		{AxisVertical, image.Point{10, 20}, image.Point{0, 20}},
+		{AxisVertical, image.Point{10, 1000}, image.Point{0, 150}},
+		{AxisHorizontal, image.Point{10, 20}, image.Point{10, 0}},
+		{AxisHorizontal, image.Point{-10, 20}, image.Point{0, 0}},
+	}
+	for _, tc := range testCases {
+		w, inner := newTestScroller(tc.axis)
+		w.ScrollTo(tc.offset)
+		if got := w.Offset(); got != tc.want {
+			t.Errorf("axis %v, ScrollTo(%v): Offset: got %v, want %v", tc.axis, tc.offset, got, tc.want)
+			continue
+		}
+		// The inner widget is moved by the negated offset.
+		if got, want := inner.Rect.Min, tc.want.Mul(-1); got != want {
+			t.Errorf("axis %v, ScrollTo(%v): inner Rect.Min: got %v, want %v", tc.axis, tc.offset, got, want)
+		}
+	}
+}
+
+func TestScrollerScrollToRect(t *testing.T) {
+	testCases := []struct {
+		start image.Point
+		r     image.Rectangle
+		want  image.Point
+	}{
+		// Already visible.
+		{image.Point{0, 0}, image.Rect(10, 10, 20, 20), image.Point{0, 0}},
+		{image.Point{50, 50}, image.Rect(60, 60, 70, 70), image.Point{50, 50}},
+		// Below and right of the viewport.
+		{image.Point{0, 0}, image.Rect(120, 60, 130, 70), image.Point{30, 20}},
+		// Above and left of the viewport.
+		{image.Point{100, 100}, image.Rect(20, 30, 30, 40), image.Point{20, 30}},
+		// Larger than the viewport: its top-left is made visible.
+		{image.Point{0, 0}, image.Rect(50, 40, 250, 140), image.Point{50, 40}},
+		// Past the inner widget's end: clamped.
+		{image.Point{0, 0}, image.Rect(290, 190, 310, 210), image.Point{200, 150}},
+	}
+	for _, tc := range testCases {
+		w, _ := newTestScroller(AxisBoth)
+		w.ScrollTo(tc.start)
+		w.ScrollToRect(tc.r)
+		if got := w.Offset(); got != tc.want {
+			t.Errorf("start %v, ScrollToRect(%v): got %v, want %v", tc.start, tc.r, got, tc.want)
+		}
+	}
+}
+
+func TestScrollBuffer(t *testing.T) {
+	testCases := []struct {
+		delta image.Point
+		want  []image.Rectangle
+	}{
+		{image.Point{0, 0}, nil},
+		{image.Point{0, 3}, []image.Rectangle{image.Rect(0, 7, 10, 10)}},
+		{image.Point{0, -3}, []image.Rectangle{image.Rect(0, 0, 10, 3)}},
+		{image.Point{2, 0}, []image.Rectangle{image.Rect(8, 0, 10, 10)}},
+		{image.Point{-2, 1}, []image.Rectangle{image.Rect(0, 0, 2, 10), image.Rect(0, 9, 10, 10)}},
+		{image.Point{0, 10}, []image.Rectangle{image.Rect(0, 0, 10, 10)}},
+		{image.Point{-12, 0}, []image.Rectangle{image.Rect(0, 0, 10, 10)}},
+	}
+	for _, tc := range testCases {
+		// Each pixel's red and green values are its x and y coordinates.
+		m := image.NewRGBA(image.Rect(0, 0, 10, 10))
+		for y := 0; y < 10; y++ {
+			for x := 0; x < 10; x++ {
+				m.SetRGBA(x, y, color.RGBA{uint8(x), uint8(y), 0, 0xff})
+			}
+		}
+		got := scrollBuffer(m, tc.delta)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("delta %v: dirty: got %v, want %v", tc.delta, got, tc.want)
+			continue
+		}
+		// Pixels outside the dirty rectangles hold what was at p.Add(delta).
+	loop:
+		for y := 0; y < 10; y++ {
+			for x := 0; x < 10; x++ {
+				p := image.Point{x, y}
+				for _, r := range got {
+					if p.In(r) {
+						continue loop
+					}
+				}
+				q := p.Add(tc.delta)
+				if c, want := m.RGBAAt(x, y), (color.RGBA{uint8(q.X), uint8(q.Y), 0, 0xff}); c != want {
+					t.Errorf("delta %v: pixel %v: got %v, want %v", tc.delta, p, c, want)
+					break loop
+				}
+			}
+		}
+	}
+}
+
+// logDrawer is a screen.Drawer that logs the calls made to it.
+type logDrawer struct {
+	log []string
+}
+
+func (d *logDrawer) Draw(src2dst f64.Aff3, src screen.Texture, sr image.Rectangle, op draw.Op, opts *screen.DrawOptions) {
+	d.log = append(d.log, fmt.Sprintf("Draw %v %v", src2dst, sr))
+}
+
+func (d *logDrawer) DrawUniform(src2dst f64.Aff3, src color.Color, sr image.Rectangle, op draw.Op, opts *screen.DrawOptions) {
+	d.log = append(d.log, fmt.Sprintf("DrawUniform %v %v", src2dst, sr))
+}
+
+func (d *logDrawer) Copy(dp image.Point, src screen.Texture, sr image.Rectangle, op draw.Op, opts *screen.DrawOptions) {
+	d.log = append(d.log, fmt.Sprintf("Copy %v %v", dp, sr))
+}
+
+func (d *logDrawer) Scale(dr image.Rectangle, src screen.Texture, sr image.Rectangle, op draw.Op, opts *screen.DrawOptions) {
+	d.log = append(d.log, fmt.Sprintf("Scale %v %v", dr, sr))
+}
+
+func TestClipDrawer(t *testing.T) {
+	at := func(x, y float64) f64.Aff3 { return f64.Aff3{1, 0, x, 0, 1, y} }
+	scaled := f64.Aff3{2, 0, 0, 0, 2, 0}
+
+	testCases := []struct {
+		desc string
+		draw func(d screen.Drawer)
+		want []string
+	}{{
+		"inside",
+		func(d screen.Drawer) { d.DrawUniform(at(20, 20), color.Black, image.Rect(0, 0, 10, 10), draw.Src, nil) },
+		[]string{"DrawUniform [1 0 20 0 1 20] (0,0)-(10,10)"},
+	}, {
+		"outside",
+		func(d screen.Drawer) {
+			d.DrawUniform(at(100, 20), color.Black, image.Rect(0, 0, 10, 10), draw.Src, nil)
+		},
+		nil,
+	}, {
+		"partly inside, translated",
+		func(d screen.Drawer) { d.Draw(at(5, 45), nil, image.Rect(0, 0, 10, 10), draw.Src, nil) },
+		[]string{"Draw [1 0 5 0 1 45] (5,0)-(10,5)"},
+	}, {
+		"partly inside, scaled",
+		func(d screen.Drawer) { d.Draw(scaled, nil, image.Rect(0, 0, 10, 10), draw.Src, nil) },
+		[]string{"Draw [2 0 0 0 2 0] (0,0)-(10,10)"},
+	}, {
+		"Copy",
+		func(d screen.Drawer) { d.Copy(image.Point{55, 5}, nil, image.Rect(0, 0, 10, 10), draw.Src, nil) },
+		[]string{"Copy (55,10) (0,5)-(5,10)"},
+	}, {
+		"Scale",
+		func(d screen.Drawer) {
+			d.Scale(image.Rect(40, 40, 60, 60), nil, image.Rect(0, 0, 10, 10), draw.Src, nil)
+		},
+		[]string{"Scale (40,40)-(60,50) (0,0)-(10,5)"},
+	}}
+	for _, tc := range testCases {
+		ld := &logDrawer{}
+		// The clip rectangle is (10,10)-(60,50) in dst space.
+		tc.draw(newClipDrawer(ld, at(10, 10), image.Rect(0, 0, 50, 40)))
+		if !reflect.DeepEqual(ld.log, tc.want) {
+			t.Errorf("%s: got %q, want %q", tc.desc, ld.log, tc.want)
+		}
+	}
+}
@@ -6,6 +6,7 @@ package widget
 
 import (
 	"image"
+	"image/color"
 	"image/draw"
 
 	"golang.org/x/exp/shiny/screen"
@@ -27,10 +28,33 @@ import (
 // editor consisting of a small header bar and a large text widget. Those two
 // nodes may be backed by two separate Sheets, since scrolling the latter
 // should not scroll the former.
+//
+// A Sheet's Offset and Transparency are effects: they change how its buffer
+// is drawn, not the buffer's contents. Changing them, such as by animating
+// them, needs only a node.MarkNeedsPaint, not a node.MarkNeedsPaintBase.
 type Sheet struct {
 	node.ShellEmbed
-	buf screen.Buffer
-	tex screen.Texture
+
+	// Offset is added to the Sheet's position when drawing its buffer and
+	// its descendants' effects, without laying the Sheet out again. It does
+	// not affect where input events are sent.
+	Offset image.Point
+
+	// Transparency is how transparent the Sheet is, from 0 (opaque) to 1
+	// (invisible). A transparent Sheet's buffer is composited into a second
+	// buffer, on the CPU, before it is uploaded to its texture.
+	//
+	// TODO: use the GPU, once screen.DrawOptions supports transparency.
+	Transparency float64
+
+	buf  screen.Buffer
+	fade screen.Buffer
+	tex  screen.Texture
+
+	// uploaded is whether tex holds buf's contents, at the opacity
+	// uploadedAlpha.
+	uploaded      bool
+	uploadedAlpha uint8
 }
 
 // NewSheet returns a new Sheet widget.
@@ -48,6 +72,11 @@ func (w *Sheet) release() {
 		w.buf.Release()
 		w.buf = nil
 	}
+	if w.fade != nil {
+		w.fade.Release()
+		w.fade = nil
+	}
+	w.uploaded = false
 	if w.tex != nil {
 		w.tex.Release()
 		w.tex = nil
@@ -80,14 +109,46 @@ func (w *Sheet) Paint(ctx *node.PaintContext, origin image.Point) (retErr error)
 		fresh = true
 	}
 	if fresh || c.Marks.NeedsPaintBase() {
-		c.Wrapper.PaintBase(&node.PaintBaseContext{
+		pbCtx := &node.PaintBaseContext{
 			Theme: ctx.Theme,
 			Dst:   w.buf.RGBA(),
-		}, image.Point{})
+		}
+		// Clear the buffer, as the child's Box, and its descendants, may paint
+		// with draw.Over.
+		draw.Draw(pbCtx.Dst, pbCtx.Dst.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		c.PaintBox(pbCtx, image.Point{})
+		c.Wrapper.PaintBase(pbCtx, image.Point{})
+		w.uploaded = false
 	}
 
-	w.tex.Upload(image.Point{}, w.buf, w.buf.Bounds())
+	alpha := w.alpha()
+	if alpha == 0 {
+		// Paint the descendants anyway, so that they are unmarked and later
+		// marks propagate up to the window, but discard their effects.
+		c2 := *ctx
+		c2.Drawer = discardDrawer{}
+		return c.Wrapper.Paint(&c2, origin.Add(w.Offset).Add(w.Rect.Min))
+	}
+	if !w.uploaded || w.uploadedAlpha != alpha {
+		src := w.buf
+		if alpha != 0xff {
+			if w.fade == nil {
+				w.fade, retErr = ctx.Screen.NewBuffer(size)
+				if retErr != nil {
+					w.release()
+					return retErr
+				}
+			}
+			b := w.buf.Bounds()
+			draw.DrawMask(w.fade.RGBA(), b, w.buf.RGBA(), b.Min,
+				image.NewUniform(color.Alpha{alpha}), image.Point{}, draw.Src)
+			src = w.fade
+		}
+		w.tex.Upload(image.Point{}, src, src.Bounds())
+		w.uploaded, w.uploadedAlpha = true, alpha
+	}
 
+	origin = origin.Add(w.Offset)
 	src2dst := ctx.Src2Dst
 	translate(&src2dst,
 		float64(origin.X+w.Rect.Min.X),
@@ -96,9 +157,33 @@ func (w *Sheet) Paint(ctx *node.PaintContext, origin image.Point) (retErr error)
 	// TODO: should draw.Over be configurable?
 	ctx.Drawer.Draw(src2dst, w.tex, w.tex.Bounds(), draw.Over, nil)
 
+	// TODO: apply the Transparency to the descendants' effects.
 	return c.Wrapper.Paint(ctx, origin.Add(w.Rect.Min))
 }
 
+// alpha returns the opacity corresponding to w.Transparency.
+func (w *Sheet) alpha() uint8 {
+	switch {
+	case w.Transparency <= 0:
+		return 0xff
+	case w.Transparency >= 1:
+		return 0
+	}
+	return uint8(0xff*(1-w.Transparency) + 0.5)
+}
+
+// discardDrawer is a screen.Drawer that draws nothing.
+type discardDrawer struct{}
+
+func (discardDrawer) Draw(f64.Aff3, screen.Texture, image.Rectangle, draw.Op, *screen.DrawOptions) {
+}
+func (discardDrawer) DrawUniform(f64.Aff3, color.Color, image.Rectangle, draw.Op, *screen.DrawOptions) {
+}
+func (discardDrawer) Copy(image.Point, screen.Texture, image.Rectangle, draw.Op, *screen.DrawOptions) {
+}
+func (discardDrawer) Scale(image.Rectangle, screen.Texture, image.Rectangle, draw.Op, *screen.DrawOptions) {
+}
+
 func translate(a *f64.Aff3, tx, ty float64) {
 	a[2] += a[0]*tx + a[1]*ty
 	a[5] += a[3]*tx + a[4]*ty
@@ -36,6 +36,6 @@ func (w *Sizer) Measure(t *theme.Theme, widthHint, heightHint int) {
 	w.MeasuredSize.X = t.Pixels(w.NaturalWidth).Round()
 	w.MeasuredSize.Y = t.Pixels(w.NaturalHeight).Round()
 	if c := w.FirstChild; c != nil {
-		c.Wrapper.Measure(t, w.MeasuredSize.X, w.MeasuredSize.Y)
+		c.MeasureOuter(t, w.MeasuredSize.X, w.MeasuredSize.Y)
 	}
 }
@@ -0,0 +1,214 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/draw"
+	"math"
+	"strconv"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/mouse"
+)
+
+// Slider is a leaf widget that selects a value in the range [Min, Max] by
+// dragging a thumb along a horizontal track or, when it has the keyboard
+// focus, by the arrow, Page Up, Page Down, Home and End keys.
+type Slider struct {
+	node.LeafEmbed
+
+	// Min and Max are the range of values.
+	Min, Max float64
+
+	// Step, if positive, is the granularity of the value. The value is
+	// rounded to the nearest multiple of Step above Min. Keyboard adjustments
+	// move the value by Step, or by 1% of the range if Step is not positive.
+	Step float64
+
+	// OnChange, if non-nil, is called when the user changes the value.
+	OnChange func(value float64)
+
+	value float64
+	c     control
+}
+
+// NewSlider returns a new Slider widget.
+func NewSlider(min, max, value float64) *Slider {
+	w := &Slider{
+		Min: min,
+		Max: max,
+	}
+	w.Wrapper = w
+	w.Focusable = true
+	w.value = w.clamp(value)
+	return w
+}
+
+// Value returns the slider's value.
+func (w *Slider) Value() float64 { return w.value }
+
+// SetValue sets the slider's value. It does not call OnChange.
+func (w *Slider) SetValue(value float64) {
+	if value = w.clamp(value); w.value != value {
+		w.value = value
+		w.Mark(node.MarkNeedsPaintBase)
+	}
+}
+
+// Disabled returns whether the slider is disabled.
+func (w *Slider) Disabled() bool { return w.c.disabled }
+
+// SetDisabled sets whether the slider is disabled. A disabled slider cannot be
+// changed by the user or focused.
+func (w *Slider) SetDisabled(disabled bool) { w.c.setDisabled(&w.Embed, disabled) }
+
+// clamp returns v rounded to the Step and clamped to the range [Min, Max].
+func (w *Slider) clamp(v float64) float64 {
+	if w.Step > 0 {
+		v = w.Min + math.Floor((v-w.Min)/w.Step+0.5)*w.Step
+	}
+	if v > w.Max {
+		v = w.Max
+	}
+	if v < w.Min {
+		v = w.Min
+	}
+	return v
+}
+
+// change sets the value and calls OnChange if the value changed.
+func (w *Slider) change(v float64) {
+	old := w.value
+	if w.SetValue(v); w.value != old && w.OnChange != nil {
+		w.OnChange(w.value)
+	}
+}
+
+// step returns the distance moved by a keyboard adjustment.
+func (w *Slider) step() float64 {
+	if w.Step > 0 {
+		return w.Step
+	}
+	return (w.Max - w.Min) / 100
+}
+
+// track returns the horizontal extent of the thumb's center, and the thumb's
+// radius, for a slider whose Rect, in some coordinate space, is r.
+func (w *Slider) track(r image.Rectangle) (x0, x1, radius int) {
+	radius = r.Dy() / 2
+	return r.Min.X + radius, r.Max.X - radius, radius
+}
+
+func (w *Slider) Measure(t *theme.Theme, widthHint, heightHint int) {
+	face := t.AcquireFontFace(theme.FontFaceOptions{})
+	defer t.ReleaseFontFace(theme.FontFaceOptions{}, face)
+	m := face.Metrics()
+
+	if widthHint < 0 {
+		// TODO: make the default width configurable.
+		widthHint = t.Pixels(unit.Ems(10)).Ceil()
+	}
+	w.MeasuredSize = image.Point{widthHint, m.Ascent.Ceil() + m.Descent.Ceil()}
+}
+
+func (w *Slider) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
+	w.Marks.UnmarkNeedsPaintBase()
+	r := w.Rect.Add(origin)
+	dst := ctx.Dst.SubImage(r).(*image.RGBA)
+	if dst.Bounds().Empty() {
+		return nil
+	}
+
+	t := ctx.Theme
+	pal := t.GetPalette()
+	x0, x1, radius := w.track(r)
+	x := x0
+	if w.Max > w.Min {
+		x += int(float64(x1-x0) * (w.value - w.Min) / (w.Max - w.Min))
+	}
+
+	lw := lineWidth(t)
+	cy := (r.Min.Y + r.Max.Y) / 2
+	active := pal.Accent()
+	if w.c.disabled {
+		active = pal.Dark()
+	}
+	draw.Draw(dst, image.Rect(x0, cy-lw, x1, cy+lw), pal.Dark(), image.Point{}, draw.Src)
+	draw.Draw(dst, image.Rect(x0, cy-lw, x, cy+lw), active, image.Point{}, draw.Src)
+
+	thumb := image.Rect(x-radius, cy-radius, x+radius, cy+radius)
+	fillRoundRect(dst, thumb, active)
+	if w.c.pressed || w.c.hovered {
+		fillRoundRect(dst, thumb.Inset(radius/2), pal.Background())
+	}
+	w.c.paintFocus(dst, t, r)
+	return nil
+}
+
+func (w *Slider) OnInputEvent(e interface{}, origin image.Point) node.EventHandled {
+	handled, _ := w.c.onInputEvent(&w.Embed, e)
+	if w.c.disabled {
+		return handled
+	}
+
+	switch e := e.(type) {
+	case mouse.Event:
+		if (e.Direction == mouse.DirPress && e.Button == mouse.ButtonLeft) ||
+			(e.Direction == mouse.DirNone && w.c.pressed) {
+
+			x0, x1, _ := w.track(w.Rect.Add(origin))
+			if x1 > x0 {
+				f := (float64(e.X) - float64(x0)) / float64(x1-x0)
+				w.change(w.Min + f*(w.Max-w.Min))
+			}
+		}
+
+	case key.Event:
+		if e.Direction == key.DirRelease {
+			break
+		}
+		step := w.step()
+		switch e.Code {
+		case key.CodeLeftArrow, key.CodeDownArrow:
+			w.change(w.value - step)
+		case key.CodeRightArrow, key.CodeUpArrow:
+			w.change(w.value + step)
+		case key.CodePageDown:
+			w.change(w.value - 10*step)
+		case key.CodePageUp:
+			w.change(w.value + 10*step)
+		case key.CodeHome:
+			w.change(w.Min)
+		case key.CodeEnd:
+			w.change(w.Max)
+		default:
+			return handled
+		}
+		return node.Handled
+	}
+	return handled
+}
+
+func (w *Slider) Accessibility() node.Accessibility {
+	return node.Accessibility{
+		Role:  node.RoleSlider,
+		Value: strconv.FormatFloat(w.value, 'g', -1, 64),
+		Range: &node.Range{
+			Min:   w.Min,
+			Max:   w.Max,
+			Step:  w.Step,
+			Value: w.value,
+		},
+		State: w.c.state(),
+		Actions: w.c.actions(
+			node.Action{Name: node.ActionIncrement, Do: func() { w.change(w.value + w.step()) }},
+			node.Action{Name: node.ActionDecrement, Do: func() { w.change(w.value - w.step()) }},
+		),
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+)
+
+// Alignment is how a child widget is aligned within the space available to
+// it, along one axis.
+type Alignment uint8
+
+const (
+	// AlignStart aligns the child to the left or top edge.
+	AlignStart Alignment = iota
+	// AlignCenter centers the child.
+	AlignCenter
+	// AlignEnd aligns the child to the right or bottom edge.
+	AlignEnd
+	// AlignStretch stretches the child to fill the space.
+	AlignStretch
+)
+
+// align returns the offset and size of a child whose natural size is size,
+// when aligned by a within space.
+func (a Alignment) align(size, space int) (offset, newSize int) {
+	if a == AlignStretch || size > space {
+		return 0, space
+	}
+	switch a {
+	case AlignCenter:
+		return (space - size) / 2, size
+	case AlignEnd:
+		return space - size, size
+	}
+	return 0, size
+}
+
+// Stack is a container widget that lays out its children on top of each
+// other, each sharing the Stack's Rect. Later children are painted over
+// earlier ones, and get input events before earlier ones. A child's position
+// and size within the Stack can be adjusted if its LayoutData is a
+// StackLayoutData.
+//
+// For example, a badge can be placed at the top right corner of an icon by
+// stacking the two, with the badge's HAlign being AlignEnd.
+type Stack struct {
+	node.ContainerEmbed
+}
+
+// NewStack returns a new Stack widget containing the given children. The
+// first child is at the bottom, and the last child is at the top.
+func NewStack(children ...node.Node) *Stack {
+	w := &Stack{}
+	w.Wrapper = w
+	for _, c := range children {
+		w.Insert(c, nil)
+	}
+	return w
+}
+
+// Raise moves the child n to the top of the Stack.
+func (w *Stack) Raise(n node.Node) { raise(&w.ContainerEmbed, n) }
+
+// Lower moves the child n to the bottom of the Stack.
+func (w *Stack) Lower(n node.Node) { lower(&w.ContainerEmbed, n) }
+
+func (w *Stack) Measure(t *theme.Theme, widthHint, heightHint int) {
+	mSize := image.Point{}
+	for c := w.FirstChild; c != nil; c = c.NextSibling {
+		d, _ := c.LayoutData.(StackLayoutData)
+		topLeft, bottomRight := d.insets(t)
+		insets := topLeft.Add(bottomRight)
+		c.MeasureOuter(t, shrinkHint(widthHint, insets.X), shrinkHint(heightHint, insets.Y))
+		size := c.OuterSize(t).Add(insets)
+		if mSize.X < size.X {
+			mSize.X = size.X
+		}
+		if mSize.Y < size.Y {
+			mSize.Y = size.Y
+		}
+	}
+	w.MeasuredSize = mSize
+}
+
+func (w *Stack) Layout(t *theme.Theme) {
+	for c := w.FirstChild; c != nil; c = c.NextSibling {
+		d, _ := c.LayoutData.(StackLayoutData)
+		topLeft, bottomRight := d.insets(t)
+		r := image.Rectangle{
+			Min: topLeft,
+			Max: w.Rect.Size().Sub(bottomRight),
+		}
+		if r.Max.X < r.Min.X {
+			r.Max.X = r.Min.X
+		}
+		if r.Max.Y < r.Min.Y {
+			r.Max.Y = r.Min.Y
+		}
+		size := c.OuterSize(t)
+		x, dx := d.HAlign.align(size.X, r.Dx())
+		y, dy := d.VAlign.align(size.Y, r.Dy())
+		c.SetOuterRect(t, image.Rect(x, y, x+dx, y+dy).Add(r.Min))
+		c.Wrapper.Layout(t)
+	}
+}
+
+// StackLayoutData is the node LayoutData type for a Stack's children.
+type StackLayoutData struct {
+	// HAlign and VAlign are how the child's outer rectangle, including its
+	// Box, is aligned horizontally and vertically within the Stack's Rect,
+	// after any insets. The zero values align the child, at its natural size,
+	// to the top left corner.
+	HAlign, VAlign Alignment
+
+	// Top, Right, Bottom and Left are the insets from the Stack's edges of
+	// the space that the child is aligned in. They are in addition to any
+	// margin in the child's Box.
+	Top, Right, Bottom, Left unit.Value
+}
+
+// insets returns d's left and top insets, and its right and bottom insets, in
+// pixels.
+func (d *StackLayoutData) insets(t *theme.Theme) (topLeft, bottomRight image.Point) {
+	return image.Point{t.Pixels(d.Left).Round(), t.Pixels(d.Top).Round()},
+		image.Point{t.Pixels(d.Right).Round(), t.Pixels(d.Bottom).Round()}
+}
+
+// shrinkHint returns the size hint for a child when its parent's size hint is
+// hint and the parent takes up delta pixels around the child.
+func shrinkHint(hint, delta int) int {
+	if hint < 0 {
+		return hint
+	}
+	if hint -= delta; hint < 0 {
+		return 0
+	}
+	return hint
+}
+
+// raise moves the child n of the container e to be e's last child.
+func raise(e *node.ContainerEmbed, n node.Node) {
+	c := n.Wrappee()
+	if c.Parent != &e.Embed {
+		panic("widget: raise called for a non-child node")
+	}
+	if c != e.LastChild {
+		e.Remove(n)
+		e.Insert(n, nil)
+		e.Mark(node.MarkNeedsPaintBase)
+	}
+}
+
+// lower moves the child n of the container e to be e's first child.
+func lower(e *node.ContainerEmbed, n node.Node) {
+	c := n.Wrappee()
+	if c.Parent != &e.Embed {
+		panic("widget: lower called for a non-child node")
+	}
+	if c != e.FirstChild {
+		e.Remove(n)
+		e.Insert(n, e.FirstChild.Wrapper)
+		e.Mark(node.MarkNeedsPaintBase)
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/mobile/event/mouse"
+)
+
+// logLeaf is a leaf widget that logs the mouse events that it receives.
+type logLeaf struct {
+	node.LeafEmbed
+	name   string
+	log    *[]string
+	handle bool
+}
+
+func newLogLeaf(name string, log *[]string, handle bool) *logLeaf {
+	w := &logLeaf{name: name, log: log, handle: handle}
+	w.Wrapper = w
+	return w
+}
+
+func (w *logLeaf) OnInputEvent(e interface{}, origin image.Point) node.EventHandled {
+	if _, ok := e.(mouse.Event); ok {
+		*w.log = append(*w.log, w.name)
+	}
+	return node.EventHandled(w.handle)
+}
+
+func press(x, y float32) mouse.Event {
+	return mouse.Event{X: x, Y: y, Button: mouse.ButtonLeft, Direction: mouse.DirPress}
+}
+
+func TestStackLayout(t *testing.T) {
+	a := NewSizer(unit.Pixels(20), unit.Pixels(10), nil)
+	b := NewSizer(unit.Pixels(20), unit.Pixels(10), nil)
+	b.LayoutData = StackLayoutData{HAlign: AlignEnd, VAlign: AlignCenter}
+	c := NewSizer(unit.Pixels(20), unit.Pixels(10), nil)
+	c.LayoutData = StackLayoutData{HAlign: AlignCenter, VAlign: AlignEnd}
+	d := NewSizer(unit.Pixels(20), unit.Pixels(10), nil)
+	d.LayoutData = StackLayoutData{HAlign: AlignStretch, VAlign: AlignStretch}
+	d.Box = &node.Box{Margin: node.Insets{
+		Top:    unit.Pixels(1),
+		Right:  unit.Pixels(2),
+		Bottom: unit.Pixels(3),
+		Left:   unit.Pixels(4),
+	}}
+	e := NewSizer(unit.Pixels(20), unit.Pixels(10), nil)
+	e.LayoutData = StackLayoutData{HAlign: AlignEnd, VAlign: AlignEnd}
+	e.Box = &node.Box{Margin: node.UniformInsets(unit.Pixels(5))}
+	f := NewSizer(unit.Pixels(20), unit.Pixels(10), nil)
+	f.LayoutData = StackLayoutData{
+		HAlign: AlignEnd,
+		VAlign: AlignEnd,
+		Right:  unit.Pixels(10),
+		Bottom: unit.Pixels(6),
+	}
+	f.Box = &node.Box{Margin: node.UniformInsets(unit.Pixels(2))}
+	g := NewSizer(unit.Pixels(20), unit.Pixels(10), nil)
+	g.LayoutData = StackLayoutData{
+		HAlign: AlignStretch,
+		VAlign: AlignStretch,
+		Top:    unit.Pixels(5),
+		Left:   unit.Pixels(5),
+	}
+
+	w := NewStack(a, b, c, d, e, f, g)
+	w.Measure(nil, node.NoHint, node.NoHint)
+	// The widest child is f, at 2+20+2 plus its inset of 10. The tallest
+	// are e, at 5+10+5, and f, at 2+10+2 plus its inset of 6.
+	if got, want := w.MeasuredSize, (image.Point{34, 20}); got != want {
+		t.Errorf("MeasuredSize: got %v, want %v", got, want)
+	}
+
+	w.Rect = image.Rect(0, 0, 100, 50)
+	w.Layout(nil)
+	testCases := []struct {
+		name string
+		n    node.Node
+		want image.Rectangle
+	}{
+		{"start", a, image.Rect(0, 0, 20, 10)},
+		{"end, center", b, image.Rect(80, 20, 100, 30)},
+		{"center, end", c, image.Rect(40, 40, 60, 50)},
+		{"stretch with margin", d, image.Rect(4, 1, 98, 47)},
+		{"end with margin", e, image.Rect(75, 35, 95, 45)},
+		{"end with insets and margin", f, image.Rect(68, 32, 88, 42)},
+		{"stretch with insets", g, image.Rect(5, 5, 100, 50)},
+	}
+	for _, tc := range testCases {
+		if got := tc.n.Wrappee().Rect; got != tc.want {
+			t.Errorf("%s: Rect: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestStackHitTesting(t *testing.T) {
+	var log []string
+	bottom := newLogLeaf("bottom", &log, true)
+	middle := newLogLeaf("middle", &log, false)
+	top := newLogLeaf("top", &log, true)
+	for _, n := range []*logLeaf{bottom, middle, top} {
+		n.LayoutData = StackLayoutData{HAlign: AlignStretch, VAlign: AlignStretch}
+	}
+	top.LayoutData = StackLayoutData{}
+	w := NewStack(bottom, middle, top)
+	w.Rect = image.Rect(0, 0, 100, 100)
+	w.Layout(nil)
+
+	check := func(desc string, e mouse.Event, want ...string) {
+		t.Helper()
+		log = nil
+		w.OnInputEvent(e, image.Point{})
+		if got, want := strings.Join(log, " "), strings.Join(want, " "); got != want {
+			t.Errorf("%s: got %q, want %q", desc, got, want)
+		}
+	}
+
+	// The top child has a zero size, so it is not hit. The middle child is
+	// tried before the bottom one, and does not handle the event.
+	check("initial order", press(50, 50), "middle", "bottom")
+
+	w.Raise(bottom)
+	check("after Raise", press(50, 50), "bottom")
+
+	w.Lower(bottom)
+	check("after Lower", press(50, 50), "middle", "bottom")
+
+	if got := w.FirstChild; got != &bottom.Embed {
+		t.Errorf("after Lower: FirstChild: got %p, want %p", got, &bottom.Embed)
+	}
+	if got := w.LastChild; got != &top.Embed {
+		t.Errorf("after Lower: LastChild: got %p, want %p", got, &top.Embed)
+	}
+}
+
+func TestAbsolute(t *testing.T) {
+	var log []string
+	a := newLogLeaf("a", &log, true)
+	a.LayoutData = AbsoluteLayoutData{
+		X: unit.Pixels(10), Y: unit.Pixels(10),
+		Width: unit.Pixels(50), Height: unit.Pixels(50),
+	}
+	b := newLogLeaf("b", &log, true)
+	b.LayoutData = AbsoluteLayoutData{
+		X: unit.Pixels(40), Y: unit.Pixels(40),
+		Width: unit.Pixels(50), Height: unit.Pixels(50),
+	}
+	b.Box = &node.Box{Margin: node.UniformInsets(unit.Pixels(2))}
+	w := NewAbsolute(a, b)
+	w.Measure(nil, node.NoHint, node.NoHint)
+	if got, want := w.MeasuredSize, (image.Point{90, 90}); got != want {
+		t.Errorf("MeasuredSize: got %v, want %v", got, want)
+	}
+	w.Rect = image.Rect(0, 0, 100, 100)
+	w.Layout(nil)
+	if got, want := a.Rect, image.Rect(10, 10, 60, 60); got != want {
+		t.Errorf("a.Rect: got %v, want %v", got, want)
+	}
+	// b's outer rectangle, including its margin, is at (40, 40).
+	if got, want := b.Rect, image.Rect(42, 42, 88, 88); got != want {
+		t.Errorf("b.Rect: got %v, want %v", got, want)
+	}
+
+	check := func(desc string, e mouse.Event, want string) {
+		t.Helper()
+		log = nil
+		w.OnInputEvent(e, image.Point{})
+		if got := strings.Join(log, " "); got != want {
+			t.Errorf("%s: got %q, want %q", desc, got, want)
+		}
+	}
+	check("overlap", press(50, 50), "b")
+	check("a only", press(20, 20), "a")
+	w.Raise(a)
+	check("overlap after Raise", press(50, 50), "a")
+	w.Lower(a)
+	check("overlap after Lower", press(50, 50), "b")
+
+	w.Move(b, unit.Pixels(0), unit.Pixels(0))
+	if got, want := b.Rect, image.Rect(2, 2, 48, 48); got != want {
+		t.Errorf("after Move: b.Rect: got %v, want %v", got, want)
+	}
+	check("after Move", press(50, 50), "a")
+}
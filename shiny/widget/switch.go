@@ -0,0 +1,121 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+)
+
+// Switch is a leaf widget that can be on or off. Clicking it, by a tap or,
+// when it has the keyboard focus, by the space bar or the enter key, toggles
+// it.
+type Switch struct {
+	node.LeafEmbed
+
+	// OnChange, if non-nil, is called when the user toggles the switch.
+	OnChange func(on bool)
+
+	on bool
+	c  control
+}
+
+// NewSwitch returns a new Switch widget.
+func NewSwitch(on bool) *Switch {
+	w := &Switch{
+		on: on,
+	}
+	w.Wrapper = w
+	w.Focusable = true
+	return w
+}
+
+// On returns whether the switch is on.
+func (w *Switch) On() bool { return w.on }
+
+// SetOn sets whether the switch is on. It does not call OnChange.
+func (w *Switch) SetOn(on bool) {
+	if w.on != on {
+		w.on = on
+		w.Mark(node.MarkNeedsPaintBase)
+	}
+}
+
+// Disabled returns whether the switch is disabled.
+func (w *Switch) Disabled() bool { return w.c.disabled }
+
+// SetDisabled sets whether the switch is disabled. A disabled switch cannot be
+// toggled or focused.
+func (w *Switch) SetDisabled(disabled bool) { w.c.setDisabled(&w.Embed, disabled) }
+
+func (w *Switch) Measure(t *theme.Theme, widthHint, heightHint int) {
+	face := t.AcquireFontFace(theme.FontFaceOptions{})
+	defer t.ReleaseFontFace(theme.FontFaceOptions{}, face)
+	m := face.Metrics()
+
+	h := m.Ascent.Ceil() + m.Descent.Ceil()
+	w.MeasuredSize = image.Point{2 * h, h}
+}
+
+func (w *Switch) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
+	w.Marks.UnmarkNeedsPaintBase()
+	r := w.Rect.Add(origin)
+	dst := ctx.Dst.SubImage(r).(*image.RGBA)
+	if dst.Bounds().Empty() {
+		return nil
+	}
+
+	t := ctx.Theme
+	pal := t.GetPalette()
+	track := pal.Dark()
+	switch {
+	case w.c.disabled:
+		track = pal.Light()
+	case w.on:
+		track = pal.Accent()
+	}
+	fillRoundRect(dst, r, track)
+
+	knob := image.Rect(r.Min.X, r.Min.Y, r.Min.X+r.Dy(), r.Max.Y)
+	if w.on {
+		knob = image.Rect(r.Max.X-r.Dy(), r.Min.Y, r.Max.X, r.Max.Y)
+	}
+	inset := 2 * lineWidth(t)
+	if w.c.pressed || w.c.hovered {
+		inset /= 2
+	}
+	fillRoundRect(dst, knob.Inset(inset), pal.Background())
+	w.c.paintFocus(dst, t, r)
+	return nil
+}
+
+func (w *Switch) OnInputEvent(e interface{}, origin image.Point) node.EventHandled {
+	handled, activate := w.c.onInputEvent(&w.Embed, e)
+	if activate {
+		w.toggle()
+	}
+	return handled
+}
+
+func (w *Switch) toggle() {
+	w.SetOn(!w.on)
+	if w.OnChange != nil {
+		w.OnChange(w.on)
+	}
+}
+
+func (w *Switch) Accessibility() node.Accessibility {
+	s := w.c.state()
+	if w.on {
+		s |= node.StateChecked
+	}
+	return node.Accessibility{
+		Role:    node.RoleSwitch,
+		State:   s,
+		Actions: w.c.actions(node.Action{Name: node.ActionToggle, Do: w.toggle}),
+	}
+}
@@ -5,22 +5,67 @@
 package widget
 
 import (
+	"bytes"
 	"image"
 	"image/draw"
+	"io/ioutil"
 
+	"golang.org/x/exp/shiny/gesture"
 	"golang.org/x/exp/shiny/text"
 	"golang.org/x/exp/shiny/unit"
 	"golang.org/x/exp/shiny/widget/node"
 	"golang.org/x/exp/shiny/widget/theme"
 	"golang.org/x/image/font"
 	"golang.org/x/image/math/fixed"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/mouse"
 )
 
-// Text is a leaf widget that holds a text label.
+// Text is a leaf widget that holds a text label, wrapped to the widget's
+// width.
+//
+// A Text can be made selectable, so that the user can select its text by
+// dragging the mouse, and copy the selection.
 type Text struct {
 	node.LeafEmbed
-	frame   text.Frame
-	faceSet bool
+	frame text.Frame
+
+	// HAlign is how each line is aligned horizontally, and VAlign is how the
+	// lines are aligned vertically, within the Text's Rect less its padding.
+	// AlignStretch is equivalent to AlignStart.
+	HAlign, VAlign Alignment
+
+	// MaxLines, if positive, is the maximum number of lines that are
+	// measured and shown.
+	MaxLines int
+
+	// Ellipsis is whether a line that is too wide for the Text is truncated
+	// and ends with an ellipsis, instead of being cut off. If there are more
+	// lines than are shown, because of MaxLines or the Text's height, the
+	// last shown line also ends with an ellipsis.
+	Ellipsis bool
+
+	// OnCopy, if non-nil, is called with the selected text when the user
+	// copies it from a selectable Text, by pressing Ctrl-C or Cmd-C.
+	//
+	// TODO: copy to the system clipboard, once the screen package provides
+	// one.
+	OnCopy func(text string)
+
+	selectable bool
+	// anchor and caret are the ends of the selection, as byte offsets in the
+	// text. The caret is the end that moves when the selection is extended.
+	anchor, caret int
+	dragging      bool
+
+	// face is the font face of the frame, acquired from faceTheme.
+	face      font.Face
+	faceTheme *theme.Theme
+
+	// ascent and lineHeight are the face's metrics, and pad is the padding,
+	// in pixels, as set by the most recent Layout or PaintBase call, as input
+	// events are not given a theme.
+	ascent, lineHeight, pad int
 
 	// TODO: scrolling, although should that be the responsibility of this
 	// widget, the parent widget or something else?
@@ -38,38 +83,127 @@ func NewText(text string) *Text {
 	return w
 }
 
-func (w *Text) setFace(t *theme.Theme) {
-	// TODO: can a theme change at runtime, or can it be set only once, at
-	// start-up?
-	if !w.faceSet {
-		w.faceSet = true
-		// TODO: when is face released? Should we just unconditionally call
-		// SetFace for every Measure, Layout and Paint? How do we avoid
-		// excessive re-calculation of soft returns when re-using the same
-		// logical face (as in "Times New Roman 12pt") even if using different
-		// physical font.Face values (as each Face may have its own caches)?
-		face := t.AcquireFontFace(theme.FontFaceOptions{})
-		w.frame.SetFace(face)
+// Selectable returns whether the user can select the text.
+func (w *Text) Selectable() bool { return w.selectable }
+
+// SetSelectable sets whether the user can select the text. A selectable Text
+// can have the keyboard focus. Making a Text unselectable clears its
+// selection.
+func (w *Text) SetSelectable(selectable bool) {
+	w.selectable = selectable
+	w.Focusable = selectable
+	if !selectable {
+		w.anchor, w.caret, w.dragging = 0, 0, false
 	}
+	w.Mark(node.MarkNeedsPaintBase)
 }
 
-// TODO: should padding (and/or margin and border) be a universal concept and
-// part of the node.Embed type instead of having each widget implement its own?
+// SelectedText returns the selected text, which is empty if there is no
+// selection.
+func (w *Text) SelectedText() string {
+	i, j := w.selection()
+	if i == j {
+		return ""
+	}
+	return string(w.text()[i:j])
+}
 
+func (w *Text) text() []byte {
+	c := w.frame.NewCaret()
+	defer c.Close()
+	b, _ := ioutil.ReadAll(c)
+	return b
+}
+
+// selection returns the start and end positions of the selected text.
+func (w *Text) selection() (i, j int) {
+	i, j = w.anchor, w.caret
+	if i > j {
+		i, j = j, i
+	}
+	return i, j
+}
+
+// setCaret moves the caret to pos. If extend is false, it also moves the
+// anchor, emptying the selection.
+func (w *Text) setCaret(pos int, extend bool) {
+	w.caret = pos
+	if !extend {
+		w.anchor = pos
+	}
+}
+
+// setFace sets the frame's font face from the theme t. The theme can change at
+// runtime, such as by a Themer, in which case the previous face is released.
+func (w *Text) setFace(t *theme.Theme) {
+	if w.face != nil && w.faceTheme == t {
+		return
+	}
+	// TODO: how do we avoid excessive re-calculation of soft returns when
+	// re-using the same logical face (as in "Times New Roman 12pt") even if
+	// using different physical font.Face values (as each Face may have its
+	// own caches)?
+	if w.face != nil {
+		w.faceTheme.ReleaseFontFace(theme.FontFaceOptions{}, w.face)
+	}
+	w.face, w.faceTheme = t.AcquireFontFace(theme.FontFaceOptions{}), t
+	w.frame.SetFace(w.face)
+	m := w.face.Metrics()
+	w.ascent = m.Ascent.Ceil()
+	w.lineHeight = m.Ascent.Ceil() + m.Descent.Ceil()
+}
+
+// padding is the Text's own padding, inside its Rect, which is in addition to
+// any padding in its node.Box.
+//
+// TODO: replace this by a default node.Box, once every container honors its
+// children's Boxes.
 func (w *Text) padding(t *theme.Theme) int {
 	return t.Pixels(unit.Ems(0.5)).Ceil()
 }
 
+func (w *Text) Baseline(t *theme.Theme) int {
+	return w.padding(t) + fontAscent(t)
+}
+
+// IntrinsicWidths returns the Text's minimum and maximum content widths,
+// including its padding. The minimum is the width of its widest word, the
+// narrowest that it can be without a word being cut off. The maximum is the
+// width of its widest line when it is not wrapped, which is its natural width.
+func (w *Text) IntrinsicWidths(t *theme.Theme) (min, max int) {
+	w.setFace(t)
+	for _, p := range bytes.Split(w.text(), []byte("\n")) {
+		if x := font.MeasureBytes(w.face, bytes.TrimRight(p, " \t\r")).Ceil(); max < x {
+			max = x
+		}
+		for _, word := range bytes.Fields(p) {
+			if x := font.MeasureBytes(w.face, word).Ceil(); min < x {
+				min = x
+			}
+		}
+	}
+	pad := 2 * w.padding(t)
+	return min + pad, max + pad
+}
+
+// height returns the height of the Text's shown lines, excluding its padding,
+// when it is wrapped to the frame's current maximum width.
+func (w *Text) height() int {
+	n := w.frame.LineCount()
+	if w.MaxLines > 0 && n > w.MaxLines {
+		n = w.MaxLines
+	}
+	return n * w.lineHeight
+}
+
 func (w *Text) Measure(t *theme.Theme, widthHint, heightHint int) {
 	w.setFace(t)
 	padding := w.padding(t)
+	_, width := w.IntrinsicWidths(t)
 
-	if widthHint < 0 {
+	if widthHint < 0 || widthHint >= width {
 		w.frame.SetMaxWidth(0)
-		w.MeasuredSize = image.Point{
-			0, // TODO: this isn't right.
-			w.frame.Height() + 2*padding,
-		}
+		w.MeasuredSize = image.Point{width, w.height() + 2*padding}
 		return
 	}
 
@@ -81,66 +215,140 @@ func (w *Text) Measure(t *theme.Theme, widthHint, heightHint int) {
 
 	w.MeasuredSize = image.Point{
 		widthHint,
-		w.frame.Height() + 2*padding,
+		w.height() + 2*padding,
 	}
 }
 
 func (w *Text) Layout(t *theme.Theme) {
 	w.setFace(t)
-	padding := w.padding(t)
-	maxWidth := fixed.I(w.Rect.Dx() - 2*padding)
+	w.pad = w.padding(t)
+	maxWidth := fixed.I(w.Rect.Dx() - 2*w.pad)
 	if maxWidth <= 1 {
 		maxWidth = 1
 	}
 	w.frame.SetMaxWidth(maxWidth)
 }
 
-func (w *Text) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
-	w.Marks.UnmarkNeedsPaintBase()
-	dst := ctx.Dst.SubImage(w.Rect.Add(origin)).(*image.RGBA)
-	if dst.Bounds().Empty() {
+// textLine is a shown line of text.
+type textLine struct {
+	// start and end are the positions of the line's first byte and of the
+	// byte after its last, including any trailing white space.
+	start, end int
+	// text is the line's text, as drawn, without any trailing white space.
+	// Its first n bytes are from the Text, and are followed by any ellipsis.
+	text []byte
+	n    int
+	// x and y are the top-left of the line, relative to the Text's
+	// Rect.Min.
+	x, y int
+}
+
+// lines returns the shown lines, for a Rect of the given size.
+func (w *Text) lines(size image.Point) []textLine {
+	if w.face == nil || w.lineHeight <= 0 {
 		return nil
 	}
+	var lines []textLine
+	f, pos := &w.frame, 0
+	for p := f.FirstParagraph(); p != nil; p = p.Next(f) {
+		for l := p.FirstLine(f); l != nil; l = l.Next(f) {
+			var s []byte
+			for b := l.FirstBox(f); b != nil; b = b.Next(f) {
+				s = append(s, b.Text(f)...)
+			}
+			t := bytes.TrimRight(s, " \t\r\n")
+			lines = append(lines, textLine{start: pos, end: pos + len(s), text: t, n: len(t)})
+			pos += len(s)
+		}
+	}
 
-	face := ctx.Theme.AcquireFontFace(theme.FontFaceOptions{})
-	defer ctx.Theme.ReleaseFontFace(theme.FontFaceOptions{}, face)
-	m := face.Metrics()
-	ascent := m.Ascent.Ceil()
-	descent := m.Descent.Ceil()
-	height := m.Height.Ceil()
+	content := image.Rectangle{Max: size}.Inset(w.pad)
+	n := len(lines)
+	if w.MaxLines > 0 && n > w.MaxLines {
+		n = w.MaxLines
+	}
+	if fit := content.Dy() / w.lineHeight; n > fit {
+		// Show at least one line, even if it is cut off.
+		if n = fit; n < 1 {
+			n = 1
+		}
+	}
+	more := n < len(lines)
+	lines = lines[:n]
+
+	y, _ := w.VAlign.align(n*w.lineHeight, content.Dy())
+	for i := range lines {
+		l := &lines[i]
+		if w.Ellipsis {
+			l.text, l.n = ellipsize(w.face, l.text, fixed.I(content.Dx()), more && i == n-1)
+		}
+		x, _ := w.HAlign.align(font.MeasureBytes(w.face, l.text).Ceil(), content.Dx())
+		l.x = content.Min.X + x
+		l.y = content.Min.Y + y + i*w.lineHeight
+	}
+	return lines
+}
 
-	padding := w.padding(ctx.Theme)
+// posAt returns the position closest to p, relative to the Text's Rect.Min.
+func (w *Text) posAt(p image.Point) int {
+	lines := w.lines(w.Rect.Size())
+	if len(lines) == 0 {
+		return 0
+	}
+	i := 0
+	if dy := p.Y - lines[0].y; dy > 0 {
+		i = dy / w.lineHeight
+	}
+	if i >= len(lines) {
+		i = len(lines) - 1
+	}
+	l := &lines[i]
+	return l.start + offsetAt(w.face, l.text[:l.n], fixed.I(p.X-l.x))
+}
 
-	draw.Draw(dst, dst.Bounds(), ctx.Theme.GetPalette().Background(), image.Point{}, draw.Src)
+func (w *Text) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
+	w.Marks.UnmarkNeedsPaintBase()
+	r := w.Rect.Add(origin)
+	dst := ctx.Dst.SubImage(r).(*image.RGBA)
+	if dst.Bounds().Empty() {
+		return nil
+	}
+	w.setFace(ctx.Theme)
+	w.pad = w.padding(ctx.Theme)
+	pal := ctx.Theme.GetPalette()
 
-	minDotY := fixed.I(dst.Bounds().Min.Y - descent)
-	maxDotY := fixed.I(dst.Bounds().Max.Y + ascent)
+	draw.Draw(dst, dst.Bounds(), pal.Background(), image.Point{}, draw.Src)
 
-	x0 := fixed.I(origin.X + w.Rect.Min.X + padding)
 	d := font.Drawer{
 		Dst:  dst,
-		Src:  ctx.Theme.GetPalette().Foreground(),
-		Face: face,
-		Dot: fixed.Point26_6{
-			X: x0,
-			Y: fixed.I(origin.Y + w.Rect.Min.Y + padding + ascent),
-		},
-	}
-	f := &w.frame
-	for p := f.FirstParagraph(); p != nil; p = p.Next(f) {
-		for l := p.FirstLine(f); l != nil; l = l.Next(f) {
-			if d.Dot.Y > minDotY {
-				if d.Dot.Y >= maxDotY {
-					return nil
-				}
-				for b := l.FirstBox(f); b != nil; b = b.Next(f) {
-					d.DrawBytes(b.TrimmedText(f))
-					// TODO: adjust d.Dot.X for any ligatures?
-				}
-				d.Dot.X = x0
+		Src:  pal.Foreground(),
+		Face: w.face,
+	}
+	selI, selJ := w.selection()
+	for _, l := range w.lines(r.Size()) {
+		x, y := r.Min.X+l.x, r.Min.Y+l.y
+		if selI < l.end && selJ > l.start {
+			i, j := selI-l.start, selJ-l.start
+			if i < 0 {
+				i = 0
+			} else if i > l.n {
+				i = l.n
+			}
+			if j > l.n {
+				j = l.n
+			}
+			sel := image.Rect(
+				x+font.MeasureBytes(w.face, l.text[:i]).Floor(), y,
+				x+font.MeasureBytes(w.face, l.text[:j]).Ceil(), y+w.lineHeight,
+			)
+			if selJ > l.start+l.n {
+				// Show that the selection continues past the end of the line.
+				sel.Max.X += w.lineHeight / 2
 			}
-			d.Dot.Y += fixed.I(height)
+			draw.Draw(dst, sel, pal.Selection(), image.Point{}, draw.Src)
 		}
+		d.Dot = fixed.P(x, y+w.ascent)
+		d.DrawBytes(l.text)
 	}
 	return nil
 }
@@ -150,3 +358,73 @@ func (w *Text) Paint(ctx *node.PaintContext, origin image.Point) error {
 	// keyboard focus.
 	return w.LeafEmbed.Paint(ctx, origin)
 }
+
+func (w *Text) OnInputEvent(e interface{}, origin image.Point) node.EventHandled {
+	if !w.selectable || w.face == nil {
+		return node.NotHandled
+	}
+	switch e := e.(type) {
+	case key.Event:
+		return w.onKeyEvent(e)
+
+	case mouse.Event:
+		p := image.Point{int(e.X), int(e.Y)}.Sub(origin.Add(w.Rect.Min))
+		switch e.Direction {
+		case mouse.DirPress:
+			if e.Button != mouse.ButtonLeft {
+				return node.NotHandled
+			}
+			w.dragging = true
+			w.setCaret(w.posAt(p), e.Modifiers&key.ModShift != 0)
+		case mouse.DirNone:
+			if !w.dragging {
+				return node.NotHandled
+			}
+			w.setCaret(w.posAt(p), true)
+		case mouse.DirRelease:
+			if !w.dragging {
+				return node.NotHandled
+			}
+			w.dragging = false
+			return node.Handled
+		default:
+			return node.NotHandled
+		}
+		w.Mark(node.MarkNeedsPaintBase)
+		return node.Handled
+
+	case gesture.Event:
+		// A mouse drag that selects text shouldn't also be treated as a
+		// scroll by an ancestor, such as a Scroller. Other gestures, such as
+		// touch drags, are left alone.
+		if w.dragging {
+			return node.Handled
+		}
+	}
+	return node.NotHandled
+}
+
+func (w *Text) onKeyEvent(e key.Event) node.EventHandled {
+	if e.Direction == key.DirRelease || e.Modifiers&(key.ModControl|key.ModMeta) == 0 {
+		return node.NotHandled
+	}
+	switch e.Code {
+	case key.CodeA:
+		w.anchor, w.caret = 0, w.frame.Len()
+		w.Mark(node.MarkNeedsPaintBase)
+	case key.CodeC:
+		if s := w.SelectedText(); s != "" && w.OnCopy != nil {
+			w.OnCopy(s)
+		}
+	default:
+		return node.NotHandled
+	}
+	return node.Handled
+}
+
+func (w *Text) Accessibility() node.Accessibility {
+	return node.Accessibility{
+		Role: node.RoleText,
+		Name: string(w.text()),
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"testing"
+
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/mouse"
+)
+
+// The default theme's font face is 8 pixels wide per glyph.
+const glyphWidth = 8
+
+// layoutText measures and lays out w at the given width hint.
+func layoutText(w *Text, widthHint int) {
+	w.Measure(nil, widthHint, node.NoHint)
+	w.Rect = image.Rectangle{Max: w.MeasuredSize}
+	w.Layout(nil)
+}
+
+func lineTexts(lines []textLine) []string {
+	var s []string
+	for _, l := range lines {
+		s = append(s, string(l.text))
+	}
+	return s
+}
+
+func TestTextMeasure(t *testing.T) {
+	w := NewText("ab cdef\ngh")
+	pad := 2 * w.padding(nil)
+
+	min, max := w.IntrinsicWidths(nil)
+	if wantMin, wantMax := 4*glyphWidth+pad, 7*glyphWidth+pad; min != wantMin || max != wantMax {
+		t.Fatalf("IntrinsicWidths: got %d, %d, want %d, %d", min, max, wantMin, wantMax)
+	}
+
+	testCases := []struct {
+		widthHint int
+		want      image.Point
+	}{
+		{node.NoHint, image.Point{max, 2*w.lineHeight + pad}},
+		// A wider hint doesn't make the Text any wider than its text.
+		{max + 100, image.Point{max, 2*w.lineHeight + pad}},
+		{max, image.Point{max, 2*w.lineHeight + pad}},
+		// A narrower hint wraps the text, making it taller.
+		{min, image.Point{min, 3*w.lineHeight + pad}},
+	}
+	for _, tc := range testCases {
+		w.Measure(nil, tc.widthHint, node.NoHint)
+		if got := w.MeasuredSize; got != tc.want {
+			t.Errorf("widthHint %d: got %v, want %v", tc.widthHint, got, tc.want)
+		}
+	}
+
+	w.MaxLines = 2
+	w.Measure(nil, min, node.NoHint)
+	if got, want := w.MeasuredSize.Y, 2*w.lineHeight+pad; got != want {
+		t.Errorf("MaxLines: got height %d, want %d", got, want)
+	}
+}
+
+func TestTextEllipsis(t *testing.T) {
+	w := NewText("abcdefgh\nij\nkl")
+	w.Ellipsis = true
+	w.MaxLines = 2
+	pad := 2 * w.padding(nil)
+	layoutText(w, 5*glyphWidth+pad)
+	e := ellipsis(w.face)
+
+	got := lineTexts(w.lines(w.Rect.Size()))
+	// The too wide line is truncated, and the last shown line ends with an
+	// ellipsis, as there are more lines than are shown.
+	want := []string{"abcd" + e, "ij" + e}
+	if e != "…" {
+		want[0] = "ab" + e
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	w.Ellipsis = false
+	got = lineTexts(w.lines(w.Rect.Size()))
+	if want := []string{"abcdefgh", "ij"}; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("no Ellipsis: got %q, want %q", got, want)
+	}
+}
+
+func TestTextSelection(t *testing.T) {
+	w := NewText("hello world")
+	layoutText(w, node.NoHint)
+	if w.OnInputEvent(mouse.Event{Button: mouse.ButtonLeft, Direction: mouse.DirPress}, image.Point{}) != node.NotHandled {
+		t.Fatal("unselectable Text handled a mouse press")
+	}
+
+	w.SetSelectable(true)
+	var copied string
+	w.OnCopy = func(s string) { copied = s }
+	// at returns the mouse position of the i'th glyph's left edge.
+	at := func(i int) (x, y float32) {
+		return float32(w.pad + i*glyphWidth), float32(w.pad + w.lineHeight/2)
+	}
+
+	x, y := at(6)
+	w.OnInputEvent(mouse.Event{X: x, Y: y, Button: mouse.ButtonLeft, Direction: mouse.DirPress}, image.Point{})
+	x, y = at(9)
+	w.OnInputEvent(mouse.Event{X: x, Y: y, Direction: mouse.DirNone}, image.Point{})
+	w.OnInputEvent(mouse.Event{X: x, Y: y, Button: mouse.ButtonLeft, Direction: mouse.DirRelease}, image.Point{})
+	if got, want := w.SelectedText(), "wor"; got != want {
+		t.Errorf("drag: got %q, want %q", got, want)
+	}
+
+	// Shift-clicking extends the selection.
+	x, y = at(2)
+	w.OnInputEvent(mouse.Event{X: x, Y: y, Button: mouse.ButtonLeft, Direction: mouse.DirPress, Modifiers: key.ModShift}, image.Point{})
+	w.OnInputEvent(mouse.Event{X: x, Y: y, Button: mouse.ButtonLeft, Direction: mouse.DirRelease}, image.Point{})
+	if got, want := w.SelectedText(), "llo "; got != want {
+		t.Errorf("shift-click: got %q, want %q", got, want)
+	}
+
+	w.OnInputEvent(key.Event{Code: key.CodeC, Modifiers: key.ModControl, Direction: key.DirPress}, image.Point{})
+	if want := "llo "; copied != want {
+		t.Errorf("copy: got %q, want %q", copied, want)
+	}
+
+	w.OnInputEvent(key.Event{Code: key.CodeA, Modifiers: key.ModControl, Direction: key.DirPress}, image.Point{})
+	if got, want := w.SelectedText(), "hello world"; got != want {
+		t.Errorf("select all: got %q, want %q", got, want)
+	}
+
+	w.SetSelectable(false)
+	if got := w.SelectedText(); got != "" {
+		t.Errorf("unselectable: got %q, want none", got)
+	}
+}
+
+func TestLabelIntrinsicWidths(t *testing.T) {
+	w := NewLabel("abcdef")
+	if min, max := w.IntrinsicWidths(nil); min != 6*glyphWidth || max != 6*glyphWidth {
+		t.Errorf("got %d, %d, want %d, %d", min, max, 6*glyphWidth, 6*glyphWidth)
+	}
+	// With an Ellipsis, the Label can be as narrow as the ellipsis.
+	w.Ellipsis = true
+	min, _ := w.IntrinsicWidths(nil)
+	var th *theme.Theme
+	face := th.AcquireFontFace(theme.FontFaceOptions{})
+	defer th.ReleaseFontFace(theme.FontFaceOptions{}, face)
+	if e := len([]rune(ellipsis(face))); min != e*glyphWidth {
+		t.Errorf("Ellipsis: got min %d, want %d", min, e*glyphWidth)
+	}
+}
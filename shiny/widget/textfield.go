@@ -0,0 +1,142 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+)
+
+// TODO: clipboard support, undo and redo, input methods.
+
+// TextField is a leaf widget that holds a single line of editable text.
+//
+// It can be focused. Its text is scrolled horizontally to keep the caret
+// visible.
+type TextField struct {
+	node.LeafEmbed
+
+	// OnChange, if non-nil, is called after the user changes the text.
+	OnChange func()
+
+	e editor
+}
+
+// NewTextField returns a new TextField widget. Any newlines in text are
+// replaced by spaces.
+func NewTextField(text string) *TextField {
+	w := &TextField{}
+	w.Wrapper = w
+	w.Focusable = true
+	w.e.init(false, text)
+	return w
+}
+
+// Text returns the widget's text.
+func (w *TextField) Text() string { return w.e.text() }
+
+// SetText sets the widget's text, and moves the caret to its end. Any
+// newlines in text are replaced by spaces. It does not call OnChange.
+func (w *TextField) SetText(text string) {
+	w.e.setText(text)
+	w.Mark(node.MarkNeedsPaintBase)
+}
+
+func (w *TextField) Measure(t *theme.Theme, widthHint, heightHint int) {
+	w.MeasuredSize = w.e.measure(t, widthHint)
+}
+
+func (w *TextField) Baseline(t *theme.Theme) int { return w.e.baseline(t) }
+
+func (w *TextField) Layout(t *theme.Theme) {
+	w.e.layout(t, w.Rect.Size())
+}
+
+func (w *TextField) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
+	w.Marks.UnmarkNeedsPaintBase()
+	return w.e.paintBase(ctx, w.Rect.Add(origin))
+}
+
+func (w *TextField) Paint(ctx *node.PaintContext, origin image.Point) error {
+	w.Marks.UnmarkNeedsPaint()
+	w.e.paint(ctx, w.Rect.Add(origin), w)
+	return nil
+}
+
+func (w *TextField) OnInputEvent(e interface{}, origin image.Point) node.EventHandled {
+	handled, changed := w.e.onInputEvent(e, w.Rect.Add(origin), &w.Embed)
+	if changed && w.OnChange != nil {
+		w.OnChange()
+	}
+	return handled
+}
+
+// TextArea is a leaf widget that holds multiple lines of editable text.
+//
+// It can be focused. Its text is wrapped to the widget's width, and is
+// scrolled vertically, by the mouse wheel or by dragging the scrollbar, to
+// keep the caret visible.
+type TextArea struct {
+	node.LeafEmbed
+
+	// OnChange, if non-nil, is called after the user changes the text.
+	OnChange func()
+
+	e editor
+}
+
+// NewTextArea returns a new TextArea widget.
+func NewTextArea(text string) *TextArea {
+	w := &TextArea{}
+	w.Wrapper = w
+	w.Focusable = true
+	w.e.init(true, text)
+	return w
+}
+
+// Text returns the widget's text.
+func (w *TextArea) Text() string { return w.e.text() }
+
+// SetText sets the widget's text, and moves the caret to its end. It does not
+// call OnChange.
+func (w *TextArea) SetText(text string) {
+	w.e.setText(text)
+	w.Mark(node.MarkNeedsPaintBase)
+}
+
+func (w *TextArea) Measure(t *theme.Theme, widthHint, heightHint int) {
+	w.MeasuredSize = w.e.measure(t, widthHint)
+}
+
+func (w *TextArea) Baseline(t *theme.Theme) int { return w.e.baseline(t) }
+
+func (w *TextArea) Layout(t *theme.Theme) {
+	w.e.layout(t, w.Rect.Size())
+}
+
+func (w *TextArea) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
+	w.Marks.UnmarkNeedsPaintBase()
+	return w.e.paintBase(ctx, w.Rect.Add(origin))
+}
+
+func (w *TextArea) Paint(ctx *node.PaintContext, origin image.Point) error {
+	w.Marks.UnmarkNeedsPaint()
+	w.e.paint(ctx, w.Rect.Add(origin), w)
+	return nil
+}
+
+func (w *TextArea) OnInputEvent(e interface{}, origin image.Point) node.EventHandled {
+	handled, changed := w.e.onInputEvent(e, w.Rect.Add(origin), &w.Embed)
+	if changed && w.OnChange != nil {
+		w.OnChange()
+	}
+	return handled
+}
+
+func (w *TextField) Accessibility() node.Accessibility { return w.e.accessibility(false) }
+
+func (w *TextArea) Accessibility() node.Accessibility { return w.e.accessibility(true) }
@@ -0,0 +1,421 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package theme
+
+import (
+	"errors"
+	"image"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// DefaultFontSize is the size, in points, of an OpenTypeCatalog's font faces
+// when the FontFaceOptions' Size is zero.
+const DefaultFontSize = 12.0
+
+// maxIdleFaces is the maximum number of font faces, with no references, that
+// an OpenTypeCatalog keeps for re-use.
+const maxIdleFaces = 16
+
+// OpenTypeCatalog is a FontFaceCatalog that provides font faces from TrueType
+// and OpenType fonts.
+//
+// AcquireFontFace resolves a FontFaceOptions' Family, Style and Weight to the
+// closest matching font that has been added to the catalog. An unknown or
+// empty Family means the family of the first font added. If that font has no
+// glyph for a rune, the glyph comes from the first font, in the order that
+// they were added, that has one.
+//
+// Each AcquireFontFace call returns a font face that is not shared with any
+// other acquisition, so that faces acquired by different goroutines, such as
+// for different windows, can be used concurrently. The parsed fonts are
+// shared. A small number of released faces are kept, by font, size and DPI,
+// for re-use.
+//
+// A font face acquired before a font is added does not use that font for
+// missing glyphs. Faces acquired afterwards do.
+//
+// Its zero value is an empty catalog, ready to use. Acquiring a font face from
+// an empty catalog panics.
+type OpenTypeCatalog struct {
+	// Hinting is how the catalog's font faces quantize glyph outlines. It
+	// should not be changed after a font face is acquired.
+	Hinting font.Hinting
+
+	mu    sync.Mutex
+	fonts []*openTypeFont
+
+	// gen is incremented whenever a font is added, so that faces with stale
+	// fallback fonts are not re-used.
+	gen int
+
+	// acquired holds the faces that are acquired but not yet released.
+	acquired map[*fallbackFace]openTypeFaceKey
+
+	// idle holds the released faces that are kept for re-use, least recently
+	// released first.
+	idle []openTypeFace
+}
+
+type openTypeFont struct {
+	f      *sfnt.Font
+	family string
+	style  font.Style
+	weight font.Weight
+}
+
+type openTypeFaceKey struct {
+	font      *openTypeFont
+	size, dpi float64
+	gen       int
+}
+
+type openTypeFace struct {
+	key  openTypeFaceKey
+	face *fallbackFace
+}
+
+// NewOpenTypeCatalog returns a new OpenTypeCatalog with the given fonts,
+// which are in the TrueType or OpenType format, or are collections of such
+// fonts.
+func NewOpenTypeCatalog(srcs ...[]byte) (*OpenTypeCatalog, error) {
+	c := &OpenTypeCatalog{}
+	for _, src := range srcs {
+		if err := c.Add(src); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// AddFile adds the fonts in the named file to the catalog, as per Add.
+func (c *OpenTypeCatalog) AddFile(filename string) error {
+	src, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	return c.Add(src)
+}
+
+// Add adds the fonts in src, which is a TrueType or OpenType font or a
+// collection of such fonts, to the catalog. Each font's family, style and
+// weight are taken from its naming table.
+//
+// The catalog keeps a reference to src, which should not be modified.
+func (c *OpenTypeCatalog) Add(src []byte) error {
+	coll, err := opentype.ParseCollection(src)
+	if err != nil {
+		return err
+	}
+	buf := &sfnt.Buffer{}
+	for i, n := 0, coll.NumFonts(); i < n; i++ {
+		f, err := coll.Font(i)
+		if err != nil {
+			return err
+		}
+		family := name(f, buf, sfnt.NameIDTypographicFamily, sfnt.NameIDFamily)
+		if family == "" {
+			return errors.New("theme: font has no family name")
+		}
+		style, weight := parseSubfamily(name(f, buf, sfnt.NameIDTypographicSubfamily, sfnt.NameIDSubfamily))
+		c.AddFont(f, family, style, weight)
+	}
+	return nil
+}
+
+// AddFont adds the font f to the catalog, with the given family, style and
+// weight.
+func (c *OpenTypeCatalog) AddFont(f *sfnt.Font, family string, style font.Style, weight font.Weight) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fonts = append(c.fonts, &openTypeFont{
+		f:      f,
+		family: family,
+		style:  style,
+		weight: weight,
+	})
+
+	// Faces made before now don't have f as a fallback.
+	c.gen++
+	for _, x := range c.idle {
+		x.face.Close()
+	}
+	c.idle = nil
+}
+
+// Families returns the family names of the catalog's fonts, in the order that
+// they were added, without duplicates.
+func (c *OpenTypeCatalog) Families() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var families []string
+	seen := map[string]bool{}
+	for _, f := range c.fonts {
+		if k := strings.ToLower(f.family); !seen[k] {
+			seen[k] = true
+			families = append(families, f.family)
+		}
+	}
+	return families
+}
+
+// name returns the first non-empty name of f with one of the given IDs.
+func name(f *sfnt.Font, buf *sfnt.Buffer, ids ...sfnt.NameID) string {
+	for _, id := range ids {
+		if s, err := f.Name(buf, id); err == nil && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// subfamilyWeights maps words in a font's subfamily name, such as "Bold
+// Italic", to weights. Longer words come before their suffixes.
+var subfamilyWeights = []struct {
+	word   string
+	weight font.Weight
+}{
+	{"extralight", font.WeightExtraLight},
+	{"ultralight", font.WeightExtraLight},
+	{"semibold", font.WeightSemiBold},
+	{"demibold", font.WeightSemiBold},
+	{"extrabold", font.WeightExtraBold},
+	{"ultrabold", font.WeightExtraBold},
+	{"thin", font.WeightThin},
+	{"light", font.WeightLight},
+	{"medium", font.WeightMedium},
+	{"bold", font.WeightBold},
+	{"black", font.WeightBlack},
+	{"heavy", font.WeightBlack},
+}
+
+// parseSubfamily returns the style and weight described by a font's
+// subfamily name, such as "Bold Italic".
+func parseSubfamily(s string) (font.Style, font.Weight) {
+	s = strings.ToLower(strings.Replace(s, " ", "", -1))
+	style := font.StyleNormal
+	switch {
+	case strings.Contains(s, "italic"):
+		style = font.StyleItalic
+	case strings.Contains(s, "oblique"):
+		style = font.StyleOblique
+	}
+	for _, w := range subfamilyWeights {
+		if strings.Contains(s, w.word) {
+			return style, w.weight
+		}
+	}
+	return style, font.WeightNormal
+}
+
+// resolve returns the font that best matches o. It must only be called while
+// holding c.mu.
+func (c *OpenTypeCatalog) resolve(o FontFaceOptions) *openTypeFont {
+	if len(c.fonts) == 0 {
+		panic("theme: AcquireFontFace called for an empty OpenTypeCatalog")
+	}
+	family := c.fonts[0].family
+	for _, f := range c.fonts {
+		if strings.EqualFold(f.family, o.Family) {
+			family = f.family
+			break
+		}
+	}
+
+	var best *openTypeFont
+	bestScore := 0
+	for _, f := range c.fonts {
+		if f.family != family {
+			continue
+		}
+		if score := matchScore(f, o); best == nil || score < bestScore {
+			best, bestScore = f, score
+		}
+	}
+	return best
+}
+
+// matchScore returns how badly f matches o's Style and Weight. Lower is
+// better. A mismatched style is worse than any mismatched weight. An italic
+// font is the next best match for an oblique style, and vice versa.
+//
+// Weights are matched roughly as CSS does: for bold weights, heavier fonts
+// are preferred to lighter ones, and for light weights, lighter fonts are
+// preferred to heavier ones.
+func matchScore(f *openTypeFont, o FontFaceOptions) int {
+	score := 0
+	if f.style != o.Style {
+		if f.style != font.StyleNormal && o.Style != font.StyleNormal {
+			score += 100
+		} else {
+			score += 200
+		}
+	}
+	d := int(f.weight) - int(o.Weight)
+	if d < 0 {
+		d = -d
+	}
+	score += 2 * d
+	if (o.Weight > font.WeightNormal && f.weight < o.Weight) ||
+		(o.Weight <= font.WeightNormal && f.weight > o.Weight) {
+		score++
+	}
+	return score
+}
+
+func (c *OpenTypeCatalog) AcquireFontFace(o FontFaceOptions) font.Face {
+	key := openTypeFaceKey{
+		size: o.Size,
+		dpi:  o.DPI,
+	}
+	if key.size <= 0 {
+		key.size = DefaultFontSize
+	}
+	if key.dpi <= 0 {
+		key.dpi = DefaultDPI
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key.font = c.resolve(o)
+	key.gen = c.gen
+	face := c.takeIdle(key)
+	if face == nil {
+		face = c.newFace(key)
+	}
+	if c.acquired == nil {
+		c.acquired = map[*fallbackFace]openTypeFaceKey{}
+	}
+	c.acquired[face] = key
+	return face
+}
+
+// takeIdle removes and returns the most recently released idle face with the
+// given key, or nil if there is no such face. It must only be called while
+// holding c.mu.
+func (c *OpenTypeCatalog) takeIdle(key openTypeFaceKey) *fallbackFace {
+	for i := len(c.idle) - 1; i >= 0; i-- {
+		if x := c.idle[i]; x.key == key {
+			copy(c.idle[i:], c.idle[i+1:])
+			c.idle[len(c.idle)-1] = openTypeFace{}
+			c.idle = c.idle[:len(c.idle)-1]
+			return x.face
+		}
+	}
+	return nil
+}
+
+// newFace returns a new face for the given key. It must only be called while
+// holding c.mu.
+func (c *OpenTypeCatalog) newFace(key openTypeFaceKey) *fallbackFace {
+	// The primary font comes first, followed by the fallback fonts.
+	face := &fallbackFace{}
+	for _, f := range append([]*openTypeFont{key.font}, c.fonts...) {
+		if len(face.fonts) > 0 && f == key.font {
+			continue
+		}
+		ff, err := opentype.NewFace(f.f, &opentype.FaceOptions{
+			Size:    key.size,
+			DPI:     key.dpi,
+			Hinting: c.Hinting,
+		})
+		if err != nil {
+			// opentype.NewFace does not return an error, for valid options.
+			panic(err)
+		}
+		face.fonts = append(face.fonts, f.f)
+		face.faces = append(face.faces, ff)
+	}
+	return face
+}
+
+func (c *OpenTypeCatalog) ReleaseFontFace(o FontFaceOptions, f font.Face) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	face, _ := f.(*fallbackFace)
+	key, ok := c.acquired[face]
+	if !ok {
+		panic("theme: ReleaseFontFace called for a font face that is not acquired")
+	}
+	delete(c.acquired, face)
+	if key.gen != c.gen {
+		face.Close()
+		return
+	}
+
+	c.idle = append(c.idle, openTypeFace{key, face})
+	if len(c.idle) > maxIdleFaces {
+		c.idle[0].face.Close()
+		copy(c.idle, c.idle[1:])
+		c.idle[len(c.idle)-1] = openTypeFace{}
+		c.idle = c.idle[:len(c.idle)-1]
+	}
+}
+
+// fallbackFace is a font.Face that takes each glyph from the first of its
+// faces whose font has that glyph. Its metrics are those of its first face.
+//
+// Like its faces, a fallbackFace is not safe for concurrent use, as buf is
+// shared by its methods.
+type fallbackFace struct {
+	fonts []*sfnt.Font
+	faces []font.Face
+	buf   sfnt.Buffer
+}
+
+// face returns the first face whose font has a glyph for r, or the first face
+// if none of them do.
+func (f *fallbackFace) face(r rune) font.Face {
+	for i, x := range f.fonts {
+		if g, err := x.GlyphIndex(&f.buf, r); err == nil && g != 0 {
+			return f.faces[i]
+		}
+	}
+	return f.faces[0]
+}
+
+func (f *fallbackFace) Close() error {
+	var err error
+	for _, x := range f.faces {
+		if e := x.Close(); err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (f *fallbackFace) Glyph(dot fixed.Point26_6, r rune) (
+	dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+
+	return f.face(r).Glyph(dot, r)
+}
+
+func (f *fallbackFace) GlyphBounds(r rune) (bounds fixed.Rectangle26_6, advance fixed.Int26_6, ok bool) {
+	return f.face(r).GlyphBounds(r)
+}
+
+func (f *fallbackFace) GlyphAdvance(r rune) (advance fixed.Int26_6, ok bool) {
+	return f.face(r).GlyphAdvance(r)
+}
+
+func (f *fallbackFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	// Kerning only applies between glyphs from the same font.
+	if x := f.face(r0); x == f.face(r1) {
+		return x.Kern(r0, r1)
+	}
+	return 0
+}
+
+func (f *fallbackFace) Metrics() font.Metrics {
+	return f.faces[0].Metrics()
+}
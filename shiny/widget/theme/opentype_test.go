@@ -0,0 +1,182 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package theme
+
+import (
+	"sync"
+	"testing"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/math/fixed"
+)
+
+func newTestCatalog(t *testing.T) *OpenTypeCatalog {
+	c, err := NewOpenTypeCatalog(goregular.TTF, gobold.TTF, goitalic.TTF, gomono.TTF)
+	if err != nil {
+		t.Fatalf("NewOpenTypeCatalog: %v", err)
+	}
+	return c
+}
+
+func TestOpenTypeCatalogResolve(t *testing.T) {
+	c := newTestCatalog(t)
+
+	if got, want := c.Families(), []string{"Go", "Go Mono"}; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("Families: got %q, want %q", got, want)
+	}
+
+	testCases := []struct {
+		o          FontFaceOptions
+		wantFamily string
+		wantStyle  font.Style
+		wantWeight font.Weight
+	}{
+		{FontFaceOptions{}, "Go", font.StyleNormal, font.WeightNormal},
+		{FontFaceOptions{Family: "go mono"}, "Go Mono", font.StyleNormal, font.WeightNormal},
+		{FontFaceOptions{Family: "Unknown"}, "Go", font.StyleNormal, font.WeightNormal},
+		{FontFaceOptions{Weight: font.WeightBold}, "Go", font.StyleNormal, font.WeightBold},
+		{FontFaceOptions{Weight: font.WeightSemiBold}, "Go", font.StyleNormal, font.WeightBold},
+		{FontFaceOptions{Weight: font.WeightLight}, "Go", font.StyleNormal, font.WeightNormal},
+		{FontFaceOptions{Style: font.StyleItalic}, "Go", font.StyleItalic, font.WeightNormal},
+		{FontFaceOptions{Style: font.StyleOblique}, "Go", font.StyleItalic, font.WeightNormal},
+		{FontFaceOptions{Style: font.StyleItalic, Weight: font.WeightBold}, "Go", font.StyleItalic, font.WeightNormal},
+		{FontFaceOptions{Family: "Go Mono", Weight: font.WeightBold}, "Go Mono", font.StyleNormal, font.WeightNormal},
+	}
+	for _, tc := range testCases {
+		f := c.resolve(tc.o)
+		if f.family != tc.wantFamily || f.style != tc.wantStyle || f.weight != tc.wantWeight {
+			t.Errorf("%+v: got %q, %v, %v, want %q, %v, %v", tc.o,
+				f.family, f.style, f.weight, tc.wantFamily, tc.wantStyle, tc.wantWeight)
+		}
+	}
+}
+
+func TestOpenTypeCatalogCache(t *testing.T) {
+	c := newTestCatalog(t)
+	o := FontFaceOptions{Size: 10}
+
+	f0 := c.AcquireFontFace(o)
+	f1 := c.AcquireFontFace(o)
+	if f0 == f1 {
+		t.Fatal("acquiring the same options twice: got the same face")
+	}
+
+	c.ReleaseFontFace(o, f0)
+	c.ReleaseFontFace(o, f1)
+	if got := len(c.idle); got != 2 {
+		t.Fatalf("idle faces: got %d, want 2", got)
+	}
+
+	// Re-acquiring re-uses the most recently released idle face.
+	if f := c.AcquireFontFace(o); f != f1 {
+		t.Fatal("re-acquiring an idle face: got a different face")
+	} else {
+		c.ReleaseFontFace(o, f)
+	}
+	if f := c.AcquireFontFace(FontFaceOptions{Size: 11}); f == f0 || f == f1 {
+		t.Fatal("acquiring a different size: got an idle face")
+	} else {
+		c.ReleaseFontFace(FontFaceOptions{Size: 11}, f)
+	}
+
+	// Idle faces are evicted, least recently released first.
+	for i := 0; i < maxIdleFaces; i++ {
+		o := FontFaceOptions{Size: float64(20 + i)}
+		c.ReleaseFontFace(o, c.AcquireFontFace(o))
+	}
+	if got := len(c.idle); got != maxIdleFaces {
+		t.Fatalf("idle faces: got %d, want %d", got, maxIdleFaces)
+	}
+	if f := c.AcquireFontFace(o); f == f0 || f == f1 {
+		t.Fatal("acquiring an evicted face: got the same face")
+	} else {
+		c.ReleaseFontFace(o, f)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("releasing an unacquired face: got no panic")
+		}
+	}()
+	c.ReleaseFontFace(o, f0)
+}
+
+func TestOpenTypeCatalogAddFont(t *testing.T) {
+	c, err := NewOpenTypeCatalog(goregular.TTF)
+	if err != nil {
+		t.Fatalf("NewOpenTypeCatalog: %v", err)
+	}
+	o := FontFaceOptions{}
+	old := c.AcquireFontFace(o)
+	f := c.AcquireFontFace(o)
+	c.ReleaseFontFace(o, f)
+
+	if err := c.Add(gomono.TTF); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if got := len(c.idle); got != 0 {
+		t.Errorf("idle faces after Add: got %d, want 0", got)
+	}
+	f = c.AcquireFontFace(o)
+	if n := len(f.(*fallbackFace).faces); n != 2 {
+		t.Errorf("faces after Add: got %d fonts, want 2", n)
+	}
+	c.ReleaseFontFace(o, f)
+
+	// A face acquired before the Add is not re-used once released.
+	c.ReleaseFontFace(o, old)
+	if f := c.AcquireFontFace(o); f == old {
+		t.Error("re-acquiring: got a face acquired before the Add")
+	}
+}
+
+// TestOpenTypeCatalogConcurrency is meant to be run with the race detector.
+func TestOpenTypeCatalogConcurrency(t *testing.T) {
+	c := newTestCatalog(t)
+	th := &Theme{FontFaceCatalog: c}
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				f := th.AcquireFontFace(FontFaceOptions{})
+				for _, r := range "Hello, 世界" {
+					f.Glyph(fixed.Point26_6{}, r)
+					f.GlyphAdvance(r)
+				}
+				th.ReleaseFontFace(FontFaceOptions{}, f)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestOpenTypeCatalogDPI(t *testing.T) {
+	c := newTestCatalog(t)
+
+	height := func(th *Theme, o FontFaceOptions) int {
+		f := th.AcquireFontFace(o)
+		defer th.ReleaseFontFace(o, f)
+		return f.Metrics().Height.Round()
+	}
+
+	th := &Theme{FontFaceCatalog: c}
+	h := height(th, FontFaceOptions{})
+	if h <= 0 {
+		t.Fatalf("height: got %d, want > 0", h)
+	}
+	if got, want := height(th, FontFaceOptions{Size: 2 * DefaultFontSize}), 2*h; !approxEqual(float64(got), float64(want), 2) {
+		t.Errorf("double size: height: got %d, want %d", got, want)
+	}
+	th.DPI = 2 * DefaultDPI
+	if got, want := height(th, FontFaceOptions{}), 2*h; !approxEqual(float64(got), float64(want), 2) {
+		t.Errorf("double DPI: height: got %d, want %d", got, want)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"image"
 	"image/color"
 
+	"golang.org/x/exp/shiny/materialdesign/colornames"
 	"golang.org/x/exp/shiny/unit"
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/inconsolata"
@@ -20,11 +21,24 @@ import (
 //
 // TODO: include font.Hinting and font.Stretch typed fields?
 //
-// TODO: include font size? If so, directly as "12pt" or indirectly as an enum
-// (Heading1, Heading2, Body, etc)?
+// TODO: also allow sizes indirectly as an enum (Heading1, Heading2, Body,
+// etc)?
 type FontFaceOptions struct {
+	// Family is the font family name, such as "Go" or "Go Mono". An empty
+	// Family means the catalog's default family.
+	Family string
+
 	Style  font.Style
 	Weight font.Weight
+
+	// Size is the font size, in points. A zero Size means the catalog's
+	// default size.
+	Size float64
+
+	// DPI is the screen resolution, in dots (i.e. pixels) per inch. A zero
+	// DPI means the catalog's default DPI. The Theme methods set it to the
+	// theme's DPI.
+	DPI float64
 }
 
 // FontFaceCatalog provides a theme's font faces.
@@ -67,24 +81,53 @@ func (s staticColor) Uniform(*Theme) *image.Uniform { return &s.u }
 //
 // The colors are expressed as image.Uniform values so that they can be easily
 // passed as the src argument to image/draw functions.
+//
+// A palette entry with a nil color falls back to another entry, as described
+// by the PaletteIndex constants, so that a palette that sets only the first
+// six entries is still complete.
 type Palette [PaletteLen]image.Uniform
 
-func (p *Palette) Light() *image.Uniform      { return &p[Light] }
-func (p *Palette) Neutral() *image.Uniform    { return &p[Neutral] }
-func (p *Palette) Dark() *image.Uniform       { return &p[Dark] }
-func (p *Palette) Accent() *image.Uniform     { return &p[Accent] }
-func (p *Palette) Foreground() *image.Uniform { return &p[Foreground] }
-func (p *Palette) Background() *image.Uniform { return &p[Background] }
+func (p *Palette) Light() *image.Uniform      { return p.uniform(Light) }
+func (p *Palette) Neutral() *image.Uniform    { return p.uniform(Neutral) }
+func (p *Palette) Dark() *image.Uniform       { return p.uniform(Dark) }
+func (p *Palette) Accent() *image.Uniform     { return p.uniform(Accent) }
+func (p *Palette) Foreground() *image.Uniform { return p.uniform(Foreground) }
+func (p *Palette) Background() *image.Uniform { return p.uniform(Background) }
+func (p *Palette) Error() *image.Uniform      { return p.uniform(Error) }
+func (p *Palette) Surface() *image.Uniform    { return p.uniform(Surface) }
+func (p *Palette) OnSurface() *image.Uniform  { return p.uniform(OnSurface) }
+func (p *Palette) Disabled() *image.Uniform   { return p.uniform(Disabled) }
+func (p *Palette) Selection() *image.Uniform  { return p.uniform(Selection) }
+
+// fallbacks maps the palette entries that were added after the original six
+// to the entries used in their place when they are not set.
+var fallbacks = [PaletteLen]PaletteIndex{
+	Error:     Accent,
+	Surface:   Background,
+	OnSurface: Foreground,
+	Disabled:  Dark,
+	Selection: Accent,
+}
+
+// uniform returns the i'th entry of p, or its fallback if that has a nil
+// color.
+func (p *Palette) uniform(i PaletteIndex) *image.Uniform {
+	if p[i].C == nil && i >= Error {
+		i = fallbacks[i]
+	}
+	return &p[i]
+}
 
 // PaletteIndex is both an integer index into a Palette array and a Color.
 type PaletteIndex int
 
-func (i PaletteIndex) Color(t *Theme) color.Color      { return t.GetPalette()[i].C }
-func (i PaletteIndex) Uniform(t *Theme) *image.Uniform { return &t.GetPalette()[i] }
+func (i PaletteIndex) Color(t *Theme) color.Color      { return t.GetPalette().uniform(i).C }
+func (i PaletteIndex) Uniform(t *Theme) *image.Uniform { return t.GetPalette().uniform(i) }
 
 const (
 	// Light, Neutral and Dark are three color tones used to fill in widgets
-	// such as buttons, menu bars and panels.
+	// such as buttons, menu bars and panels. In a dark palette, they are
+	// still in order of increasing prominence, so that Light is the darkest.
 	Light   = PaletteIndex(0)
 	Neutral = PaletteIndex(1)
 	Dark    = PaletteIndex(2)
@@ -99,7 +142,25 @@ const (
 	// non-editable label text will typically be on the Neutral color.
 	Background = PaletteIndex(5)
 
-	PaletteLen = 6
+	// Error is the color used to indicate errors, such as invalid input. It
+	// falls back to Accent.
+	Error = PaletteIndex(6)
+
+	// Surface is the color of elevated widgets, such as popup menus and
+	// dialogs, and OnSurface is the color used for text and icons on it.
+	// They fall back to Background and Foreground.
+	Surface   = PaletteIndex(7)
+	OnSurface = PaletteIndex(8)
+
+	// Disabled is the color used for the text and marks of disabled widgets.
+	// It falls back to Dark.
+	Disabled = PaletteIndex(9)
+
+	// Selection is the color used behind selected text and list rows. It
+	// falls back to Accent.
+	Selection = PaletteIndex(10)
+
+	PaletteLen = 11
 )
 
 // DefaultDPI is the fallback value of a theme's DPI, if the underlying context
@@ -110,16 +171,47 @@ var (
 	// DefaultFontFaceCatalog is a catalog for a basic font face.
 	DefaultFontFaceCatalog FontFaceCatalog = defaultFontFaceCatalog{}
 
-	// DefaultPalette is the default theme's palette.
-	DefaultPalette = Palette{
-		Light:      image.Uniform{C: color.RGBA{0xf5, 0xf5, 0xf5, 0xff}}, // Material Design "Grey 100".
-		Neutral:    image.Uniform{C: color.RGBA{0xee, 0xee, 0xee, 0xff}}, // Material Design "Grey 200".
-		Dark:       image.Uniform{C: color.RGBA{0xe0, 0xe0, 0xe0, 0xff}}, // Material Design "Grey 300".
-		Accent:     image.Uniform{C: color.RGBA{0x21, 0x96, 0xf3, 0xff}}, // Material Design "Blue 500".
-		Foreground: image.Uniform{C: color.RGBA{0x00, 0x00, 0x00, 0xff}}, // Material Design "Black".
-		Background: image.Uniform{C: color.RGBA{0xff, 0xff, 0xff, 0xff}}, // Material Design "White".
+	// LightPalette is a palette of dark text on light backgrounds, using
+	// Material Design colors.
+	LightPalette = Palette{
+		Light:      image.Uniform{C: colornames.Grey100},
+		Neutral:    image.Uniform{C: colornames.Grey200},
+		Dark:       image.Uniform{C: colornames.Grey300},
+		Accent:     image.Uniform{C: colornames.Blue500},
+		Foreground: image.Uniform{C: colornames.Black},
+		Background: image.Uniform{C: colornames.White},
+		Error:      image.Uniform{C: colornames.Red700},
+		Surface:    image.Uniform{C: colornames.White},
+		OnSurface:  image.Uniform{C: colornames.Grey900},
+		Disabled:   image.Uniform{C: colornames.Grey500},
+		Selection:  image.Uniform{C: colornames.Blue100},
 	}
 
+	// DarkPalette is a palette of light text on dark backgrounds, using
+	// Material Design colors.
+	DarkPalette = Palette{
+		Light:      image.Uniform{C: colornames.Grey800},
+		Neutral:    image.Uniform{C: colornames.Grey700},
+		Dark:       image.Uniform{C: colornames.Grey600},
+		Accent:     image.Uniform{C: colornames.Blue300},
+		Foreground: image.Uniform{C: colornames.White},
+		Background: image.Uniform{C: colornames.Grey900},
+		Error:      image.Uniform{C: colornames.Red300},
+		Surface:    image.Uniform{C: colornames.Grey800},
+		OnSurface:  image.Uniform{C: colornames.Grey50},
+		Disabled:   image.Uniform{C: colornames.Grey500},
+		Selection:  image.Uniform{C: colornames.Blue900},
+	}
+
+	// DefaultPalette is the default theme's palette.
+	DefaultPalette = LightPalette
+
+	// LightTheme and DarkTheme use the default DPI and FontFaceCatalog, and
+	// the LightPalette and DarkPalette. Like any Theme, they can be applied
+	// to part of a widget tree, or replaced at runtime, with a widget.Themer.
+	LightTheme = &Theme{Palette: &LightPalette}
+	DarkTheme  = &Theme{Palette: &DarkPalette}
+
 	// Default uses the default DPI, FontFaceCatalog and Palette.
 	//
 	// The nil-valued pointer is a valid receiver for a Theme's methods.
@@ -184,13 +276,45 @@ func (t *Theme) GetPalette() *Palette {
 	return &DefaultPalette
 }
 
+// Override returns a new Theme with the non-zero fields of o, and the other
+// fields of t. It is typically used to change part of an inherited theme,
+// such as its Palette, for a subtree of widgets, while keeping the rest, such
+// as its DPI.
+func (t *Theme) Override(o *Theme) *Theme {
+	u := &Theme{}
+	if t != nil {
+		*u = *t
+	}
+	if o == nil {
+		return u
+	}
+	if o.DPI != 0 {
+		u.DPI = o.DPI
+	}
+	if o.FontFaceCatalog != nil {
+		u.FontFaceCatalog = o.FontFaceCatalog
+	}
+	if o.Palette != nil {
+		u.Palette = o.Palette
+	}
+	return u
+}
+
 // AcquireFontFace calls the same method on the result of GetFontFaceCatalog.
+// If o's DPI is zero, it is set to the result of GetDPI.
 func (t *Theme) AcquireFontFace(o FontFaceOptions) font.Face {
+	if o.DPI == 0 {
+		o.DPI = t.GetDPI()
+	}
 	return t.GetFontFaceCatalog().AcquireFontFace(o)
 }
 
 // ReleaseFontFace calls the same method on the result of GetFontFaceCatalog.
+// If o's DPI is zero, it is set to the result of GetDPI.
 func (t *Theme) ReleaseFontFace(o FontFaceOptions, f font.Face) {
+	if o.DPI == 0 {
+		o.DPI = t.GetDPI()
+	}
 	t.GetFontFaceCatalog().ReleaseFontFace(o, f)
 }
 
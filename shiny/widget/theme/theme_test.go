@@ -40,3 +40,62 @@ func TestThemeIsAUnitConverter(t *testing.T) {
 		}
 	}
 }
+
+func TestPalettesAreComplete(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		p    *Palette
+	}{
+		{"LightPalette", &LightPalette},
+		{"DarkPalette", &DarkPalette},
+	} {
+		for i := range tc.p {
+			if tc.p[i].C == nil {
+				t.Errorf("%s: index %d: got nil color", tc.name, i)
+			}
+		}
+	}
+}
+
+func TestPaletteFallbacks(t *testing.T) {
+	// A palette literal with only the original six entries.
+	p := &Palette{
+		Light:      LightPalette[Light],
+		Neutral:    LightPalette[Neutral],
+		Dark:       LightPalette[Dark],
+		Accent:     LightPalette[Accent],
+		Foreground: LightPalette[Foreground],
+		Background: LightPalette[Background],
+	}
+	th := &Theme{Palette: p}
+	for i := PaletteIndex(0); i < PaletteLen; i++ {
+		if i.Color(th) == nil {
+			t.Errorf("index %d: got nil color", i)
+		}
+	}
+	if got, want := p.Surface(), p.Background(); got != want {
+		t.Errorf("Surface: got %p, want Background %p", got, want)
+	}
+	if got, want := p.OnSurface(), p.Foreground(); got != want {
+		t.Errorf("OnSurface: got %p, want Foreground %p", got, want)
+	}
+	if got, want := LightPalette.Surface(), &LightPalette[Surface]; got != want {
+		t.Errorf("LightPalette.Surface: got %p, want its own entry %p", got, want)
+	}
+}
+
+func TestOverride(t *testing.T) {
+	parent := &Theme{DPI: 160}
+	got := parent.Override(DarkTheme)
+	if got.DPI != 160 || got.Palette != &DarkPalette {
+		t.Errorf("got DPI %v, dark palette %t, want 160, true", got.DPI, got.Palette == &DarkPalette)
+	}
+	if parent.Palette != nil {
+		t.Error("Override modified its receiver")
+	}
+
+	got = Default.Override(&Theme{DPI: 96})
+	if got.DPI != 96 || got.GetPalette() != &DefaultPalette {
+		t.Errorf("nil receiver: got DPI %v, default palette %t, want 96, true", got.DPI, got.GetPalette() == &DefaultPalette)
+	}
+}
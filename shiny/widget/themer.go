@@ -0,0 +1,108 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+)
+
+// Themer is a shell widget that overrides the theme of its child and that
+// child's descendants.
+//
+// The theme that a Themer passes to its child is its parent's theme,
+// overridden by the non-zero fields of its own Theme, as per
+// theme.Theme.Override. For example, a Themer whose Theme has only a Palette
+// changes the colors of a subtree but keeps the window's DPI.
+//
+// A Themer at the root of a window's widget tree can change that window's
+// theme while it is running, such as to switch between theme.LightTheme and
+// theme.DarkTheme.
+//
+// TODO: Popups, such as Menus, that are shown by the descendants of a Themer
+// that is not at the root are children of the window's Overlay, not of the
+// Themer, and so do not use its theme.
+type Themer struct {
+	node.ShellEmbed
+
+	// Theme holds the fields that override the parent's theme. A nil Theme
+	// means to use the parent's theme unchanged. Call SetTheme, instead of
+	// setting this field directly, to change the theme of a running widget
+	// tree.
+	Theme *theme.Theme
+
+	// theme is the overridden theme passed to the child, computed from the
+	// parent theme and the Theme field, as they were at that time. Re-using
+	// the same *theme.Theme value, until either of those changes, lets
+	// descendants such as Text widgets cache their font faces.
+	theme, parent, override *theme.Theme
+}
+
+// NewThemer returns a new Themer widget that overrides the theme of inner
+// with t.
+func NewThemer(t *theme.Theme, inner node.Node) *Themer {
+	w := &Themer{
+		Theme: t,
+	}
+	w.Wrapper = w
+	if inner != nil {
+		w.Insert(inner, nil)
+	}
+	return w
+}
+
+// SetTheme changes w's Theme, and marks its descendants as needing to be
+// measured, laid out and painted again with the new theme.
+func (w *Themer) SetTheme(t *theme.Theme) {
+	w.Theme = t
+	markThemeChanged(&w.Embed)
+}
+
+// markThemeChanged marks e and all of its descendants as needing measure,
+// layout and paint, including the PaintBase of any descendant Sheets.
+func markThemeChanged(e *node.Embed) {
+	for c := e.FirstChild; c != nil; c = c.NextSibling {
+		markThemeChanged(c)
+	}
+	e.Wrapper.Mark(node.MarkNeedsMeasureLayout | node.MarkNeedsPaint | node.MarkNeedsPaintBase)
+}
+
+// childTheme returns the theme to pass to w's child, given w's parent's theme
+// t.
+func (w *Themer) childTheme(t *theme.Theme) *theme.Theme {
+	if w.Theme == nil {
+		return t
+	}
+	if w.theme == nil || w.parent != t || w.override != w.Theme {
+		w.theme, w.parent, w.override = t.Override(w.Theme), t, w.Theme
+	}
+	return w.theme
+}
+
+func (w *Themer) Measure(t *theme.Theme, widthHint, heightHint int) {
+	w.ShellEmbed.Measure(w.childTheme(t), widthHint, heightHint)
+}
+
+func (w *Themer) Baseline(t *theme.Theme) int {
+	return w.ShellEmbed.Baseline(w.childTheme(t))
+}
+
+func (w *Themer) Layout(t *theme.Theme) {
+	w.ShellEmbed.Layout(w.childTheme(t))
+}
+
+func (w *Themer) Paint(ctx *node.PaintContext, origin image.Point) error {
+	c := *ctx
+	c.Theme = w.childTheme(ctx.Theme)
+	return w.ShellEmbed.Paint(&c, origin)
+}
+
+func (w *Themer) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
+	c := *ctx
+	c.Theme = w.childTheme(ctx.Theme)
+	return w.ShellEmbed.PaintBase(&c, origin)
+}
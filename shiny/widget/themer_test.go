@@ -0,0 +1,135 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+)
+
+// themeLeaf is a leaf widget that records the theme that it is measured, laid
+// out and painted with.
+type themeLeaf struct {
+	node.LeafEmbed
+	themes []*theme.Theme
+}
+
+func newThemeLeaf() *themeLeaf {
+	w := &themeLeaf{}
+	w.Wrapper = w
+	return w
+}
+
+func (w *themeLeaf) Measure(t *theme.Theme, widthHint, heightHint int) {
+	w.themes = append(w.themes, t)
+	w.LeafEmbed.Measure(t, widthHint, heightHint)
+}
+
+func (w *themeLeaf) Layout(t *theme.Theme) {
+	w.themes = append(w.themes, t)
+	w.LeafEmbed.Layout(t)
+}
+
+func (w *themeLeaf) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
+	w.themes = append(w.themes, ctx.Theme)
+	return w.LeafEmbed.PaintBase(ctx, origin)
+}
+
+// unmarkAll clears the marks of e and all of its descendants.
+func unmarkAll(e *node.Embed) {
+	for c := e.FirstChild; c != nil; c = c.NextSibling {
+		unmarkAll(c)
+	}
+	e.Marks = 0
+}
+
+func TestThemerSetTheme(t *testing.T) {
+	a, b := newThemeLeaf(), newThemeLeaf()
+	w := NewThemer(nil, NewFlow(AxisHorizontal, a, NewSizer(unit.Value{}, unit.Value{}, b)))
+	root := NewSheet(w)
+	unmarkAll(&root.Embed)
+
+	w.SetTheme(&theme.Theme{DPI: 144})
+	const all = node.MarkNeedsMeasureLayout | node.MarkNeedsPaint | node.MarkNeedsPaintBase
+	var check func(e *node.Embed)
+	check = func(e *node.Embed) {
+		if e.Marks&all != all {
+			t.Errorf("%T: got marks %#x, want %#x", e.Wrapper, e.Marks, all)
+		}
+		for c := e.FirstChild; c != nil; c = c.NextSibling {
+			check(c)
+		}
+	}
+	check(&w.Embed)
+	// The Themer's ancestors are marked too, so that the change is noticed.
+	if m := root.Marks; !m.NeedsMeasureLayout() || !m.NeedsPaint() {
+		t.Errorf("parent: got marks %#x, want measure, layout and paint", m)
+	}
+}
+
+func TestThemerChildTheme(t *testing.T) {
+	leaf := newThemeLeaf()
+	w := NewThemer(nil, leaf)
+	parent := &theme.Theme{DPI: 72}
+	run := func() {
+		leaf.themes = leaf.themes[:0]
+		w.Measure(parent, node.NoHint, node.NoHint)
+		w.Rect = image.Rect(0, 0, 10, 10)
+		w.Layout(parent)
+		w.PaintBase(&node.PaintBaseContext{Theme: parent, Dst: image.NewRGBA(w.Rect)}, image.Point{})
+	}
+
+	// A nil Theme passes the parent's theme through.
+	run()
+	for _, got := range leaf.themes {
+		if got != parent {
+			t.Fatalf("nil Theme: got %p, want the parent's %p", got, parent)
+		}
+	}
+
+	// childTheme checks that every call used the same theme, and returns it.
+	childTheme := func(desc string) *theme.Theme {
+		t.Helper()
+		run()
+		if len(leaf.themes) != 3 {
+			t.Fatalf("%s: got %d calls, want 3", desc, len(leaf.themes))
+		}
+		for _, got := range leaf.themes[1:] {
+			if got != leaf.themes[0] {
+				t.Errorf("%s: got themes %p, want them all the same", desc, leaf.themes)
+				break
+			}
+		}
+		return leaf.themes[0]
+	}
+
+	w.SetTheme(&theme.Theme{DPI: 144})
+	t0 := childTheme("SetTheme")
+	if t0 == parent || t0.GetDPI() != 144 {
+		t.Errorf("SetTheme: got DPI %v, want 144", t0.GetDPI())
+	}
+	if t1 := childTheme("unchanged"); t1 != t0 {
+		t.Error("unchanged: got a new theme")
+	}
+
+	// A new parent theme, even with the same fields, makes a new theme.
+	parent = &theme.Theme{DPI: 72}
+	t1 := childTheme("new parent")
+	if t1 == t0 {
+		t.Error("new parent: got the old theme")
+	}
+	if t2 := childTheme("new parent, unchanged"); t2 != t1 {
+		t.Error("new parent, unchanged: got a new theme")
+	}
+
+	w.SetTheme(&theme.Theme{DPI: 96})
+	if t2 := childTheme("new Theme"); t2 == t1 || t2.GetDPI() != 96 {
+		t.Errorf("new Theme: got DPI %v, want a new theme with DPI 96", t2.GetDPI())
+	}
+}
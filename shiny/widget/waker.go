@@ -0,0 +1,91 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/exp/shiny/screen"
+	"golang.org/x/exp/shiny/widget/node"
+)
+
+// wakeEvent is sent to a window's event deque when one or more of its waker's
+// wake-ups are due.
+type wakeEvent struct{}
+
+type wakeUp struct {
+	t time.Time
+	n node.Node
+	m node.Marks
+}
+
+// waker implements the node.Waker interface for RunWindow.
+//
+// Its WakeAt and wake methods are called only from the event loop's goroutine,
+// but its timer fires on another goroutine, hence the mutex.
+type waker struct {
+	q screen.EventDeque
+
+	mu      sync.Mutex
+	wakeUps []wakeUp
+	timer   *time.Timer
+	timerAt time.Time
+}
+
+func (w *waker) WakeAt(t time.Time, n node.Node, m node.Marks) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.wakeUps = append(w.wakeUps, wakeUp{t, n, m})
+	w.arm(t)
+}
+
+// arm makes sure that the timer fires no later than t. It must only be called
+// while holding w.mu.
+func (w *waker) arm(t time.Time) {
+	if w.timer != nil {
+		if !t.Before(w.timerAt) {
+			return
+		}
+		w.timer.Stop()
+	}
+	w.timerAt = t
+	w.timer = time.AfterFunc(time.Until(t), func() {
+		w.q.Send(wakeEvent{})
+	})
+}
+
+// wake marks the nodes whose wake-ups are due at the time now.
+func (w *waker) wake(now time.Time) {
+	w.mu.Lock()
+	due, pending := []wakeUp(nil), w.wakeUps[:0]
+	for _, u := range w.wakeUps {
+		if u.t.After(now) {
+			pending = append(pending, u)
+		} else {
+			due = append(due, u)
+		}
+	}
+	for i := len(pending); i < len(w.wakeUps); i++ {
+		w.wakeUps[i] = wakeUp{}
+	}
+	w.wakeUps = pending
+
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	for _, u := range pending {
+		w.arm(u.t)
+	}
+	w.mu.Unlock()
+
+	// Call Mark without holding w.mu, as marking a node can, in theory, call
+	// back into WakeAt.
+	for _, u := range due {
+		u.n.Wrappee().Mark(u.m)
+	}
+}
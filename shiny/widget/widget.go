@@ -9,13 +9,19 @@ package widget // import "golang.org/x/exp/shiny/widget"
 
 import (
 	"image"
+	"time"
 
+	"golang.org/x/exp/shiny/dnd"
 	"golang.org/x/exp/shiny/gesture"
 	"golang.org/x/exp/shiny/screen"
 	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/vsync"
+	"golang.org/x/exp/shiny/widget/access"
+	"golang.org/x/exp/shiny/widget/anim"
 	"golang.org/x/exp/shiny/widget/node"
 	"golang.org/x/exp/shiny/widget/theme"
 	"golang.org/x/image/math/f64"
+	"golang.org/x/mobile/event/key"
 	"golang.org/x/mobile/event/lifecycle"
 	"golang.org/x/mobile/event/mouse"
 	"golang.org/x/mobile/event/paint"
@@ -48,6 +54,16 @@ type RunWindowOptions struct {
 	NewWindowOptions screen.NewWindowOptions
 	Theme            theme.Theme
 
+	// Animator, if non-nil, is ticked at the start of every frame while it
+	// has running animations, so that the program can animate widgets by
+	// calling its Start method.
+	Animator *anim.Animator
+
+	// Accessibility, if non-nil, is given a snapshot of the window's
+	// accessibility tree after the window is painted, at most ten times per
+	// second.
+	Accessibility access.Exporter
+
 	// TODO: some mechanism to process, filter and inject events. Perhaps a
 	// screen.EventFilter interface, and note that the zero value in this
 	// RunWindowOptions implicitly includes the gesture.EventFilter?
@@ -57,22 +73,34 @@ type RunWindowOptions struct {
 // like lifecycle events or app-specific)? How does it stop the event loop when
 // the app's work is done?
 
-// TODO: how do widgets signal that they need repaint or relayout?
-
-// TODO: propagate keyboard / mouse / touch events.
+// TODO: propagate touch events.
 
 // RunWindow creates a new window for s, with the given widget tree, and runs
 // its event loop.
 //
+// Unless root is an *Overlay, the widget tree is wrapped in one, so that its
+// widgets can show Popups such as menus and dialogs. If root is a *Themer or
+// an *Inspector, the Overlay is inserted between it and its child instead, so
+// that calling the Themer's SetTheme changes the theme of the whole window,
+// including its Popups, and the Inspector shows those Popups too. Likewise
+// for a Themer in an Inspector, or vice versa.
+//
 // A nil opts is valid and means to use the default option values.
 func RunWindow(s screen.Screen, root node.Node, opts *RunWindowOptions) error {
 	var (
-		nwo *screen.NewWindowOptions
-		t   *theme.Theme
+		nwo      *screen.NewWindowOptions
+		t        *theme.Theme
+		animator *anim.Animator
+		exporter access.Exporter
 	)
 	if opts != nil {
 		nwo = &opts.NewWindowOptions
 		t = &opts.Theme
+		animator = opts.Animator
+		exporter = opts.Accessibility
+	}
+	if animator == nil {
+		animator = new(anim.Animator)
 	}
 	w, err := s.NewWindow(nwo)
 	if err != nil {
@@ -80,22 +108,66 @@ func RunWindow(s screen.Screen, root node.Node, opts *RunWindowOptions) error {
 	}
 	defer w.Release()
 
-	// paintPending batches up multiple NeedsPaint observations so that we
-	// paint only once (which can be relatively expensive) even when there are
-	// multiple input events in the queue, such as from a rapidly moving mouse
-	// or from the user typing many keys.
-	//
-	// TODO: determine somehow if there's an external paint event in the queue,
-	// not just internal paint events?
-	//
-	// TODO: if every package that uses package screen should basically
-	// throttle like this, should it be provided at a lower level?
-	paintPending := false
+	// sched batches up multiple NeedsPaint observations so that we paint
+	// only once per frame (painting can be relatively expensive) even when
+	// there are multiple input events in the queue, such as from a rapidly
+	// moving mouse or from the user typing many keys.
+	sched := vsync.Scheduler{Window: w}
+
+	// dndX and dndY are the position of the most recent drag and drop event.
+	// A dnd.TypeLeave event carries no position of its own, so it is routed
+	// as if it were at that position.
+	dndX, dndY := float32(0), float32(0)
+
+	// focus is the node, if any, that has the keyboard focus. Pressing a
+	// mouse button focuses the focusable node under the pointer, and Tab and
+	// Shift-Tab move the focus in tree order, unless the focused node (or one
+	// of its ancestors) handles those key events.
+	focus := node.Focus{}
+
+	// pointer tracks the nodes under the mouse pointer, sending them
+	// node.PointerEvents as the pointer enters and leaves them. A mouse press
+	// captures the pointer until the button is released, so that a drag that
+	// leaves a node's Rect still sends mouse events to that node.
+	pointer := node.Pointer{}
+
+	// ov shows popups above the rest of the widget tree. While a modal popup
+	// is shown, only its descendants can have the focus.
+	ov, root := windowOverlay(root)
+	ov.focus = &focus
+	ov.pointer = &pointer
+
+	// wk lets nodes ask to be marked at a later time, such as to blink a
+	// text caret.
+	wk := &waker{q: w}
+
+	// tree takes snapshots of the widget tree for the exporter, and run lets
+	// the exporter call a function, such as an accessibility action, on this
+	// goroutine.
+	tree := access.Tree{}
+	run := func(f func()) { w.Send(runEvent(f)) }
+
+	// exported is when the last snapshot was exported, and exportPending is
+	// whether an exportEvent has been scheduled. Snapshots are taken at most
+	// once per exportInterval, and painting during that interval schedules an
+	// exportEvent for its end, so that the final state is always exported.
+	exported, exportPending := time.Time{}, false
 
 	gef := gesture.EventFilter{EventDeque: w}
+	// bounds is the window's bounds, from the most recent size event. The
+	// widget tree is laid out again, within those bounds, whenever the root
+	// is marked as needing it.
+	bounds := image.Rectangle{}
+
 	for {
 		e := w.NextEvent()
 
+		if f, ok := e.(vsync.Event); ok && animator.Running() {
+			animator.Tick(f.Time)
+		}
+		if e = sched.Filter(e); e == nil {
+			continue
+		}
 		if e = gef.Filter(e); e == nil {
 			continue
 		}
@@ -107,7 +179,37 @@ func RunWindow(s screen.Screen, root node.Node, opts *RunWindowOptions) error {
 				return nil
 			}
 
-		case gesture.Event, mouse.Event:
+		case gesture.Event:
+			root.OnInputEvent(e, image.Point{})
+
+		case mouse.Event:
+			if e.Direction == mouse.DirPress {
+				focus.Validate(root)
+				p, scope := image.Point{int(e.X), int(e.Y)}, ov.focusScope()
+				if p.In(scope.Wrappee().Rect) {
+					focus.SetAt(scope, p)
+				}
+			}
+			pointer.Validate(root)
+			pointer.OnMouseEvent(root, e)
+
+		case key.Event:
+			focus.Validate(root)
+			if focus.OnKeyEvent(root, e) == node.NotHandled &&
+				e.Code == key.CodeTab && e.Direction != key.DirRelease {
+				if e.Modifiers&key.ModShift != 0 {
+					focus.Prev(ov.focusScope())
+				} else {
+					focus.Next(ov.focusScope())
+				}
+			}
+
+		case dnd.Event:
+			if e.Type == dnd.TypeLeave {
+				e.X, e.Y = dndX, dndY
+			} else {
+				dndX, dndY = e.X, e.Y
+			}
 			root.OnInputEvent(e, image.Point{})
 
 		case paint.Event:
@@ -115,6 +217,7 @@ func RunWindow(s screen.Screen, root node.Node, opts *RunWindowOptions) error {
 				Theme:  t,
 				Screen: s,
 				Drawer: w,
+				Waker:  wk,
 				Src2Dst: f64.Aff3{
 					1, 0, 0,
 					0, 1, 0,
@@ -124,7 +227,15 @@ func RunWindow(s screen.Screen, root node.Node, opts *RunWindowOptions) error {
 				return err
 			}
 			w.Publish()
-			paintPending = false
+			if exporter != nil && !exportPending {
+				if d := exportInterval - time.Since(exported); d > 0 {
+					exportPending = true
+					time.AfterFunc(d, func() { w.Send(exportEvent{}) })
+				} else {
+					exporter.Export(tree.Snapshot(root), run)
+					exported = time.Now()
+				}
+			}
 
 		case size.Event:
 			if dpi := float64(e.PixelsPerPt) * unit.PointsPerInch; dpi != t.GetDPI() {
@@ -136,19 +247,98 @@ func RunWindow(s screen.Screen, root node.Node, opts *RunWindowOptions) error {
 				t = newT
 			}
 
-			size := e.Size()
-			root.Measure(t, size.X, size.Y)
-			root.Wrappee().Rect = e.Bounds()
-			root.Layout(t)
+			bounds = e.Bounds()
+			layout(root, t, bounds)
 			// TODO: call Mark(node.MarkNeedsPaint)?
 
+		case wakeEvent:
+			wk.wake(time.Now())
+
+		case runEvent:
+			e()
+
+		case exportEvent:
+			exportPending = false
+			exporter.Export(tree.Snapshot(root), run)
+			exported = time.Now()
+
 		case error:
 			return e
 		}
 
-		if !paintPending && root.Wrappee().Marks.NeedsPaint() {
-			paintPending = true
-			w.Send(paint.Event{})
+		if root.Wrappee().Marks.NeedsMeasureLayout() {
+			layout(root, t, bounds)
+			root.Mark(node.MarkNeedsPaint)
+		}
+		// A running animation changes the widget tree every frame, so keep
+		// painting until it finishes.
+		if root.Wrappee().Marks.NeedsPaint() || animator.Running() {
+			sched.SchedulePaint()
 		}
 	}
 }
+
+// runEvent is a function to be called on a window's event loop goroutine.
+type runEvent func()
+
+// exportInterval is the minimum time between two snapshots of a window's
+// accessibility tree. Taking a snapshot visits the whole widget tree, so a
+// window that is painted every frame, such as during an animation, should not
+// take one every frame.
+const exportInterval = 100 * time.Millisecond
+
+// exportEvent is sent to a window's event deque when a snapshot of its
+// accessibility tree, delayed by exportInterval, is due.
+type exportEvent struct{}
+
+// windowOverlay returns the Overlay of a window's widget tree, inserting one
+// if necessary, and the tree's root, which may have changed. The Overlay goes
+// below any Inspectors and Themers at the root of the tree.
+func windowOverlay(root node.Node) (*Overlay, node.Node) {
+	// shell is the deepest of those Inspectors and Themers, and n is its
+	// child.
+	var shell node.Node
+	n := root
+loop:
+	for n != nil {
+		switch x := n.(type) {
+		case *Overlay:
+			return x, root
+		case *Inspector, *Themer:
+			shell, n = x, nil
+			if c := shell.Wrappee().FirstChild; c != nil {
+				n = c.Wrapper
+			}
+		default:
+			break loop
+		}
+	}
+	if shell == nil {
+		ov := NewOverlay(root)
+		return ov, ov
+	}
+	if n != nil {
+		shell.Remove(n)
+	}
+	ov := NewOverlay(n)
+	shell.Insert(ov, nil)
+	return ov, root
+}
+
+// layout measures and lays out the widget tree rooted at root to fill bounds,
+// and then clears the NeedsMeasureLayout marks of every node in the tree, so
+// that marking any of them again will propagate to the root.
+func layout(root node.Node, t *theme.Theme, bounds image.Rectangle) {
+	size, e := bounds.Size(), root.Wrappee()
+	e.MeasureOuter(t, size.X, size.Y)
+	e.SetOuterRect(t, bounds)
+	root.Layout(t)
+	unmarkNeedsMeasureLayout(root.Wrappee())
+}
+
+func unmarkNeedsMeasureLayout(e *node.Embed) {
+	e.Marks.UnmarkNeedsMeasureLayout()
+	for c := e.FirstChild; c != nil; c = c.NextSibling {
+		unmarkNeedsMeasureLayout(c)
+	}
+}